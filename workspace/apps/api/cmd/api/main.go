@@ -15,10 +15,14 @@ import (
 
 	"github.com/emiliospot/footie/api/internal/api"
 	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/archive"
 	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/livestats"
 	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
 	"github.com/emiliospot/footie/api/internal/infrastructure/redis"
 	ws "github.com/emiliospot/footie/api/internal/infrastructure/websocket"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
 )
 
 // @title Footie API.
@@ -129,7 +133,7 @@ func main() {
 	// Initialize WebSocket hub (only if Redis is available)
 	var hub *ws.Hub
 	if redisClient != nil {
-		hub = ws.NewHub(redisClient, appLogger)
+		hub = ws.NewHub(redisClient, appLogger, cfg.WS)
 		go hub.Run(ctx)
 		appLogger.Info("WebSocket hub started")
 	} else {
@@ -137,6 +141,23 @@ func main() {
 		hub = nil
 	}
 
+	// Start the live stats stream consumer (only if Redis is available)
+	if redisClient != nil && cfg.LiveStats.Enabled {
+		consumer := livestats.NewConsumer(redisClient, events.NewPublisher(redisClient, appLogger, cfg.Streams), appLogger, cfg.LiveStats)
+		go consumer.Run(ctx)
+	}
+
+	// Start the stream archiver (needs Redis and the database to find finished matches)
+	if redisClient != nil && pool != nil && cfg.Streams.ArchiveEnabled {
+		store, storeErr := archive.NewStore(cfg.Streams, cfg.AWS)
+		if storeErr != nil {
+			appLogger.Error("Stream archiver not started", "error", storeErr)
+		} else {
+			archiver := archive.NewArchiver(redisClient, sqlc.New(pool), store, appLogger, cfg.Streams)
+			go archiver.Run(ctx)
+		}
+	}
+
 	// Initialize router (pool and redis can be nil in development for mock endpoints)
 	// Note: Handlers that use database will fail if pool is nil, but rankings (mock data) will work
 	router := api.NewRouter(cfg, pool, redisClient, hub, appLogger)
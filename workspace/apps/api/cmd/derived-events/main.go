@@ -0,0 +1,139 @@
+// Command derived-events infers stored matches' assists, second assists, key
+// passes and shot-creating actions from their event sequences and recounts their
+// players' assists and key passes. Matches are otherwise inferred when their
+// status changes to finished and when one of their events is corrected.
+//
+// Usage:
+//
+//	derived-events [-match 123] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"math"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/assists"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of matches read per query.
+const batchSize = 100
+
+func main() {
+	matchID := flag.Int("match", 0, "match to infer (defaults to every finished match)")
+	dryRun := flag.Bool("dry-run", false, "infer without writing")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	if *matchID != 0 {
+		count, err := infer(ctx, pool, queries, int32(*matchID), *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to infer match %d: %v", *matchID, err)
+		}
+		log.Printf("Match %d: %d annotated events", *matchID, count)
+		return
+	}
+
+	matches, total := 0, 0
+	for offset := int32(0); ; offset += batchSize {
+		batch, err := queries.GetMatchesByStatus(ctx, sqlc.GetMatchesByStatusParams{
+			Status: "finished",
+			Limit:  batchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("Failed to list matches: %v", err)
+		}
+		for _, match := range batch {
+			count, err := infer(ctx, pool, queries, match.ID, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to infer match %d after %d matches: %v", match.ID, matches, err)
+			}
+			matches++
+			total += count
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d events would be annotated in %d matches", total, matches)
+		return
+	}
+	log.Printf("Annotated %d events in %d matches", total, matches)
+}
+
+// infer derives the passes and creating actions of a match's events and, unless
+// dryRun is set, stores them and recounts the match's player statistics. It
+// returns the number of annotated events.
+func infer(ctx context.Context, pool *pgxpool.Pool, queries *sqlc.Queries, matchID int32, dryRun bool) (int, error) {
+	sqlcEvents, err := queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, err
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	annotations := assists.Annotate(matchEvents)
+	if dryRun {
+		return len(annotations), nil
+	}
+
+	arg := sqlc.SetMatchEventDerivedParams{MatchID: matchID}
+	for _, a := range annotations {
+		xa := math.NaN()
+		if a.XA != nil {
+			xa = *a.XA
+		}
+		arg.Ids = append(arg.Ids, a.EventID)
+		arg.DerivedTypes = append(arg.DerivedTypes, a.DerivedType)
+		arg.Xas = append(arg.Xas, xa)
+		arg.ShotEventIds = append(arg.ShotEventIds, a.ShotEventID)
+		arg.CreationTypes = append(arg.CreationTypes, a.CreationType)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txQueries := queries.WithTx(tx)
+	if err := txQueries.SetMatchEventDerived(ctx, arg); err != nil {
+		return 0, err
+	}
+	if err := txQueries.UpdatePlayerCreativeStats(ctx, matchID); err != nil {
+		return 0, err
+	}
+	return len(annotations), tx.Commit(ctx)
+}
@@ -0,0 +1,114 @@
+// Command game-states tags stored events with their team's game state and goal
+// differential at the time. Matches are otherwise tagged when their status
+// changes to finished.
+//
+// Usage:
+//
+//	game-states [-match 123] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of matches read per query.
+const batchSize = 100
+
+func main() {
+	matchID := flag.Int("match", 0, "match to tag (defaults to every finished match)")
+	dryRun := flag.Bool("dry-run", false, "tag without writing")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	if *matchID != 0 {
+		count, err := tag(ctx, queries, int32(*matchID), *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to tag match %d: %v", *matchID, err)
+		}
+		log.Printf("Match %d: %d events", *matchID, count)
+		return
+	}
+
+	matches, total := 0, 0
+	for offset := int32(0); ; offset += batchSize {
+		batch, err := queries.GetMatchesByStatus(ctx, sqlc.GetMatchesByStatusParams{
+			Status: "finished",
+			Limit:  batchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("Failed to list matches: %v", err)
+		}
+		for _, match := range batch {
+			count, err := tag(ctx, queries, match.ID, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to tag match %d after %d matches: %v", match.ID, matches, err)
+			}
+			matches++
+			total += count
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d events would be tagged in %d matches", total, matches)
+		return
+	}
+	log.Printf("Tagged %d events in %d matches", total, matches)
+}
+
+// tag computes the game state of a match's events and, unless dryRun is set,
+// stores them. It returns the number of events tagged.
+func tag(ctx context.Context, queries *sqlc.Queries, matchID int32, dryRun bool) (int, error) {
+	sqlcEvents, err := queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, err
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	tags := gamestate.Tags(matchEvents)
+	if dryRun {
+		return len(tags), nil
+	}
+
+	arg := sqlc.SetMatchEventGameStatesParams{MatchID: matchID}
+	for _, t := range tags {
+		arg.Ids = append(arg.Ids, t.EventID)
+		arg.GameStates = append(arg.GameStates, string(t.State))
+		arg.ScoreDifferentials = append(arg.ScoreDifferentials, int32(t.Differential))
+	}
+	return len(tags), queries.SetMatchEventGameStates(ctx, arg)
+}
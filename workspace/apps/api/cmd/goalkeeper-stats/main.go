@@ -0,0 +1,183 @@
+// Command goalkeeper-stats computes the statistics of the goalkeepers of stored
+// matches, stores them by match and recomputes the goalkeepers' clean sheets,
+// goals conceded, saves, save percentage and penalties saved over their season and
+// competition. Goalkeepers are otherwise computed when one of their matches'
+// status changes to finished.
+//
+// Usage:
+//
+//	goalkeeper-stats [-match 123] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/comparison"
+	"github.com/emiliospot/footie/api/internal/analytics/goalkeeping"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of matches read per query.
+const batchSize = 100
+
+// backfill computes the goalkeepers of matches.
+type backfill struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+	known   map[int32]bool // Goalkeepers by position
+	teams   map[int32]bool // Teams whose players are in known
+}
+
+func main() {
+	matchID := flag.Int("match", 0, "match whose goalkeepers to compute (defaults to every finished match)")
+	dryRun := flag.Bool("dry-run", false, "compute without writing")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	b := &backfill{
+		pool:    pool,
+		queries: sqlc.New(pool),
+		known:   map[int32]bool{},
+		teams:   map[int32]bool{},
+	}
+	if *matchID != 0 {
+		match, err := b.queries.GetMatchByID(ctx, int32(*matchID))
+		if err != nil {
+			log.Fatalf("Failed to get match %d: %v", *matchID, err)
+		}
+		count, err := b.compute(ctx, match, *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to compute match %d: %v", *matchID, err)
+		}
+		log.Printf("Match %d: %d goalkeepers", *matchID, count)
+		return
+	}
+
+	matches, total := 0, 0
+	for offset := int32(0); ; offset += batchSize {
+		batch, err := b.queries.GetMatchesByStatus(ctx, sqlc.GetMatchesByStatusParams{
+			Status: "finished",
+			Limit:  batchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("Failed to list matches: %v", err)
+		}
+		for _, match := range batch {
+			count, err := b.compute(ctx, match, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to compute match %d after %d matches: %v", match.ID, matches, err)
+			}
+			matches++
+			total += count
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d goalkeepers would be stored in %d matches", total, matches)
+		return
+	}
+	log.Printf("Stored %d goalkeepers in %d matches", total, matches)
+}
+
+// compute computes the statistics of a match's goalkeepers and, unless dryRun is
+// set, stores them and recomputes the goalkeepers' season statistics. It returns
+// the number of goalkeepers.
+func (b *backfill) compute(ctx context.Context, match sqlc.Match, dryRun bool) (int, error) {
+	for _, teamID := range []int32{match.HomeTeamID, match.AwayTeamID} {
+		if b.teams[teamID] {
+			continue
+		}
+		players, err := b.queries.GetPlayersByTeam(ctx, teamID)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range players {
+			if group, ok := comparison.GroupFor(p.Position); ok && group == comparison.Goalkeeper {
+				b.known[p.ID] = true
+			}
+		}
+		b.teams[teamID] = true
+	}
+
+	sqlcEvents, err := b.queries.GetMatchEvents(ctx, match.ID)
+	if err != nil {
+		return 0, err
+	}
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+
+	arg := sqlc.CreateGoalkeeperMatchStatsParams{MatchID: match.ID}
+	for _, s := range goalkeeping.Compute(matchEvents, b.known) {
+		arg.PlayerIds = append(arg.PlayerIds, s.PlayerID)
+		arg.TeamIds = append(arg.TeamIds, s.TeamID)
+		arg.CleanSheets = append(arg.CleanSheets, s.CleanSheets > 0)
+		arg.ShotsOnTarget = append(arg.ShotsOnTarget, int32(s.ShotsOnTarget))
+		arg.Saves = append(arg.Saves, int32(s.Saves))
+		arg.GoalsConceded = append(arg.GoalsConceded, int32(s.GoalsConceded))
+		arg.PostShotXg = append(arg.PostShotXg, s.PostShotXG)
+		arg.GoalsPrevented = append(arg.GoalsPrevented, s.GoalsPrevented)
+		arg.PenaltiesFaced = append(arg.PenaltiesFaced, int32(s.PenaltiesFaced))
+		arg.PenaltiesSaved = append(arg.PenaltiesSaved, int32(s.PenaltiesSaved))
+		arg.CrossesFaced = append(arg.CrossesFaced, int32(s.CrossesFaced))
+		arg.CrossesClaimed = append(arg.CrossesClaimed, int32(s.CrossesClaimed))
+	}
+	if dryRun {
+		return len(arg.PlayerIds), nil
+	}
+
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txQueries := b.queries.WithTx(tx)
+	previous, err := txQueries.DeleteGoalkeeperMatchStats(ctx, match.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(arg.PlayerIds) > 0 {
+		if err := txQueries.CreateGoalkeeperMatchStats(ctx, arg); err != nil {
+			return 0, err
+		}
+	}
+	if err := txQueries.RefreshGoalkeeperStats(ctx, sqlc.RefreshGoalkeeperStatsParams{
+		PlayerIds: append(previous, arg.PlayerIds...),
+		MatchID:   match.ID,
+	}); err != nil {
+		return 0, err
+	}
+	return len(arg.PlayerIds), tx.Commit(ctx)
+}
@@ -0,0 +1,114 @@
+// Command possessions reconstructs possessions from stored event logs and
+// assigns each event its possession_id. Matches are otherwise assigned when
+// their status changes to finished.
+//
+// Usage:
+//
+//	possessions [-match 123] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of matches read per query.
+const batchSize = 100
+
+func main() {
+	matchID := flag.Int("match", 0, "match to reconstruct (defaults to every finished match)")
+	dryRun := flag.Bool("dry-run", false, "reconstruct without writing")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	if *matchID != 0 {
+		count, err := assign(ctx, queries, int32(*matchID), *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to reconstruct match %d: %v", *matchID, err)
+		}
+		log.Printf("Match %d: %d possessions", *matchID, count)
+		return
+	}
+
+	matches, total := 0, 0
+	for offset := int32(0); ; offset += batchSize {
+		batch, err := queries.GetMatchesByStatus(ctx, sqlc.GetMatchesByStatusParams{
+			Status: "finished",
+			Limit:  batchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("Failed to list matches: %v", err)
+		}
+		for _, match := range batch {
+			count, err := assign(ctx, queries, match.ID, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to reconstruct match %d after %d matches: %v", match.ID, matches, err)
+			}
+			matches++
+			total += count
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d possessions would be assigned in %d matches", total, matches)
+		return
+	}
+	log.Printf("Assigned %d possessions in %d matches", total, matches)
+}
+
+// assign reconstructs a match's possessions and, unless dryRun is set, stores
+// each event's possession_id. It returns the number of possessions.
+func assign(ctx context.Context, queries *sqlc.Queries, matchID int32, dryRun bool) (int, error) {
+	sqlcEvents, err := queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, err
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	possessions := possession.Reconstruct(matchEvents)
+	if dryRun {
+		return len(possessions), nil
+	}
+
+	eventIDs, possessionIDs := possession.Assignments(possessions)
+	err = queries.SetMatchEventPossessions(ctx, sqlc.SetMatchEventPossessionsParams{
+		Ids:           eventIDs,
+		PossessionIds: possessionIDs,
+		MatchID:       matchID,
+	})
+	return len(possessions), err
+}
@@ -0,0 +1,142 @@
+// Command pressing-stats computes the pressing statistics of the teams of stored
+// matches and stores them by match for the pressing rankings. Teams are otherwise
+// computed when one of their matches' status changes to finished.
+//
+// Usage:
+//
+//	pressing-stats [-match 123] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/analytics/pressing"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of matches read per query.
+const batchSize = 100
+
+func main() {
+	matchID := flag.Int("match", 0, "match whose teams to compute (defaults to every finished match)")
+	dryRun := flag.Bool("dry-run", false, "compute without writing")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	if *matchID != 0 {
+		match, err := queries.GetMatchByID(ctx, int32(*matchID))
+		if err != nil {
+			log.Fatalf("Failed to get match %d: %v", *matchID, err)
+		}
+		count, err := compute(ctx, pool, queries, match, *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to compute match %d: %v", *matchID, err)
+		}
+		log.Printf("Match %d: %d teams", *matchID, count)
+		return
+	}
+
+	matches, total := 0, 0
+	for offset := int32(0); ; offset += batchSize {
+		batch, err := queries.GetMatchesByStatus(ctx, sqlc.GetMatchesByStatusParams{
+			Status: "finished",
+			Limit:  batchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("Failed to list matches: %v", err)
+		}
+		for _, match := range batch {
+			count, err := compute(ctx, pool, queries, match, *dryRun)
+			if err != nil {
+				log.Fatalf("Failed to compute match %d after %d matches: %v", match.ID, matches, err)
+			}
+			matches++
+			total += count
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	if *dryRun {
+		log.Printf("%d teams would be stored in %d matches", total, matches)
+		return
+	}
+	log.Printf("Stored %d teams in %d matches", total, matches)
+}
+
+// compute computes the pressing statistics of a match's teams and, unless dryRun
+// is set, stores them in place of those of an earlier pass. It returns the
+// number of teams.
+func compute(ctx context.Context, pool *pgxpool.Pool, queries *sqlc.Queries, match sqlc.Match, dryRun bool) (int, error) {
+	sqlcEvents, err := queries.GetMatchEvents(ctx, match.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(sqlcEvents) == 0 {
+		return 0, nil
+	}
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+
+	arg := sqlc.CreateTeamPressingMatchStatsParams{MatchID: match.ID}
+	possessions := possession.Reconstruct(matchEvents)
+	for _, s := range pressing.Compute(matchEvents, possessions, match.HomeTeamID, match.AwayTeamID) {
+		arg.TeamIds = append(arg.TeamIds, s.TeamID)
+		arg.OpponentPasses = append(arg.OpponentPasses, int32(s.OpponentPasses))
+		arg.DefensiveActions = append(arg.DefensiveActions, int32(s.DefensiveActions))
+		arg.HighTurnovers = append(arg.HighTurnovers, int32(s.HighTurnovers))
+		arg.FinalThirdRecoveries = append(arg.FinalThirdRecoveries, int32(s.FinalThirdRecoveries))
+		arg.BallLosses = append(arg.BallLosses, int32(s.BallLosses))
+		arg.CounterPressRegains = append(arg.CounterPressRegains, int32(s.CounterPressRegains))
+	}
+	if dryRun {
+		return len(arg.TeamIds), nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	txQueries := queries.WithTx(tx)
+	if err := txQueries.DeleteTeamPressingMatchStats(ctx, match.ID); err != nil {
+		return 0, err
+	}
+	if err := txQueries.CreateTeamPressingMatchStats(ctx, arg); err != nil {
+		return 0, err
+	}
+	return len(arg.TeamIds), tx.Commit(ctx)
+}
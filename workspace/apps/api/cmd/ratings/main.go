@@ -0,0 +1,255 @@
+// Command ratings rebuilds the stored Elo rating history from finished matches
+// and backtests the match outcome predictions. A competition's ratings are
+// otherwise rebuilt when one of its matches finishes.
+//
+// Usage:
+//
+//	ratings rebuild [-competition "Premier League"] [-dry-run]
+//	ratings backtest [-competition "Premier League"] [-min-matches 5] [-window 10] [-xg-weight 0.6]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/rating"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "rebuild":
+		rebuild(os.Args[2:])
+	case "backtest":
+		backtest(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ratings rebuild|backtest [flags]")
+	os.Exit(2)
+}
+
+// rebuild recomputes and stores every competition's rating history.
+func rebuild(args []string) {
+	fs := flag.NewFlagSet("rebuild", flag.ExitOnError)
+	competition := fs.String("competition", "", "competition to rebuild (defaults to every competition)")
+	dryRun := fs.Bool("dry-run", false, "rate without writing")
+	_ = fs.Parse(args)
+
+	ctx := context.Background()
+	pool := connect(ctx)
+	defer pool.Close()
+
+	queries := sqlc.New(pool)
+	competitions := loadResults(ctx, queries, *competition)
+	for _, c := range competitions {
+		_, changes := rating.Run(rating.DefaultEloConfig, c.results)
+		teams := map[int32]bool{}
+		for _, change := range changes {
+			teams[change.TeamID] = true
+		}
+		if !*dryRun {
+			if err := store(ctx, pool, queries, c.name, changes); err != nil {
+				log.Fatalf("Failed to store %s ratings: %v", c.name, err)
+			}
+		}
+		log.Printf("%s: %d matches, %d teams", c.name, len(c.results), len(teams))
+	}
+
+	if *dryRun {
+		log.Printf("%d competitions would be rated", len(competitions))
+		return
+	}
+	log.Printf("Rebuilt ratings for %d competitions", len(competitions))
+}
+
+// store replaces a competition's rating history.
+func store(ctx context.Context, pool *pgxpool.Pool, queries *sqlc.Queries, competition string, changes []rating.Change) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var arg sqlc.CreateTeamRatingsParams
+	for _, c := range changes {
+		arg.TeamIds = append(arg.TeamIds, c.TeamID)
+		arg.OpponentIds = append(arg.OpponentIds, c.OpponentID)
+		arg.MatchIds = append(arg.MatchIds, c.MatchID)
+		arg.Homes = append(arg.Homes, c.Home)
+		arg.RatingsBefore = append(arg.RatingsBefore, c.Before)
+		arg.RatingsAfter = append(arg.RatingsAfter, c.After)
+		arg.ExpectedScores = append(arg.ExpectedScores, c.Expected)
+	}
+	txQueries := queries.WithTx(tx)
+	if err := txQueries.DeleteTeamRatings(ctx, competition); err != nil {
+		return err
+	}
+	if err := txQueries.CreateTeamRatings(ctx, arg); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// score accumulates the Brier scores of a set of predictions.
+type score struct {
+	matches int
+	poisson float64
+	elo     float64
+	base    float64
+}
+
+func (s *score) String() string {
+	if s.matches == 0 {
+		return "no matches with enough history"
+	}
+	n := float64(s.matches)
+	return fmt.Sprintf("%d matches, Brier poisson %.4f, elo %.4f, base rate %.4f", s.matches, s.poisson/n, s.elo/n, s.base/n)
+}
+
+// backtest predicts every finished match from the results before it and
+// reports the Brier scores of the Poisson model against two baselines: Elo
+// expected scores with the draw rate so far, and the outcome rates so far.
+func backtest(args []string) {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	competition := fs.String("competition", "", "competition to backtest (defaults to every competition)")
+	minMatches := fs.Int("min-matches", 5, "earlier results both teams need for a match to be predicted")
+	window := fs.Int("window", rating.DefaultPoissonConfig.Window, "recent matches team strengths are fitted on")
+	xgWeight := fs.Float64("xg-weight", rating.DefaultPoissonConfig.XGWeight, "weight of xG against goals, from 0 to 1")
+	_ = fs.Parse(args)
+
+	cfg := rating.DefaultPoissonConfig
+	cfg.Window, cfg.XGWeight = *window, *xgWeight
+
+	ctx := context.Background()
+	pool := connect(ctx)
+	defer pool.Close()
+
+	var total score
+	for _, c := range loadResults(ctx, sqlc.New(pool), *competition) {
+		var s score
+		elo := rating.NewElo(rating.DefaultEloConfig)
+		played := map[int32]int{}
+		var outcomes [3]int // Home wins, draws, away wins so far
+		for i := range c.results {
+			r := &c.results[i]
+			if i > 0 && played[r.HomeTeamID] >= *minMatches && played[r.AwayTeamID] >= *minMatches {
+				n := float64(i)
+				base := rating.Prediction{
+					Home: float64(outcomes[0]) / n,
+					Draw: float64(outcomes[1]) / n,
+					Away: float64(outcomes[2]) / n,
+				}
+				s.matches++
+				s.poisson += rating.Brier(rating.FitPoisson(cfg, c.results[:i]).Predict(r.HomeTeamID, r.AwayTeamID), r.HomeGoals, r.AwayGoals)
+				s.elo += rating.Brier(eloPrediction(elo.Expected(r.HomeTeamID, r.AwayTeamID), base.Draw), r.HomeGoals, r.AwayGoals)
+				s.base += rating.Brier(base, r.HomeGoals, r.AwayGoals)
+			}
+
+			elo.Apply(r)
+			played[r.HomeTeamID]++
+			played[r.AwayTeamID]++
+			switch {
+			case r.HomeGoals > r.AwayGoals:
+				outcomes[0]++
+			case r.HomeGoals < r.AwayGoals:
+				outcomes[2]++
+			default:
+				outcomes[1]++
+			}
+		}
+
+		log.Printf("%s: %s", c.name, &s)
+		total.matches += s.matches
+		total.poisson += s.poisson
+		total.elo += s.elo
+		total.base += s.base
+	}
+	log.Printf("Total: %s", &total)
+}
+
+// eloPrediction splits an Elo expected score into outcome probabilities,
+// taking the draw probability as given.
+func eloPrediction(expected, draw float64) rating.Prediction {
+	home := math.Max(0, expected-draw/2)
+	away := math.Max(0, 1-expected-draw/2)
+	total := home + draw + away
+	return rating.Prediction{Home: home / total, Draw: draw / total, Away: away / total}
+}
+
+// competitionResults are a competition's finished matches in date order.
+type competitionResults struct {
+	name    string
+	results []rating.Result
+}
+
+// loadResults lists finished matches by competition, in order of each
+// competition's first match.
+func loadResults(ctx context.Context, queries *sqlc.Queries, competition string) []competitionResults {
+	var filter *string
+	if competition != "" {
+		filter = &competition
+	}
+	rows, err := queries.ListMatchResults(ctx, sqlc.ListMatchResultsParams{Competition: filter})
+	if err != nil {
+		log.Fatalf("Failed to list results: %v", err)
+	}
+
+	var competitions []competitionResults
+	index := map[string]int{}
+	for _, row := range rows {
+		i, ok := index[row.Competition]
+		if !ok {
+			i = len(competitions)
+			index[row.Competition] = i
+			competitions = append(competitions, competitionResults{name: row.Competition})
+		}
+		competitions[i].results = append(competitions[i].results, rating.Result{
+			MatchID:    row.ID,
+			Date:       row.MatchDate.Time,
+			HomeTeamID: row.HomeTeamID,
+			AwayTeamID: row.AwayTeamID,
+			HomeGoals:  row.HomeTeamScore,
+			AwayGoals:  row.AwayTeamScore,
+			HomeXG:     row.HomeXg,
+			AwayXG:     row.AwayXg,
+		})
+	}
+	return competitions
+}
+
+// connect loads the configuration and opens a database pool.
+func connect(ctx context.Context) *pgxpool.Pool {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return pool
+}
@@ -0,0 +1,68 @@
+// Command stream-restore loads an archived match event stream back into Redis
+// so it can be replayed over SSE or WebSocket.
+//
+// Usage:
+//
+//	stream-restore -match 42 [-stream match:42:stream] [-ttl 24h] [-force]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/archive"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/redis"
+)
+
+func main() {
+	matchID := flag.Int("match", 0, "ID of the match to restore")
+	stream := flag.String("stream", "", "target stream key (defaults to match:{id}:stream)")
+	ttl := flag.Duration("ttl", 24*time.Hour, "expiry for the restored stream (0 keeps it until archived again)")
+	force := flag.Bool("force", false, "replace the target stream if it already exists")
+	flag.Parse()
+
+	if *matchID <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	store, err := archive.NewStore(cfg.Streams, cfg.AWS)
+	if err != nil {
+		log.Fatalf("Failed to create archive store: %v", err)
+	}
+
+	client, err := redis.NewRedisClient(cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer client.Close()
+
+	target := *stream
+	if target == "" {
+		target = events.StreamKey(int32(*matchID))
+	}
+
+	ctx := context.Background()
+	count, err := archive.RestoreMatch(ctx, client, store, cfg.Streams.ArchivePrefix, int32(*matchID), target, *force, *ttl)
+	switch {
+	case errors.Is(err, archive.ErrNotFound):
+		log.Fatalf("No archive found for match %d", *matchID)
+	case errors.Is(err, archive.ErrStreamExists):
+		log.Fatalf("Stream %s already exists; use -force to replace it", target)
+	case err != nil:
+		log.Fatalf("Failed to restore stream after %d entries: %v", count, err)
+	}
+
+	log.Printf("Restored %d entries for match %d into %s", count, *matchID, target)
+}
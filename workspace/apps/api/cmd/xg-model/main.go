@@ -0,0 +1,215 @@
+// Command xg-model trains the expected goals model on historical shots and
+// rescores stored shots after the model changes.
+//
+// Usage:
+//
+//	xg-model train -out xg_model.json -version 2025-01
+//	xg-model recompute [-model xg_model.json] [-dry-run]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of shots read per query.
+const batchSize = 1000
+
+// shotEventTypes are the stored event types that can carry xG.
+var shotEventTypes = []string{
+	"shot", "shot_on_target", "shot_off_target", "shot_blocked", "shot_saved", "shot_post", "shot_woodwork",
+	"goal", "penalty", "penalty_goal", "penalty_miss",
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "train":
+		train(os.Args[2:])
+	case "recompute":
+		recompute(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: xg-model train|recompute [flags]")
+	os.Exit(2)
+}
+
+// train fits a model to every stored shot and writes its coefficients.
+func train(args []string) {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	out := fs.String("out", "xg_model.json", "file to write the trained model to")
+	version := fs.String("version", "", "version recorded on shots scored by the new model (required)")
+	provider := fs.String("provider", "", "coordinate system for shots without a recorded provider (statsbomb, opta, generic)")
+	_ = fs.Parse(args)
+
+	if *version == "" {
+		log.Fatal("-version is required")
+	}
+
+	ctx := context.Background()
+	cfg, pool := connect(ctx)
+	defer pool.Close()
+
+	var samples []xg.Sample
+	err := eachShot(ctx, sqlc.New(pool), func(event sqlc.MatchEvent, meta map[string]interface{}) error {
+		p := *provider
+		if recorded, ok := meta[xg.MetaProvider].(string); ok {
+			p = recorded
+		}
+		x, y := position(event)
+		shot, ok := xg.ShotFromEvent(event.EventType, x, y, meta, pitch.FrameFor(meta, p))
+		if ok {
+			samples = append(samples, xg.Sample{Shot: shot, Goal: xg.IsGoal(event.EventType, meta)})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to read shots: %v", err)
+	}
+
+	base, err := xg.Load(cfg.XG.ModelPath)
+	if err != nil {
+		log.Fatalf("Failed to load current model: %v", err)
+	}
+
+	model, err := xg.Train(samples, *version, base)
+	if err != nil {
+		log.Fatalf("Failed to train model: %v", err)
+	}
+	if err := model.Save(*out); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Trained xG model %s on %d shots, written to %s", model.Version, len(samples), *out)
+	log.Printf("Set XG_MODEL_PATH=%s and run 'xg-model recompute' to rescore stored shots", *out)
+}
+
+// recompute rescores shots without provider xG that were scored by another model version.
+func recompute(args []string) {
+	fs := flag.NewFlagSet("recompute", flag.ExitOnError)
+	modelPath := fs.String("model", "", "model file (defaults to XG_MODEL_PATH, then the built-in model)")
+	dryRun := fs.Bool("dry-run", false, "count the shots that would change without writing")
+	_ = fs.Parse(args)
+
+	ctx := context.Background()
+	cfg, pool := connect(ctx)
+	defer pool.Close()
+
+	path := *modelPath
+	if path == "" {
+		path = cfg.XG.ModelPath
+	}
+	model, err := xg.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	queries := sqlc.New(pool)
+	updated := 0
+	err = eachShot(ctx, queries, func(event sqlc.MatchEvent, _ map[string]interface{}) error {
+		x, y := position(event)
+		filled, ok := model.Fill(event.EventType, x, y, string(event.Metadata), "")
+		if !ok {
+			return nil
+		}
+		updated++
+		if *dryRun {
+			return nil
+		}
+		return queries.UpdateMatchEventMetadata(ctx, sqlc.UpdateMatchEventMetadataParams{
+			ID:       event.ID,
+			Metadata: []byte(filled),
+		})
+	})
+	if err != nil {
+		log.Fatalf("Failed to recompute xG after %d shots: %v", updated, err)
+	}
+
+	if *dryRun {
+		log.Printf("%d shots would be rescored with xG model %s", updated, model.Version)
+		return
+	}
+	log.Printf("Rescored %d shots with xG model %s", updated, model.Version)
+}
+
+// eachShot calls fn for every stored shot event in ID order.
+func eachShot(ctx context.Context, queries *sqlc.Queries, fn func(sqlc.MatchEvent, map[string]interface{}) error) error {
+	var afterID int32
+	for {
+		shots, err := queries.ListEventsByTypes(ctx, sqlc.ListEventsByTypesParams{
+			AfterID:    afterID,
+			EventTypes: shotEventTypes,
+			Limit:      batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range shots {
+			meta := map[string]interface{}{}
+			if len(event.Metadata) > 0 {
+				_ = json.Unmarshal(event.Metadata, &meta)
+			}
+			if err := fn(event, meta); err != nil {
+				return err
+			}
+			afterID = event.ID
+		}
+
+		if len(shots) < batchSize {
+			return nil
+		}
+	}
+}
+
+// position returns an event's coordinates.
+func position(event sqlc.MatchEvent) (*float64, *float64) {
+	var x, y *float64
+	if v, err := event.PositionX.Float64Value(); err == nil && v.Valid {
+		x = &v.Float64
+	}
+	if v, err := event.PositionY.Float64Value(); err == nil && v.Valid {
+		y = &v.Float64
+	}
+	return x, y
+}
+
+// connect loads the configuration and opens a database pool.
+func connect(ctx context.Context) (*config.Config, *pgxpool.Pool) {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return cfg, pool
+}
@@ -0,0 +1,218 @@
+// Command xt-grid builds the expected threat (xT) grid from historical passes,
+// carries and shots, and revalues stored passes and carries after it changes.
+//
+// Usage:
+//
+//	xt-grid build -out xt_grid.json -version 2025-01 [-columns 16 -rows 12]
+//	xt-grid recompute [-grid xt_grid.json] [-dry-run]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+	"github.com/emiliospot/footie/api/internal/infrastructure/database"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// batchSize is the number of events read per query.
+const batchSize = 1000
+
+// moveEventTypes are the stored pass and carry event types.
+var moveEventTypes = []string{
+	"pass", "pass_completed", "pass_incomplete", "key_pass", "assist", "through_ball", "cross", "long_ball", "short_pass", "carry",
+}
+
+// shotEventTypes are the stored event types that end a possession with a shot.
+var shotEventTypes = []string{
+	"shot", "shot_on_target", "shot_off_target", "shot_blocked", "shot_saved", "shot_post", "shot_woodwork", "goal",
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "build":
+		build(os.Args[2:])
+	case "recompute":
+		recompute(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: xt-grid build|recompute [flags]")
+	os.Exit(2)
+}
+
+// build computes a grid from every stored pass, carry and non-penalty shot and writes it.
+func build(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	out := fs.String("out", "xt_grid.json", "file to write the grid to")
+	version := fs.String("version", "", "version recorded on events valued with the new grid (required)")
+	columns := fs.Int("columns", xt.DefaultColumns, "zones along the pitch")
+	rows := fs.Int("rows", xt.DefaultRows, "zones across the pitch")
+	provider := fs.String("provider", "", "coordinate system for events without a recorded provider (statsbomb, opta, generic)")
+	_ = fs.Parse(args)
+
+	if *version == "" {
+		log.Fatal("-version is required")
+	}
+
+	ctx := context.Background()
+	_, pool := connect(ctx)
+	defer pool.Close()
+
+	var actions []xt.Action
+	eventTypes := append(append([]string{}, moveEventTypes...), shotEventTypes...)
+	err := eachEvent(ctx, sqlc.New(pool), eventTypes, func(event sqlc.MatchEvent, meta map[string]interface{}) error {
+		p := *provider
+		if recorded, ok := meta[xg.MetaProvider].(string); ok {
+			p = recorded
+		}
+		x, y := position(event)
+		if action, ok := xt.ActionFromEvent(event.EventType, x, y, meta, pitch.FrameFor(meta, p)); ok {
+			actions = append(actions, action)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to read events: %v", err)
+	}
+
+	grid, err := xt.Build(actions, *columns, *rows, *version)
+	if err != nil {
+		log.Fatalf("Failed to build grid: %v", err)
+	}
+	if err := grid.Save(*out); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Built xT grid %s (%dx%d) from %d actions, written to %s", grid.Version, grid.Columns, grid.Rows, len(actions), *out)
+	log.Printf("Set XT_GRID_PATH=%s and run 'xt-grid recompute' to revalue stored passes and carries", *out)
+}
+
+// recompute revalues stored passes and carries with the current grid and
+// progression rules.
+func recompute(args []string) {
+	fs := flag.NewFlagSet("recompute", flag.ExitOnError)
+	gridPath := fs.String("grid", "", "grid file (defaults to XT_GRID_PATH; none computes progression flags only)")
+	dryRun := fs.Bool("dry-run", false, "count the events that would change without writing")
+	_ = fs.Parse(args)
+
+	ctx := context.Background()
+	cfg, pool := connect(ctx)
+	defer pool.Close()
+
+	path := *gridPath
+	if path == "" {
+		path = cfg.XT.GridPath
+	}
+	grid, err := xt.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	queries := sqlc.New(pool)
+	updated := 0
+	err = eachEvent(ctx, queries, moveEventTypes, func(event sqlc.MatchEvent, _ map[string]interface{}) error {
+		x, y := position(event)
+		annotated, ok := xt.Annotate(grid, event.EventType, x, y, string(event.Metadata), "")
+		if !ok {
+			return nil
+		}
+		updated++
+		if *dryRun {
+			return nil
+		}
+		return queries.UpdateMatchEventMetadata(ctx, sqlc.UpdateMatchEventMetadataParams{
+			ID:       event.ID,
+			Metadata: []byte(annotated),
+		})
+	})
+	if err != nil {
+		log.Fatalf("Failed to recompute xT after %d events: %v", updated, err)
+	}
+
+	if *dryRun {
+		log.Printf("%d passes and carries would be revalued", updated)
+		return
+	}
+	log.Printf("Revalued %d passes and carries", updated)
+}
+
+// eachEvent calls fn for every stored event of the given types in ID order.
+func eachEvent(ctx context.Context, queries *sqlc.Queries, eventTypes []string, fn func(sqlc.MatchEvent, map[string]interface{}) error) error {
+	var afterID int32
+	for {
+		events, err := queries.ListEventsByTypes(ctx, sqlc.ListEventsByTypesParams{
+			AfterID:    afterID,
+			EventTypes: eventTypes,
+			Limit:      batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			meta := map[string]interface{}{}
+			if len(event.Metadata) > 0 {
+				_ = json.Unmarshal(event.Metadata, &meta)
+			}
+			if err := fn(event, meta); err != nil {
+				return err
+			}
+			afterID = event.ID
+		}
+
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
+
+// position returns an event's coordinates.
+func position(event sqlc.MatchEvent) (*float64, *float64) {
+	var x, y *float64
+	if v, err := event.PositionX.Float64Value(); err == nil && v.Valid {
+		x = &v.Float64
+	}
+	if v, err := event.PositionY.Float64Value(); err == nil && v.Valid {
+		y = &v.Float64
+	}
+	return x, y
+}
+
+// connect loads the configuration and opens a database pool.
+func connect(ctx context.Context) (*config.Config, *pgxpool.Pool) {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	pool, err := database.NewPgxPool(ctx, &database.PgxConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return cfg, pool
+}
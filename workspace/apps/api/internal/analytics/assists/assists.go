@@ -0,0 +1,222 @@
+// Package assists infers assists, second assists and key passes from a match's
+// event sequence, for providers that do not send assist and key_pass events,
+// and the shot-creating and goal-creating actions before each shot.
+//
+// A pass leads directly to a shot when the shooter is the next of the team's
+// players on the ball: only the receiver's own carries and actions and the
+// opponent's failed challenges may come in between. Corners count as passes.
+// The key pass before a goal is the assist, and the completed pass to the
+// assister by another teammate before it is the second assist. Penalties are
+// not assisted.
+package assists
+
+import (
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// Derived event types, stored on the pass.
+const (
+	TypeAssist       = "assist"
+	TypeSecondAssist = "second_assist"
+	TypeKeyPass      = "key_pass"
+)
+
+// Derived is a pass inferred as an assist, second assist or key pass.
+type Derived struct {
+	EventID     int32    `json:"event_id"` // The pass
+	Type        string   `json:"type"`
+	ShotEventID int32    `json:"shot_event_id"` // The shot or goal it led to
+	XA          *float64 `json:"xa,omitempty"`  // Assists and key passes: the shot's xG, when known
+}
+
+// Infer returns the derived passes of a match's events, in match clock order.
+// A goal the provider sent its own assist event for gets no inferred assist, so
+// each goal is assisted once.
+func Infer(matchEvents []matchevent.Event) []Derived {
+	var c chain
+	derived := []Derived{}
+	assisted := providerAssists(matchEvents)
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		t := events.Normalize(e.EventType)
+		if e.Clock.Period == events.PeriodPenalties || stoppage(t) {
+			c.reset()
+			continue
+		}
+		if len(c.passes) > 0 && e.Clock.PeriodNumber() != c.passes[0].Clock.PeriodNumber() {
+			c.reset()
+		}
+		if e.TeamID == nil {
+			continue
+		}
+
+		switch {
+		case xg.IsShotEvent(e.EventType):
+			if !penalty(t) && c.continuedBy(e) {
+				derived = append(derived, c.credit(e, assisted[e.ID])...)
+			}
+			c.reset()
+		case t.IsPass() || t == events.EventTypeCorner:
+			// Corners have no completion outcome; a lost one resets the chain with the opponent's next action
+			if t != events.EventTypeCorner && !xt.Completed(e.EventType, e.Meta) {
+				c.reset()
+				continue
+			}
+			if !c.continuedBy(e) {
+				c.reset()
+				c.teamID = *e.TeamID
+			}
+			c.passes = append(c.passes, e)
+			c.holder = e.SecondaryPlayerID
+		case *e.TeamID != c.teamID:
+			if !challenges(t) {
+				c.reset()
+			}
+		default:
+			if !c.continuedBy(e) {
+				c.reset()
+				continue
+			}
+			c.holder = e.PlayerID
+		}
+	}
+	return derived
+}
+
+// chain is a team's run of completed passes, each to the player of the next,
+// up to the player on the ball.
+type chain struct {
+	teamID int32
+	passes []*matchevent.Event
+	holder *int32 // The player on the ball, nil when the last pass's recipient is unknown
+}
+
+func (c *chain) reset() {
+	c.passes = c.passes[:0]
+	c.holder = nil
+}
+
+// continuedBy reports whether the event's player received the chain's last pass
+// or has carried the ball since.
+func (c *chain) continuedBy(e *matchevent.Event) bool {
+	if len(c.passes) == 0 || *e.TeamID != c.teamID || e.PlayerID == nil {
+		return false
+	}
+	if c.holder != nil {
+		return *c.holder == *e.PlayerID
+	}
+	last := c.passes[len(c.passes)-1]
+	return last.PlayerID == nil || *last.PlayerID != *e.PlayerID
+}
+
+// credit derives the passes leading to a shot the chain continued into. assist is
+// the provider's assist event for the shot, if any.
+func (c *chain) credit(shot, assist *matchevent.Event) []Derived {
+	var xa *float64
+	if v, ok := events.MetaFloat(shot.Meta, xg.MetaXG, "xg"); ok {
+		xa = &v
+	}
+	last := c.passes[len(c.passes)-1]
+	keyPass := Derived{EventID: last.ID, Type: TypeKeyPass, ShotEventID: shot.ID, XA: xa}
+	if !xg.IsGoal(shot.EventType, shot.Meta) {
+		return []Derived{keyPass}
+	}
+	// The provider credits another player with the assist
+	if shot.SecondaryPlayerID != nil && !samePlayer(last.PlayerID, shot.SecondaryPlayerID) {
+		return []Derived{keyPass}
+	}
+
+	keyPass.Type = TypeAssist
+	derived := []Derived{keyPass}
+	// The provider's assist event already counts as the assist and key pass,
+	// unless it is the chain's last pass itself
+	if assist != nil && assist != last {
+		if !samePlayer(last.PlayerID, assist.PlayerID) {
+			keyPass.Type = TypeKeyPass
+			return []Derived{keyPass}
+		}
+		derived = derived[:0]
+	}
+	if len(c.passes) > 1 {
+		prev := c.passes[len(c.passes)-2]
+		if prev.PlayerID != nil && !samePlayer(prev.PlayerID, last.PlayerID) && !samePlayer(prev.PlayerID, shot.PlayerID) {
+			derived = append(derived, Derived{EventID: prev.ID, Type: TypeSecondAssist, ShotEventID: shot.ID})
+		}
+	}
+	return derived
+}
+
+// providerAssists returns the provider's assist event for each goal that has
+// one, by goal event ID: an assist event of the scoring team in the move before
+// the goal, back to the previous shot or stoppage, or sent right after it.
+func providerAssists(matchEvents []matchevent.Event) map[int32]*matchevent.Event {
+	assisted := map[int32]*matchevent.Event{}
+	isAssist := func(e, goal *matchevent.Event) bool {
+		return events.Normalize(e.EventType) == events.EventTypeAssist &&
+			e.TeamID != nil && *e.TeamID == *goal.TeamID
+	}
+	for g := range matchEvents {
+		goal := &matchEvents[g]
+		if goal.TeamID == nil || !xg.IsShotEvent(goal.EventType) || !xg.IsGoal(goal.EventType, goal.Meta) {
+			continue
+		}
+		for i := g - 1; i >= 0 && assisted[goal.ID] == nil; i-- {
+			e := &matchEvents[i]
+			if e.Clock.Period != goal.Clock.Period || stoppage(events.Normalize(e.EventType)) || xg.IsShotEvent(e.EventType) {
+				break
+			}
+			if isAssist(e, goal) {
+				assisted[goal.ID] = e
+			}
+		}
+		for i := g + 1; i < len(matchEvents) && assisted[goal.ID] == nil; i++ {
+			e := &matchEvents[i]
+			if e.Clock.Period != goal.Clock.Period ||
+				(events.Normalize(e.EventType) != events.EventTypeAssist && e.Clock.Ms != goal.Clock.Ms) {
+				break
+			}
+			if isAssist(e, goal) {
+				assisted[goal.ID] = e
+			}
+		}
+	}
+	return assisted
+}
+
+// samePlayer reports whether two known players are the same.
+func samePlayer(a, b *int32) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// penalty reports whether a shot is a penalty kick.
+func penalty(t events.EventType) bool {
+	switch t {
+	case events.EventTypePenalty, events.EventTypePenaltyGoal, events.EventTypePenaltyMiss:
+		return true
+	}
+	return false
+}
+
+// stoppage reports whether an event type stops play or marks a period.
+func stoppage(t events.EventType) bool {
+	switch events.GetCategory(t) {
+	case events.CategoryFoul, events.CategoryCard, events.CategorySubstitution, events.CategoryVar, events.CategoryMatchState:
+		return true
+	}
+	return false
+}
+
+// challenges reports whether an opponent's event contests the ball without
+// winning it.
+func challenges(t events.EventType) bool {
+	switch t {
+	case events.EventTypeDuelWon, events.EventTypeAerialDuelWon:
+		return false
+	case events.EventTypeTackleLost:
+		return true
+	}
+	return events.GetCategory(t) == events.CategoryDuel
+}
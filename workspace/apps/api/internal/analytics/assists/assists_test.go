@@ -0,0 +1,203 @@
+package assists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent/matcheventtest"
+)
+
+var (
+	ptr   = matcheventtest.Ptr[int32]
+	event = matcheventtest.New
+)
+
+// pass builds a completed pass to a known recipient.
+func pass(id, teamID, from, to, second int32) matchevent.Event {
+	e := event(id, "pass", teamID, from, second)
+	e.SecondaryPlayerID = ptr(to)
+	return e
+}
+
+func withXG(e matchevent.Event, xg float64) matchevent.Event {
+	e.Meta = map[string]interface{}{"xG": xg}
+	return e
+}
+
+func TestInferGoal(t *testing.T) {
+	derived := Infer([]matchevent.Event{
+		pass(1, 1, 7, 8, 1),
+		pass(2, 1, 8, 9, 3),
+		event(3, "carry", 1, 9, 4),
+		event(4, "duel_lost", 2, 20, 5), // A failed challenge keeps the chain
+		withXG(event(5, "goal", 1, 9, 6), 0.3),
+	})
+	xa := 0.3
+	assert.Equal(t, []Derived{
+		{EventID: 2, Type: TypeAssist, ShotEventID: 5, XA: &xa},
+		{EventID: 1, Type: TypeSecondAssist, ShotEventID: 5},
+	}, derived)
+}
+
+func TestInferKeyPass(t *testing.T) {
+	// Without a recipient the next teammate on the ball received the pass
+	unknown := event(1, "cross", 1, 7, 1)
+	derived := Infer([]matchevent.Event{
+		unknown,
+		withXG(event(2, "shot_saved", 1, 9, 2), 0.12),
+	})
+	assert.Len(t, derived, 1)
+	assert.Equal(t, TypeKeyPass, derived[0].Type)
+	assert.InDelta(t, 0.12, *derived[0].XA, 1e-9)
+
+	// Corners count as passes
+	derived = Infer([]matchevent.Event{event(1, "corner", 1, 7, 1), event(2, "shot", 1, 5, 2)})
+	assert.Equal(t, []Derived{{EventID: 1, Type: TypeKeyPass, ShotEventID: 2}}, derived)
+}
+
+func TestInferBrokenChains(t *testing.T) {
+	incomplete := pass(1, 1, 7, 8, 1)
+	incomplete.EventType = "pass_incomplete"
+	recovered := []matchevent.Event{incomplete, event(2, "shot", 1, 8, 2)}
+
+	cases := map[string][]matchevent.Event{
+		"incomplete pass": recovered,
+		"interception": {
+			pass(1, 1, 7, 8, 1), event(2, "interception", 2, 20, 2), event(3, "shot", 1, 8, 3),
+		},
+		"another receiver": {
+			pass(1, 1, 7, 8, 1), event(2, "shot", 1, 9, 2),
+		},
+		"foul": {
+			pass(1, 1, 7, 8, 1), event(2, "foul_committed", 2, 20, 2), event(3, "shot", 1, 8, 3),
+		},
+		"penalty": {
+			pass(1, 1, 7, 8, 1), event(2, "penalty_goal", 1, 8, 2),
+		},
+		"own goal": {
+			pass(1, 1, 7, 8, 1), event(2, "own_goal", 2, 20, 2),
+		},
+	}
+	for name, matchEvents := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Empty(t, Infer(matchEvents))
+		})
+	}
+}
+
+func TestInferProviderAssister(t *testing.T) {
+	goal := event(3, "goal", 1, 9, 3)
+	goal.SecondaryPlayerID = ptr(6)
+	derived := Infer([]matchevent.Event{pass(1, 1, 7, 8, 1), pass(2, 1, 8, 9, 2), goal})
+	// Someone else is credited, so the pass is only a key pass
+	assert.Equal(t, []Derived{{EventID: 2, Type: TypeKeyPass, ShotEventID: 3}}, derived)
+
+	goal.SecondaryPlayerID = ptr(8)
+	derived = Infer([]matchevent.Event{pass(1, 1, 7, 8, 1), pass(2, 1, 8, 9, 2), goal})
+	assert.Len(t, derived, 2)
+	assert.Equal(t, TypeAssist, derived[0].Type)
+}
+
+func TestInferProviderAssist(t *testing.T) {
+	chain := func(assist matchevent.Event) []matchevent.Event {
+		return []matchevent.Event{
+			pass(1, 1, 7, 8, 1),
+			pass(2, 1, 8, 9, 3),
+			event(3, "goal", 1, 9, 6),
+			assist,
+			event(5, "kick_off", 2, 20, 40),
+		}
+	}
+
+	// The provider's assist event already credits the passer
+	derived := Infer(chain(event(4, "assist", 1, 8, 6)))
+	assert.Equal(t, []Derived{{EventID: 1, Type: TypeSecondAssist, ShotEventID: 3}}, derived)
+
+	// The provider credits another player, so the pass is only a key pass
+	derived = Infer(chain(event(4, "assist", 1, 10, 6)))
+	assert.Equal(t, []Derived{{EventID: 2, Type: TypeKeyPass, ShotEventID: 3}}, derived)
+
+	// An assist event in the chain is the assist itself
+	assist := pass(2, 1, 8, 9, 3)
+	assist.EventType = "assist"
+	derived = Infer([]matchevent.Event{pass(1, 1, 7, 8, 1), assist, event(3, "goal", 1, 9, 6)})
+	assert.Equal(t, []Derived{
+		{EventID: 2, Type: TypeAssist, ShotEventID: 3},
+		{EventID: 1, Type: TypeSecondAssist, ShotEventID: 3},
+	}, derived)
+}
+
+func TestInferSecondAssistByScorer(t *testing.T) {
+	// A one-two: the scorer's own pass is not a second assist
+	derived := Infer([]matchevent.Event{pass(1, 1, 9, 8, 1), pass(2, 1, 8, 9, 2), event(3, "goal", 1, 9, 3)})
+	assert.Equal(t, []Derived{{EventID: 2, Type: TypeAssist, ShotEventID: 3}}, derived)
+}
+
+func TestCreating(t *testing.T) {
+	foulWon := event(4, "foul_won", 1, 8, 4)
+	creations := Creating([]matchevent.Event{
+		pass(1, 1, 7, 8, 1),
+		event(2, "duel_won", 1, 8, 2), // A dribble past the opponent's duel_lost
+		event(3, "duel_lost", 2, 20, 2),
+		foulWon,
+		event(5, "foul_committed", 2, 21, 4),
+		event(6, "yellow_card", 2, 21, 5),
+		pass(7, 1, 8, 9, 30), // The free kick
+		event(8, "goal", 1, 9, 31),
+	})
+	assert.Equal(t, []Creation{
+		{EventID: 4, Type: TypeGoalCreating, ShotEventID: 8},
+		{EventID: 7, Type: TypeGoalCreating, ShotEventID: 8},
+	}, creations)
+
+	// A foul won leading to a penalty
+	creations = Creating([]matchevent.Event{event(1, "foul_won", 1, 9, 1), event(2, "penalty_miss", 1, 9, 40)})
+	assert.Equal(t, []Creation{{EventID: 1, Type: TypeShotCreating, ShotEventID: 2}}, creations)
+
+	// The opponent's duel_won starts its own move
+	creations = Creating([]matchevent.Event{
+		pass(1, 1, 7, 8, 1), event(2, "duel_won", 2, 20, 2), pass(3, 2, 20, 21, 3), event(4, "shot", 2, 21, 4),
+	})
+	assert.Equal(t, []Creation{{EventID: 3, Type: TypeShotCreating, ShotEventID: 4}}, creations)
+}
+
+func TestCreatingBrokenMoves(t *testing.T) {
+	cases := map[string][]matchevent.Event{
+		"offside": {pass(1, 1, 7, 8, 1), event(2, "offside", 1, 8, 2), event(3, "shot", 1, 8, 3)},
+		"clearance": {
+			pass(1, 1, 7, 8, 1), event(2, "clearance", 2, 20, 2), event(3, "shot", 1, 8, 3),
+		},
+		"rebound": {
+			pass(1, 1, 7, 8, 1), event(2, "shot_blocked", 1, 8, 2), event(3, "shot", 1, 9, 3),
+		},
+		"own goal": {pass(1, 1, 7, 8, 1), event(2, "own_goal", 2, 20, 2)},
+	}
+	for name, matchEvents := range cases {
+		t.Run(name, func(t *testing.T) {
+			creations := Creating(matchEvents)
+			for _, c := range creations {
+				assert.Equal(t, int32(2), c.ShotEventID, "only the first shot is created")
+			}
+			if name != "rebound" {
+				assert.Empty(t, creations)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	// Only the last two of the move's actions create the shot
+	annotations := Annotate([]matchevent.Event{
+		pass(1, 1, 6, 7, 1),
+		event(2, "duel_won", 1, 7, 2),
+		pass(3, 1, 7, 8, 3),
+		withXG(event(4, "shot_saved", 1, 8, 4), 0.2),
+	})
+	xa := 0.2
+	assert.Equal(t, []Annotation{
+		{EventID: 2, ShotEventID: 4, CreationType: TypeShotCreating},
+		{EventID: 3, DerivedType: TypeKeyPass, XA: &xa, ShotEventID: 4, CreationType: TypeShotCreating},
+	}, annotations)
+}
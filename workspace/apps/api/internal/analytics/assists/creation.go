@@ -0,0 +1,150 @@
+package assists
+
+import (
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// Creating action types, stored on the action.
+const (
+	TypeShotCreating = "shot_creating"
+	TypeGoalCreating = "goal_creating" // The shot was scored; goal-creating actions are also shot-creating
+)
+
+// creatingActions is the number of offensive actions credited with each shot.
+const creatingActions = 2
+
+// Creation is an offensive action credited with creating a shot.
+type Creation struct {
+	EventID     int32  `json:"event_id"` // The pass, dribble or foul won
+	Type        string `json:"type"`
+	ShotEventID int32  `json:"shot_event_id"`
+}
+
+// Creating returns the shot-creating and goal-creating actions of a match's
+// events, in match clock order: the last two offensive actions of the shooting
+// team's move before each shot, from completed passes (corners included),
+// dribbles (duels won by the team on the ball) and fouls won. A move ends when
+// the opponent wins the ball, at an incomplete pass, an offside or a foul by the
+// team, at a shot and at the end of a period; a foul won carries it on into the
+// set piece. Own goals are not created.
+func Creating(matchEvents []matchevent.Event) []Creation {
+	var m move
+	creations := []Creation{}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		t := events.Normalize(e.EventType)
+		if e.Clock.Period == events.PeriodPenalties || events.GetCategory(t) == events.CategoryMatchState ||
+			e.Clock.PeriodNumber() != m.period {
+			m.reset(0)
+			m.period = e.Clock.PeriodNumber()
+		}
+		if e.TeamID == nil {
+			continue
+		}
+
+		own := *e.TeamID == m.teamID
+		switch {
+		case xg.IsShotEvent(e.EventType):
+			if own {
+				creations = append(creations, m.credit(e)...)
+			}
+			m.reset(*e.TeamID)
+		case t.IsPass() || t == events.EventTypeCorner:
+			if !own {
+				m.reset(*e.TeamID)
+			}
+			if t != events.EventTypeCorner && !xt.Completed(e.EventType, e.Meta) {
+				m.reset(*e.TeamID)
+				continue
+			}
+			m.actions = append(m.actions, e)
+		case t == events.EventTypeFoulWon, t == events.EventTypeDuelWon && own:
+			if !own {
+				m.reset(*e.TeamID)
+			}
+			m.actions = append(m.actions, e)
+		case own:
+			// The team gives the ball away
+			if t == events.EventTypeOffside || t == events.EventTypeFoul || t == events.EventTypeFoulCommitted {
+				m.reset(0)
+			}
+		default:
+			// The opponent wins the ball unless it fouls, challenges and loses or
+			// the event stops play
+			switch events.GetCategory(t) {
+			case events.CategoryFoul, events.CategoryCard, events.CategorySubstitution, events.CategoryVar:
+			default:
+				if !challenges(t) {
+					m.reset(*e.TeamID)
+				}
+			}
+		}
+	}
+	return creations
+}
+
+// move is a team's run of offensive actions since it won the ball.
+type move struct {
+	period  int16
+	teamID  int32
+	actions []*matchevent.Event
+}
+
+func (m *move) reset(teamID int32) {
+	m.teamID = teamID
+	m.actions = m.actions[:0]
+}
+
+// credit returns the move's last offensive actions as the shot's creating actions.
+func (m *move) credit(shot *matchevent.Event) []Creation {
+	creationType := TypeShotCreating
+	if xg.IsGoal(shot.EventType, shot.Meta) {
+		creationType = TypeGoalCreating
+	}
+	actions := m.actions
+	if len(actions) > creatingActions {
+		actions = actions[len(actions)-creatingActions:]
+	}
+	creations := make([]Creation, 0, len(actions))
+	for _, a := range actions {
+		creations = append(creations, Creation{EventID: a.ID, Type: creationType, ShotEventID: shot.ID})
+	}
+	return creations
+}
+
+// Annotation is the derived data stored on a match event.
+type Annotation struct {
+	EventID      int32
+	DerivedType  string   // assist, second_assist or key_pass; empty for other events
+	XA           *float64 // Assists and key passes: the shot's xG, when known
+	ShotEventID  int32    // The shot or goal the event led to
+	CreationType string   // shot_creating or goal_creating; empty for other events
+}
+
+// Annotate infers a match's derived passes and creating actions, with one
+// annotation per event in match clock order.
+func Annotate(matchEvents []matchevent.Event) []Annotation {
+	byID := map[int32]*Annotation{}
+	for _, d := range Infer(matchEvents) {
+		byID[d.EventID] = &Annotation{EventID: d.EventID, DerivedType: d.Type, XA: d.XA, ShotEventID: d.ShotEventID}
+	}
+	for _, c := range Creating(matchEvents) {
+		a, ok := byID[c.EventID]
+		if !ok {
+			a = &Annotation{EventID: c.EventID, ShotEventID: c.ShotEventID}
+			byID[c.EventID] = a
+		}
+		a.CreationType = c.Type
+	}
+
+	annotations := make([]Annotation, 0, len(byID))
+	for i := range matchEvents {
+		if a, ok := byID[matchEvents[i].ID]; ok {
+			annotations = append(annotations, *a)
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,225 @@
+// Package comparison compares players' season statistics per 90 minutes and
+// ranks them as percentiles within a pool of players in the same position group.
+package comparison
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+// Group is a position group with its own set of metrics.
+type Group string
+
+// Position groups.
+const (
+	Forward    Group = "forward"
+	Midfielder Group = "midfielder"
+	Defender   Group = "defender"
+	Goalkeeper Group = "goalkeeper"
+)
+
+// groupKeywords map words in a player's position to a group. Abbreviations are
+// matched as whole words; the others as prefixes, so "Centre-Back" and
+// "Defensive Midfielder" are matched by their last word first.
+var groupKeywords = []struct {
+	group Group
+	words []string
+}{
+	{Goalkeeper, []string{"goalkeeper", "keeper", "gk"}},
+	{Midfielder, []string{"midfield", "cm", "cdm", "cam", "dm", "am", "lm", "rm"}},
+	{Defender, []string{"defender", "back", "cb", "lb", "rb", "lwb", "rwb", "sw"}},
+	{Forward, []string{"forward", "striker", "attacker", "winger", "wing", "st", "cf", "lw", "rw", "ss"}},
+}
+
+// ParseGroup returns the group named s.
+func ParseGroup(s string) (Group, bool) {
+	switch g := Group(strings.ToLower(strings.TrimSpace(s))); g {
+	case Forward, Midfielder, Defender, Goalkeeper:
+		return g, true
+	}
+	return "", false
+}
+
+// GroupFor returns the position group of a player's position, such as
+// "Goalkeeper", "Centre-Back", "CDM" or "Striker".
+func GroupFor(position string) (Group, bool) {
+	words := strings.FieldsFunc(strings.ToLower(position), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/' || r == '_'
+	})
+	// The last word decides: a defensive midfielder is a midfielder
+	for i := len(words) - 1; i >= 0; i-- {
+		for _, k := range groupKeywords {
+			for _, word := range k.words {
+				if words[i] == word || (len(word) > 3 && strings.HasPrefix(words[i], word)) {
+					return k.group, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// Metric describes a compared statistic.
+type Metric struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Unit  string `json:"unit"` // "/90'" or "%"
+	// LowerIsBetter inverts the percentile, e.g. for goals conceded
+	LowerIsBetter bool `json:"lower_is_better,omitempty"`
+}
+
+const (
+	unitPer90   = "/90'"
+	unitPercent = "%"
+)
+
+// metrics are the metric sets of each group, in radar order.
+var metrics = map[Group][]Metric{
+	Forward: {
+		{Key: "goals", Label: "Goals", Unit: unitPer90},
+		{Key: "shots", Label: "Shots", Unit: unitPer90},
+		{Key: "shots_on_target", Label: "Shots on Target", Unit: unitPer90},
+		{Key: "goal_conversion", Label: "Goal Conversion", Unit: unitPercent},
+		{Key: "assists", Label: "Assists", Unit: unitPer90},
+		{Key: "key_passes", Label: "Key Passes", Unit: unitPer90},
+		{Key: "fouls_drawn", Label: "Fouls Drawn", Unit: unitPer90},
+		{Key: "aerial_duels_won", Label: "Aerial Duels Won", Unit: unitPer90},
+	},
+	Midfielder: {
+		{Key: "passes_completed", Label: "Passes Completed", Unit: unitPer90},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Unit: unitPercent},
+		{Key: "key_passes", Label: "Key Passes", Unit: unitPer90},
+		{Key: "assists", Label: "Assists", Unit: unitPer90},
+		{Key: "goals", Label: "Goals", Unit: unitPer90},
+		{Key: "tackles_won", Label: "Tackles Won", Unit: unitPer90},
+		{Key: "interceptions", Label: "Interceptions", Unit: unitPer90},
+		{Key: "duels_won", Label: "Duels Won", Unit: unitPer90},
+	},
+	Defender: {
+		{Key: "tackles_won", Label: "Tackles Won", Unit: unitPer90},
+		{Key: "interceptions", Label: "Interceptions", Unit: unitPer90},
+		{Key: "clearances", Label: "Clearances", Unit: unitPer90},
+		{Key: "blocked_shots", Label: "Blocked Shots", Unit: unitPer90},
+		{Key: "aerial_duels_won", Label: "Aerial Duels Won", Unit: unitPer90},
+		{Key: "duel_success", Label: "Duel Success", Unit: unitPercent},
+		{Key: "passes_completed", Label: "Passes Completed", Unit: unitPer90},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Unit: unitPercent},
+	},
+	Goalkeeper: {
+		{Key: "saves", Label: "Saves", Unit: unitPer90},
+		{Key: "save_percentage", Label: "Save Percentage", Unit: unitPercent},
+		{Key: "goals_conceded", Label: "Goals Conceded", Unit: unitPer90, LowerIsBetter: true},
+		{Key: "clean_sheets", Label: "Clean Sheets", Unit: unitPercent},
+		{Key: "penalties_saved", Label: "Penalties Saved", Unit: unitPer90},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Unit: unitPercent},
+	},
+}
+
+// MetricsFor returns a group's metrics.
+func MetricsFor(g Group) []Metric {
+	return metrics[g]
+}
+
+// Values returns every metric of a player's season statistics: counts per 90
+// minutes and rates as percentages of attempts (clean sheets as a percentage of
+// matches played). Rates without attempts are 0.
+func Values(s *models.PlayerStatistics) map[string]float64 {
+	p90 := func(v int32) float64 {
+		if s.MinutesPlayed <= 0 {
+			return 0
+		}
+		return float64(v) * 90 / float64(s.MinutesPlayed)
+	}
+	pct := func(part, whole int32) float64 {
+		if whole <= 0 {
+			return 0
+		}
+		return float64(part) * 100 / float64(whole)
+	}
+	value := func(v *int32) int32 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+
+	saves, conceded := value(s.SavesTotal), value(s.GoalsConceded)
+	savePercentage := pct(saves, saves+conceded)
+	if s.SavePercentage != nil {
+		savePercentage = *s.SavePercentage
+	}
+
+	return map[string]float64{
+		"goals":            p90(s.Goals),
+		"shots":            p90(s.ShotsTotal),
+		"shots_on_target":  p90(s.ShotsOnTarget),
+		"goal_conversion":  pct(s.Goals, s.ShotsTotal),
+		"assists":          p90(s.Assists),
+		"key_passes":       p90(s.KeyPasses),
+		"fouls_drawn":      p90(s.FoulsDrawn),
+		"aerial_duels_won": p90(s.AerialDuelsWon),
+		"passes_completed": p90(s.PassesCompleted),
+		"pass_accuracy":    pct(s.PassesCompleted, s.PassesTotal),
+		"tackles_won":      p90(s.TacklesWon),
+		"interceptions":    p90(s.Interceptions),
+		"duels_won":        p90(s.DuelsWon),
+		"clearances":       p90(s.Clearances),
+		"blocked_shots":    p90(s.BlockedShots),
+		"duel_success":     pct(s.DuelsWon, s.Duels),
+		"saves":            p90(saves),
+		"save_percentage":  savePercentage,
+		"goals_conceded":   p90(conceded),
+		"clean_sheets":     pct(value(s.CleanSheets), s.MatchesPlayed),
+		"penalties_saved":  p90(value(s.PenaltiesSaved)),
+	}
+}
+
+// Percentile returns the percentage of the pool a value is better than, with
+// ties counting half.
+func Percentile(v float64, pool []float64, lowerIsBetter bool) float64 {
+	if len(pool) == 0 {
+		return 0
+	}
+	var better, equal int
+	for _, p := range pool {
+		switch {
+		case p == v:
+			equal++
+		case (p < v) != lowerIsBetter:
+			better++
+		}
+	}
+	return round((float64(better) + float64(equal)/2) * 100 / float64(len(pool)))
+}
+
+// MetricValue is a player's value for a metric and its percentile in the pool.
+type MetricValue struct {
+	Metric
+	Value      float64 `json:"value"`
+	Percentile float64 `json:"percentile"`
+}
+
+// Rank returns a player's values for a group's metrics with their percentiles
+// among the pool's values.
+func Rank(g Group, values map[string]float64, pool []map[string]float64) []MetricValue {
+	ranked := make([]MetricValue, 0, len(metrics[g]))
+	column := make([]float64, len(pool))
+	for _, m := range metrics[g] {
+		for i, p := range pool {
+			column[i] = p[m.Key]
+		}
+		ranked = append(ranked, MetricValue{
+			Metric:     m,
+			Value:      round(values[m.Key]),
+			Percentile: Percentile(values[m.Key], column, m.LowerIsBetter),
+		})
+	}
+	return ranked
+}
+
+// round rounds to 2 decimal places.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
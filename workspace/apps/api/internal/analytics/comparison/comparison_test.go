@@ -0,0 +1,74 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+func TestGroupFor(t *testing.T) {
+	for position, want := range map[string]Group{
+		"Goalkeeper":           Goalkeeper,
+		"GK":                   Goalkeeper,
+		"Centre-Back":          Defender,
+		"Left Wing Back":       Defender,
+		"Defensive Midfielder": Midfielder,
+		"CDM":                  Midfielder,
+		"Right Winger":         Forward,
+		"Striker":              Forward,
+		"ST":                   Forward,
+	} {
+		got, ok := GroupFor(position)
+		assert.True(t, ok, position)
+		assert.Equal(t, want, got, position)
+	}
+
+	_, ok := GroupFor("Coach")
+	assert.False(t, ok)
+}
+
+func TestValues(t *testing.T) {
+	saves, conceded, cleanSheets := int32(30), int32(10), int32(3)
+	values := Values(&models.PlayerStatistics{
+		MatchesPlayed:   10,
+		MinutesPlayed:   900,
+		Goals:           5,
+		ShotsTotal:      20,
+		PassesTotal:     400,
+		PassesCompleted: 300,
+		SavesTotal:      &saves,
+		GoalsConceded:   &conceded,
+		CleanSheets:     &cleanSheets,
+	})
+
+	assert.Equal(t, 0.5, values["goals"])
+	assert.Equal(t, 25.0, values["goal_conversion"])
+	assert.Equal(t, 75.0, values["pass_accuracy"])
+	assert.Equal(t, 75.0, values["save_percentage"])
+	assert.Equal(t, 30.0, values["clean_sheets"])
+	assert.Equal(t, 0.0, values["duel_success"])
+}
+
+func TestRank(t *testing.T) {
+	assert.Equal(t, 62.5, Percentile(3, []float64{1, 2, 3, 4}, false))
+	assert.Equal(t, 37.5, Percentile(3, []float64{1, 2, 3, 4}, true))
+	assert.Equal(t, 0.0, Percentile(3, nil, false))
+
+	pool := []map[string]float64{
+		{"goals_conceded": 0.8, "saves": 2},
+		{"goals_conceded": 1.2, "saves": 3},
+		{"goals_conceded": 1.6, "saves": 4},
+	}
+	ranked := Rank(Goalkeeper, map[string]float64{"goals_conceded": 1.0, "saves": 3.5}, pool)
+	require.Len(t, ranked, len(MetricsFor(Goalkeeper)))
+
+	byKey := map[string]MetricValue{}
+	for _, m := range ranked {
+		byKey[m.Key] = m
+	}
+	assert.InDelta(t, 66.67, byKey["saves"].Percentile, 0.01)
+	assert.InDelta(t, 66.67, byKey["goals_conceded"].Percentile, 0.01)
+}
@@ -0,0 +1,256 @@
+// Package gamestate tracks the score through a match so events can be split by
+// whether a team was winning, drawing or losing at the time.
+package gamestate
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// State is a team's game state: its result if the match ended at that moment.
+type State string
+
+const (
+	Winning State = "winning"
+	Drawing State = "drawing"
+	Losing  State = "losing"
+)
+
+// Parse parses a game state, case-insensitively.
+func Parse(s string) (State, bool) {
+	switch state := State(strings.ToLower(strings.TrimSpace(s))); state {
+	case Winning, Drawing, Losing:
+		return state, true
+	}
+	return "", false
+}
+
+// ForDifferential returns the game state for a goal differential.
+func ForDifferential(diff int) State {
+	switch {
+	case diff > 0:
+		return Winning
+	case diff < 0:
+		return Losing
+	default:
+		return Drawing
+	}
+}
+
+// Tracker keeps the running score from goal events applied in match clock order.
+// The zero value is an empty score.
+type Tracker struct {
+	goals    map[int32]int // Goals scored by each team
+	ownGoals map[int32]int // Own goals conceded by each team, credited to the opponent
+}
+
+// Apply records an event if it changes the score. Own goals carry the team of the
+// player who scored them. Penalty shootout kicks do not count.
+func (t *Tracker) Apply(eventType string, teamID *int32, period events.Period) bool {
+	if !scores(eventType, teamID, period) {
+		return false
+	}
+	switch events.Normalize(eventType) {
+	case events.EventTypeGoal, events.EventTypePenaltyGoal:
+		if t.goals == nil {
+			t.goals = make(map[int32]int)
+		}
+		t.goals[*teamID]++
+	case events.EventTypeOwnGoal:
+		if t.ownGoals == nil {
+			t.ownGoals = make(map[int32]int)
+		}
+		t.ownGoals[*teamID]++
+	}
+	return true
+}
+
+// scores reports whether an event changes the score.
+func scores(eventType string, teamID *int32, period events.Period) bool {
+	if teamID == nil || period == events.PeriodPenalties {
+		return false
+	}
+	switch events.Normalize(eventType) {
+	case events.EventTypeGoal, events.EventTypePenaltyGoal, events.EventTypeOwnGoal:
+		return true
+	}
+	return false
+}
+
+// Score returns the goals a team has scored and conceded so far.
+func (t *Tracker) Score(teamID int32) (scored, conceded int) {
+	for team, goals := range t.goals {
+		if team == teamID {
+			scored += goals
+		} else {
+			conceded += goals
+		}
+	}
+	for team, goals := range t.ownGoals {
+		if team == teamID {
+			conceded += goals
+		} else {
+			scored += goals
+		}
+	}
+	return scored, conceded
+}
+
+// Differential returns a team's goal differential so far.
+func (t *Tracker) Differential(teamID int32) int {
+	scored, conceded := t.Score(teamID)
+	return scored - conceded
+}
+
+// State returns a team's game state so far.
+func (t *Tracker) State(teamID int32) State {
+	return ForDifferential(t.Differential(teamID))
+}
+
+// Range is an inclusive goal differential range. Nil bounds are open.
+type Range struct {
+	Min *int
+	Max *int
+}
+
+// RangeFor returns the goal differentials of a game state.
+func RangeFor(state State) Range {
+	zero, one, minusOne := 0, 1, -1
+	switch state {
+	case Winning:
+		return Range{Min: &one}
+	case Losing:
+		return Range{Max: &minusOne}
+	default:
+		return Range{Min: &zero, Max: &zero}
+	}
+}
+
+// Contains reports whether a goal differential is in the range.
+func (r Range) Contains(diff int) bool {
+	return (r.Min == nil || diff >= *r.Min) && (r.Max == nil || diff <= *r.Max)
+}
+
+// Tag is an event's game state from the point of view of the event's team.
+type Tag struct {
+	EventID      int32
+	State        State
+	Differential int
+}
+
+// Tags returns the game state of each of a match's events with a team, from the
+// score just before the event, so a goal is tagged with the state it was scored
+// in. Events must be in match clock order.
+func Tags(matchEvents []matchevent.Event) []Tag {
+	var tracker Tracker
+	tags := make([]Tag, 0, len(matchEvents))
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.TeamID != nil {
+			diff := tracker.Differential(*e.TeamID)
+			tags = append(tags, Tag{EventID: e.ID, State: ForDifferential(diff), Differential: diff})
+		}
+		tracker.Apply(e.EventType, e.TeamID, e.Clock.Period)
+	}
+	return tags
+}
+
+// Durations is the time a team spent at each goal differential, in milliseconds.
+type Durations map[int]int64
+
+// DurationsFor measures the time a team spent at each goal differential in a
+// match. Each period runs from its nominal start to its last event; the penalty
+// shootout and events without a known period are not timed. Events must be in
+// match clock order.
+func DurationsFor(matchEvents []matchevent.Event, teamID int32) Durations {
+	return durationsFor(matchEvents, teamID, nil)
+}
+
+// DurationsWithin is DurationsFor counting only the time inside the spans, such
+// as a player's spells on the pitch.
+func DurationsWithin(matchEvents []matchevent.Event, teamID int32, spans []events.ClockSpan) Durations {
+	if len(spans) == 0 {
+		return Durations{}
+	}
+	return durationsFor(matchEvents, teamID, spans)
+}
+
+// durationsFor measures the time at each goal differential, inside the spans
+// unless they are nil.
+func durationsFor(matchEvents []matchevent.Event, teamID int32, spans []events.ClockSpan) Durations {
+	durations := Durations{}
+	var tracker Tracker
+	var period events.Period
+	var from, last int64
+	timed := false
+	flush := func(to int64) {
+		if !timed || to <= from {
+			return
+		}
+		ms := to - from
+		if spans != nil {
+			ms = events.Overlap(period, from, to, spans)
+		}
+		if ms > 0 {
+			durations[tracker.Differential(teamID)] += ms
+		}
+	}
+
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.Clock.Period != period {
+			flush(last)
+			period = e.Clock.Period
+			timed = period.Number() > 0 && period != events.PeriodPenalties
+			from = events.NewMatchClock(period, 0, 0, 0).Ms
+			last = from
+		}
+		if e.Clock.Ms > last {
+			last = e.Clock.Ms
+		}
+		if scores(e.EventType, e.TeamID, e.Clock.Period) && e.Clock.Ms > from {
+			flush(e.Clock.Ms)
+			from = e.Clock.Ms
+		}
+		tracker.Apply(e.EventType, e.TeamID, e.Clock.Period)
+	}
+	flush(last)
+	return durations
+}
+
+// Add adds another match's durations.
+func (d Durations) Add(o Durations) {
+	for diff, ms := range o {
+		d[diff] += ms
+	}
+}
+
+// Minutes returns the minutes spent at the goal differentials in a range.
+func (d Durations) Minutes(r Range) float64 {
+	var ms int64
+	for diff, v := range d {
+		if r.Contains(diff) {
+			ms += v
+		}
+	}
+	return math.Round(float64(ms)/600) / 100
+}
+
+// Minutes are the minutes a team spent in each game state.
+type Minutes struct {
+	Winning float64 `json:"winning"`
+	Drawing float64 `json:"drawing"`
+	Losing  float64 `json:"losing"`
+}
+
+// ByState returns the minutes spent in each game state.
+func (d Durations) ByState() Minutes {
+	return Minutes{
+		Winning: d.Minutes(RangeFor(Winning)),
+		Drawing: d.Minutes(RangeFor(Drawing)),
+		Losing:  d.Minutes(RangeFor(Losing)),
+	}
+}
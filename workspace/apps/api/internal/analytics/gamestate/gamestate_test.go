@@ -0,0 +1,64 @@
+package gamestate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+func TestTagsAndDurations(t *testing.T) {
+	home, away := int32(1), int32(2)
+	at := func(period events.Period, minute int32) events.MatchClock {
+		return events.NewMatchClock(period, minute, 0, 0)
+	}
+
+	matchEvents := []matchevent.Event{
+		{ID: 1, EventType: "kick_off", Clock: at(events.PeriodFirstHalf, 0)},
+		{ID: 2, TeamID: &home, EventType: "goal", Clock: at(events.PeriodFirstHalf, 10)},
+		{ID: 3, TeamID: &away, EventType: "pass", Clock: at(events.PeriodFirstHalf, 20)},
+		{ID: 4, TeamID: &home, EventType: "half_time", Clock: at(events.PeriodFirstHalf, 45)},
+		{ID: 5, TeamID: &home, EventType: "own_goal", Clock: at(events.PeriodSecondHalf, 60)},
+		{ID: 6, TeamID: &away, EventType: "penalty_goal", Clock: at(events.PeriodSecondHalf, 80)},
+		{ID: 7, TeamID: &home, EventType: "full_time", Clock: at(events.PeriodSecondHalf, 90)},
+		{ID: 8, TeamID: &away, EventType: "penalty_goal", Clock: at(events.PeriodPenalties, 120)},
+	}
+
+	tags := Tags(matchEvents)
+	require.Len(t, tags, 7)
+	assert.Equal(t, Tag{EventID: 2, State: Drawing, Differential: 0}, tags[0])
+	assert.Equal(t, Tag{EventID: 3, State: Losing, Differential: -1}, tags[1])
+	assert.Equal(t, Tag{EventID: 6, State: Drawing, Differential: 0}, tags[4])
+	assert.Equal(t, Tag{EventID: 7, State: Losing, Differential: -1}, tags[5])
+
+	durations := DurationsFor(matchEvents, home)
+	assert.Equal(t, Minutes{Winning: 35 + 15, Drawing: 10 + 20, Losing: 10}, durations.ByState())
+
+	season := Durations{}
+	season.Add(durations)
+	season.Add(DurationsFor(matchEvents, away))
+	minusOne := -1
+	assert.Equal(t, 60.0, season.Minutes(Range{Max: &minusOne}))
+	assert.Equal(t, 60.0, season.Minutes(RangeFor(Drawing)))
+
+	// A player on for the first five minutes and from the 50th
+	ms := func(minute int64) int64 { return minute * 60000 }
+	spells := []events.ClockSpan{
+		{Period: events.PeriodFirstHalf, FromMs: 0, ToMs: ms(5)},
+		{Period: events.PeriodSecondHalf, FromMs: ms(50), ToMs: ms(90)},
+	}
+	within := DurationsWithin(matchEvents, home, spells)
+	assert.Equal(t, Minutes{Winning: 10, Drawing: 5 + 20, Losing: 10}, within.ByState())
+	assert.Empty(t, DurationsWithin(matchEvents, home, nil))
+}
+
+func TestRangeFor(t *testing.T) {
+	assert.True(t, RangeFor(Winning).Contains(2))
+	assert.False(t, RangeFor(Winning).Contains(0))
+	assert.True(t, RangeFor(Drawing).Contains(0))
+	assert.True(t, RangeFor(Losing).Contains(-1))
+	assert.False(t, RangeFor(Losing).Contains(0))
+}
@@ -0,0 +1,530 @@
+// Package goalkeeping computes goalkeeper statistics from a match's event log:
+// post-shot expected goals (PSxG) faced and goals prevented, save percentage by
+// shot zone, cross claiming and distribution accuracy by pass length.
+//
+// Coordinates are canonical meters from the acting team's own goal line, so an
+// opponent's shot at x = 100 is 5 m from the goalkeeper's goal line, and a shot
+// ending at y = pitch.Width/2 is in the middle of the goal.
+package goalkeeping
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/shots"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Shot zones, from where the shot was taken.
+const (
+	ZoneSixYardBox  = "six_yard_box"
+	ZonePenaltyArea = "penalty_area" // Outside the six-yard box
+	ZoneOutsideBox  = "outside_box"
+)
+
+const (
+	// LongPassLength is how far, in meters, a pass has to travel to be long
+	LongPassLength = 32.0
+	// SaveWindowMs is how soon after a shot a goalkeeper's save event has to come
+	// to be the save of that shot
+	SaveWindowMs = 5000
+)
+
+// Goal and area dimensions in meters.
+const (
+	goalHalfWidth    = 3.66
+	goalHeight       = 2.44
+	sixYardDepth     = 5.5
+	sixYardHalfWidth = 9.16
+	boxDepth         = 16.5
+	boxHalfWidth     = 20.16
+	// defaultHeight is the share of the goal's height assumed for shots whose end
+	// location has no height
+	defaultHeight = 0.3
+	// optaCrossbar is the Opta GoalMouthZ value of the crossbar
+	optaCrossbar = 38.0
+)
+
+// psxgKeys are the metadata keys of a provider's own post-shot xG.
+var psxgKeys = []string{"psxg", "post_shot_xg", "PSxG"}
+
+// savedOutcomes are the shot outcomes, normalized by shots.FromEvent, of shots
+// on target that did not go in.
+var savedOutcomes = map[string]bool{
+	shots.OutcomeSaved: true,
+	"saved_to_post":    true,
+	"on_target":        true,
+}
+
+// saveZones are the zones of the goalkeeper events that name one.
+var saveZones = map[events.EventType]string{
+	events.EventTypeSaveSixYardBox:  ZoneSixYardBox,
+	events.EventTypeSavePenaltyArea: ZonePenaltyArea,
+	events.EventTypeSavePenalty:     ZonePenaltyArea,
+	events.EventTypeSaveOutOfBox:    ZoneOutsideBox,
+}
+
+// ZoneStats are the shots on target a goalkeeper faced from one zone.
+type ZoneStats struct {
+	ShotsOnTarget  int     `json:"shots_on_target"`
+	Saves          int     `json:"saves"`
+	SavePercentage float64 `json:"save_percentage"`
+}
+
+// Zones are a goalkeeper's shots on target faced by zone. Shots without a
+// position are only in the totals.
+type Zones struct {
+	SixYardBox  ZoneStats `json:"six_yard_box"`
+	PenaltyArea ZoneStats `json:"penalty_area"`
+	OutsideBox  ZoneStats `json:"outside_box"`
+}
+
+// PassStats are a goalkeeper's passes and how many were completed.
+type PassStats struct {
+	Passes    int     `json:"passes"`
+	Completed int     `json:"completed"`
+	Accuracy  float64 `json:"accuracy"` // Percent
+}
+
+// Distribution is a goalkeeper's passing by length. Passes without an end
+// location or a long_ball or short_pass type are only in All.
+type Distribution struct {
+	All   PassStats `json:"all"`
+	Short PassStats `json:"short"`
+	Long  PassStats `json:"long"` // At least LongPassLength
+}
+
+// Stats are a goalkeeper's statistics over one or more matches.
+type Stats struct {
+	PlayerID int32 `json:"player_id"`
+	TeamID   int32 `json:"team_id"` // The team of the first match added
+	Matches  int   `json:"matches"`
+	// CleanSheets are matches in which the goalkeeper was the team's only one and
+	// it conceded no goals
+	CleanSheets int `json:"clean_sheets"`
+	// ShotsOnTarget are the opponent's shots on target, goals included
+	ShotsOnTarget int `json:"shots_on_target"`
+	Saves         int `json:"saves"`
+	// GoalsConceded include own goals
+	GoalsConceded  int     `json:"goals_conceded"`
+	SavePercentage float64 `json:"save_percentage"`
+	// PostShotXG is the post-shot expected goals of the shots on target faced
+	PostShotXG float64 `json:"post_shot_xg"`
+	// GoalsPrevented is PostShotXG less the goals conceded from those shots; own
+	// goals are left out
+	GoalsPrevented float64 `json:"goals_prevented"`
+	PenaltiesFaced int     `json:"penalties_faced"`
+	PenaltiesSaved int     `json:"penalties_saved"`
+	Zones          Zones   `json:"zones"`
+	// CrossesFaced are the opponent's crosses
+	CrossesFaced   int          `json:"crosses_faced"`
+	CrossesClaimed int          `json:"crosses_claimed"`
+	CrossesPunched int          `json:"crosses_punched"`
+	ClaimRate      float64      `json:"claim_rate"` // Claims per cross faced, in percent
+	SweeperActions int          `json:"sweeper_actions"`
+	Distribution   Distribution `json:"distribution"`
+
+	goals float64 // Goals conceded from shots on target, for GoalsPrevented
+}
+
+// faced is an opponent's shot on target faced by a goalkeeper.
+type faced struct {
+	keeper  *Stats
+	clock   events.MatchClock
+	zone    string
+	psxg    float64
+	goal    bool
+	penalty bool
+}
+
+// Keepers returns the goalkeepers of a match's events, in order of their first
+// event: players with goalkeeper events and the known goalkeepers (by position)
+// with any event. Penalty shootouts are left out.
+func Keepers(matchEvents []matchevent.Event, known map[int32]bool) []int32 {
+	isKeeper := map[int32]bool{}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.PlayerID != nil && e.Clock.Period != events.PeriodPenalties &&
+			(known[*e.PlayerID] || events.GetCategory(events.Normalize(e.EventType)) == events.CategoryGoalkeeper) {
+			isKeeper[*e.PlayerID] = true
+		}
+	}
+
+	var keepers []int32
+	seen := map[int32]bool{}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.PlayerID == nil || e.TeamID == nil || seen[*e.PlayerID] || !isKeeper[*e.PlayerID] {
+			continue
+		}
+		seen[*e.PlayerID] = true
+		keepers = append(keepers, *e.PlayerID)
+	}
+	return keepers
+}
+
+// Compute computes each goalkeeper's statistics from a match's events, in match
+// clock order, in the order of Keepers. Shots are faced by the goalkeeper of the
+// other team who last had an event before them, or by its first goalkeeper.
+// Penalty shootouts are left out.
+func Compute(matchEvents []matchevent.Event, known map[int32]bool) []Stats {
+	keeperIDs := Keepers(matchEvents, known)
+	stats := make([]Stats, len(keeperIDs))
+	byPlayer := make(map[int32]*Stats, len(keeperIDs))
+	for i, id := range keeperIDs {
+		stats[i] = Stats{PlayerID: id, Matches: 1}
+		byPlayer[id] = &stats[i]
+	}
+
+	var teamIDs []int32
+	current := map[int32]*Stats{} // Each team's goalkeeper
+	keepersPerTeam := map[int32]int{}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.TeamID == nil || e.Clock.Period == events.PeriodPenalties {
+			continue
+		}
+		if !containsTeam(teamIDs, *e.TeamID) {
+			teamIDs = append(teamIDs, *e.TeamID)
+		}
+		if e.PlayerID == nil {
+			continue
+		}
+		if s, ok := byPlayer[*e.PlayerID]; ok && s.TeamID == 0 {
+			s.TeamID = *e.TeamID
+			keepersPerTeam[*e.TeamID]++
+			if current[*e.TeamID] == nil {
+				current[*e.TeamID] = s
+			}
+		}
+	}
+	opponent := func(teamID int32) int32 {
+		for _, id := range teamIDs {
+			if id != teamID {
+				return id
+			}
+		}
+		return 0
+	}
+
+	var shotsFaced []*faced
+	var last *faced // The last shot, until a save event claims it
+	conceded := map[int32]int{}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.TeamID == nil || e.Clock.Period == events.PeriodPenalties {
+			continue
+		}
+		if e.PlayerID != nil {
+			if s, ok := byPlayer[*e.PlayerID]; ok && s.TeamID == *e.TeamID {
+				current[*e.TeamID] = s
+			}
+		}
+		t := events.Normalize(e.EventType)
+
+		if shots.IsShotMapEvent(e.EventType) {
+			shot, _ := shots.FromEvent(e.EventType, e.X, e.Y, e.Meta, "")
+			if shot.OwnGoal {
+				conceded[*e.TeamID]++
+				if s := current[*e.TeamID]; s != nil {
+					s.GoalsConceded++
+				}
+				last = nil
+				continue
+			}
+			defending := opponent(*e.TeamID)
+			if shot.Goal {
+				conceded[defending]++
+			}
+			last = nil
+			s := current[defending]
+			if s == nil {
+				continue
+			}
+			f := &faced{
+				keeper:  s,
+				clock:   e.Clock,
+				zone:    zone(shot),
+				psxg:    PostShotXG(shot, e.Meta),
+				goal:    shot.Goal,
+				penalty: shot.Situation == xg.SituationPenalty,
+			}
+			if shot.Goal || onTarget(shot, e.Meta) {
+				shotsFaced = append(shotsFaced, f)
+			} else {
+				last = f
+			}
+			if f.penalty {
+				s.PenaltiesFaced++
+			}
+			continue
+		}
+
+		if e.PlayerID == nil {
+			continue
+		}
+		s, isKeeper := byPlayer[*e.PlayerID]
+		if !isKeeper || s.TeamID != *e.TeamID {
+			if t == events.EventTypeCross || crossFlag(e.Meta) {
+				if keeper := current[opponent(*e.TeamID)]; keeper != nil {
+					keeper.CrossesFaced++
+				}
+			}
+			continue
+		}
+
+		switch {
+		case t == events.EventTypeClaim:
+			s.CrossesClaimed++
+		case t == events.EventTypePunch:
+			s.CrossesPunched++
+		case t == events.EventTypeSweeperKeeper:
+			s.SweeperActions++
+		case events.GetCategory(t) == events.CategoryGoalkeeper:
+			// A save event marks the last shot as saved when the shot itself does
+			// not say it was on target
+			if last != nil && last.keeper == s && e.Clock.PeriodNumber() == last.clock.PeriodNumber() &&
+				e.Clock.Ms-last.clock.Ms <= SaveWindowMs {
+				if last.zone == "" {
+					last.zone = saveZones[t]
+				}
+				last.penalty = last.penalty || t == events.EventTypeSavePenalty
+				shotsFaced = append(shotsFaced, last)
+				last = nil
+			}
+		case t.IsPass():
+			countPass(s, e)
+		}
+	}
+
+	for _, f := range shotsFaced {
+		f.keeper.add(f)
+	}
+	for teamID, s := range current {
+		if keepersPerTeam[teamID] == 1 && conceded[teamID] == 0 {
+			s.CleanSheets = 1
+		}
+	}
+	for i := range stats {
+		stats[i].finish()
+	}
+	return stats
+}
+
+// add counts a shot on target faced.
+func (s *Stats) add(f *faced) {
+	s.ShotsOnTarget++
+	s.PostShotXG += f.psxg
+	if f.goal {
+		s.GoalsConceded++
+		s.goals++
+	} else {
+		s.Saves++
+		if f.penalty {
+			s.PenaltiesSaved++
+		}
+	}
+
+	var z *ZoneStats
+	switch f.zone {
+	case ZoneSixYardBox:
+		z = &s.Zones.SixYardBox
+	case ZonePenaltyArea:
+		z = &s.Zones.PenaltyArea
+	case ZoneOutsideBox:
+		z = &s.Zones.OutsideBox
+	default:
+		return
+	}
+	z.ShotsOnTarget++
+	if !f.goal {
+		z.Saves++
+	}
+}
+
+// Add adds another match's or season's statistics for the same goalkeeper.
+func (s *Stats) Add(o Stats) {
+	if s.TeamID == 0 {
+		s.TeamID = o.TeamID
+	}
+	s.Matches += o.Matches
+	s.CleanSheets += o.CleanSheets
+	s.ShotsOnTarget += o.ShotsOnTarget
+	s.Saves += o.Saves
+	s.GoalsConceded += o.GoalsConceded
+	s.PostShotXG += o.PostShotXG
+	s.goals += o.goals
+	s.PenaltiesFaced += o.PenaltiesFaced
+	s.PenaltiesSaved += o.PenaltiesSaved
+	s.Zones.SixYardBox.add(o.Zones.SixYardBox)
+	s.Zones.PenaltyArea.add(o.Zones.PenaltyArea)
+	s.Zones.OutsideBox.add(o.Zones.OutsideBox)
+	s.CrossesFaced += o.CrossesFaced
+	s.CrossesClaimed += o.CrossesClaimed
+	s.CrossesPunched += o.CrossesPunched
+	s.SweeperActions += o.SweeperActions
+	s.Distribution.All.add(o.Distribution.All)
+	s.Distribution.Short.add(o.Distribution.Short)
+	s.Distribution.Long.add(o.Distribution.Long)
+	s.finish()
+}
+
+func (z *ZoneStats) add(o ZoneStats) {
+	z.ShotsOnTarget += o.ShotsOnTarget
+	z.Saves += o.Saves
+}
+
+func (p *PassStats) add(o PassStats) {
+	p.Passes += o.Passes
+	p.Completed += o.Completed
+}
+
+// finish computes the ratios from the counts.
+func (s *Stats) finish() {
+	s.PostShotXG = xg.Round(s.PostShotXG)
+	s.GoalsPrevented = xg.Round(s.PostShotXG - s.goals)
+	s.SavePercentage = percent(s.Saves, s.ShotsOnTarget)
+	s.ClaimRate = percent(s.CrossesClaimed, s.CrossesFaced)
+	for _, z := range []*ZoneStats{&s.Zones.SixYardBox, &s.Zones.PenaltyArea, &s.Zones.OutsideBox} {
+		z.SavePercentage = percent(z.Saves, z.ShotsOnTarget)
+	}
+	for _, p := range []*PassStats{&s.Distribution.All, &s.Distribution.Short, &s.Distribution.Long} {
+		p.Accuracy = percent(p.Completed, p.Passes)
+	}
+}
+
+// countPass counts a goalkeeper's pass by length.
+func countPass(s *Stats, e *matchevent.Event) {
+	completed := xt.Completed(e.EventType, e.Meta)
+	long, known := false, false
+	switch events.Normalize(e.EventType) {
+	case events.EventTypeLongBall:
+		long, known = true, true
+	case events.EventTypeShortPass:
+		known = true
+	default:
+		provider, _ := e.Meta[xg.MetaProvider].(string)
+		if m, ok := xt.MoveFromEvent(e.EventType, e.X, e.Y, e.Meta, pitch.FrameFor(e.Meta, provider)); ok {
+			long = math.Hypot(m.EndX-m.StartX, m.EndY-m.StartY) >= LongPassLength
+			known = true
+		}
+	}
+
+	passes := []*PassStats{&s.Distribution.All}
+	if known && long {
+		passes = append(passes, &s.Distribution.Long)
+	} else if known {
+		passes = append(passes, &s.Distribution.Short)
+	}
+	for _, p := range passes {
+		p.Passes++
+		if completed {
+			p.Completed++
+		}
+	}
+}
+
+// PostShotXG returns a shot on target's post-shot xG: the provider's own when it
+// sends one, otherwise the shot's xG adjusted for where it crossed the goal line.
+// Shots placed towards the corners are harder to save than shots at the
+// goalkeeper. Without an end location it is the shot's xG.
+func PostShotXG(shot shots.Shot, meta map[string]interface{}) float64 {
+	if v, ok := events.MetaFloat(meta, psxgKeys...); ok {
+		return v
+	}
+	y, z, ok := goalMouth(meta)
+	if !ok {
+		return shot.XG
+	}
+	p := math.Min(math.Max(shot.XG, 0.01), 0.99)
+	lateral := math.Min(math.Abs(y-pitch.Width/2)/goalHalfWidth, 1)
+	height := math.Min(math.Max(z/goalHeight, 0), 1)
+	logit := -1.0 + 0.6*math.Log(p/(1-p)) + 2.2*lateral*lateral + 0.8*height*lateral
+	return xg.Round(1 / (1 + math.Exp(-logit)))
+}
+
+// goalMouth returns where a shot crossed the goal line: y in canonical meters and
+// the height in meters. Shot end locations are normalized on ingest; Opta's
+// GoalMouthY and GoalMouthZ qualifiers are percentages of the pitch width and of
+// optaCrossbar.
+func goalMouth(meta map[string]interface{}) (float64, float64, bool) {
+	values := make(map[string]interface{}, len(meta))
+	for key, value := range meta {
+		values[strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(key))] = value
+	}
+	if y, ok := events.ToFloat(values["shotendy"]); ok {
+		provider, _ := meta[xg.MetaProvider].(string)
+		_, y = pitch.FrameFor(meta, provider).Meters(0, y)
+		z, ok := events.ToFloat(values["shotendz"])
+		if !ok {
+			z = defaultHeight * goalHeight
+		}
+		return y, z, true
+	}
+	if y, ok := events.ToFloat(values["goalmouthy"]); ok {
+		_, y = pitch.Opta.Meters(0, y)
+		z := defaultHeight * goalHeight
+		if v, ok := events.ToFloat(values["goalmouthz"]); ok {
+			z = v / optaCrossbar * goalHeight
+		}
+		return y, z, true
+	}
+	return 0, 0, false
+}
+
+// onTarget reports whether a shot that was not scored was on target, from its
+// outcome or an on_target flag.
+func onTarget(shot shots.Shot, meta map[string]interface{}) bool {
+	if v, ok := meta["on_target"].(bool); ok {
+		return v
+	}
+	return savedOutcomes[shot.Outcome]
+}
+
+// zone returns the zone a shot was taken from, or "" without a position.
+func zone(shot shots.Shot) string {
+	if shot.X == nil || shot.Y == nil {
+		if shot.Situation == xg.SituationPenalty {
+			return ZonePenaltyArea
+		}
+		return ""
+	}
+	depth, width := pitch.Length-*shot.X, math.Abs(*shot.Y-pitch.Width/2)
+	switch {
+	case depth <= sixYardDepth && width <= sixYardHalfWidth:
+		return ZoneSixYardBox
+	case depth <= boxDepth && width <= boxHalfWidth:
+		return ZonePenaltyArea
+	}
+	return ZoneOutsideBox
+}
+
+// crossFlag reports whether a pass is marked as a cross: a StatsBomb cross flag
+// or an Opta Cross qualifier.
+func crossFlag(meta map[string]interface{}) bool {
+	if v, ok := meta["cross"].(bool); ok {
+		return v
+	}
+	_, ok := meta["Cross"]
+	return ok
+}
+
+func containsTeam(teamIDs []int32, teamID int32) bool {
+	for _, id := range teamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(total)*10000) / 100
+}
@@ -0,0 +1,165 @@
+package goalkeeping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent/matcheventtest"
+	"github.com/emiliospot/footie/api/internal/analytics/shots"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+const (
+	home int32 = 1
+	away int32 = 2
+
+	homeKeeper int32 = 10
+	awayKeeper int32 = 20
+	striker    int32 = 29
+)
+
+var (
+	event = matcheventtest.New
+	at    = matcheventtest.At
+)
+
+// shot builds an away shot from a point in canonical meters.
+func shot(id int32, eventType string, x, y float64, second int32, meta map[string]interface{}) matchevent.Event {
+	e := at(event(id, eventType, away, striker, second), x, y)
+	for k, v := range meta {
+		e.Meta[k] = v
+	}
+	return e
+}
+
+func TestCompute(t *testing.T) {
+	stats := Compute([]matchevent.Event{
+		event(1, "pass", home, homeKeeper, 1),
+		event(2, "pass", away, awayKeeper, 2),
+		shot(3, "shot_saved", 100, 34, 10, map[string]interface{}{"xG": 0.3}),
+		event(4, "save_six_yard_box", home, homeKeeper, 11),
+		shot(5, "goal", 90, 40, 20, map[string]interface{}{"xG": 0.1, "psxg": 0.6}),
+		// Off target without a save event
+		shot(6, "shot_off_target", 80, 34, 30, map[string]interface{}{"xG": 0.02}),
+		// The shot does not say it was on target, the save does
+		shot(7, "shot", 95, 30, 40, map[string]interface{}{"xG": 0.2, "psxg": 0.25}),
+		event(8, "save", home, homeKeeper, 42),
+		event(9, "cross", away, 27, 50),
+		event(10, "claim", home, homeKeeper, 51),
+		event(11, "cross", away, 27, 55),
+		event(12, "punch", home, homeKeeper, 56),
+		event(13, "sweeper_keeper", home, homeKeeper, 58),
+	}, map[int32]bool{awayKeeper: true})
+
+	require.Len(t, stats, 2)
+	keeper := stats[0]
+	assert.Equal(t, homeKeeper, keeper.PlayerID)
+	assert.Equal(t, home, keeper.TeamID)
+	assert.Equal(t, 3, keeper.ShotsOnTarget)
+	assert.Equal(t, 2, keeper.Saves)
+	assert.Equal(t, 1, keeper.GoalsConceded)
+	assert.Equal(t, 66.67, keeper.SavePercentage)
+	assert.Equal(t, 0, keeper.CleanSheets)
+	assert.Equal(t, ZoneStats{ShotsOnTarget: 1, Saves: 1, SavePercentage: 100}, keeper.Zones.SixYardBox)
+	assert.Equal(t, ZoneStats{ShotsOnTarget: 2, Saves: 1, SavePercentage: 50}, keeper.Zones.PenaltyArea)
+	assert.Equal(t, ZoneStats{}, keeper.Zones.OutsideBox)
+	// Without an end location the first shot's post-shot xG is its xG
+	assert.InDelta(t, 1.15, keeper.PostShotXG, 1e-9)
+	assert.InDelta(t, 0.15, keeper.GoalsPrevented, 1e-9)
+	assert.Equal(t, 2, keeper.CrossesFaced)
+	assert.Equal(t, 1, keeper.CrossesClaimed)
+	assert.Equal(t, 1, keeper.CrossesPunched)
+	assert.Equal(t, 50.0, keeper.ClaimRate)
+	assert.Equal(t, 1, keeper.SweeperActions)
+
+	// The away goalkeeper kept a clean sheet
+	assert.Equal(t, awayKeeper, stats[1].PlayerID)
+	assert.Equal(t, 1, stats[1].CleanSheets)
+	assert.Equal(t, 0, stats[1].ShotsOnTarget)
+}
+
+func TestComputeKeepersAndPenalties(t *testing.T) {
+	penalty := shot(3, "penalty_miss", 94, 34, 10, map[string]interface{}{"xG": 0.76})
+	ownGoal := event(5, "own_goal", home, 5, 30)
+	sub := event(4, "pass", home, 11, 20) // The second goalkeeper, known by position
+	shootout := shot(7, "penalty_goal", 94, 34, 0, nil)
+	shootout.Clock = events.NewMatchClock(events.PeriodPenalties, 120, 0, 0)
+
+	stats := Compute([]matchevent.Event{
+		event(1, "pass", home, homeKeeper, 1),
+		event(2, "duel_won", away, striker, 2),
+		penalty,
+		event(8, "save_penalty", home, homeKeeper, 11),
+		sub,
+		ownGoal,
+		shot(6, "goal", 100, 30, 40, nil),
+		shootout,
+	}, map[int32]bool{11: true})
+
+	require.Len(t, stats, 2)
+	first, second := stats[0], stats[1]
+	assert.Equal(t, 1, first.PenaltiesFaced)
+	assert.Equal(t, 1, first.PenaltiesSaved)
+	assert.Equal(t, 1, first.Saves)
+	assert.Equal(t, 1, first.Zones.PenaltyArea.Saves)
+
+	assert.Equal(t, int32(11), second.PlayerID)
+	assert.Equal(t, 2, second.GoalsConceded, "the own goal counts, the shootout does not")
+	assert.Equal(t, 1, second.ShotsOnTarget)
+	assert.Equal(t, 0, first.CleanSheets+second.CleanSheets)
+}
+
+func TestDistribution(t *testing.T) {
+	long := at(event(2, "pass", home, homeKeeper, 2), 5, 34, 60, 20)
+	short := at(event(3, "pass", home, homeKeeper, 3), 5, 34, 15, 10)
+	lost := event(4, "long_ball", home, homeKeeper, 4)
+	lost.Meta["outcome"] = "Incomplete"
+
+	stats := Compute([]matchevent.Event{
+		event(1, "claim", home, homeKeeper, 1),
+		long,
+		short,
+		lost,
+		event(5, "pass", home, homeKeeper, 5),
+	}, nil)
+
+	require.Len(t, stats, 1)
+	d := stats[0].Distribution
+	assert.Equal(t, PassStats{Passes: 4, Completed: 3, Accuracy: 75}, d.All)
+	assert.Equal(t, PassStats{Passes: 2, Completed: 1, Accuracy: 50}, d.Long)
+	assert.Equal(t, PassStats{Passes: 1, Completed: 1, Accuracy: 100}, d.Short)
+}
+
+func TestPostShotXG(t *testing.T) {
+	s := shots.Shot{XG: 0.1}
+	canonical := func(y, z float64) map[string]interface{} {
+		return map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical, "shot_end_y": y, "shot_end_z": z}
+	}
+
+	central := PostShotXG(s, canonical(34, 0.2))
+	corner := PostShotXG(s, canonical(37.5, 2.3))
+	assert.Less(t, central, s.XG, "a low shot at the goalkeeper is easy to save")
+	assert.Greater(t, corner, 0.5, "a shot into the top corner is hard to save")
+
+	// Opta goal mouth qualifiers: y measured from the right touchline, z against the crossbar
+	opta := PostShotXG(s, map[string]interface{}{"GoalMouthY": "45", "GoalMouthZ": "36"})
+	assert.InDelta(t, corner, opta, 0.05)
+
+	assert.Equal(t, 0.4, PostShotXG(s, map[string]interface{}{"psxg": 0.4}))
+	assert.Equal(t, 0.1, PostShotXG(s, nil))
+}
+
+func TestAdd(t *testing.T) {
+	total := Stats{PlayerID: homeKeeper}
+	total.Add(Stats{TeamID: home, Matches: 1, ShotsOnTarget: 4, Saves: 3, GoalsConceded: 1, PostShotXG: 1.5, goals: 1})
+	total.Add(Stats{TeamID: away, Matches: 1, CleanSheets: 1, ShotsOnTarget: 2, Saves: 2, PostShotXG: 0.4})
+
+	assert.Equal(t, home, total.TeamID)
+	assert.Equal(t, 2, total.Matches)
+	assert.Equal(t, 83.33, total.SavePercentage)
+	assert.InDelta(t, 0.9, total.GoalsPrevented, 1e-9)
+}
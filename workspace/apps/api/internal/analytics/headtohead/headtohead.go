@@ -0,0 +1,118 @@
+// Package headtohead summarizes the meetings between two teams.
+package headtohead
+
+import (
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+// Record is a team's results in a set of meetings.
+type Record struct {
+	Played       int `json:"played"`
+	Wins         int `json:"wins"`
+	Draws        int `json:"draws"`
+	Losses       int `json:"losses"`
+	GoalsFor     int `json:"goals_for"`
+	GoalsAgainst int `json:"goals_against"`
+}
+
+// add counts a result from the team's side.
+func (r *Record) add(goalsFor, goalsAgainst int32) {
+	r.Played++
+	r.GoalsFor += int(goalsFor)
+	r.GoalsAgainst += int(goalsAgainst)
+	switch {
+	case goalsFor > goalsAgainst:
+		r.Wins++
+	case goalsFor < goalsAgainst:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+}
+
+// Summary is a team's record against an opponent, overall and split by venue.
+type Summary struct {
+	TeamID  int32  `json:"team_id"`
+	Overall Record `json:"overall"`
+	Home    Record `json:"home"` // Meetings the team hosted
+	Away    Record `json:"away"`
+}
+
+// Summarize returns a team's record in the meetings. Matches the team did not
+// play in are skipped.
+func Summarize(meetings []models.Match, teamID int32) Summary {
+	s := Summary{TeamID: teamID}
+	for i := range meetings {
+		m := &meetings[i]
+		switch teamID {
+		case m.HomeTeamID:
+			s.Overall.add(m.HomeTeamScore, m.AwayTeamScore)
+			s.Home.add(m.HomeTeamScore, m.AwayTeamScore)
+		case m.AwayTeamID:
+			s.Overall.add(m.AwayTeamScore, m.HomeTeamScore)
+			s.Away.add(m.AwayTeamScore, m.HomeTeamScore)
+		}
+	}
+	return s
+}
+
+// Totals are a team's event statistics summed over the meetings with events.
+type Totals struct {
+	TeamID          int32
+	Matches         int64
+	Shots           int64
+	ShotsOnTarget   int64
+	XG              float64
+	Passes          int64
+	PassesCompleted int64
+	Corners         int64
+	Fouls           int64
+	YellowCards     int64
+	RedCards        int64
+}
+
+// EventStats are a team's event statistics per meeting.
+type EventStats struct {
+	TeamID        int32   `json:"team_id"`
+	Matches       int64   `json:"matches"` // Meetings with events for the team
+	Shots         float64 `json:"shots"`
+	ShotsOnTarget float64 `json:"shots_on_target"`
+	XG            float64 `json:"xg"`
+	Passes        float64 `json:"passes"`
+	PassAccuracy  float64 `json:"pass_accuracy"` // Percentage of completed passes over every meeting
+	Corners       float64 `json:"corners"`
+	Fouls         float64 `json:"fouls"`
+	YellowCards   float64 `json:"yellow_cards"`
+	RedCards      float64 `json:"red_cards"`
+}
+
+// Average returns a team's event statistics per meeting.
+func Average(t Totals) EventStats {
+	s := EventStats{TeamID: t.TeamID, Matches: t.Matches}
+	if t.Matches == 0 {
+		return s
+	}
+	n := float64(t.Matches)
+	per := func(v float64) float64 {
+		return round(v / n)
+	}
+	s.Shots = per(float64(t.Shots))
+	s.ShotsOnTarget = per(float64(t.ShotsOnTarget))
+	s.XG = per(t.XG)
+	s.Passes = per(float64(t.Passes))
+	s.Corners = per(float64(t.Corners))
+	s.Fouls = per(float64(t.Fouls))
+	s.YellowCards = per(float64(t.YellowCards))
+	s.RedCards = per(float64(t.RedCards))
+	if t.Passes > 0 {
+		s.PassAccuracy = round(float64(t.PassesCompleted) / float64(t.Passes) * 100)
+	}
+	return s
+}
+
+// round rounds to 2 decimal places.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
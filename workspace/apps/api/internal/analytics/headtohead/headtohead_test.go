@@ -0,0 +1,38 @@
+package headtohead
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+func TestSummarize(t *testing.T) {
+	meetings := []models.Match{
+		{HomeTeamID: 1, AwayTeamID: 2, HomeTeamScore: 2, AwayTeamScore: 0},
+		{HomeTeamID: 2, AwayTeamID: 1, HomeTeamScore: 1, AwayTeamScore: 1},
+		{HomeTeamID: 2, AwayTeamID: 1, HomeTeamScore: 3, AwayTeamScore: 1},
+		{HomeTeamID: 3, AwayTeamID: 2, HomeTeamScore: 5, AwayTeamScore: 0}, // Not a meeting
+	}
+
+	s := Summarize(meetings, 1)
+	assert.Equal(t, Record{Played: 3, Wins: 1, Draws: 1, Losses: 1, GoalsFor: 4, GoalsAgainst: 4}, s.Overall)
+	assert.Equal(t, Record{Played: 1, Wins: 1, GoalsFor: 2}, s.Home)
+	assert.Equal(t, Record{Played: 2, Draws: 1, Losses: 1, GoalsFor: 2, GoalsAgainst: 4}, s.Away)
+
+	other := Summarize(meetings[:3], 2)
+	assert.Equal(t, s.Overall.Wins, other.Overall.Losses)
+	assert.Equal(t, s.Home.Played, other.Away.Played)
+}
+
+func TestAverage(t *testing.T) {
+	s := Average(Totals{TeamID: 1, Matches: 4, Shots: 50, XG: 5.3, Passes: 2000, PassesCompleted: 1700, Corners: 21})
+	assert.Equal(t, 12.5, s.Shots)
+	assert.Equal(t, 1.33, s.XG)
+	assert.Equal(t, 500.0, s.Passes)
+	assert.Equal(t, 85.0, s.PassAccuracy)
+	assert.Equal(t, 5.25, s.Corners)
+
+	assert.Equal(t, EventStats{TeamID: 2}, Average(Totals{TeamID: 2}))
+}
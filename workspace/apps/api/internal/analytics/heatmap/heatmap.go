@@ -0,0 +1,163 @@
+// Package heatmap bins event locations on the canonical pitch into heatmaps,
+// touch maps and zone summaries.
+package heatmap
+
+import (
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Default grid size: 12 zones along the pitch and 8 across it.
+const (
+	DefaultColumns = 12
+	DefaultRows    = 8
+	MaxColumns     = 50
+	MaxRows        = 50
+)
+
+// Zone boundaries in canonical meters.
+const (
+	thirdLength = pitch.Length / 3
+	boxLength   = 16.5
+	boxWidth    = 40.32
+	// wideChannel is the width of each wing channel, outside the width of the box
+	wideChannel = (pitch.Width - boxWidth) / 2
+)
+
+// Point is an event location in canonical meters.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Heatmap holds event counts (the touch map) and normalized densities on a
+// Columns x Rows grid. Cells are indexed [row][column]; column 0 is at the
+// team's own goal line and row 0 at its left touchline.
+type Heatmap struct {
+	Columns int         `json:"columns"`
+	Rows    int         `json:"rows"`
+	Length  float64     `json:"length"`
+	Width   float64     `json:"width"`
+	Total   int         `json:"total"`
+	Counts  [][]int     `json:"counts"`
+	Density [][]float64 `json:"density"` // Share of all events in each cell; sums to 1
+	Zones   Zones       `json:"zones"`
+}
+
+// Zones summarizes events by third, channel and penalty box.
+type Zones struct {
+	Thirds   map[string]ZoneShare `json:"thirds"`   // defensive, middle, attacking
+	Channels map[string]ZoneShare `json:"channels"` // left, center, right
+	Box      ZoneShare            `json:"box"`      // Opposition penalty area
+}
+
+// ZoneShare is the number and share of events in a zone.
+type ZoneShare struct {
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// Build bins points into a columns x rows heatmap with zone summaries.
+// Points off the pitch are clamped to the nearest cell.
+func Build(points []Point, columns, rows int) Heatmap {
+	if columns <= 0 {
+		columns = DefaultColumns
+	}
+	if rows <= 0 {
+		rows = DefaultRows
+	}
+
+	h := Heatmap{
+		Columns: columns,
+		Rows:    rows,
+		Length:  pitch.Length,
+		Width:   pitch.Width,
+		Total:   len(points),
+		Counts:  make([][]int, rows),
+		Density: make([][]float64, rows),
+	}
+	for r := range h.Counts {
+		h.Counts[r] = make([]int, columns)
+		h.Density[r] = make([]float64, columns)
+	}
+
+	thirds := map[string]int{"defensive": 0, "middle": 0, "attacking": 0}
+	channels := map[string]int{"left": 0, "center": 0, "right": 0}
+	box := 0
+	for _, p := range points {
+		col := clamp(int(p.X/pitch.Length*float64(columns)), columns)
+		row := clamp(int(p.Y/pitch.Width*float64(rows)), rows)
+		h.Counts[row][col]++
+
+		thirds[third(p.X)]++
+		channels[channel(p.Y)]++
+		if inBox(p) {
+			box++
+		}
+	}
+
+	for r := range h.Counts {
+		for c := range h.Counts[r] {
+			h.Density[r][c] = share(h.Counts[r][c], h.Total)
+		}
+	}
+	h.Zones = Zones{
+		Thirds:   shares(thirds, h.Total),
+		Channels: shares(channels, h.Total),
+		Box:      ZoneShare{Count: box, Share: share(box, h.Total)},
+	}
+	return h
+}
+
+func third(x float64) string {
+	switch {
+	case x < thirdLength:
+		return "defensive"
+	case x < 2*thirdLength:
+		return "middle"
+	default:
+		return "attacking"
+	}
+}
+
+func channel(y float64) string {
+	switch {
+	case y < wideChannel:
+		return "left"
+	case y > pitch.Width-wideChannel:
+		return "right"
+	default:
+		return "center"
+	}
+}
+
+func inBox(p Point) bool {
+	return p.X >= pitch.Length-boxLength && p.Y >= wideChannel && p.Y <= pitch.Width-wideChannel
+}
+
+func shares(counts map[string]int, total int) map[string]ZoneShare {
+	out := make(map[string]ZoneShare, len(counts))
+	for zone, count := range counts {
+		out[zone] = ZoneShare{Count: count, Share: share(count, total)}
+	}
+	return out
+}
+
+// share returns count/total rounded to four decimals.
+func share(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)/float64(total)*10000) / 10000
+}
+
+func clamp(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
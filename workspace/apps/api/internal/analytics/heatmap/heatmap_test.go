@@ -0,0 +1,37 @@
+package heatmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	points := []Point{
+		{X: 1, Y: 1},     // Own corner
+		{X: 52.5, Y: 34}, // Center spot
+		{X: 95, Y: 34},   // Penalty spot area
+		{X: 100, Y: 60},  // Right wing, final third
+		{X: 110, Y: -5},  // Off the pitch, clamped
+	}
+
+	h := Build(points, 3, 2)
+
+	assert.Equal(t, 5, h.Total)
+	assert.Equal(t, [][]int{{1, 0, 1}, {0, 1, 2}}, h.Counts)
+	assert.InDelta(t, 0.4, h.Density[1][2], 0.0001)
+	assert.Equal(t, ZoneShare{Count: 3, Share: 0.6}, h.Zones.Thirds["attacking"])
+	assert.Equal(t, ZoneShare{Count: 1, Share: 0.2}, h.Zones.Thirds["defensive"])
+	assert.Equal(t, 2, h.Zones.Channels["left"].Count)
+	assert.Equal(t, 1, h.Zones.Channels["right"].Count)
+	assert.Equal(t, ZoneShare{Count: 1, Share: 0.2}, h.Zones.Box)
+}
+
+func TestBuild_Empty(t *testing.T) {
+	h := Build(nil, 0, 0)
+
+	assert.Equal(t, DefaultColumns, h.Columns)
+	assert.Equal(t, DefaultRows, h.Rows)
+	assert.Equal(t, 0.0, h.Density[0][0])
+	assert.Equal(t, 0, h.Zones.Box.Count)
+}
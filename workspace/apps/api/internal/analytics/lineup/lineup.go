@@ -0,0 +1,160 @@
+// Package lineup computes the minutes each player in a match's lineups spent on
+// the pitch from the substitution and sending-off events.
+package lineup
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// Player is a player in a team's lineup.
+type Player struct {
+	PlayerID int32
+	Starter  bool
+}
+
+// Appearance is a player's time on the pitch in a match.
+type Appearance struct {
+	PlayerID  int32 `json:"player_id"`
+	Starter   bool  `json:"starter"`
+	SubbedOn  bool  `json:"subbed_on"`
+	SubbedOff bool  `json:"subbed_off"`
+	SentOff   bool  `json:"sent_off"`
+	Minutes   int   `json:"minutes"`
+	// Spells are the stretches of each period the player was on the pitch
+	Spells []events.ClockSpan `json:"-"`
+}
+
+// Played reports whether the player took part in the match.
+func (a *Appearance) Played() bool {
+	return a.Starter || a.SubbedOn
+}
+
+// Appearances follows the players of a match's lineups on and off the pitch and
+// returns their appearances in lineup order. Starters are on from kick-off;
+// substitution_on brings player_id on, substitution_off takes player_id off, and
+// a generic substitution takes player_id off and brings secondary_player_id on.
+// Red cards and second yellow cards send player_id off.
+//
+// Each period runs from its nominal start to its last event, so stoppage time
+// counts as long as the period's end (half_time, full_time) or any later event
+// is recorded. The penalty shootout and events without a known period are not
+// timed. Events must be in match clock order.
+func Appearances(players []Player, matchEvents []matchevent.Event) []Appearance {
+	appearances := make([]Appearance, len(players))
+	index := make(map[int32]int, len(players))
+	onPitch := make([]bool, len(players))
+	since := make([]int64, len(players))
+	played := make([]int64, len(players))
+	for i, p := range players {
+		appearances[i] = Appearance{PlayerID: p.PlayerID, Starter: p.Starter}
+		index[p.PlayerID] = i
+		onPitch[i] = p.Starter
+	}
+
+	var period events.Period
+	var start, last int64
+	timed := false
+	flush := func(i int, to int64) {
+		if timed && to > since[i] {
+			played[i] += to - since[i]
+			appearances[i].Spells = append(appearances[i].Spells, events.ClockSpan{Period: period, FromMs: since[i], ToMs: to})
+		}
+	}
+	lookup := func(playerID *int32) (int, bool) {
+		if playerID == nil {
+			return 0, false
+		}
+		i, ok := index[*playerID]
+		return i, ok
+	}
+	comeOn := func(playerID *int32, at int64) {
+		if i, ok := lookup(playerID); ok && !onPitch[i] {
+			onPitch[i], since[i] = true, at
+			appearances[i].SubbedOn = !appearances[i].Starter
+		}
+	}
+	goOff := func(playerID *int32, at int64) *Appearance {
+		i, ok := lookup(playerID)
+		if !ok || !onPitch[i] {
+			return nil
+		}
+		flush(i, at)
+		onPitch[i] = false
+		return &appearances[i]
+	}
+
+	for k := range matchEvents {
+		e := &matchEvents[k]
+		if e.Clock.Period != period {
+			for i := range players {
+				if onPitch[i] {
+					flush(i, last)
+				}
+			}
+			period = e.Clock.Period
+			timed = period.Number() > 0 && period != events.PeriodPenalties
+			start = events.NewMatchClock(period, 0, 0, 0).Ms
+			last = start
+			for i := range since {
+				since[i] = start
+			}
+		}
+		if e.Clock.Ms > last {
+			last = e.Clock.Ms
+		}
+		at := max(e.Clock.Ms, start)
+
+		switch events.Normalize(e.EventType) {
+		case events.EventTypeSubstitutionOn:
+			comeOn(e.PlayerID, at)
+		case events.EventTypeSubstitutionOff:
+			if a := goOff(e.PlayerID, at); a != nil {
+				a.SubbedOff = true
+			}
+		case events.EventTypeSubstitution:
+			if a := goOff(e.PlayerID, at); a != nil {
+				a.SubbedOff = true
+			}
+			comeOn(e.SecondaryPlayerID, at)
+		case events.EventTypeRedCard, events.EventTypeSecondYellow, events.EventTypeVarRedCard:
+			if a := goOff(e.PlayerID, at); a != nil {
+				a.SentOff = true
+			}
+		}
+	}
+	for i := range players {
+		if onPitch[i] {
+			flush(i, last)
+		}
+		appearances[i].Minutes = int(math.Round(float64(played[i]) / 60000))
+	}
+	return appearances
+}
+
+// NormalizeFormation formats a formation as lines separated by dashes: "433",
+// "4-3-3" and "4 3 3" all give "4-3-3". It reports false when the formation is
+// not a sequence of outfield lines adding up to 10 players.
+func NormalizeFormation(formation string) (string, bool) {
+	lines := strings.FieldsFunc(formation, func(r rune) bool { return r == '-' || r == ' ' })
+	if len(lines) == 1 {
+		lines = strings.Split(lines[0], "")
+	}
+
+	outfield := 0
+	for _, line := range lines {
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 1 {
+			return "", false
+		}
+		outfield += n
+	}
+	if len(lines) < 2 || outfield != 10 {
+		return "", false
+	}
+	return strings.Join(lines, "-"), true
+}
@@ -0,0 +1,73 @@
+package lineup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+func TestAppearances(t *testing.T) {
+	id := func(v int32) *int32 { return &v }
+	at := func(period events.Period, minute, stoppage int32) events.MatchClock {
+		return events.NewMatchClock(period, minute, stoppage, 0)
+	}
+
+	players := []Player{
+		{PlayerID: 1, Starter: true},
+		{PlayerID: 2, Starter: true},
+		{PlayerID: 3, Starter: true},
+		{PlayerID: 4},
+		{PlayerID: 5},
+		{PlayerID: 6},
+	}
+	matchEvents := []matchevent.Event{
+		{EventType: "kick_off", Clock: at(events.PeriodFirstHalf, 0, 0)},
+		{PlayerID: id(3), EventType: "second_yellow_card", Clock: at(events.PeriodFirstHalf, 30, 0)},
+		{EventType: "half_time", Clock: at(events.PeriodFirstHalf, 45, 3)},
+		{PlayerID: id(2), SecondaryPlayerID: id(4), EventType: "substitution", Clock: at(events.PeriodSecondHalf, 60, 0)},
+		{PlayerID: id(5), EventType: "Substitution_On", Clock: at(events.PeriodSecondHalf, 75, 0)},
+		{PlayerID: id(1), EventType: "substitution_off", Clock: at(events.PeriodSecondHalf, 75, 0)},
+		{EventType: "full_time", Clock: at(events.PeriodSecondHalf, 90, 5)},
+	}
+
+	appearances := Appearances(players, matchEvents)
+	require.Len(t, appearances, 6)
+	ms := func(minute int64) int64 { return minute * 60000 }
+	firstHalf := events.ClockSpan{Period: events.PeriodFirstHalf, FromMs: 0, ToMs: ms(48)}
+	secondHalf := func(from, to int64) events.ClockSpan {
+		return events.ClockSpan{Period: events.PeriodSecondHalf, FromMs: ms(from), ToMs: ms(to)}
+	}
+	assert.Equal(t, Appearance{PlayerID: 1, Starter: true, SubbedOff: true, Minutes: 48 + 30,
+		Spells: []events.ClockSpan{firstHalf, secondHalf(45, 75)}}, appearances[0])
+	assert.Equal(t, Appearance{PlayerID: 2, Starter: true, SubbedOff: true, Minutes: 48 + 15,
+		Spells: []events.ClockSpan{firstHalf, secondHalf(45, 60)}}, appearances[1])
+	assert.Equal(t, Appearance{PlayerID: 3, Starter: true, SentOff: true, Minutes: 30,
+		Spells: []events.ClockSpan{{Period: events.PeriodFirstHalf, FromMs: 0, ToMs: ms(30)}}}, appearances[2])
+	assert.Equal(t, Appearance{PlayerID: 4, SubbedOn: true, Minutes: 35,
+		Spells: []events.ClockSpan{secondHalf(60, 95)}}, appearances[3])
+	assert.Equal(t, Appearance{PlayerID: 5, SubbedOn: true, Minutes: 20,
+		Spells: []events.ClockSpan{secondHalf(75, 95)}}, appearances[4])
+	assert.Equal(t, Appearance{PlayerID: 6}, appearances[5])
+	assert.False(t, appearances[5].Played())
+}
+
+func TestNormalizeFormation(t *testing.T) {
+	for input, want := range map[string]string{
+		"433":     "4-3-3",
+		"4-2-3-1": "4-2-3-1",
+		"3 5 2":   "3-5-2",
+	} {
+		got, ok := NormalizeFormation(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "44", "4-4-3", "four-four-two"} {
+		_, ok := NormalizeFormation(input)
+		assert.False(t, ok, input)
+	}
+}
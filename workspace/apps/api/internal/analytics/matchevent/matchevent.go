@@ -0,0 +1,44 @@
+// Package matchevent is the stored match event as the event-log analytics read
+// it: who acted, when, where and with which provider metadata.
+package matchevent
+
+import (
+	"encoding/json"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+// Event is a stored match event. Coordinates are in the frame recorded in the
+// metadata, or the provider's own for events stored before normalization.
+type Event struct {
+	ID       int32
+	TeamID   *int32
+	PlayerID *int32
+	// SecondaryPlayerID is a pass's recipient or a goal's assister, when the
+	// provider sends one
+	SecondaryPlayerID *int32
+	EventType         string
+	Clock             events.MatchClock
+	X, Y              *float64
+	Meta              map[string]interface{}
+}
+
+// FromModel converts a stored match event, decoding its metadata.
+func FromModel(e *models.MatchEvent) Event {
+	var meta map[string]interface{}
+	if len(e.Metadata) > 0 {
+		_ = json.Unmarshal(e.Metadata, &meta)
+	}
+	return Event{
+		ID:                e.ID,
+		TeamID:            e.TeamID,
+		PlayerID:          e.PlayerID,
+		SecondaryPlayerID: e.SecondaryPlayerID,
+		EventType:         e.EventType,
+		Clock:             e.Clock(),
+		X:                 e.PositionX,
+		Y:                 e.PositionY,
+		Meta:              meta,
+	}
+}
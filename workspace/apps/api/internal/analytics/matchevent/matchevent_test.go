@@ -0,0 +1,41 @@
+package matchevent
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+func TestFromModel(t *testing.T) {
+	team, player, receiver := int32(1), int32(9), int32(10)
+	x, y := 80.0, 30.0
+	e := FromModel(&models.MatchEvent{
+		ID:                5,
+		TeamID:            &team,
+		PlayerID:          &player,
+		SecondaryPlayerID: &receiver,
+		EventType:         "pass",
+		Minute:            12,
+		Period:            string(events.PeriodFirstHalf),
+		PeriodNumber:      1,
+		ClockMs:           12 * 60000,
+		PositionX:         &x,
+		PositionY:         &y,
+		Metadata:          json.RawMessage(`{"pass_end_x": 95, "outcome": "Complete"}`),
+	})
+
+	assert.Equal(t, int32(5), e.ID)
+	assert.Equal(t, &receiver, e.SecondaryPlayerID)
+	assert.Equal(t, events.PeriodFirstHalf, e.Clock.Period)
+	assert.Equal(t, int64(12*60000), e.Clock.Ms)
+	assert.Equal(t, 80.0, *e.X)
+	assert.Equal(t, 95.0, e.Meta["pass_end_x"])
+
+	// Events without metadata, or with metadata that is not an object, have none
+	assert.Nil(t, FromModel(&models.MatchEvent{EventType: "kick_off"}).Meta)
+	assert.Nil(t, FromModel(&models.MatchEvent{Metadata: json.RawMessage(`"x"`)}).Meta)
+}
@@ -0,0 +1,33 @@
+// Package matcheventtest builds match events for the analytics tests.
+package matcheventtest
+
+import (
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Ptr returns a pointer to v.
+func Ptr[T any](v T) *T { return &v }
+
+// New builds an event by a team's player at a second of the first half, with
+// locations in canonical meters.
+func New(id int32, eventType string, teamID, playerID int32, second int32) matchevent.Event {
+	return matchevent.Event{
+		ID:        id,
+		TeamID:    Ptr(teamID),
+		PlayerID:  Ptr(playerID),
+		EventType: eventType,
+		Clock:     events.NewMatchClock(events.PeriodFirstHalf, 10, 0, second),
+		Meta:      map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical},
+	}
+}
+
+// At places an event at a point in canonical meters, with an optional end location.
+func At(e matchevent.Event, x, y float64, end ...float64) matchevent.Event {
+	e.X, e.Y = Ptr(x), Ptr(y)
+	if len(end) == 2 {
+		e.Meta["pass_end_x"], e.Meta["pass_end_y"] = end[0], end[1]
+	}
+	return e
+}
@@ -0,0 +1,212 @@
+// Package passnetwork builds a team's pass network for a match: each player's
+// average position and the number of completed passes between each pair.
+package passnetwork
+
+import (
+	"math"
+	"sort"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Options filters the passes in a network. The zero value covers the whole match.
+type Options struct {
+	// Period limits passes to one period; empty keeps all periods
+	Period events.Period
+	// GameState limits passes to those made while the team was winning, drawing or losing
+	GameState gamestate.State
+	// FromMs and ToMs bound the match clock in milliseconds; ToMs 0 leaves the end open
+	FromMs, ToMs int64
+	// UntilFirstSubstitution stops at the team's first substitution, so the
+	// network shows a single XI
+	UntilFirstSubstitution bool
+	// MinPasses drops edges with fewer passes
+	MinPasses int
+}
+
+// Node is a player in the network. X and Y are the average canonical position of
+// the player's passes and receptions, nil when none had coordinates.
+type Node struct {
+	PlayerID int32    `json:"player_id"`
+	X        *float64 `json:"x,omitempty"`
+	Y        *float64 `json:"y,omitempty"`
+	Passes   int      `json:"passes"`   // Completed passes made
+	Received int      `json:"received"` // Completed passes received
+}
+
+// Edge is the number of completed passes from one player to another.
+type Edge struct {
+	From   int32 `json:"from"`
+	To     int32 `json:"to"`
+	Passes int   `json:"passes"`
+}
+
+// Network is a team's pass network.
+type Network struct {
+	TeamID int32 `json:"team_id"`
+	Passes int   `json:"passes"` // Completed passes in the network
+	// FirstSubstitution is the clock of the team's first substitution, if any
+	FirstSubstitution *events.MatchClock `json:"first_substitution,omitempty"`
+	Nodes             []Node             `json:"nodes"`
+	Edges             []Edge             `json:"edges"`
+}
+
+type position struct {
+	sumX, sumY float64
+	n          int
+}
+
+func (p *position) add(x, y float64) {
+	p.sumX += x
+	p.sumY += y
+	p.n++
+}
+
+// Build computes a team's pass network from a match's events. Events must be in
+// match clock order so the score, and with it the game state, is known at each pass.
+func Build(matchEvents []matchevent.Event, teamID int32, opts Options) Network {
+	network := Network{TeamID: teamID, Nodes: []Node{}, Edges: []Edge{}}
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.TeamID != nil && *e.TeamID == teamID && events.Normalize(e.EventType).IsSubstitution() {
+			clock := e.Clock
+			network.FirstSubstitution = &clock
+			break
+		}
+	}
+
+	nodes := make(map[int32]*Node)
+	positions := make(map[int32]*position)
+	edges := make(map[[2]int32]int)
+	node := func(playerID int32) *Node {
+		if nodes[playerID] == nil {
+			nodes[playerID] = &Node{PlayerID: playerID}
+			positions[playerID] = &position{}
+		}
+		return nodes[playerID]
+	}
+
+	var score gamestate.Tracker
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		state := score.State(teamID)
+		if score.Apply(e.EventType, e.TeamID, e.Clock.Period) {
+			continue
+		}
+		if !opts.includes(e, teamID, state, network.FirstSubstitution) {
+			continue
+		}
+
+		pass, ok := passFromEvent(e)
+		if !ok {
+			continue
+		}
+		network.Passes++
+
+		passer := node(*e.PlayerID)
+		passer.Passes++
+		if pass.hasStart {
+			positions[*e.PlayerID].add(pass.startX, pass.startY)
+		}
+		receiver := node(*e.SecondaryPlayerID)
+		receiver.Received++
+		if pass.hasEnd {
+			positions[*e.SecondaryPlayerID].add(pass.endX, pass.endY)
+		}
+		edges[[2]int32{*e.PlayerID, *e.SecondaryPlayerID}]++
+	}
+
+	for playerID, n := range nodes {
+		if p := positions[playerID]; p.n > 0 {
+			x := round1(p.sumX / float64(p.n))
+			y := round1(p.sumY / float64(p.n))
+			n.X, n.Y = &x, &y
+		}
+		network.Nodes = append(network.Nodes, *n)
+	}
+	sort.Slice(network.Nodes, func(i, j int) bool {
+		return network.Nodes[i].PlayerID < network.Nodes[j].PlayerID
+	})
+
+	for pair, passes := range edges {
+		if passes >= opts.MinPasses {
+			network.Edges = append(network.Edges, Edge{From: pair[0], To: pair[1], Passes: passes})
+		}
+	}
+	sort.Slice(network.Edges, func(i, j int) bool {
+		a, b := network.Edges[i], network.Edges[j]
+		if a.Passes != b.Passes {
+			return a.Passes > b.Passes
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return network
+}
+
+// includes reports whether an event by the team falls within the filters.
+func (o Options) includes(e *matchevent.Event, teamID int32, state gamestate.State, firstSub *events.MatchClock) bool {
+	if e.TeamID == nil || *e.TeamID != teamID {
+		return false
+	}
+	if o.Period != "" && e.Clock.Period != o.Period {
+		return false
+	}
+	if o.GameState != "" && state != o.GameState {
+		return false
+	}
+	if e.Clock.Ms < o.FromMs || (o.ToMs > 0 && e.Clock.Ms >= o.ToMs) {
+		return false
+	}
+	if o.UntilFirstSubstitution && firstSub != nil && !e.Clock.Before(*firstSub) {
+		return false
+	}
+	return true
+}
+
+// pass is a completed pass in canonical meters.
+type pass struct {
+	startX, startY float64
+	endX, endY     float64
+	hasStart       bool
+	hasEnd         bool
+}
+
+// passFromEvent returns a completed pass between two known players. The start is
+// the event position and the end the pass end location, when present.
+func passFromEvent(e *matchevent.Event) (pass, bool) {
+	if e.PlayerID == nil || e.SecondaryPlayerID == nil || *e.PlayerID == *e.SecondaryPlayerID {
+		return pass{}, false
+	}
+	if !events.Normalize(e.EventType).IsPass() || !xt.Completed(e.EventType, e.Meta) {
+		return pass{}, false
+	}
+
+	provider, _ := e.Meta[xg.MetaProvider].(string)
+	frame := pitch.FrameFor(e.Meta, provider)
+	if move, ok := xt.MoveFromEvent(e.EventType, e.X, e.Y, e.Meta, frame); ok {
+		return pass{
+			startX: move.StartX, startY: move.StartY,
+			endX: move.EndX, endY: move.EndY,
+			hasStart: true, hasEnd: true,
+		}, true
+	}
+
+	var p pass
+	if e.X != nil && e.Y != nil {
+		p.startX, p.startY = frame.Meters(*e.X, *e.Y)
+		p.hasStart = true
+	}
+	return p, true
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
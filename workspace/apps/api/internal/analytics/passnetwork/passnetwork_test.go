@@ -0,0 +1,57 @@
+package passnetwork
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+func TestBuild(t *testing.T) {
+	home, away := int32(1), int32(2)
+	p7, p8, p9, p10 := int32(7), int32(8), int32(9), int32(10)
+	canonical := func(endX, endY float64) map[string]interface{} {
+		return map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical, "end_x": endX, "end_y": endY}
+	}
+	at := func(minute int32) events.MatchClock {
+		return events.NewMatchClock(events.PeriodRegular, minute, 0, 0)
+	}
+	x1, y1, x2, y2 := 30.0, 20.0, 50.0, 40.0
+
+	matchEvents := []matchevent.Event{
+		{TeamID: &home, PlayerID: &p7, SecondaryPlayerID: &p8, EventType: "pass", Clock: at(5), X: &x1, Y: &y1, Meta: canonical(40, 30)},
+		{TeamID: &home, PlayerID: &p8, SecondaryPlayerID: &p7, EventType: "pass", Clock: at(6), X: &x2, Y: &y2, Meta: canonical(30, 20)},
+		{TeamID: &home, PlayerID: &p7, SecondaryPlayerID: &p8, EventType: "pass", Clock: at(7), Meta: map[string]interface{}{"completed": false}},
+		{TeamID: &away, PlayerID: &p10, SecondaryPlayerID: &p10, EventType: "pass", Clock: at(8)},
+		{TeamID: &away, PlayerID: &p10, EventType: "goal", Clock: at(20)},
+		{TeamID: &home, PlayerID: &p7, SecondaryPlayerID: &p8, EventType: "pass", Clock: at(25), X: &x1, Y: &y1, Meta: canonical(40, 30)},
+		{TeamID: &home, PlayerID: &p9, EventType: "substitution", Clock: at(60)},
+		{TeamID: &home, PlayerID: &p9, SecondaryPlayerID: &p7, EventType: "pass", Clock: at(61), Meta: canonical(30, 20)},
+	}
+
+	network := Build(matchEvents, home, Options{UntilFirstSubstitution: true})
+	assert.Equal(t, 3, network.Passes)
+	require.NotNil(t, network.FirstSubstitution)
+	assert.Equal(t, int32(60), network.FirstSubstitution.Minute)
+	require.Len(t, network.Nodes, 2)
+	assert.Equal(t, Node{PlayerID: p7, X: ptr(30), Y: ptr(20), Passes: 2, Received: 1}, network.Nodes[0])
+	assert.Equal(t, Edge{From: p7, To: p8, Passes: 2}, network.Edges[0])
+	assert.Equal(t, Edge{From: p8, To: p7, Passes: 1}, network.Edges[1])
+
+	losing := Build(matchEvents, home, Options{GameState: gamestate.Losing, MinPasses: 1})
+	assert.Equal(t, 2, losing.Passes)
+	assert.Len(t, losing.Nodes, 3)
+
+	firstWindow := Build(matchEvents, home, Options{ToMs: 10 * 60000, MinPasses: 2})
+	assert.Equal(t, 2, firstWindow.Passes)
+	assert.Empty(t, firstWindow.Edges)
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
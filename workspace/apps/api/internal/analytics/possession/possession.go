@@ -0,0 +1,283 @@
+// Package possession reconstructs possessions from a match's event log and
+// computes possession-based team statistics.
+//
+// A possession is an unbroken spell of one team on the ball. It starts with the
+// team's first controlled action (pass, carry, shot, set piece or ball winning
+// action) and ends when the opponent controls the ball (a turnover), at a
+// stoppage (foul, offside, card, substitution, VAR review or goal) or at the end
+// of a period. Opponent actions that do not win the ball, such as a lost tackle,
+// belong to the possession they happen in.
+package possession
+
+import (
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Possession outcomes.
+const (
+	OutcomeGoal      = "goal"
+	OutcomeOwnGoal   = "own_goal" // Conceded by the opponent
+	OutcomeShot      = "shot"     // Lost after a shot
+	OutcomeTurnover  = "turnover"
+	OutcomeStoppage  = "stoppage"
+	OutcomePeriodEnd = "period_end"
+)
+
+// Start zones: the third of the pitch where a possession's first located
+// action took place.
+const (
+	ZoneDefensive = "defensive"
+	ZoneMiddle    = "middle"
+	ZoneAttacking = "attacking"
+)
+
+// winEventTypes are defensive actions that win the ball for the team making them.
+var winEventTypes = map[events.EventType]bool{
+	events.EventTypeInterception:  true,
+	events.EventTypeTackleWon:     true,
+	events.EventTypeClaim:         true,
+	events.EventTypeSweeperKeeper: true,
+}
+
+// periodEndEventTypes mark the end of a period.
+var periodEndEventTypes = map[events.EventType]bool{
+	events.EventTypeHalfTime:        true,
+	events.EventTypeFullTime:        true,
+	events.EventTypeExtraTime:       true,
+	events.EventTypePenaltyShootout: true,
+}
+
+// Possession is a reconstructed possession. StartX and EndX are canonical meters
+// from the team's own goal line: where its first located action started and its
+// last located action ended.
+type Possession struct {
+	ID         int32             `json:"id"` // Numbered from 1 within the match
+	TeamID     int32             `json:"team_id"`
+	Start      events.MatchClock `json:"start"`
+	End        events.MatchClock `json:"end"`
+	DurationMs int64             `json:"duration_ms"` // Until the next possession starts, or the last event in the period
+	Events     int               `json:"events"`      // The team's events
+	Passes     int               `json:"passes"`
+	Shots      int               `json:"shots"`
+	XG         float64           `json:"xg"`
+	StartZone  string            `json:"start_zone,omitempty"`
+	StartX     *float64          `json:"start_x,omitempty"`
+	EndX       *float64          `json:"end_x,omitempty"`
+	Outcome    string            `json:"outcome"`
+	EventIDs   []int32           `json:"-"` // Every event in the possession, including the opponent's
+}
+
+// Reconstruct groups a match's events, in match clock order, into possessions.
+// Penalty shootout kicks are not part of any possession.
+func Reconstruct(matchEvents []matchevent.Event) []Possession {
+	r := reconstructor{possessions: []Possession{}}
+	for i := range matchEvents {
+		r.apply(&matchEvents[i])
+	}
+	r.close(OutcomePeriodEnd)
+
+	for i := range r.possessions {
+		p := &r.possessions[i]
+		end := p.End
+		if i+1 < len(r.possessions) && r.possessions[i+1].Start.PeriodNumber() == p.Start.PeriodNumber() {
+			end = r.possessions[i+1].Start
+		}
+		if end.Ms > p.Start.Ms {
+			p.DurationMs = end.Ms - p.Start.Ms
+		}
+		p.XG = round4(p.XG)
+	}
+	return r.possessions
+}
+
+// Assignments returns the IDs of the events in possessions and the possession
+// each belongs to, as parallel slices.
+func Assignments(possessions []Possession) (eventIDs, possessionIDs []int32) {
+	for _, p := range possessions {
+		for _, id := range p.EventIDs {
+			eventIDs = append(eventIDs, id)
+			possessionIDs = append(possessionIDs, p.ID)
+		}
+	}
+	return eventIDs, possessionIDs
+}
+
+// reconstructor holds the possession being built.
+type reconstructor struct {
+	possessions []Possession
+	current     *Possession
+	lastShot    bool // The current team's last action was a shot
+}
+
+func (r *reconstructor) apply(e *matchevent.Event) {
+	t := events.Normalize(e.EventType)
+	if e.Clock.Period == events.PeriodPenalties {
+		return
+	}
+	if r.current != nil && e.Clock.PeriodNumber() != r.current.Start.PeriodNumber() {
+		r.close(OutcomePeriodEnd)
+	}
+	if periodEndEventTypes[t] {
+		r.include(e)
+		r.close(OutcomePeriodEnd)
+		return
+	}
+	if e.TeamID == nil {
+		r.include(e)
+		return
+	}
+	teamID := *e.TeamID
+
+	switch {
+	case t == events.EventTypeOwnGoal:
+		r.include(e)
+		r.close(OutcomeOwnGoal)
+		return
+	case stoppage(t):
+		r.include(e)
+		r.close(OutcomeStoppage)
+		return
+	case controls(t):
+		if r.current == nil || r.current.TeamID != teamID {
+			r.close(r.turnoverOutcome())
+			r.start(e, teamID)
+		}
+	default:
+		// Actions that do not win the ball stay in the current possession
+		r.include(e)
+		if r.current != nil && r.current.TeamID == teamID {
+			r.current.Events++
+			r.locate(e, t)
+		}
+		return
+	}
+
+	p := r.current
+	p.EventIDs = append(p.EventIDs, e.ID)
+	p.End = e.Clock
+	p.Events++
+	r.locate(e, t)
+	r.lastShot = false
+
+	switch {
+	case t.IsPass():
+		p.Passes++
+	case xg.IsShotEvent(e.EventType):
+		p.Shots++
+		xG, _ := events.MetaFloat(e.Meta, xg.MetaXG, "xg")
+		p.XG += xG
+		r.lastShot = true
+		if xg.IsGoal(e.EventType, e.Meta) {
+			r.close(OutcomeGoal)
+		}
+	}
+}
+
+// start opens a possession for a team with its first controlled action.
+func (r *reconstructor) start(e *matchevent.Event, teamID int32) {
+	r.possessions = append(r.possessions, Possession{
+		ID:     int32(len(r.possessions) + 1),
+		TeamID: teamID,
+		Start:  e.Clock,
+		End:    e.Clock,
+	})
+	r.current = &r.possessions[len(r.possessions)-1]
+	r.lastShot = false
+}
+
+// include adds an event to the current possession without crediting the team.
+func (r *reconstructor) include(e *matchevent.Event) {
+	if r.current == nil {
+		return
+	}
+	r.current.EventIDs = append(r.current.EventIDs, e.ID)
+	if r.current.End.Before(e.Clock) {
+		r.current.End = e.Clock
+	}
+}
+
+// close ends the current possession.
+func (r *reconstructor) close(outcome string) {
+	if r.current == nil {
+		return
+	}
+	r.current.Outcome = outcome
+	r.current = nil
+	r.lastShot = false
+}
+
+// turnoverOutcome is the outcome of a possession lost to the opponent.
+func (r *reconstructor) turnoverOutcome() string {
+	if r.lastShot {
+		return OutcomeShot
+	}
+	return OutcomeTurnover
+}
+
+// locate records where the possession started and how far it got.
+func (r *reconstructor) locate(e *matchevent.Event, t events.EventType) {
+	if e.X == nil || e.Y == nil {
+		return
+	}
+	provider, _ := e.Meta[xg.MetaProvider].(string)
+	frame := pitch.FrameFor(e.Meta, provider)
+	x, _ := frame.Meters(*e.X, *e.Y)
+	end := x
+	if t.IsPass() || t.IsCarry() {
+		if move, ok := xt.MoveFromEvent(e.EventType, e.X, e.Y, e.Meta, frame); ok && move.Successful {
+			end = move.EndX
+		}
+	}
+
+	p := r.current
+	if p.StartX == nil {
+		p.StartX = &x
+		p.StartZone = zone(x)
+	}
+	p.EndX = &end
+}
+
+// controls reports whether an event type means the team has the ball.
+func controls(t events.EventType) bool {
+	switch {
+	case t.IsPass(), t.IsCarry(), t.IsShot():
+		return true
+	case t.IsGoal():
+		return t != events.EventTypeOwnGoal
+	}
+	return t == events.EventTypeCorner || winEventTypes[t]
+}
+
+// stoppage reports whether an event type stops play and ends the possession.
+func stoppage(t events.EventType) bool {
+	switch events.GetCategory(t) {
+	case events.CategoryFoul, events.CategoryCard, events.CategorySubstitution, events.CategoryVar:
+		return true
+	}
+	return false
+}
+
+func zone(x float64) string {
+	switch {
+	case x < pitch.Length/3:
+		return ZoneDefensive
+	case x < 2*pitch.Length/3:
+		return ZoneMiddle
+	default:
+		return ZoneAttacking
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,69 @@
+package possession
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+func TestReconstruct(t *testing.T) {
+	home, away := int32(1), int32(2)
+	at := func(period events.Period, minute, second int32) events.MatchClock {
+		return events.NewMatchClock(period, minute, 0, second)
+	}
+	canonical := map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical}
+	x := func(v float64) *float64 { return &v }
+	y := x(34)
+
+	matchEvents := []matchevent.Event{
+		{ID: 1, EventType: "kick_off", Clock: at(events.PeriodFirstHalf, 0, 0)},
+		{ID: 2, TeamID: &home, EventType: "pass", Clock: at(events.PeriodFirstHalf, 0, 1), X: x(52), Y: y, Meta: canonical},
+		{ID: 3, TeamID: &home, EventType: "pass", Clock: at(events.PeriodFirstHalf, 0, 5), X: x(60), Y: y, Meta: canonical},
+		{ID: 4, TeamID: &away, EventType: "tackle_lost", Clock: at(events.PeriodFirstHalf, 0, 8)},
+		{ID: 5, TeamID: &home, EventType: "shot", Clock: at(events.PeriodFirstHalf, 0, 10), X: x(92), Y: y, Meta: map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical, "xG": 0.12}},
+		{ID: 6, TeamID: &away, EventType: "claim", Clock: at(events.PeriodFirstHalf, 0, 12), X: x(3), Y: y, Meta: canonical},
+		{ID: 7, TeamID: &away, EventType: "pass", Clock: at(events.PeriodFirstHalf, 0, 20), X: x(10), Y: y, Meta: canonical},
+		{ID: 8, TeamID: &away, EventType: "foul_won", Clock: at(events.PeriodFirstHalf, 0, 30)},
+		{ID: 9, TeamID: &away, EventType: "pass", Clock: at(events.PeriodFirstHalf, 0, 40), X: x(40), Y: y, Meta: canonical},
+		{ID: 10, TeamID: &away, EventType: "goal", Clock: at(events.PeriodFirstHalf, 1, 0), X: x(95), Y: y, Meta: map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical, "xG": 0.4}},
+		{ID: 11, TeamID: &home, EventType: "pass", Clock: at(events.PeriodSecondHalf, 45, 0), X: x(52), Y: y, Meta: canonical},
+	}
+
+	possessions := Reconstruct(matchEvents)
+	require.Len(t, possessions, 4)
+
+	first := possessions[0]
+	assert.Equal(t, int32(1), first.ID)
+	assert.Equal(t, home, first.TeamID)
+	assert.Equal(t, []int32{2, 3, 4, 5}, first.EventIDs)
+	assert.Equal(t, 2, first.Passes)
+	assert.Equal(t, 1, first.Shots)
+	assert.Equal(t, 0.12, first.XG)
+	assert.Equal(t, ZoneMiddle, first.StartZone)
+	assert.Equal(t, OutcomeShot, first.Outcome)
+	assert.Equal(t, int64(11000), first.DurationMs)
+
+	assert.Equal(t, away, possessions[1].TeamID)
+	assert.Equal(t, OutcomeStoppage, possessions[1].Outcome)
+	assert.Equal(t, []int32{6, 7, 8}, possessions[1].EventIDs)
+
+	assert.Equal(t, OutcomeGoal, possessions[2].Outcome)
+	assert.Equal(t, int64(20000), possessions[2].DurationMs)
+
+	assert.Equal(t, home, possessions[3].TeamID)
+	assert.Equal(t, OutcomePeriodEnd, possessions[3].Outcome)
+
+	stats := Summarize(possessions, home, away)
+	require.Len(t, stats, 2)
+	assert.Equal(t, 2, stats[0].Possessions)
+	assert.Equal(t, 1.5, stats[0].PassesPerPossession)
+	assert.Equal(t, 1, stats[1].Goals)
+	assert.Equal(t, 0.4, stats[1].XG)
+	assert.InDelta(t, 100, stats[0].Possession+stats[1].Possession, 0.01)
+	assert.Equal(t, 3.64, stats[0].DirectSpeed) // (92 - 52) m in 11 s
+}
@@ -0,0 +1,85 @@
+package possession
+
+// TeamStats are a team's possession-based statistics.
+type TeamStats struct {
+	TeamID      int32 `json:"team_id"`
+	Possessions int   `json:"possessions"`
+	// Possession is the team's share of time in possession, in percent. It falls
+	// back to the share of possessions when the event log has no timing.
+	Possession          float64 `json:"possession"`
+	AvgDurationSeconds  float64 `json:"avg_duration_seconds"`
+	PassesPerPossession float64 `json:"passes_per_possession"`
+	// DirectSpeed is the distance gained towards the opponent's goal per second
+	// in possession, in m/s, over possessions with a known start and end
+	DirectSpeed        float64 `json:"direct_speed"`
+	Shots              int     `json:"shots"`
+	ShotsPerPossession float64 `json:"shots_per_possession"`
+	XG                 float64 `json:"xg"`
+	XGPerPossession    float64 `json:"xg_per_possession"`
+	Goals              int     `json:"goals"`
+	// StartZones counts possessions by the third they started in
+	StartZones map[string]int `json:"start_zones"`
+	// Outcomes counts possessions by how they ended
+	Outcomes map[string]int `json:"outcomes"`
+}
+
+// Summarize computes possession statistics for each team from a match's
+// possessions.
+func Summarize(possessions []Possession, teamIDs ...int32) []TeamStats {
+	var totalMs int64
+	for _, p := range possessions {
+		totalMs += p.DurationMs
+	}
+
+	stats := make([]TeamStats, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		s := TeamStats{
+			TeamID:     teamID,
+			StartZones: map[string]int{ZoneDefensive: 0, ZoneMiddle: 0, ZoneAttacking: 0},
+			Outcomes:   map[string]int{},
+		}
+		var durationMs, directMs int64
+		var passes int
+		var gained float64
+		for _, p := range possessions {
+			if p.TeamID != teamID {
+				continue
+			}
+			s.Possessions++
+			durationMs += p.DurationMs
+			passes += p.Passes
+			s.Shots += p.Shots
+			s.XG += p.XG
+			if p.Outcome == OutcomeGoal {
+				s.Goals++
+			}
+			if p.StartZone != "" {
+				s.StartZones[p.StartZone]++
+			}
+			s.Outcomes[p.Outcome]++
+			if p.StartX != nil && p.EndX != nil && p.DurationMs > 0 {
+				gained += *p.EndX - *p.StartX
+				directMs += p.DurationMs
+			}
+		}
+
+		if totalMs > 0 {
+			s.Possession = round2(float64(durationMs) / float64(totalMs) * 100)
+		} else if len(possessions) > 0 {
+			s.Possession = round2(float64(s.Possessions) / float64(len(possessions)) * 100)
+		}
+		if s.Possessions > 0 {
+			n := float64(s.Possessions)
+			s.AvgDurationSeconds = round2(float64(durationMs) / 1000 / n)
+			s.PassesPerPossession = round2(float64(passes) / n)
+			s.ShotsPerPossession = round4(float64(s.Shots) / n)
+			s.XGPerPossession = round4(s.XG / n)
+		}
+		if directMs > 0 {
+			s.DirectSpeed = round2(gained / (float64(directMs) / 1000))
+		}
+		s.XG = round4(s.XG)
+		stats = append(stats, s)
+	}
+	return stats
+}
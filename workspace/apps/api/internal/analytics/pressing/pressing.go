@@ -0,0 +1,177 @@
+// Package pressing computes a team's pressing intensity from a match's event log
+// and its reconstructed possessions.
+//
+// Coordinates are canonical meters from the acting team's own goal line, so a
+// defensive action at x = 80 is 25 m from the goal the team attacks and an
+// opponent pass at x = 20 is deep in the opponent's own half.
+package pressing
+
+import (
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+const (
+	// PressingZoneX is where the opponent's 60% of the pitch begins: PPDA counts
+	// defensive actions from here and opponent passes short of Length - PressingZoneX
+	PressingZoneX = 0.4 * pitch.Length
+	// HighTurnoverDistance is how close to the opponent's goal, in meters, a
+	// possession won from open play has to start to count as a high turnover
+	HighTurnoverDistance = 40.0
+	// FinalThirdX is where the final third begins
+	FinalThirdX = 2 * pitch.Length / 3
+	// CounterPressWindowMs is how soon after losing the ball a team has to win it
+	// back for a counter-press regain
+	CounterPressWindowMs = 5000
+)
+
+// defensiveActionTypes are the defensive actions PPDA counts.
+var defensiveActionTypes = map[events.EventType]bool{
+	events.EventTypeTackle:        true,
+	events.EventTypeTackleWon:     true,
+	events.EventTypeTackleLost:    true,
+	events.EventTypeInterception:  true,
+	events.EventTypeFoulCommitted: true,
+	events.EventTypeDuelWon:       true,
+}
+
+// recoveryTypes are the defensive actions that win the ball.
+var recoveryTypes = map[events.EventType]bool{
+	events.EventTypeTackleWon:    true,
+	events.EventTypeInterception: true,
+	events.EventTypeDuelWon:      true,
+}
+
+// TeamStats are a team's pressing statistics over one or more matches.
+type TeamStats struct {
+	TeamID  int32 `json:"team_id"`
+	Matches int   `json:"matches"`
+	// OpponentPasses are the opponent's passes in its own 60% of the pitch
+	OpponentPasses int `json:"opponent_passes"`
+	// DefensiveActions are tackles, interceptions, fouls and won duels in the
+	// opponent's 60% of the pitch
+	DefensiveActions int `json:"defensive_actions"`
+	// PPDA is opponent passes allowed per defensive action; lower means a more
+	// intense press. It is zero when the team made no defensive actions there.
+	PPDA float64 `json:"ppda"`
+	// HighTurnovers are possessions won from the opponent in open play that
+	// started within HighTurnoverDistance of the opponent's goal
+	HighTurnovers int `json:"high_turnovers"`
+	// FinalThirdRecoveries are ball-winning actions in the final third
+	FinalThirdRecoveries int `json:"final_third_recoveries"`
+	// BallLosses are possessions lost to the opponent in open play
+	BallLosses int `json:"ball_losses"`
+	// CounterPressRegains are ball losses won back within CounterPressWindowMs
+	CounterPressRegains int `json:"counter_press_regains"`
+	// CounterPressRate is the share of ball losses won back, in percent
+	CounterPressRate float64 `json:"counter_press_rate"`
+}
+
+// Compute computes pressing statistics for each team from a match's events, in
+// match clock order, and the possessions reconstructed from them.
+func Compute(matchEvents []matchevent.Event, possessions []possession.Possession, teamIDs ...int32) []TeamStats {
+	stats := make([]TeamStats, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		s := TeamStats{TeamID: teamID, Matches: 1}
+		countEvents(&s, matchEvents)
+		countPossessions(&s, possessions)
+		s.finish()
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// Add adds another match's or season's statistics for the same team.
+func (s *TeamStats) Add(o TeamStats) {
+	s.Matches += o.Matches
+	s.OpponentPasses += o.OpponentPasses
+	s.DefensiveActions += o.DefensiveActions
+	s.HighTurnovers += o.HighTurnovers
+	s.FinalThirdRecoveries += o.FinalThirdRecoveries
+	s.BallLosses += o.BallLosses
+	s.CounterPressRegains += o.CounterPressRegains
+	s.finish()
+}
+
+// finish computes the ratios from the counts.
+func (s *TeamStats) finish() {
+	s.PPDA = 0
+	if s.DefensiveActions > 0 {
+		s.PPDA = round2(float64(s.OpponentPasses) / float64(s.DefensiveActions))
+	}
+	s.CounterPressRate = 0
+	if s.BallLosses > 0 {
+		s.CounterPressRate = round2(float64(s.CounterPressRegains) / float64(s.BallLosses) * 100)
+	}
+}
+
+// countEvents counts the PPDA inputs and final third recoveries.
+func countEvents(s *TeamStats, matchEvents []matchevent.Event) {
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.TeamID == nil || e.Clock.Period == events.PeriodPenalties {
+			continue
+		}
+		x, ok := meters(e)
+		if !ok {
+			continue
+		}
+		t := events.Normalize(e.EventType)
+		if *e.TeamID != s.TeamID {
+			if t.IsPass() && x < pitch.Length-PressingZoneX {
+				s.OpponentPasses++
+			}
+			continue
+		}
+		if defensiveActionTypes[t] && x >= PressingZoneX {
+			s.DefensiveActions++
+		}
+		if recoveryTypes[t] && x >= FinalThirdX {
+			s.FinalThirdRecoveries++
+		}
+	}
+}
+
+// countPossessions counts high turnovers, ball losses and counter-press regains.
+// A turnover outcome means the next possession is the opponent's and started in
+// the same period.
+func countPossessions(s *TeamStats, possessions []possession.Possession) {
+	for i := range possessions {
+		p := &possessions[i]
+		if p.TeamID != s.TeamID {
+			continue
+		}
+		if i > 0 && possessions[i-1].Outcome == possession.OutcomeTurnover &&
+			p.StartX != nil && *p.StartX >= pitch.Length-HighTurnoverDistance {
+			s.HighTurnovers++
+		}
+		if p.Outcome != possession.OutcomeTurnover || i+1 == len(possessions) {
+			continue
+		}
+		s.BallLosses++
+		lost := &possessions[i+1]
+		if lost.Outcome == possession.OutcomeTurnover && i+2 < len(possessions) &&
+			possessions[i+2].Start.Ms-lost.Start.Ms <= CounterPressWindowMs {
+			s.CounterPressRegains++
+		}
+	}
+}
+
+// meters returns an event's canonical x coordinate.
+func meters(e *matchevent.Event) (float64, bool) {
+	if e.X == nil || e.Y == nil {
+		return 0, false
+	}
+	provider, _ := e.Meta[xg.MetaProvider].(string)
+	x, _ := pitch.FrameFor(e.Meta, provider).Meters(*e.X, *e.Y)
+	return x, true
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,62 @@
+package pressing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+func TestCompute(t *testing.T) {
+	home, away := int32(1), int32(2)
+	at := func(second int32) events.MatchClock {
+		return events.NewMatchClock(events.PeriodFirstHalf, 0, 0, second)
+	}
+	canonical := map[string]interface{}{pitch.MetaCoordinates: pitch.Canonical}
+	x := func(v float64) *float64 { return &v }
+	y := x(34)
+
+	matchEvents := []matchevent.Event{
+		{ID: 1, TeamID: &away, EventType: "pass", Clock: at(1), X: x(20), Y: y, Meta: canonical},
+		{ID: 2, TeamID: &away, EventType: "pass", Clock: at(3), X: x(30), Y: y, Meta: canonical},
+		{ID: 3, TeamID: &home, EventType: "tackle_lost", Clock: at(4), X: x(70), Y: y, Meta: canonical},
+		{ID: 4, TeamID: &away, EventType: "pass", Clock: at(5), X: x(50), Y: y, Meta: canonical},
+		// High turnover: won 30 m from the opponent's goal
+		{ID: 5, TeamID: &home, EventType: "interception", Clock: at(6), X: x(75), Y: y, Meta: canonical},
+		{ID: 6, TeamID: &home, EventType: "pass", Clock: at(8), X: x(78), Y: y, Meta: canonical},
+		// Lost at 10 s and won back at 13 s
+		{ID: 7, TeamID: &away, EventType: "interception", Clock: at(10), X: x(25), Y: y, Meta: canonical},
+		{ID: 8, TeamID: &home, EventType: "tackle_won", Clock: at(13), X: x(82), Y: y, Meta: canonical},
+		{ID: 9, TeamID: &home, EventType: "pass", Clock: at(15), X: x(40), Y: y, Meta: canonical},
+		// Lost at 20 s and not won back until 40 s
+		{ID: 10, TeamID: &away, EventType: "pass", Clock: at(20), X: x(70), Y: y, Meta: canonical},
+		{ID: 11, TeamID: &home, EventType: "interception", Clock: at(40), X: x(30), Y: y, Meta: canonical},
+	}
+
+	possessions := possession.Reconstruct(matchEvents)
+	stats := Compute(matchEvents, possessions, home, away)
+	require.Len(t, stats, 2)
+
+	s := stats[0]
+	assert.Equal(t, home, s.TeamID)
+	assert.Equal(t, 1, s.Matches)
+	assert.Equal(t, 3, s.OpponentPasses) // The pass at x = 70 is in the opponent's attacking 40%
+	assert.Equal(t, 3, s.DefensiveActions)
+	assert.Equal(t, 1.0, s.PPDA)
+	assert.Equal(t, 2, s.HighTurnovers)
+	assert.Equal(t, 2, s.FinalThirdRecoveries)
+	assert.Equal(t, 2, s.BallLosses)
+	assert.Equal(t, 1, s.CounterPressRegains)
+	assert.Equal(t, 50.0, s.CounterPressRate)
+
+	season := s
+	season.Add(TeamStats{TeamID: home, Matches: 1, OpponentPasses: 9, DefensiveActions: 1, BallLosses: 2})
+	assert.Equal(t, 2, season.Matches)
+	assert.Equal(t, 3.0, season.PPDA)
+	assert.Equal(t, 25.0, season.CounterPressRate)
+}
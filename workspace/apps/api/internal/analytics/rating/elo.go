@@ -0,0 +1,130 @@
+// Package rating rates teams from their results with Elo and predicts match
+// outcomes with a Poisson goals model.
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+// Result is a finished match.
+type Result struct {
+	MatchID    int32
+	Date       time.Time
+	HomeTeamID int32
+	AwayTeamID int32
+	HomeGoals  int32
+	AwayGoals  int32
+	// HomeXG and AwayXG are the teams' xG from their shots, nil when the match
+	// has no shot events
+	HomeXG *float64
+	AwayXG *float64
+}
+
+// EloConfig holds the Elo parameters.
+type EloConfig struct {
+	Initial       float64 // Rating of a team without results
+	K             float64 // Points at stake in a one-goal match
+	HomeAdvantage float64 // Rating points added to the home team's expected score
+}
+
+// DefaultEloConfig follows the World Football Elo ratings, with a smaller K
+// for league matches.
+var DefaultEloConfig = EloConfig{
+	Initial:       1500,
+	K:             20,
+	HomeAdvantage: 65,
+}
+
+// Change is a team's rating change from a match.
+type Change struct {
+	MatchID    int32   `json:"match_id"`
+	TeamID     int32   `json:"team_id"`
+	OpponentID int32   `json:"opponent_id"`
+	Home       bool    `json:"home"`
+	Before     float64 `json:"rating_before"`
+	After      float64 `json:"rating_after"`
+	Expected   float64 `json:"expected_score"` // From 0 (certain loss) to 1 (certain win)
+}
+
+// Elo maintains teams' ratings as results are applied in date order.
+type Elo struct {
+	cfg     EloConfig
+	ratings map[int32]float64
+}
+
+// NewElo creates a rating engine where every team starts at cfg.Initial.
+func NewElo(cfg EloConfig) *Elo {
+	return &Elo{cfg: cfg, ratings: map[int32]float64{}}
+}
+
+// Rating returns a team's current rating.
+func (e *Elo) Rating(teamID int32) float64 {
+	if r, ok := e.ratings[teamID]; ok {
+		return r
+	}
+	return e.cfg.Initial
+}
+
+// Expected returns the home team's expected score against the away team.
+func (e *Elo) Expected(homeTeamID, awayTeamID int32) float64 {
+	diff := e.Rating(homeTeamID) + e.cfg.HomeAdvantage - e.Rating(awayTeamID)
+	return 1 / (1 + math.Pow(10, -diff/400))
+}
+
+// Apply updates both teams' ratings with a result and returns their changes,
+// the home team's first. The points exchanged grow with the goal difference.
+func (e *Elo) Apply(r *Result) [2]Change {
+	expected := e.Expected(r.HomeTeamID, r.AwayTeamID)
+	score := 0.5
+	switch {
+	case r.HomeGoals > r.AwayGoals:
+		score = 1
+	case r.HomeGoals < r.AwayGoals:
+		score = 0
+	}
+	delta := e.cfg.K * goalMultiplier(r.HomeGoals-r.AwayGoals) * (score - expected)
+
+	home, away := e.Rating(r.HomeTeamID), e.Rating(r.AwayTeamID)
+	e.ratings[r.HomeTeamID] = home + delta
+	e.ratings[r.AwayTeamID] = away - delta
+	return [2]Change{
+		{MatchID: r.MatchID, TeamID: r.HomeTeamID, OpponentID: r.AwayTeamID, Home: true,
+			Before: round(home), After: round(home + delta), Expected: round(expected)},
+		{MatchID: r.MatchID, TeamID: r.AwayTeamID, OpponentID: r.HomeTeamID,
+			Before: round(away), After: round(away - delta), Expected: round(1 - expected)},
+	}
+}
+
+// Run applies results in date order to a new engine and returns every change.
+// Results must be from one competition.
+func Run(cfg EloConfig, results []Result) (*Elo, []Change) {
+	e := NewElo(cfg)
+	changes := make([]Change, 0, 2*len(results))
+	for i := range results {
+		c := e.Apply(&results[i])
+		changes = append(changes, c[0], c[1])
+	}
+	return e, changes
+}
+
+// goalMultiplier scales the points at stake by the goal difference: 1 for a
+// draw or one goal, 1.5 for two and (11 + n) / 8 for n of three or more.
+func goalMultiplier(diff int32) float64 {
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= 1:
+		return 1
+	case diff == 2:
+		return 1.5
+	default:
+		return (11 + float64(diff)) / 8
+	}
+}
+
+// round rounds to 2 decimal places.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,183 @@
+package rating
+
+import "math"
+
+// PoissonConfig holds the goals model parameters.
+type PoissonConfig struct {
+	Window   int     // A team's most recent matches its strengths are fitted on
+	XGWeight float64 // Weight of xG against goals in a match's scoring, from 0 to 1
+	Prior    float64 // League average matches added to every team, shrinking small samples
+	MaxGoals int     // Scores summed per team; the rest of the distribution is renormalized
+}
+
+// DefaultPoissonConfig fits strengths on the last ten matches, leaning on xG
+// because it is a steadier measure of chances than goals.
+var DefaultPoissonConfig = PoissonConfig{
+	Window:   10,
+	XGWeight: 0.6,
+	Prior:    3,
+	MaxGoals: 10,
+}
+
+// League averages used before a competition has results.
+const (
+	defaultHomeGoals = 1.5
+	defaultAwayGoals = 1.2
+)
+
+// form is a team's recent scoring.
+type form struct {
+	matches  int
+	scored   float64
+	conceded float64
+}
+
+// Poisson predicts match outcomes from teams' attack and defence strengths,
+// relative to the league average, with each team's goals Poisson distributed.
+type Poisson struct {
+	cfg       PoissonConfig
+	homeGoals float64 // League goals per match by the home team
+	awayGoals float64
+	teams     map[int32]*form
+}
+
+// Prediction is a match's expected goals and outcome probabilities.
+type Prediction struct {
+	HomeGoals float64 `json:"home_goals"` // Expected
+	AwayGoals float64 `json:"away_goals"`
+	Home      float64 `json:"home"` // Probability of a home win
+	Draw      float64 `json:"draw"`
+	Away      float64 `json:"away"`
+}
+
+// FitPoisson fits the model on results in date order, usually a competition's
+// results before the matches to predict. Each team's strengths come from its
+// last cfg.Window results, and league averages from every result.
+func FitPoisson(cfg PoissonConfig, results []Result) *Poisson {
+	p := &Poisson{cfg: cfg, homeGoals: defaultHomeGoals, awayGoals: defaultAwayGoals, teams: map[int32]*form{}}
+	if len(results) > 0 {
+		var home, away float64
+		for i := range results {
+			home += p.scoring(results[i].HomeGoals, results[i].HomeXG)
+			away += p.scoring(results[i].AwayGoals, results[i].AwayXG)
+		}
+		p.homeGoals, p.awayGoals = home/float64(len(results)), away/float64(len(results))
+	}
+
+	add := func(teamID int32, scored, conceded float64) {
+		f, ok := p.teams[teamID]
+		if !ok {
+			f = &form{}
+			p.teams[teamID] = f
+		}
+		if f.matches < cfg.Window {
+			f.matches++
+			f.scored += scored
+			f.conceded += conceded
+		}
+	}
+	for i := len(results) - 1; i >= 0; i-- {
+		r := &results[i]
+		home, away := p.scoring(r.HomeGoals, r.HomeXG), p.scoring(r.AwayGoals, r.AwayXG)
+		add(r.HomeTeamID, home, away)
+		add(r.AwayTeamID, away, home)
+	}
+	return p
+}
+
+// scoring blends a team's goals in a match with its xG, when known.
+func (p *Poisson) scoring(goals int32, xg *float64) float64 {
+	if xg == nil {
+		return float64(goals)
+	}
+	return p.cfg.XGWeight**xg + (1-p.cfg.XGWeight)*float64(goals)
+}
+
+// Matches returns the number of results a team's strengths are fitted on.
+func (p *Poisson) Matches(teamID int32) int {
+	if f, ok := p.teams[teamID]; ok {
+		return f.matches
+	}
+	return 0
+}
+
+// strengths returns a team's attack and defence relative to the league
+// average, 1 being average; a lower defence concedes less.
+func (p *Poisson) strengths(teamID int32) (attack, defence float64) {
+	avg := (p.homeGoals + p.awayGoals) / 2
+	if avg <= 0 {
+		return 1, 1
+	}
+	f, ok := p.teams[teamID]
+	if !ok {
+		return 1, 1
+	}
+	n := float64(f.matches) + p.cfg.Prior
+	attack = (f.scored + p.cfg.Prior*avg) / n / avg
+	defence = (f.conceded + p.cfg.Prior*avg) / n / avg
+	return attack, defence
+}
+
+// Predict returns the expected goals and outcome probabilities of a match.
+func (p *Poisson) Predict(homeTeamID, awayTeamID int32) Prediction {
+	homeAttack, homeDefence := p.strengths(homeTeamID)
+	awayAttack, awayDefence := p.strengths(awayTeamID)
+	lambdaHome := p.homeGoals * homeAttack * awayDefence
+	lambdaAway := p.awayGoals * awayAttack * homeDefence
+
+	home := distribution(lambdaHome, p.cfg.MaxGoals)
+	away := distribution(lambdaAway, p.cfg.MaxGoals)
+	var pred Prediction
+	var total float64
+	for h, ph := range home {
+		for a, pa := range away {
+			prob := ph * pa
+			total += prob
+			switch {
+			case h > a:
+				pred.Home += prob
+			case h < a:
+				pred.Away += prob
+			default:
+				pred.Draw += prob
+			}
+		}
+	}
+	if total > 0 {
+		pred.Home, pred.Draw, pred.Away = pred.Home/total, pred.Draw/total, pred.Away/total
+	}
+
+	pred.HomeGoals, pred.AwayGoals = round(lambdaHome), round(lambdaAway)
+	pred.Home, pred.Draw, pred.Away = round4(pred.Home), round4(pred.Draw), round4(pred.Away)
+	return pred
+}
+
+// distribution returns the Poisson probabilities of 0 to maxGoals goals.
+func distribution(lambda float64, maxGoals int) []float64 {
+	probs := make([]float64, maxGoals+1)
+	probs[0] = math.Exp(-lambda)
+	for k := 1; k <= maxGoals; k++ {
+		probs[k] = probs[k-1] * lambda / float64(k)
+	}
+	return probs
+}
+
+// Brier returns the Brier score of a prediction given the final score: the
+// squared error summed over the three outcomes, from 0 (certain and right) to 2.
+func Brier(p Prediction, homeGoals, awayGoals int32) float64 {
+	var home, draw, away float64
+	switch {
+	case homeGoals > awayGoals:
+		home = 1
+	case homeGoals < awayGoals:
+		away = 1
+	default:
+		draw = 1
+	}
+	return math.Pow(p.Home-home, 2) + math.Pow(p.Draw-draw, 2) + math.Pow(p.Away-away, 2)
+}
+
+// round4 rounds to 4 decimal places.
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,80 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElo(t *testing.T) {
+	e := NewElo(DefaultEloConfig)
+	assert.InDelta(t, 0.592, e.Expected(1, 2), 0.001) // Home advantage only
+
+	changes := e.Apply(&Result{MatchID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeGoals: 3, AwayGoals: 0})
+	home, away := changes[0], changes[1]
+	assert.True(t, home.Home)
+	assert.Equal(t, 1500.0, home.Before)
+	// K 20 x 1.75 for three goals x (1 - 0.592)
+	assert.InDelta(t, 1514.26, home.After, 0.01)
+	assert.InDelta(t, 1500-(home.After-1500), away.After, 0.01)
+	assert.Equal(t, round(1-home.Expected), away.Expected)
+
+	// A draw at home costs the stronger home team points
+	changes = e.Apply(&Result{MatchID: 2, HomeTeamID: 1, AwayTeamID: 2, HomeGoals: 1, AwayGoals: 1})
+	assert.Less(t, changes[0].After, changes[0].Before)
+}
+
+func TestGoalMultiplier(t *testing.T) {
+	assert.Equal(t, 1.0, goalMultiplier(0))
+	assert.Equal(t, 1.0, goalMultiplier(-1))
+	assert.Equal(t, 1.5, goalMultiplier(2))
+	assert.Equal(t, 1.75, goalMultiplier(-3))
+}
+
+func TestPoisson(t *testing.T) {
+	xg := func(v float64) *float64 { return &v }
+	var results []Result
+	for i := 0; i < 6; i++ {
+		// Team 1 beats everyone, team 2 loses to everyone
+		results = append(results,
+			Result{HomeTeamID: 1, AwayTeamID: 3, HomeGoals: 3, AwayGoals: 0, HomeXG: xg(2.5), AwayXG: xg(0.4)},
+			Result{HomeTeamID: 2, AwayTeamID: 3, HomeGoals: 0, AwayGoals: 2},
+		)
+	}
+	p := FitPoisson(DefaultPoissonConfig, results)
+	assert.Equal(t, 6, p.Matches(1))
+	assert.Equal(t, 10, p.Matches(3)) // Limited to the window
+	assert.Equal(t, 0, p.Matches(4))
+
+	pred := p.Predict(1, 2)
+	assert.Greater(t, pred.Home, 0.7)
+	assert.Greater(t, pred.HomeGoals, pred.AwayGoals)
+	assert.InDelta(t, 1, pred.Home+pred.Draw+pred.Away, 0.001)
+
+	reverse := p.Predict(2, 1)
+	assert.Greater(t, reverse.Away, reverse.Home)
+
+	// Unknown teams are average
+	empty := FitPoisson(DefaultPoissonConfig, nil).Predict(1, 2)
+	assert.Equal(t, defaultHomeGoals, empty.HomeGoals)
+	assert.Greater(t, empty.Home, empty.Away)
+}
+
+func TestBrier(t *testing.T) {
+	p := Prediction{Home: 0.5, Draw: 0.3, Away: 0.2}
+	assert.InDelta(t, 0.38, Brier(p, 2, 1), 1e-9)
+	assert.InDelta(t, 0.78, Brier(p, 1, 1), 1e-9)
+	assert.InDelta(t, 2.0, Brier(Prediction{Home: 1}, 0, 1), 1e-9)
+}
+
+func TestRun(t *testing.T) {
+	e, changes := Run(DefaultEloConfig, []Result{
+		{MatchID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeGoals: 1},
+		{MatchID: 2, HomeTeamID: 2, AwayTeamID: 1, AwayGoals: 2},
+	})
+	require.Len(t, changes, 4)
+	assert.Equal(t, changes[0].After, changes[3].Before)
+	assert.Greater(t, e.Rating(1), e.Rating(2))
+	assert.InDelta(t, 3000, e.Rating(1)+e.Rating(2), 1e-9)
+}
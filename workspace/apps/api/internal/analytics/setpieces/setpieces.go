@@ -0,0 +1,550 @@
+// Package setpieces identifies set-piece routines (corners, free kicks, throw-ins
+// and penalties) in a match's event log and attributes the shots, xG and goals
+// that came from each, with corner and free kick deliveries categorized by swing
+// and by the zone they were aimed at.
+//
+// Coordinates are canonical meters from the acting team's own goal line, so a
+// corner from the attacking team's left is taken at y = 0 and one from its right
+// at y = pitch.Width.
+package setpieces
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/shots"
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Set-piece types.
+const (
+	TypeCorner   = "corner"
+	TypeFreeKick = "free_kick"
+	TypeThrowIn  = "throw_in"
+	TypePenalty  = "penalty"
+)
+
+// Delivery swings: an inswinger curls towards the goal, an outswinger away from it.
+const (
+	SwingInswinging  = "inswinging"
+	SwingOutswinging = "outswinging"
+	SwingStraight    = "straight"
+)
+
+// Delivery targets, where a delivery ended. Near and far are measured from the
+// side the set piece was taken on.
+const (
+	TargetNearPost   = "near_post"   // In the box, short of the near post
+	TargetCentral    = "central"     // In the box, between the posts
+	TargetFarPost    = "far_post"    // In the box, beyond the far post
+	TargetOutsideBox = "outside_box" // Short corners and lay-offs
+)
+
+// SequenceWindowMs is how long after the restart a routine's shots still count.
+const SequenceWindowMs = 20000
+
+// Penalty area and goal on the canonical pitch, in meters.
+const (
+	boxDepth      = 16.5
+	boxHalfWidth  = 20.16
+	goalHalfWidth = 3.66
+)
+
+// restartValues map normalized restart metadata values (StatsBomb pass_type, a
+// generic set_piece or restart field) to set-piece types.
+var restartValues = map[string]string{
+	"corner":   TypeCorner,
+	"freekick": TypeFreeKick,
+	"throwin":  TypeThrowIn,
+	"penalty":  TypePenalty,
+}
+
+// restartFlags map normalized Opta qualifier keys to set-piece types.
+var restartFlags = map[string]string{
+	"cornertaken":   TypeCorner,
+	"freekicktaken": TypeFreeKick,
+	"throwin":       TypeThrowIn,
+}
+
+// Sequence is a set-piece routine: the restart and the taking team's play until
+// it lost the ball, play stopped or SequenceWindowMs passed.
+type Sequence struct {
+	TeamID       int32             `json:"team_id"`
+	Type         string            `json:"type"`
+	EventID      int32             `json:"event_id"` // The restart
+	TakerID      *int32            `json:"taker_id,omitempty"`
+	Clock        events.MatchClock `json:"clock"`
+	Side         string            `json:"side,omitempty"`   // left or right, from the taking team's view
+	Swing        string            `json:"swing,omitempty"`  // Corners and free kicks, when known
+	Target       string            `json:"target,omitempty"` // Corners and free kicks with an end location
+	Shots        int               `json:"shots"`
+	XG           float64           `json:"xg"`
+	Goals        int               `json:"goals"` // Own goals by the opponent included
+	ShotEventIDs []int32           `json:"shot_event_ids"`
+}
+
+// Outcome is what a group of set-piece routines produced.
+type Outcome struct {
+	Sequences int     `json:"sequences"`
+	Shots     int     `json:"shots"`
+	Goals     int     `json:"goals"`
+	XG        float64 `json:"xg"`
+	// XGPerSequence is zero when there were no routines
+	XGPerSequence float64 `json:"xg_per_sequence"`
+	// ShotRate is the share of routines that produced a shot, in percent
+	ShotRate float64 `json:"shot_rate"`
+	shotted  int
+}
+
+// Deliveries break a routine type down by the delivery's swing and target.
+// Deliveries whose swing or end location is unknown are left out.
+type Deliveries struct {
+	Inswinging  Outcome `json:"inswinging"`
+	Outswinging Outcome `json:"outswinging"`
+	Straight    Outcome `json:"straight"`
+	NearPost    Outcome `json:"near_post"`
+	Central     Outcome `json:"central"`
+	FarPost     Outcome `json:"far_post"`
+	OutsideBox  Outcome `json:"outside_box"`
+}
+
+// Routine is the outcome of one type of set piece, with its deliveries.
+type Routine struct {
+	Outcome
+	Deliveries Deliveries `json:"deliveries"`
+}
+
+// Report is the outcome of a team's set pieces, or of its opponents' set pieces
+// against it.
+type Report struct {
+	Total     Outcome `json:"total"`
+	Corners   Routine `json:"corners"`
+	FreeKicks Routine `json:"free_kicks"`
+	ThrowIns  Outcome `json:"throw_ins"`
+	Penalties Outcome `json:"penalties"`
+}
+
+// TeamReport is a team's set-piece report over one or more matches.
+type TeamReport struct {
+	TeamID  int32 `json:"team_id"`
+	Matches int   `json:"matches"`
+	// Attacking are the team's own set pieces
+	Attacking Report `json:"attacking"`
+	// Defending are the opponents' set pieces
+	Defending Report `json:"defending"`
+}
+
+// Compute computes the set-piece report of each team from a match's routines.
+func Compute(sequences []Sequence, teamIDs ...int32) []TeamReport {
+	reports := make([]TeamReport, 0, len(teamIDs))
+	for _, teamID := range teamIDs {
+		r := TeamReport{TeamID: teamID, Matches: 1}
+		for i := range sequences {
+			if sequences[i].TeamID == teamID {
+				r.Attacking.count(&sequences[i])
+			} else {
+				r.Defending.count(&sequences[i])
+			}
+		}
+		r.finish()
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// Add adds another match's or season's report for the same team.
+func (r *TeamReport) Add(o TeamReport) {
+	r.Matches += o.Matches
+	r.Attacking.add(&o.Attacking)
+	r.Defending.add(&o.Defending)
+	r.finish()
+}
+
+// finish computes the ratios from the counts.
+func (r *TeamReport) finish() {
+	for _, report := range []*Report{&r.Attacking, &r.Defending} {
+		for _, o := range report.outcomes() {
+			o.finish()
+		}
+	}
+}
+
+// count adds a routine to the outcomes it belongs to.
+func (r *Report) count(s *Sequence) {
+	r.Total.count(s)
+	var routine *Routine
+	switch s.Type {
+	case TypeCorner:
+		routine = &r.Corners
+	case TypeFreeKick:
+		routine = &r.FreeKicks
+	case TypeThrowIn:
+		r.ThrowIns.count(s)
+		return
+	case TypePenalty:
+		r.Penalties.count(s)
+		return
+	default:
+		return
+	}
+	routine.count(s)
+	switch s.Swing {
+	case SwingInswinging:
+		routine.Deliveries.Inswinging.count(s)
+	case SwingOutswinging:
+		routine.Deliveries.Outswinging.count(s)
+	case SwingStraight:
+		routine.Deliveries.Straight.count(s)
+	}
+	switch s.Target {
+	case TargetNearPost:
+		routine.Deliveries.NearPost.count(s)
+	case TargetCentral:
+		routine.Deliveries.Central.count(s)
+	case TargetFarPost:
+		routine.Deliveries.FarPost.count(s)
+	case TargetOutsideBox:
+		routine.Deliveries.OutsideBox.count(s)
+	}
+}
+
+// add adds another report's counts, outcome by outcome.
+func (r *Report) add(o *Report) {
+	mine, theirs := r.outcomes(), o.outcomes()
+	for i := range mine {
+		mine[i].add(theirs[i])
+	}
+}
+
+// outcomes returns every outcome in the report, in a fixed order.
+func (r *Report) outcomes() []*Outcome {
+	outcomes := []*Outcome{&r.Total, &r.ThrowIns, &r.Penalties}
+	for _, routine := range []*Routine{&r.Corners, &r.FreeKicks} {
+		d := &routine.Deliveries
+		outcomes = append(outcomes, &routine.Outcome,
+			&d.Inswinging, &d.Outswinging, &d.Straight,
+			&d.NearPost, &d.Central, &d.FarPost, &d.OutsideBox)
+	}
+	return outcomes
+}
+
+func (o *Outcome) count(s *Sequence) {
+	o.Sequences++
+	o.Shots += s.Shots
+	o.Goals += s.Goals
+	o.XG += s.XG
+	if s.Shots > 0 {
+		o.shotted++
+	}
+}
+
+func (o *Outcome) add(other *Outcome) {
+	o.Sequences += other.Sequences
+	o.Shots += other.Shots
+	o.Goals += other.Goals
+	o.XG += other.XG
+	o.shotted += other.shotted
+}
+
+func (o *Outcome) finish() {
+	o.XG = xg.Round(o.XG)
+	o.XGPerSequence, o.ShotRate = 0, 0
+	if o.Sequences > 0 {
+		o.XGPerSequence = xg.Round(o.XG / float64(o.Sequences))
+		o.ShotRate = math.Round(float64(o.shotted)/float64(o.Sequences)*10000) / 100
+	}
+}
+
+// Sequences returns a match's set-piece routines from its events, in match clock
+// order. Restarts are corner and penalty events, passes marked as set pieces by
+// the provider, direct free kick shots, and a team's first action after a foul
+// against it or an opponent's offside, which is its free kick. feet maps players
+// to their preferred foot, for the swing of deliveries whose body part is not
+// recorded. Penalty shootouts are left out.
+func Sequences(matchEvents []matchevent.Event, feet map[int32]string) []Sequence {
+	sequences := []Sequence{}
+	var current *Sequence
+	closeSequence := func() {
+		if current != nil {
+			current.XG = xg.Round(current.XG)
+			sequences = append(sequences, *current)
+			current = nil
+		}
+	}
+
+	var freeKickTeam int32 // The team awarded a free kick that has not been taken
+	period := int16(0)
+	for i := range matchEvents {
+		e := &matchEvents[i]
+		if e.Clock.Period == events.PeriodPenalties {
+			continue
+		}
+		if e.Clock.PeriodNumber() != period {
+			closeSequence()
+			freeKickTeam = 0
+			period = e.Clock.PeriodNumber()
+		}
+		t := events.Normalize(e.EventType)
+		if events.GetCategory(t) == events.CategoryMatchState {
+			closeSequence()
+			freeKickTeam = 0
+			continue
+		}
+		if e.TeamID == nil {
+			continue
+		}
+		if current != nil && e.Clock.Ms-current.Clock.Ms > SequenceWindowMs {
+			closeSequence()
+		}
+
+		// Stoppages: the other team gets a free kick
+		switch t {
+		case events.EventTypeFoulCommitted, events.EventTypeFoul, events.EventTypeOffside:
+			closeSequence()
+			freeKickTeam = opponentOf(matchEvents, *e.TeamID)
+			continue
+		case events.EventTypeFoulWon:
+			closeSequence()
+			freeKickTeam = *e.TeamID
+			continue
+		}
+		switch events.GetCategory(t) {
+		case events.CategoryCard, events.CategorySubstitution, events.CategoryVar:
+			continue
+		}
+
+		restart := restartType(e)
+		if restart == "" && freeKickTeam == *e.TeamID && !challenge(t) {
+			restart = TypeFreeKick
+		}
+		if !challenge(t) {
+			freeKickTeam = 0
+		}
+		if restart != "" {
+			closeSequence()
+			current = newSequence(e, restart, feet)
+		}
+		if current == nil {
+			continue
+		}
+
+		if *e.TeamID == current.TeamID {
+			if shots.IsShotMapEvent(e.EventType) {
+				shot, _ := shots.FromEvent(e.EventType, e.X, e.Y, e.Meta, "")
+				if shot.OwnGoal {
+					// Scored into the taking team's own net: the routine is over
+					closeSequence()
+					continue
+				}
+				current.Shots++
+				current.XG += shot.XG
+				current.ShotEventIDs = append(current.ShotEventIDs, e.ID)
+				if shot.Goal {
+					current.Goals++
+					closeSequence()
+				}
+			}
+			continue
+		}
+
+		// The opponent
+		switch {
+		case t == events.EventTypeOwnGoal:
+			current.Goals++
+			closeSequence()
+		case t == events.EventTypePunch || (events.GetCategory(t) == events.CategoryGoalkeeper && t != events.EventTypeClaim):
+			// Saves and punches rarely keep the ball; the rebound is part of the routine
+		case challenge(t):
+		default:
+			closeSequence()
+		}
+	}
+	closeSequence()
+	return sequences
+}
+
+// newSequence starts a routine at its restart and categorizes the delivery.
+func newSequence(e *matchevent.Event, restart string, feet map[int32]string) *Sequence {
+	s := &Sequence{
+		TeamID:       *e.TeamID,
+		Type:         restart,
+		EventID:      e.ID,
+		TakerID:      e.PlayerID,
+		Clock:        e.Clock,
+		ShotEventIDs: []int32{},
+	}
+	if restart == TypePenalty || e.Y == nil {
+		return s
+	}
+
+	provider, _ := e.Meta[xg.MetaProvider].(string)
+	frame := pitch.FrameFor(e.Meta, provider)
+	_, y := frame.Meters(0, *e.Y)
+	left := y < pitch.Width/2
+	s.Side = "right"
+	if left {
+		s.Side = "left"
+	}
+	if restart == TypeThrowIn {
+		return s
+	}
+
+	s.Swing = swing(e.Meta)
+	if s.Swing == "" {
+		foot := footFromBodyPart(e.Meta)
+		if foot == "" && e.PlayerID != nil {
+			foot = normalizeFoot(feet[*e.PlayerID])
+		}
+		// A right-footed delivery from the right curls towards goal, like a
+		// left-footed one from the left
+		switch {
+		case foot == "right" && !left, foot == "left" && left:
+			s.Swing = SwingInswinging
+		case foot != "":
+			s.Swing = SwingOutswinging
+		}
+	}
+
+	// Corner events are deliveries too, so the end location is read as a pass's
+	if m, ok := xt.MoveFromEvent(string(events.EventTypePass), e.X, e.Y, e.Meta, frame); ok {
+		s.Target = target(m.EndX, m.EndY, left)
+	}
+	return s
+}
+
+// restartType returns the set-piece type an event restarts play with, or "".
+func restartType(e *matchevent.Event) string {
+	t := events.Normalize(e.EventType)
+	switch t {
+	case events.EventTypeCorner:
+		return TypeCorner
+	case events.EventTypePenalty, events.EventTypePenaltyGoal, events.EventTypePenaltyMiss:
+		return TypePenalty
+	}
+	if xg.IsShotEvent(e.EventType) {
+		shot, _ := shots.FromEvent(e.EventType, e.X, e.Y, e.Meta, "")
+		switch shot.Situation {
+		case xg.SituationPenalty:
+			return TypePenalty
+		case xg.SituationFreeKick:
+			return TypeFreeKick
+		}
+		return ""
+	}
+	if !t.IsPass() {
+		return ""
+	}
+	for _, key := range []string{"pass_type", "set_piece", "restart"} {
+		if v, _ := e.Meta[key].(string); restartValues[normalizeToken(v)] != "" {
+			return restartValues[normalizeToken(v)]
+		}
+	}
+	for key := range e.Meta {
+		if restart, ok := restartFlags[normalizeToken(key)]; ok {
+			return restart
+		}
+	}
+	return ""
+}
+
+// swing returns a delivery's swing from StatsBomb's technique or Opta's
+// Inswinger, Outswinger and Straight qualifiers.
+func swing(meta map[string]interface{}) string {
+	technique, _ := meta["technique"].(string)
+	switch normalizeToken(technique) {
+	case "inswinging", "inswinger":
+		return SwingInswinging
+	case "outswinging", "outswinger":
+		return SwingOutswinging
+	case "straight":
+		return SwingStraight
+	}
+	for key := range meta {
+		switch normalizeToken(key) {
+		case "inswinger":
+			return SwingInswinging
+		case "outswinger":
+			return SwingOutswinging
+		case "straight":
+			return SwingStraight
+		}
+	}
+	return ""
+}
+
+// footFromBodyPart returns the foot a delivery was struck with, from a body_part
+// field or Opta's RightFoot and LeftFoot qualifiers.
+func footFromBodyPart(meta map[string]interface{}) string {
+	if b, _ := meta["body_part"].(string); b != "" {
+		return normalizeFoot(b)
+	}
+	if _, ok := meta["RightFoot"]; ok {
+		return "right"
+	}
+	if _, ok := meta["LeftFoot"]; ok {
+		return "left"
+	}
+	return ""
+}
+
+// normalizeFoot reads "Right Foot", "right" or "R" as right and likewise left.
+// Two-footed players have no foot.
+func normalizeFoot(s string) string {
+	s = normalizeToken(s)
+	switch {
+	case strings.HasPrefix(s, "r"):
+		return "right"
+	case strings.HasPrefix(s, "l"):
+		return "left"
+	}
+	return ""
+}
+
+// target returns the zone a delivery ended in. left is the side it was taken on.
+func target(x, y float64, left bool) string {
+	if x < pitch.Length-boxDepth || math.Abs(y-pitch.Width/2) > boxHalfWidth {
+		return TargetOutsideBox
+	}
+	// Distance from the touchline on the delivery's side
+	d := y
+	if !left {
+		d = pitch.Width - y
+	}
+	switch {
+	case d < pitch.Width/2-goalHalfWidth:
+		return TargetNearPost
+	case d > pitch.Width/2+goalHalfWidth:
+		return TargetFarPost
+	}
+	return TargetCentral
+}
+
+// challenge reports whether an event type is a challenge that does not win the
+// ball when it is the only thing the opponent did.
+func challenge(t events.EventType) bool {
+	switch t {
+	case events.EventTypeDuelLost, events.EventTypeTackleLost, events.EventTypeAerialDuelLost:
+		return true
+	}
+	return false
+}
+
+// opponentOf returns the other team with events in the match.
+func opponentOf(matchEvents []matchevent.Event, teamID int32) int32 {
+	for i := range matchEvents {
+		if id := matchEvents[i].TeamID; id != nil && *id != teamID {
+			return *id
+		}
+	}
+	return 0
+}
+
+// normalizeToken lowercases a value and drops spaces, underscores and dashes,
+// so "Free Kick", "free_kick" and "Throw-in" compare equal to their flags.
+func normalizeToken(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(s))
+}
@@ -0,0 +1,157 @@
+package setpieces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent/matcheventtest"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+const (
+	home int32 = 1
+	away int32 = 2
+
+	taker   int32 = 7
+	striker int32 = 9
+	keeper  int32 = 20
+)
+
+var (
+	event = matcheventtest.New
+	at    = matcheventtest.At
+)
+
+func TestSequences(t *testing.T) {
+	corner := at(event(1, "corner", home, taker, 0), 105, 0, 100, 28)
+	header := at(event(2, "shot_off_target", home, striker, 2), 99, 30)
+	header.Meta["xG"] = 0.08
+	// The rebound off the goalkeeper is part of the routine
+	second := at(event(4, "goal", home, striker, 5), 100, 36)
+	second.Meta["xG"] = 0.4
+	// After a goal the routine is over
+	late := at(event(5, "shot", home, striker, 8), 90, 34)
+
+	throwIn := at(event(6, "pass", away, 3, 30), 40, 68)
+	throwIn.Meta["pass_type"] = "Throw-in"
+	clearance := event(7, "clearance", home, 4, 32)
+	// Fouled: the next away action is a free kick, the card in between is not
+	foul := event(8, "foul_committed", home, 4, 40)
+	card := event(9, "yellow_card", home, 4, 41)
+	freeKick := at(event(10, "pass", away, taker, 50), 75, 60, 98, 36)
+	freeKick.Meta["technique"] = "Outswinging"
+	ownGoal := event(11, "own_goal", home, 4, 52)
+
+	sequences := Sequences([]matchevent.Event{
+		corner, header,
+		event(3, "save", away, keeper, 3),
+		second, late,
+		throwIn, clearance,
+		foul, card, freeKick, ownGoal,
+	}, map[int32]string{taker: "Right"})
+
+	require.Len(t, sequences, 3)
+	c := sequences[0]
+	assert.Equal(t, TypeCorner, c.Type)
+	assert.Equal(t, home, c.TeamID)
+	assert.Equal(t, "left", c.Side)
+	// A right-footed corner from the left swings away from goal
+	assert.Equal(t, SwingOutswinging, c.Swing)
+	assert.Equal(t, TargetNearPost, c.Target)
+	assert.Equal(t, 2, c.Shots)
+	assert.Equal(t, 1, c.Goals)
+	assert.Equal(t, 0.48, c.XG)
+	assert.Equal(t, []int32{2, 4}, c.ShotEventIDs)
+
+	ti := sequences[1]
+	assert.Equal(t, TypeThrowIn, ti.Type)
+	assert.Equal(t, away, ti.TeamID)
+	assert.Equal(t, "right", ti.Side)
+	assert.Empty(t, ti.Swing)
+	assert.Equal(t, 0, ti.Shots)
+
+	fk := sequences[2]
+	assert.Equal(t, TypeFreeKick, fk.Type)
+	assert.Equal(t, SwingOutswinging, fk.Swing, "technique wins over the preferred foot")
+	// From the right, y = 36 is between the posts
+	assert.Equal(t, TargetCentral, fk.Target)
+	assert.Equal(t, 1, fk.Goals, "the opponent's own goal counts")
+	assert.Equal(t, 0, fk.Shots)
+}
+
+func TestSequencesPenaltiesAndOffsides(t *testing.T) {
+	penalty := at(event(1, "penalty_goal", home, striker, 0), 94, 34)
+	penalty.Meta["xG"] = 0.76
+	offside := event(2, "offside", home, striker, 30)
+	freeKick := at(event(3, "long_ball", away, keeper, 40), 20, 30)
+	direct := at(event(4, "shot_saved", home, taker, 60), 80, 40)
+	direct.Meta["shot_type"] = "Free Kick"
+	direct.Meta["xG"] = 0.05
+	shootout := at(event(5, "penalty_goal", home, striker, 0), 94, 34)
+	shootout.Clock = events.NewMatchClock(events.PeriodPenalties, 120, 0, 0)
+
+	sequences := Sequences([]matchevent.Event{penalty, offside, freeKick, direct, shootout}, nil)
+
+	require.Len(t, sequences, 3)
+	assert.Equal(t, TypePenalty, sequences[0].Type)
+	assert.Equal(t, 1, sequences[0].Goals)
+	assert.Empty(t, sequences[0].Side)
+	assert.Equal(t, TypeFreeKick, sequences[1].Type)
+	assert.Equal(t, away, sequences[1].TeamID)
+	assert.Equal(t, TypeFreeKick, sequences[2].Type)
+	assert.Equal(t, home, sequences[2].TeamID)
+	assert.Equal(t, 1, sequences[2].Shots)
+}
+
+func TestSequencesWindow(t *testing.T) {
+	corner := at(event(1, "corner", home, taker, 0), 105, 68)
+	corner.Meta["Inswinger"] = ""
+	recycled := event(2, "pass", home, 5, 10)
+	late := at(event(3, "shot", home, striker, 25), 100, 34)
+
+	sequences := Sequences([]matchevent.Event{corner, recycled, late}, nil)
+
+	require.Len(t, sequences, 1)
+	assert.Equal(t, SwingInswinging, sequences[0].Swing)
+	assert.Empty(t, sequences[0].Target)
+	assert.Equal(t, 0, sequences[0].Shots, "the shot came after the window")
+}
+
+func TestCompute(t *testing.T) {
+	sequences := []Sequence{
+		{TeamID: home, Type: TypeCorner, Swing: SwingInswinging, Target: TargetFarPost, Shots: 2, Goals: 1, XG: 0.5},
+		{TeamID: home, Type: TypeCorner, Swing: SwingInswinging, Target: TargetNearPost},
+		{TeamID: home, Type: TypeThrowIn},
+		{TeamID: away, Type: TypePenalty, Shots: 1, XG: 0.76},
+		{TeamID: away, Type: TypeFreeKick, Target: TargetOutsideBox, Shots: 1, XG: 0.04},
+	}
+
+	reports := Compute(sequences, home, away)
+	require.Len(t, reports, 2)
+	r := reports[0]
+	assert.Equal(t, home, r.TeamID)
+	assert.Equal(t, 1, r.Matches)
+	assert.Equal(t, Outcome{Sequences: 3, Shots: 2, Goals: 1, XG: 0.5, XGPerSequence: 0.1667, ShotRate: 33.33, shotted: 1}, r.Attacking.Total)
+	assert.Equal(t, 2, r.Attacking.Corners.Sequences)
+	assert.Equal(t, 0.25, r.Attacking.Corners.XGPerSequence)
+	assert.Equal(t, 2, r.Attacking.Corners.Deliveries.Inswinging.Sequences)
+	assert.Equal(t, 1, r.Attacking.Corners.Deliveries.FarPost.Goals)
+	assert.Equal(t, 1, r.Attacking.ThrowIns.Sequences)
+	assert.Equal(t, 0.8, r.Defending.Total.XG)
+	assert.Equal(t, 1, r.Defending.Penalties.Sequences)
+	assert.Equal(t, 1, r.Defending.FreeKicks.Deliveries.OutsideBox.Shots)
+
+	assert.Equal(t, reports[0].Attacking, reports[1].Defending)
+
+	total := TeamReport{TeamID: home}
+	total.Add(reports[0])
+	total.Add(reports[0])
+	assert.Equal(t, 2, total.Matches)
+	assert.Equal(t, 6, total.Attacking.Total.Sequences)
+	assert.Equal(t, 1.0, total.Attacking.Total.XG)
+	assert.Equal(t, 33.33, total.Attacking.Total.ShotRate)
+	assert.Equal(t, 0.25, total.Attacking.Corners.Deliveries.Inswinging.XGPerSequence)
+}
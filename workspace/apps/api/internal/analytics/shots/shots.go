@@ -0,0 +1,199 @@
+// Package shots builds match shot maps and cumulative expected goals (xG) timelines.
+package shots
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Shot outcomes derived from the event type when the provider sends none.
+const (
+	OutcomeGoal      = "goal"
+	OutcomeOwnGoal   = "own_goal"
+	OutcomeSaved     = "saved"
+	OutcomeOffTarget = "off_target"
+	OutcomeBlocked   = "blocked"
+	OutcomePost      = "post"
+	OutcomeMissed    = "missed"
+	OutcomeUnknown   = "unknown"
+)
+
+// bodyPartFlags maps Opta body part qualifiers, sent as metadata keys, to body parts.
+var bodyPartFlags = []struct{ key, bodyPart string }{
+	{"Head", "head"},
+	{"RightFoot", "right_foot"},
+	{"LeftFoot", "left_foot"},
+	{"OtherBodyPart", "other"},
+}
+
+// Shot is a shot map entry. X and Y are canonical meters, attacking the goal at
+// x = pitch.Length.
+type Shot struct {
+	EventID    int32             `json:"event_id"`
+	TeamID     *int32            `json:"team_id,omitempty"`
+	PlayerID   *int32            `json:"player_id,omitempty"`
+	PlayerName string            `json:"player_name,omitempty"`
+	EventType  string            `json:"event_type"`
+	Clock      events.MatchClock `json:"clock"`
+	X          *float64          `json:"x,omitempty"`
+	Y          *float64          `json:"y,omitempty"`
+	XG         float64           `json:"xg"`
+	Outcome    string            `json:"outcome"`
+	BodyPart   string            `json:"body_part,omitempty"`
+	Situation  string            `json:"situation,omitempty"`
+	Goal       bool              `json:"goal"`
+	OwnGoal    bool              `json:"own_goal,omitempty"`
+}
+
+// IsShotMapEvent reports whether an event type belongs on a shot map or xG
+// timeline: attempts on goal and own goals.
+func IsShotMapEvent(eventType string) bool {
+	return xg.IsShotEvent(eventType) || events.Normalize(eventType) == events.EventTypeOwnGoal
+}
+
+// FromEvent builds a shot from an event's position and metadata. The provider
+// selects the coordinate system of events stored before coordinates were
+// normalized; when empty, the provider recorded in the metadata is used. The
+// caller fills in the event, team, player and clock. It returns false for events
+// that are not shots or own goals.
+func FromEvent(eventType string, x, y *float64, meta map[string]interface{}, provider string) (Shot, bool) {
+	if !IsShotMapEvent(eventType) {
+		return Shot{}, false
+	}
+	if provider == "" {
+		provider, _ = meta[xg.MetaProvider].(string)
+	}
+	frame := pitch.FrameFor(meta, provider)
+
+	shot := Shot{EventType: eventType, BodyPart: bodyPart(meta)}
+	if x != nil && y != nil {
+		mx, my := frame.Meters(*x, *y)
+		shot.X, shot.Y = &mx, &my
+	}
+
+	if events.Normalize(eventType) == events.EventTypeOwnGoal {
+		shot.Outcome = OutcomeOwnGoal
+		shot.Goal = true
+		shot.OwnGoal = true
+		return shot, true
+	}
+
+	shot.XG, _ = events.MetaFloat(meta, xg.MetaXG, "xg")
+	shot.Goal = xg.IsGoal(eventType, meta)
+	shot.Outcome = outcome(eventType, meta, shot.Goal)
+	if features, ok := xg.ShotFromEvent(eventType, x, y, meta, frame); ok {
+		shot.Situation = features.Situation
+	}
+	return shot, true
+}
+
+// Point is a shot on a team's xG timeline.
+type Point struct {
+	EventID      int32             `json:"event_id"`
+	PlayerID     *int32            `json:"player_id,omitempty"`
+	Clock        events.MatchClock `json:"clock"`
+	XG           float64           `json:"xg"`
+	CumulativeXG float64           `json:"cumulative_xg"`
+	Goal         bool              `json:"goal"`
+	OwnGoal      bool              `json:"own_goal,omitempty"` // Scored by the opponent into its own net
+}
+
+// TeamTimeline is a team's cumulative xG over the match clock.
+type TeamTimeline struct {
+	TeamID int32   `json:"team_id"`
+	XG     float64 `json:"xg"`
+	Goals  int     `json:"goals"`
+	Points []Point `json:"points"`
+}
+
+// Timeline builds the home and away teams' cumulative xG from a match's shots,
+// in match clock order. Own goals are marked on the other team's line and add no
+// xG. Shots without a team, or by a team not in the match, are skipped.
+func Timeline(shots []Shot, homeTeamID, awayTeamID int32) []TeamTimeline {
+	ordered := make([]Shot, len(shots))
+	copy(ordered, shots)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Clock.Before(ordered[j].Clock)
+	})
+
+	lines := []TeamTimeline{
+		{TeamID: homeTeamID, Points: []Point{}},
+		{TeamID: awayTeamID, Points: []Point{}},
+	}
+	for _, shot := range ordered {
+		if shot.TeamID == nil {
+			continue
+		}
+		var line *TeamTimeline
+		switch *shot.TeamID {
+		case homeTeamID:
+			line = &lines[0]
+			if shot.OwnGoal {
+				line = &lines[1]
+			}
+		case awayTeamID:
+			line = &lines[1]
+			if shot.OwnGoal {
+				line = &lines[0]
+			}
+		default:
+			continue
+		}
+
+		line.XG = xg.Round(line.XG + shot.XG)
+		if shot.Goal {
+			line.Goals++
+		}
+		line.Points = append(line.Points, Point{
+			EventID:      shot.EventID,
+			PlayerID:     shot.PlayerID,
+			Clock:        shot.Clock,
+			XG:           shot.XG,
+			CumulativeXG: line.XG,
+			Goal:         shot.Goal,
+			OwnGoal:      shot.OwnGoal,
+		})
+	}
+	return lines
+}
+
+// outcome returns the provider's shot outcome (e.g. StatsBomb "Saved", "Off T"),
+// or one derived from the event type.
+func outcome(eventType string, meta map[string]interface{}, goal bool) string {
+	if goal {
+		return OutcomeGoal
+	}
+	if o, _ := meta["outcome"].(string); o != "" {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(o)), " ", "_")
+	}
+	switch events.Normalize(eventType) {
+	case events.EventTypeShotOnTarget, events.EventTypeShotSaved:
+		return OutcomeSaved
+	case events.EventTypeShotOffTarget:
+		return OutcomeOffTarget
+	case events.EventTypeShotBlocked:
+		return OutcomeBlocked
+	case events.EventTypeShotPost, events.EventTypeShotWoodwork:
+		return OutcomePost
+	case events.EventTypePenaltyMiss:
+		return OutcomeMissed
+	}
+	return OutcomeUnknown
+}
+
+// bodyPart returns the shot's body part from a body_part field or Opta qualifiers.
+func bodyPart(meta map[string]interface{}) string {
+	if b, _ := meta["body_part"].(string); b != "" {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(b)), " ", "_")
+	}
+	for _, flag := range bodyPartFlags {
+		if _, ok := meta[flag.key]; ok {
+			return flag.bodyPart
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package shots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+func TestFromEvent(t *testing.T) {
+	x, y := 108.0, 40.0
+	shot, ok := FromEvent("shot", &x, &y, map[string]interface{}{
+		"xG":        0.21,
+		"outcome":   "Off T",
+		"body_part": "Right Foot",
+	}, "statsbomb")
+	require.True(t, ok)
+	assert.InDelta(t, 94.5, *shot.X, 0.001)
+	assert.InDelta(t, 34.0, *shot.Y, 0.001)
+	assert.Equal(t, 0.21, shot.XG)
+	assert.Equal(t, "off_t", shot.Outcome)
+	assert.Equal(t, "right_foot", shot.BodyPart)
+	assert.Equal(t, "open_play", shot.Situation)
+	assert.False(t, shot.Goal)
+
+	penalty, ok := FromEvent("penalty_goal", nil, nil, map[string]interface{}{"xg": "0.76"}, "")
+	require.True(t, ok)
+	assert.Equal(t, OutcomeGoal, penalty.Outcome)
+	assert.Equal(t, 0.76, penalty.XG)
+	assert.True(t, penalty.Goal)
+
+	_, ok = FromEvent("pass", &x, &y, nil, "")
+	assert.False(t, ok)
+}
+
+func TestTimeline(t *testing.T) {
+	home, away := int32(1), int32(2)
+	clock := func(period events.Period, minute int32) events.MatchClock {
+		return events.NewMatchClock(period, minute, 0, 0)
+	}
+
+	timeline := Timeline([]Shot{
+		{EventID: 3, TeamID: &home, Clock: clock(events.PeriodSecondHalf, 60), XG: 0.4, Goal: true},
+		{EventID: 1, TeamID: &home, Clock: clock(events.PeriodFirstHalf, 10), XG: 0.1},
+		{EventID: 2, TeamID: &away, Clock: clock(events.PeriodFirstHalf, 30), XG: 0.05},
+		{EventID: 4, TeamID: &home, Clock: clock(events.PeriodSecondHalf, 80), Goal: true, OwnGoal: true},
+	}, home, away)
+
+	require.Len(t, timeline, 2)
+	assert.Equal(t, home, timeline[0].TeamID)
+	assert.Equal(t, 0.5, timeline[0].XG)
+	assert.Equal(t, 1, timeline[0].Goals)
+	require.Len(t, timeline[0].Points, 2)
+	assert.Equal(t, int32(1), timeline[0].Points[0].EventID)
+	assert.Equal(t, 0.1, timeline[0].Points[0].CumulativeXG)
+	assert.Equal(t, 0.5, timeline[0].Points[1].CumulativeXG)
+
+	assert.Equal(t, away, timeline[1].TeamID)
+	assert.Equal(t, 0.05, timeline[1].XG)
+	assert.Equal(t, 1, timeline[1].Goals)
+	require.Len(t, timeline[1].Points, 2)
+	assert.True(t, timeline[1].Points[1].OwnGoal)
+	assert.Equal(t, 0.05, timeline[1].Points[1].CumulativeXG)
+}
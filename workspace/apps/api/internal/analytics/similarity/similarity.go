@@ -0,0 +1,189 @@
+// Package similarity finds statistically similar players by comparing weighted,
+// standardized per-90 profiles.
+package similarity
+
+import (
+	"math"
+	"sort"
+
+	"github.com/emiliospot/footie/api/internal/analytics/comparison"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+// Feature is a profile value compared between players, with its weight.
+type Feature struct {
+	Key    string  `json:"key"`
+	Label  string  `json:"label"`
+	Weight float64 `json:"weight"`
+}
+
+// features are the weighted profile of each position group.
+var features = map[comparison.Group][]Feature{
+	comparison.Forward: {
+		{Key: "goals", Label: "Goals", Weight: 1.5},
+		{Key: "shots", Label: "Shots", Weight: 1},
+		{Key: "shots_on_target", Label: "Shots on Target", Weight: 1},
+		{Key: "goal_conversion", Label: "Goal Conversion", Weight: 0.75},
+		{Key: "assists", Label: "Assists", Weight: 1},
+		{Key: "key_passes", Label: "Key Passes", Weight: 1},
+		{Key: "expected_threat", Label: "xT", Weight: 1.25},
+		{Key: "progressive_carries", Label: "Progressive Carries", Weight: 1},
+		{Key: "box_entries", Label: "Box Penetrations", Weight: 1.25},
+		{Key: "aerial_duels_won", Label: "Aerial Duels Won", Weight: 0.5},
+		{Key: "fouls_drawn", Label: "Fouls Drawn", Weight: 0.5},
+	},
+	comparison.Midfielder: {
+		{Key: "passes_completed", Label: "Passes Completed", Weight: 1.25},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Weight: 1},
+		{Key: "key_passes", Label: "Key Passes", Weight: 1},
+		{Key: "assists", Label: "Assists", Weight: 0.75},
+		{Key: "goals", Label: "Goals", Weight: 0.5},
+		{Key: "progressive_passes", Label: "Progressive Passes", Weight: 1.25},
+		{Key: "progressive_carries", Label: "Progressive Carries", Weight: 1},
+		{Key: "expected_threat", Label: "xT", Weight: 1},
+		{Key: "tackles_won", Label: "Tackles Won", Weight: 1},
+		{Key: "interceptions", Label: "Interceptions", Weight: 1},
+		{Key: "duels_won", Label: "Duels Won", Weight: 0.75},
+	},
+	comparison.Defender: {
+		{Key: "tackles_won", Label: "Tackles Won", Weight: 1},
+		{Key: "interceptions", Label: "Interceptions", Weight: 1.25},
+		{Key: "clearances", Label: "Clearances", Weight: 1.25},
+		{Key: "blocked_shots", Label: "Blocked Shots", Weight: 0.75},
+		{Key: "aerial_duels_won", Label: "Aerial Duels Won", Weight: 1.25},
+		{Key: "duel_success", Label: "Duel Success", Weight: 1},
+		{Key: "passes_completed", Label: "Passes Completed", Weight: 1},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Weight: 0.75},
+		{Key: "progressive_passes", Label: "Progressive Passes", Weight: 1},
+		{Key: "progressive_carries", Label: "Progressive Carries", Weight: 0.5},
+	},
+	comparison.Goalkeeper: {
+		{Key: "saves", Label: "Saves", Weight: 1.25},
+		{Key: "save_percentage", Label: "Save Percentage", Weight: 1.5},
+		{Key: "goals_conceded", Label: "Goals Conceded", Weight: 1},
+		{Key: "clean_sheets", Label: "Clean Sheets", Weight: 1},
+		{Key: "penalties_saved", Label: "Penalties Saved", Weight: 0.5},
+		{Key: "passes_completed", Label: "Passes Completed", Weight: 0.75},
+		{Key: "pass_accuracy", Label: "Pass Accuracy", Weight: 0.75},
+	},
+}
+
+// FeaturesFor returns a position group's weighted features.
+func FeaturesFor(g comparison.Group) []Feature {
+	return features[g]
+}
+
+// Threat holds a player's event-derived season totals.
+type Threat struct {
+	ExpectedThreat     float64
+	ProgressivePasses  int64
+	ProgressiveCarries int64
+	BoxEntries         int64
+}
+
+// Values returns a player's profile: the comparison metrics of their season
+// statistics plus xT and ball progression per 90 minutes.
+func Values(s *models.PlayerStatistics, t Threat) map[string]float64 {
+	values := comparison.Values(s)
+	p90 := func(v float64) float64 {
+		if s.MinutesPlayed <= 0 {
+			return 0
+		}
+		return v * 90 / float64(s.MinutesPlayed)
+	}
+	values["expected_threat"] = p90(t.ExpectedThreat)
+	values["progressive_passes"] = p90(float64(t.ProgressivePasses))
+	values["progressive_carries"] = p90(float64(t.ProgressiveCarries))
+	values["box_entries"] = p90(float64(t.BoxEntries))
+	return values
+}
+
+// Contribution is one feature's share of a candidate's similarity.
+type Contribution struct {
+	Feature
+	Value          float64 `json:"value"`           // The target player's per-90 value or rate
+	CandidateValue float64 `json:"candidate_value"` // The candidate's
+	// Contribution is the feature's part of the similarity; contributions add up
+	// to it. Positive when both players are on the same side of the pool average.
+	Contribution float64 `json:"contribution"`
+}
+
+// Match is a candidate's similarity to the target, from -100 (opposite
+// profiles) to 100 (the same profile).
+type Match struct {
+	Index         int            `json:"-"` // Position in the candidates
+	Similarity    float64        `json:"similarity"`
+	Contributions []Contribution `json:"contributions"` // By contribution, largest first
+}
+
+// Nearest ranks candidates by similarity to the target, most similar first.
+// Each feature is standardized over the target and the candidates and weighted,
+// and similarity is the cosine of the angle between the weighted profiles, so
+// players with the same strengths and weaknesses relative to the pool are
+// similar whatever their volume.
+func Nearest(fs []Feature, target map[string]float64, candidates []map[string]float64) []Match {
+	mean := make([]float64, len(fs))
+	std := make([]float64, len(fs))
+	n := float64(len(candidates) + 1)
+	for j, f := range fs {
+		sum := target[f.Key]
+		for _, c := range candidates {
+			sum += c[f.Key]
+		}
+		mean[j] = sum / n
+
+		sq := math.Pow(target[f.Key]-mean[j], 2)
+		for _, c := range candidates {
+			sq += math.Pow(c[f.Key]-mean[j], 2)
+		}
+		std[j] = math.Sqrt(sq / n)
+	}
+	// weighted returns a profile's standardized values scaled by the square root
+	// of their weights, and its norm
+	weighted := func(values map[string]float64) ([]float64, float64) {
+		z := make([]float64, len(fs))
+		var norm float64
+		for j, f := range fs {
+			if std[j] > 0 {
+				z[j] = (values[f.Key] - mean[j]) / std[j] * math.Sqrt(f.Weight)
+			}
+			norm += z[j] * z[j]
+		}
+		return z, math.Sqrt(norm)
+	}
+
+	zt, normT := weighted(target)
+	matches := make([]Match, 0, len(candidates))
+	for i, c := range candidates {
+		zc, normC := weighted(c)
+		m := Match{Index: i, Contributions: make([]Contribution, 0, len(fs))}
+		for j, f := range fs {
+			var part float64
+			if normT > 0 && normC > 0 {
+				part = zt[j] * zc[j] / (normT * normC) * 100
+			}
+			m.Similarity += part
+			m.Contributions = append(m.Contributions, Contribution{
+				Feature:        f,
+				Value:          round(target[f.Key]),
+				CandidateValue: round(c[f.Key]),
+				Contribution:   round(part),
+			})
+		}
+		m.Similarity = round(m.Similarity)
+		sort.SliceStable(m.Contributions, func(a, b int) bool {
+			return m.Contributions[a].Contribution > m.Contributions[b].Contribution
+		})
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].Similarity > matches[b].Similarity
+	})
+	return matches
+}
+
+// round rounds to 2 decimal places.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
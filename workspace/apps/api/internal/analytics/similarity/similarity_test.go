@@ -0,0 +1,52 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/domain/models"
+)
+
+func TestNearest(t *testing.T) {
+	fs := []Feature{
+		{Key: "goals", Label: "Goals", Weight: 2},
+		{Key: "key_passes", Label: "Key Passes", Weight: 1},
+	}
+	target := map[string]float64{"goals": 0.8, "key_passes": 1}
+	candidates := []map[string]float64{
+		{"goals": 0.2, "key_passes": 3},   // Creator
+		{"goals": 0.7, "key_passes": 1.2}, // Finisher like the target
+		{"goals": 0.4, "key_passes": 2},
+	}
+
+	matches := Nearest(fs, target, candidates)
+	require.Len(t, matches, 3)
+	assert.Equal(t, 1, matches[0].Index)
+	assert.Equal(t, 0, matches[2].Index)
+	assert.Greater(t, matches[0].Similarity, 90.0)
+	assert.Less(t, matches[2].Similarity, 0.0)
+
+	best := matches[0]
+	require.Len(t, best.Contributions, 2)
+	assert.Equal(t, "goals", best.Contributions[0].Key)
+	assert.Equal(t, 0.8, best.Contributions[0].Value)
+	assert.Equal(t, 0.7, best.Contributions[0].CandidateValue)
+	assert.InDelta(t, best.Similarity, best.Contributions[0].Contribution+best.Contributions[1].Contribution, 0.02)
+}
+
+func TestNearestWithoutSpread(t *testing.T) {
+	fs := []Feature{{Key: "goals", Weight: 1}}
+	matches := Nearest(fs, map[string]float64{"goals": 1}, []map[string]float64{{"goals": 1}})
+	require.Len(t, matches, 1)
+	assert.Equal(t, 0.0, matches[0].Similarity)
+}
+
+func TestValues(t *testing.T) {
+	values := Values(&models.PlayerStatistics{MinutesPlayed: 1800, Goals: 10}, Threat{ExpectedThreat: 4, BoxEntries: 30})
+	assert.Equal(t, 0.5, values["goals"])
+	assert.Equal(t, 0.2, values["expected_threat"])
+	assert.Equal(t, 1.5, values["box_entries"])
+	assert.Equal(t, 0.0, values["progressive_passes"])
+}
@@ -0,0 +1,191 @@
+// Package winprob estimates each side's in-play win probability from the
+// pre-match expected goals, the score, the time remaining and sendings-off.
+// The goals still to come are Poisson distributed, scaled from the pre-match
+// expectation to the minutes left.
+package winprob
+
+import (
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// Config holds the in-play model parameters.
+type Config struct {
+	AddedTime float64 // Stoppage minutes expected at the end of each half
+	SentOff   float64 // Scoring rate multiplier for each player a side is down
+	Opponent  float64 // Scoring rate multiplier for each player a side is up
+	MaxGoals  int     // Goals still to come summed per team; the rest is renormalized
+}
+
+// DefaultConfig follows the usual estimates of a sending-off: the side down a
+// player scores about a third less and its opponent about a third more.
+var DefaultConfig = Config{
+	AddedTime: 4,
+	SentOff:   0.7,
+	Opponent:  1.3,
+	MaxGoals:  10,
+}
+
+// State is the score and sendings-off after an event.
+type State struct {
+	HomeGoals    int32 `json:"home_goals"`
+	AwayGoals    int32 `json:"away_goals"`
+	HomeRedCards int32 `json:"home_red_cards"`
+	AwayRedCards int32 `json:"away_red_cards"`
+}
+
+// Point is the outcome probabilities after an event.
+type Point struct {
+	EventID int32             `json:"event_id"`
+	Clock   events.MatchClock `json:"clock"`
+	State
+	Home float64 `json:"home"` // Probability of a home win
+	Draw float64 `json:"draw"`
+	Away float64 `json:"away"`
+}
+
+// Model predicts a match's outcome from any point in it.
+type Model struct {
+	cfg       Config
+	homeGoals float64 // Expected over the whole match, from kick-off
+	awayGoals float64
+}
+
+// New creates a model for a match from its pre-match expected goals.
+func New(cfg Config, homeGoals, awayGoals float64) *Model {
+	return &Model{cfg: cfg, homeGoals: homeGoals, awayGoals: awayGoals}
+}
+
+// Apply records an event in the state if it changes the score or sends a
+// player off. Own goals carry the team of the player who scored them.
+func (s *State) Apply(e *matchevent.Event, homeTeamID int32) {
+	if e.TeamID == nil || e.Clock.Period == events.PeriodPenalties {
+		return
+	}
+	home := *e.TeamID == homeTeamID
+	switch events.Normalize(e.EventType) {
+	case events.EventTypeGoal, events.EventTypePenaltyGoal:
+		if home {
+			s.HomeGoals++
+		} else {
+			s.AwayGoals++
+		}
+	case events.EventTypeOwnGoal:
+		if home {
+			s.AwayGoals++
+		} else {
+			s.HomeGoals++
+		}
+	case events.EventTypeRedCard, events.EventTypeSecondYellow:
+		if home {
+			s.HomeRedCards++
+		} else {
+			s.AwayRedCards++
+		}
+	}
+}
+
+// Next applies an event to the state before it and returns the point after
+// it. Penalty shootout kicks have no point.
+func (m *Model) Next(s State, homeTeamID int32, e *matchevent.Event) (Point, bool) {
+	if e.Clock.Period == events.PeriodPenalties {
+		return Point{}, false
+	}
+	s.Apply(e, homeTeamID)
+	p := Point{EventID: e.ID, Clock: e.Clock, State: s}
+	p.Home, p.Draw, p.Away = m.Predict(s, e.Clock)
+	return p, true
+}
+
+// Timeline returns the point after each of a match's events. Events must be in
+// match clock order.
+func (m *Model) Timeline(homeTeamID int32, matchEvents []matchevent.Event) []Point {
+	var s State
+	points := make([]Point, 0, len(matchEvents))
+	for i := range matchEvents {
+		if p, ok := m.Next(s, homeTeamID, &matchEvents[i]); ok {
+			points = append(points, p)
+			s = p.State
+		}
+	}
+	return points
+}
+
+// Predict returns the probabilities of a home win, a draw and an away win from
+// a state at a point in the match. A draw after extra time is left a draw.
+func (m *Model) Predict(s State, clock events.MatchClock) (home, draw, away float64) {
+	share := m.remaining(clock) / (90 + 2*m.cfg.AddedTime)
+	homeRate, awayRate := m.homeGoals*share, m.awayGoals*share
+
+	down := float64(s.HomeRedCards - s.AwayRedCards)
+	switch {
+	case down > 0:
+		homeRate *= math.Pow(m.cfg.SentOff, down)
+		awayRate *= math.Pow(m.cfg.Opponent, down)
+	case down < 0:
+		homeRate *= math.Pow(m.cfg.Opponent, -down)
+		awayRate *= math.Pow(m.cfg.SentOff, -down)
+	}
+
+	homeGoals := distribution(homeRate, m.cfg.MaxGoals)
+	awayGoals := distribution(awayRate, m.cfg.MaxGoals)
+	var total float64
+	for h, ph := range homeGoals {
+		for a, pa := range awayGoals {
+			prob := ph * pa
+			total += prob
+			switch diff := int(s.HomeGoals-s.AwayGoals) + h - a; {
+			case diff > 0:
+				home += prob
+			case diff < 0:
+				away += prob
+			default:
+				draw += prob
+			}
+		}
+	}
+	if total > 0 {
+		home, draw, away = home/total, draw/total, away/total
+	}
+	return round4(home), round4(draw), round4(away)
+}
+
+// remaining returns the minutes left to play from a point in the match,
+// including the expected stoppage time of the current and later halves. Extra
+// time only counts once it has started.
+func (m *Model) remaining(clock events.MatchClock) float64 {
+	end, halves := 90.0, 0.0
+	switch clock.Period {
+	case events.PeriodFirstHalf:
+		halves = 1
+	case events.PeriodExtraTimeFirst:
+		end, halves = 120, 1
+	case events.PeriodExtraTimeSecond:
+		end = 120
+	}
+
+	played := float64(clock.Minute) + float64(clock.Second)/60
+	added := m.cfg.AddedTime
+	if clock.Stoppage > 0 {
+		played = float64(clock.Minute)
+		added = math.Max(0, added-float64(clock.Stoppage)-float64(clock.Second)/60)
+	}
+	return math.Max(0, end-played) + added + halves*m.cfg.AddedTime
+}
+
+// distribution returns the Poisson probabilities of 0 to maxGoals goals.
+func distribution(lambda float64, maxGoals int) []float64 {
+	probs := make([]float64, maxGoals+1)
+	probs[0] = math.Exp(-lambda)
+	for k := 1; k <= maxGoals; k++ {
+		probs[k] = probs[k-1] * lambda / float64(k)
+	}
+	return probs
+}
+
+// round4 rounds to 4 decimal places.
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,66 @@
+package winprob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+func clock(period events.Period, minute, stoppage int32) events.MatchClock {
+	return events.NewMatchClock(period, minute, stoppage, 0)
+}
+
+func TestRemaining(t *testing.T) {
+	m := New(DefaultConfig, 1.5, 1.2)
+	assert.InDelta(t, 98, m.remaining(clock(events.PeriodFirstHalf, 0, 0)), 1e-9)
+	assert.InDelta(t, 51, m.remaining(clock(events.PeriodFirstHalf, 45, 2)), 1e-9)
+	assert.InDelta(t, 34, m.remaining(clock(events.PeriodSecondHalf, 60, 0)), 1e-9)
+	assert.InDelta(t, 0, m.remaining(clock(events.PeriodSecondHalf, 90, 6)), 1e-9)
+	assert.InDelta(t, 19, m.remaining(clock(events.PeriodExtraTimeSecond, 105, 0)), 1e-9)
+}
+
+func TestPredict(t *testing.T) {
+	m := New(DefaultConfig, 1.5, 1.2)
+
+	home, draw, away := m.Predict(State{}, clock(events.PeriodFirstHalf, 0, 0))
+	assert.Greater(t, home, away)
+	assert.InDelta(t, 1, home+draw+away, 0.001)
+
+	// A late lead is nearly safe, and certain once stoppage time has run out
+	home, _, _ = m.Predict(State{HomeGoals: 1}, clock(events.PeriodSecondHalf, 89, 0))
+	assert.Greater(t, home, 0.85)
+	home, draw, away = m.Predict(State{HomeGoals: 1}, clock(events.PeriodSecondHalf, 90, 6))
+	assert.Equal(t, [3]float64{1, 0, 0}, [3]float64{home, draw, away})
+
+	// A sending-off swings a level match towards the opponent
+	level, _, _ := m.Predict(State{}, clock(events.PeriodFirstHalf, 20, 0))
+	down, _, downAway := m.Predict(State{HomeRedCards: 1}, clock(events.PeriodFirstHalf, 20, 0))
+	assert.Less(t, down, level)
+	assert.Greater(t, downAway, down)
+}
+
+func TestTimeline(t *testing.T) {
+	home, away := int32(1), int32(2)
+	m := New(DefaultConfig, 1.5, 1.2)
+	points := m.Timeline(home, []matchevent.Event{
+		{ID: 1, EventType: "pass", TeamID: &home, Clock: clock(events.PeriodFirstHalf, 5, 0)},
+		{ID: 2, EventType: "own_goal", TeamID: &home, Clock: clock(events.PeriodFirstHalf, 20, 0)},
+		{ID: 3, EventType: "second_yellow_card", TeamID: &away, Clock: clock(events.PeriodSecondHalf, 50, 0)},
+		{ID: 4, EventType: "goal", TeamID: &home, Clock: clock(events.PeriodSecondHalf, 70, 0)},
+		{ID: 5, EventType: "penalty_goal", TeamID: &home, Clock: clock(events.PeriodPenalties, 120, 0)},
+	})
+	require.Len(t, points, 4) // Shootout kicks are skipped
+
+	assert.Equal(t, State{}, points[0].State)
+	assert.Equal(t, State{AwayGoals: 1}, points[1].State)
+	assert.Greater(t, points[1].Away, points[0].Away)
+	assert.Equal(t, State{AwayGoals: 1, AwayRedCards: 1}, points[2].State)
+	withoutCard, _, _ := m.Predict(points[1].State, points[2].Clock)
+	assert.Greater(t, points[2].Home, withoutCard)
+	assert.Equal(t, State{HomeGoals: 1, AwayGoals: 1, AwayRedCards: 1}, points[3].State)
+	assert.Equal(t, int32(4), points[3].EventID)
+}
@@ -0,0 +1,165 @@
+package xg
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// goalWidth is the distance between the posts in meters.
+const goalWidth = 7.32
+
+// Shot situations. Open play is the baseline and has no coefficient.
+const (
+	SituationOpenPlay      = "open_play"
+	SituationCorner        = "corner"
+	SituationSetPiece      = "set_piece"
+	SituationFreeKick      = "free_kick" // Direct free kick
+	SituationCounterAttack = "counter_attack"
+	SituationPenalty       = "penalty"
+)
+
+// Shot holds the features the model scores.
+type Shot struct {
+	Distance      float64 // Meters from the center of the goal
+	Angle         float64 // Radians subtended by the goal mouth
+	Header        bool
+	OtherBodyPart bool // Neither foot nor head
+	Situation     string
+}
+
+// IsShotEvent reports whether an event type is an attempt on goal that gets an xG value.
+// Own goals are excluded.
+func IsShotEvent(eventType string) bool {
+	t := events.Normalize(eventType)
+	switch t {
+	case events.EventTypeGoal, events.EventTypePenalty, events.EventTypePenaltyGoal, events.EventTypePenaltyMiss:
+		return true
+	}
+	return t.IsShot()
+}
+
+// IsGoal reports whether a shot event was scored.
+func IsGoal(eventType string, meta map[string]interface{}) bool {
+	switch events.Normalize(eventType) {
+	case events.EventTypeGoal, events.EventTypePenaltyGoal:
+		return true
+	}
+	outcome, _ := meta["outcome"].(string)
+	return strings.EqualFold(outcome, "goal")
+}
+
+// ShotFromEvent builds shot features from an event's position, in the given frame,
+// and metadata. Shots attack the goal at x = pitch.Length. It returns false for
+// non-shots and for non-penalty shots without a position.
+func ShotFromEvent(eventType string, x, y *float64, meta map[string]interface{}, frame pitch.Frame) (Shot, bool) {
+	if !IsShotEvent(eventType) {
+		return Shot{}, false
+	}
+
+	shot := Shot{Situation: situation(eventType, meta)}
+	flags := metaFlags(meta)
+	bodyPart := normalizeToken(metaString(meta, "body_part"))
+	shot.Header = strings.Contains(bodyPart, "head") || flags["head"] || flags["header"]
+	shot.OtherBodyPart = !shot.Header && (strings.Contains(bodyPart, "other") || flags["otherbodypart"])
+
+	if shot.Situation == SituationPenalty {
+		shot.Distance = 11
+		shot.Angle = goalAngle(11, 0)
+		return shot, true
+	}
+	if x == nil || y == nil {
+		return Shot{}, false
+	}
+
+	mx, my := frame.Meters(*x, *y)
+	dx := pitch.Length - mx
+	dy := my - pitch.Width/2
+	if dx < 0 {
+		dx = 0
+	}
+	shot.Distance = math.Hypot(dx, dy)
+	shot.Angle = goalAngle(dx, dy)
+	return shot, true
+}
+
+// goalAngle returns the angle between the posts as seen from a point dx meters in
+// front of the goal line and dy meters off the center line.
+func goalAngle(dx, dy float64) float64 {
+	half := goalWidth / 2
+	angle := math.Atan2(goalWidth*dx, dx*dx+dy*dy-half*half)
+	if angle < 0 {
+		angle += math.Pi
+	}
+	return angle
+}
+
+// situation classifies how a shot came about from the event type, the StatsBomb
+// shot_type/play_pattern fields, a generic situation field or Opta qualifiers.
+func situation(eventType string, meta map[string]interface{}) string {
+	switch events.Normalize(eventType) {
+	case events.EventTypePenalty, events.EventTypePenaltyGoal, events.EventTypePenaltyMiss:
+		return SituationPenalty
+	}
+
+	shotType := normalizeToken(metaString(meta, "shot_type"))
+	pattern := normalizeToken(metaString(meta, "situation") + " " + metaString(meta, "play_pattern"))
+	flags := metaFlags(meta)
+
+	switch {
+	case strings.Contains(shotType, "penalty") || strings.Contains(pattern, "penalty") || flags["penalty"]:
+		return SituationPenalty
+	case strings.Contains(shotType, "freekick") || flags["directfreekick"] || strings.Contains(pattern, "directfreekick"):
+		return SituationFreeKick
+	case strings.Contains(shotType, "corner") || strings.Contains(pattern, "corner") || flags["fromcorner"]:
+		return SituationCorner
+	case strings.Contains(pattern, "setpiece") || strings.Contains(pattern, "freekick") ||
+		strings.Contains(pattern, "throwin") || flags["setpiece"] || flags["freekick"]:
+		return SituationSetPiece
+	case strings.Contains(pattern, "counter") || strings.Contains(pattern, "fastbreak") || flags["fastbreak"]:
+		return SituationCounterAttack
+	}
+	return SituationOpenPlay
+}
+
+// metaFlags returns the normalized metadata keys that are set. Opta qualifiers
+// arrive as keys (e.g. "Head", "FromCorner") with an empty value; other providers
+// send booleans or 0/1, so {"penalty": false} leaves the flag unset.
+func metaFlags(meta map[string]interface{}) map[string]bool {
+	flags := make(map[string]bool, len(meta))
+	for key, value := range meta {
+		if flagSet(value) {
+			flags[normalizeToken(key)] = true
+		}
+	}
+	return flags
+}
+
+// flagSet reports whether a metadata value marks its key as set.
+func flagSet(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v == ""
+	}
+	return false
+}
+
+// normalizeToken lowercases a value and drops spaces, underscores and dashes,
+// so "Free Kick", "free_kick" and "FreeKick" compare equal.
+func normalizeToken(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(s))
+}
+
+// metaString returns a string metadata value.
+func metaString(meta map[string]interface{}, key string) string {
+	s, _ := meta[key].(string)
+	return s
+}
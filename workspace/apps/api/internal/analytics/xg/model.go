@@ -0,0 +1,189 @@
+// Package xg implements a logistic expected goals (xG) model for shots that arrive
+// without a provider xG value.
+package xg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Metadata keys written on events scored by the model. Provider xG is stored under
+// the same "xG" key but without a model version, and is never overwritten.
+const (
+	MetaXG       = "xG"
+	MetaModel    = "xg_model"
+	MetaProvider = "provider"
+)
+
+//go:embed default_model.json
+var defaultModel []byte
+
+// Model holds logistic regression coefficients. The log-odds of a goal is the
+// intercept plus each coefficient times its feature; penalties use a fixed value.
+type Model struct {
+	Version       string             `json:"version"`
+	Intercept     float64            `json:"intercept"`
+	Distance      float64            `json:"distance"`        // Per meter
+	Angle         float64            `json:"angle"`           // Per radian
+	Header        float64            `json:"header"`          // Applied to headers
+	OtherBodyPart float64            `json:"other_body_part"` // Applied to shots with neither foot nor head
+	Situations    map[string]float64 `json:"situations"`      // Keyed by situation; open play is 0
+	PenaltyXG     float64            `json:"penalty_xg"`
+	Shots         int                `json:"shots,omitempty"` // Number of shots the model was trained on
+}
+
+// Default returns the built-in model.
+func Default() *Model {
+	m, err := parse(defaultModel)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in xG model: %v", err))
+	}
+	return m
+}
+
+// Load reads a model from a JSON coefficients file. An empty path returns the
+// built-in model.
+func Load(path string) (*Model, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xG model: %w", err)
+	}
+	return parse(data)
+}
+
+// Save writes the model as indented JSON.
+func (m *Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode xG model: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write xG model: %w", err)
+	}
+	return nil
+}
+
+func parse(data []byte) (*Model, error) {
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse xG model: %w", err)
+	}
+	if m.Version == "" {
+		return nil, fmt.Errorf("xG model has no version")
+	}
+	return &m, nil
+}
+
+// Predict returns the probability that a shot is scored.
+func (m *Model) Predict(shot Shot) float64 {
+	if shot.Situation == SituationPenalty {
+		return m.PenaltyXG
+	}
+	return sigmoid(dot(m.coefficients(), features(shot)))
+}
+
+// Fill scores a shot event that has no xG and returns its updated metadata JSON.
+// Shots scored by an older model version are rescored; provider xG is left alone.
+// It returns false when the metadata should not change. The provider selects the
+// coordinate system of events stored before coordinates were normalized and is
+// recorded; when empty, the provider recorded in the metadata is used.
+func (m *Model) Fill(eventType string, x, y *float64, metadata, provider string) (string, bool) {
+	if !IsShotEvent(eventType) {
+		return metadata, false
+	}
+
+	meta := map[string]interface{}{}
+	if metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+			return metadata, false
+		}
+	}
+
+	version, scored := meta[MetaModel].(string)
+	if !scored && (meta[MetaXG] != nil || meta["xg"] != nil) {
+		return metadata, false
+	}
+	if scored && version == m.Version {
+		return metadata, false
+	}
+
+	if provider == "" {
+		provider, _ = meta[MetaProvider].(string)
+	} else {
+		meta[MetaProvider] = provider
+	}
+	shot, ok := ShotFromEvent(eventType, x, y, meta, pitch.FrameFor(meta, provider))
+	if !ok {
+		return metadata, false
+	}
+
+	meta[MetaXG] = Round(m.Predict(shot))
+	meta[MetaModel] = m.Version
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return metadata, false
+	}
+	return string(data), true
+}
+
+// Round rounds an xG value to the four decimals xG is stored with.
+func Round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
+
+// situationOrder fixes the position of each situation in the feature vector.
+var situationOrder = []string{SituationCorner, SituationSetPiece, SituationFreeKick, SituationCounterAttack}
+
+// features returns the feature vector for a shot, starting with the intercept term.
+func features(shot Shot) []float64 {
+	f := []float64{1, shot.Distance, shot.Angle, boolFloat(shot.Header), boolFloat(shot.OtherBodyPart)}
+	for _, s := range situationOrder {
+		f = append(f, boolFloat(shot.Situation == s))
+	}
+	return f
+}
+
+// coefficients returns the model coefficients in feature vector order.
+func (m *Model) coefficients() []float64 {
+	c := []float64{m.Intercept, m.Distance, m.Angle, m.Header, m.OtherBodyPart}
+	for _, s := range situationOrder {
+		c = append(c, m.Situations[s])
+	}
+	return c
+}
+
+// setCoefficients stores coefficients given in feature vector order.
+func (m *Model) setCoefficients(c []float64) {
+	m.Intercept, m.Distance, m.Angle, m.Header, m.OtherBodyPart = c[0], c[1], c[2], c[3], c[4]
+	m.Situations = make(map[string]float64, len(situationOrder))
+	for i, s := range situationOrder {
+		m.Situations[s] = c[5+i]
+	}
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+package xg
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestShotFromEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		x, y      *float64
+		meta      map[string]interface{}
+		frame     pitch.Frame
+		wantOK    bool
+		wantDist  float64
+		want      Shot
+	}{
+		{
+			name:      "statsbomb penalty spot",
+			eventType: "shot",
+			x:         ptr(108),
+			y:         ptr(40),
+			meta:      map[string]interface{}{"body_part": "Right Foot"},
+			frame:     pitch.StatsBomb,
+			wantOK:    true,
+			wantDist:  10.5,
+			want:      Shot{Situation: SituationOpenPlay},
+		},
+		{
+			name:      "opta header from corner",
+			eventType: "goal",
+			x:         ptr(95),
+			y:         ptr(50),
+			meta:      map[string]interface{}{"Head": "", "FromCorner": ""},
+			frame:     pitch.Opta,
+			wantOK:    true,
+			wantDist:  5.25,
+			want:      Shot{Header: true, Situation: SituationCorner},
+		},
+		{
+			name:      "penalty without position",
+			eventType: "penalty_miss",
+			wantOK:    true,
+			wantDist:  11,
+			want:      Shot{Situation: SituationPenalty},
+		},
+		{
+			name:      "statsbomb direct free kick",
+			eventType: "shot_saved",
+			x:         ptr(96),
+			y:         ptr(40),
+			meta:      map[string]interface{}{"shot_type": "Free Kick", "play_pattern": "From Free Kick"},
+			frame:     pitch.StatsBomb,
+			wantOK:    true,
+			wantDist:  21,
+			want:      Shot{Situation: SituationFreeKick},
+		},
+		{
+			name:      "flags set to false",
+			eventType: "shot",
+			x:         ptr(95),
+			y:         ptr(50),
+			meta:      map[string]interface{}{"penalty": false, "header": false, "FromCorner": 0.0},
+			frame:     pitch.Opta,
+			wantOK:    true,
+			wantDist:  5.25,
+			want:      Shot{Situation: SituationOpenPlay},
+		},
+		{
+			name:      "flags set to true",
+			eventType: "shot",
+			x:         ptr(95),
+			y:         ptr(50),
+			meta:      map[string]interface{}{"header": true, "fast_break": 1.0},
+			frame:     pitch.Opta,
+			wantOK:    true,
+			wantDist:  5.25,
+			want:      Shot{Header: true, Situation: SituationCounterAttack},
+		},
+		{
+			name:      "shot without position",
+			eventType: "shot",
+		},
+		{
+			name:      "own goal is not a shot",
+			eventType: "own_goal",
+			x:         ptr(99),
+			y:         ptr(50),
+			frame:     pitch.Opta,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shot, ok := ShotFromEvent(tt.eventType, tt.x, tt.y, tt.meta, tt.frame)
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.InDelta(t, tt.wantDist, shot.Distance, 1e-9)
+			assert.Equal(t, tt.want.Header, shot.Header)
+			assert.Equal(t, tt.want.Situation, shot.Situation)
+		})
+	}
+}
+
+func TestDefaultModel_Predict(t *testing.T) {
+	m := Default()
+
+	near := m.Predict(Shot{Distance: 6, Angle: goalAngle(6, 0), Situation: SituationOpenPlay})
+	spot := m.Predict(Shot{Distance: 11, Angle: goalAngle(11, 0), Situation: SituationOpenPlay})
+	far := m.Predict(Shot{Distance: 25, Angle: goalAngle(25, 0), Situation: SituationOpenPlay})
+	header := m.Predict(Shot{Distance: 11, Angle: goalAngle(11, 0), Header: true, Situation: SituationOpenPlay})
+
+	assert.Greater(t, near, spot)
+	assert.Greater(t, spot, far)
+	assert.Less(t, header, spot)
+	assert.InDelta(t, 0.2, spot, 0.05)
+	assert.Equal(t, 0.76, m.Predict(Shot{Situation: SituationPenalty}))
+}
+
+func TestModel_Fill(t *testing.T) {
+	m := Default()
+
+	t.Run("fills missing xG", func(t *testing.T) {
+		out, ok := m.Fill("shot", ptr(90), ptr(50), `{"outcome":"Saved"}`, "opta")
+		require.True(t, ok)
+
+		var meta map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &meta))
+		assert.Equal(t, "Saved", meta["outcome"])
+		assert.Equal(t, m.Version, meta[MetaModel])
+		assert.Greater(t, meta[MetaXG], 0.0)
+	})
+
+	t.Run("keeps provider xG", func(t *testing.T) {
+		_, ok := m.Fill("shot", ptr(90), ptr(50), `{"xG":0.31}`, "statsbomb")
+		assert.False(t, ok)
+	})
+
+	t.Run("skips current model version", func(t *testing.T) {
+		_, ok := m.Fill("shot", ptr(90), ptr(50), `{"xG":0.1,"xg_model":"default-1"}`, "")
+		assert.False(t, ok)
+	})
+
+	t.Run("rescores older model version", func(t *testing.T) {
+		out, ok := m.Fill("shot", ptr(108), ptr(40), `{"xG":0.9,"xg_model":"old","provider":"statsbomb"}`, "")
+		require.True(t, ok)
+		assert.Contains(t, out, `"xg_model":"default-1"`)
+		assert.NotContains(t, out, `"xG":0.9`)
+	})
+
+	t.Run("ignores non-shots", func(t *testing.T) {
+		_, ok := m.Fill("pass", ptr(90), ptr(50), "", "")
+		assert.False(t, ok)
+	})
+}
+
+func TestTrain_RecoversCoefficients(t *testing.T) {
+	truth := Default()
+	rng := rand.New(rand.NewSource(1))
+
+	samples := make([]Sample, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		dx := 2 + rng.Float64()*30
+		dy := (rng.Float64() - 0.5) * 40
+		shot := Shot{Header: rng.Float64() < 0.2, Situation: SituationOpenPlay}
+		if rng.Float64() < 0.2 {
+			shot.Situation = situationOrder[rng.Intn(len(situationOrder))]
+		}
+		shot.Distance = math.Hypot(dx, dy)
+		shot.Angle = goalAngle(dx, dy)
+		samples = append(samples, Sample{Shot: shot, Goal: rng.Float64() < truth.Predict(shot)})
+	}
+
+	m, err := Train(samples, "test-1", truth)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-1", m.Version)
+	assert.InDelta(t, truth.Distance, m.Distance, 0.03)
+	assert.InDelta(t, truth.Angle, m.Angle, 0.4)
+	assert.InDelta(t, truth.Header, m.Header, 0.3)
+	assert.Equal(t, truth.PenaltyXG, m.PenaltyXG)
+
+	_, err = Train(samples[:10], "test-2", truth)
+	assert.Error(t, err)
+}
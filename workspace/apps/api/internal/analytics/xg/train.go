@@ -0,0 +1,165 @@
+package xg
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// minTrainingShots is the smallest sample the trainer accepts.
+	minTrainingShots = 200
+
+	// minPenalties is the number of penalties needed to estimate the penalty conversion
+	// rate; with fewer the base model's value is kept.
+	minPenalties = 30
+
+	// ridge is the L2 penalty that keeps rare situations from diverging.
+	ridge = 1e-3
+
+	maxIterations = 50
+	tolerance     = 1e-8
+)
+
+// Sample is a historical shot with its result.
+type Sample struct {
+	Shot Shot
+	Goal bool
+}
+
+// Train fits a model to historical shots by iteratively reweighted least squares.
+// Penalties are not part of the regression; their conversion rate becomes PenaltyXG
+// when there are enough of them, otherwise base.PenaltyXG is kept.
+func Train(samples []Sample, version string, base *Model) (*Model, error) {
+	var xs [][]float64
+	var ys []float64
+	var penalties, penaltyGoals int
+	for _, s := range samples {
+		if s.Shot.Situation == SituationPenalty {
+			penalties++
+			if s.Goal {
+				penaltyGoals++
+			}
+			continue
+		}
+		xs = append(xs, features(s.Shot))
+		ys = append(ys, boolFloat(s.Goal))
+	}
+
+	if len(xs) < minTrainingShots {
+		return nil, fmt.Errorf("need at least %d non-penalty shots to train, got %d", minTrainingShots, len(xs))
+	}
+	var goals float64
+	for _, y := range ys {
+		goals += y
+	}
+	if goals == 0 || goals == float64(len(ys)) {
+		return nil, fmt.Errorf("training shots must include both goals and misses")
+	}
+
+	beta, err := fitLogistic(xs, ys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{Version: version, PenaltyXG: base.PenaltyXG, Shots: len(samples)}
+	m.setCoefficients(beta)
+	if penalties >= minPenalties {
+		m.PenaltyXG = math.Round(float64(penaltyGoals)/float64(penalties)*1000) / 1000
+	}
+	return m, nil
+}
+
+// fitLogistic returns ridge-regularized logistic regression coefficients.
+func fitLogistic(xs [][]float64, ys []float64) ([]float64, error) {
+	n := len(xs[0])
+	beta := make([]float64, n)
+
+	for iter := 0; iter < maxIterations; iter++ {
+		// Newton step: (X'WX + λI) Δ = X'(y - p) - λβ
+		hessian := make([][]float64, n)
+		for i := range hessian {
+			hessian[i] = make([]float64, n)
+			hessian[i][i] = ridge
+		}
+		gradient := make([]float64, n)
+		for i := range gradient {
+			gradient[i] = -ridge * beta[i]
+		}
+
+		for k, x := range xs {
+			p := sigmoid(dot(beta, x))
+			w := p * (1 - p)
+			for i := 0; i < n; i++ {
+				gradient[i] += (ys[k] - p) * x[i]
+				for j := 0; j <= i; j++ {
+					hessian[i][j] += w * x[i] * x[j]
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < i; j++ {
+				hessian[j][i] = hessian[i][j]
+			}
+		}
+
+		step, err := solve(hessian, gradient)
+		if err != nil {
+			return nil, err
+		}
+
+		var change float64
+		for i := range beta {
+			beta[i] += step[i]
+			change = math.Max(change, math.Abs(step[i]))
+		}
+		if change < tolerance {
+			break
+		}
+	}
+
+	for _, b := range beta {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return nil, fmt.Errorf("xG training did not converge")
+		}
+	}
+	return beta, nil
+}
+
+// solve solves a x = b by Gaussian elimination with partial pivoting.
+func solve(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append(append([]float64{}, a[i]...), b[i])
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("xG training matrix is singular")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for row := col + 1; row < n; row++ {
+			f := m[row][col] / m[col][col]
+			for k := col; k <= n; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x, nil
+}
@@ -0,0 +1,183 @@
+// Package xt values ball progression with an expected threat (xT) grid: the
+// probability that possession in a pitch zone leads to a goal within the next actions.
+package xt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Default grid resolution: 16 columns along the pitch, 12 rows across it.
+const (
+	DefaultColumns = 16
+	DefaultRows    = 12
+)
+
+const (
+	maxIterations = 100
+	tolerance     = 1e-7
+)
+
+// Grid holds the xT value of every zone of the canonical pitch.
+type Grid struct {
+	Version string      `json:"version"`
+	Columns int         `json:"columns"`
+	Rows    int         `json:"rows"`
+	Values  [][]float64 `json:"values"`            // Indexed [row][column]; column 0 is at the own goal line
+	Actions int         `json:"actions,omitempty"` // Number of actions the grid was built from
+}
+
+// Load reads a grid from a JSON file. An empty path returns nil, which disables
+// xT while progression flags are still computed.
+func Load(path string) (*Grid, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xT grid: %w", err)
+	}
+
+	var g Grid
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse xT grid: %w", err)
+	}
+	if g.Version == "" || g.Rows <= 0 || g.Columns <= 0 || len(g.Values) != g.Rows {
+		return nil, fmt.Errorf("invalid xT grid")
+	}
+	for _, row := range g.Values {
+		if len(row) != g.Columns {
+			return nil, fmt.Errorf("invalid xT grid")
+		}
+	}
+	return &g, nil
+}
+
+// Save writes the grid as indented JSON.
+func (g *Grid) Save(path string) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode xT grid: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write xT grid: %w", err)
+	}
+	return nil
+}
+
+// Value returns the xT of the zone containing a point in meters.
+func (g *Grid) Value(x, y float64) float64 {
+	col, row := cell(x, y, g.Columns, g.Rows)
+	return g.Values[row][col]
+}
+
+// cell returns the zone containing a point, clamping points off the pitch.
+func cell(x, y float64, columns, rows int) (int, int) {
+	col := int(x / pitch.Length * float64(columns))
+	row := int(y / pitch.Width * float64(rows))
+	return clamp(col, columns), clamp(row, rows)
+}
+
+func clamp(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// Action is a historical on-ball action in meters, used to build a grid.
+type Action struct {
+	StartX, StartY float64
+	EndX, EndY     float64 // Moves only
+	Shot           bool    // A shot rather than a pass or carry
+	Successful     bool    // Moves: the team kept the ball at the end location
+	Goal           bool    // Shots: the shot was scored
+}
+
+// Build computes a grid from historical actions by value iteration:
+//
+//	xT(z) = s(z)·g(z) + m(z)·Σ T(z→z')·xT(z')
+//
+// where s and m are the shares of shots and moves taken from z, g is the shot
+// conversion rate and T the probability that a move from z ends, still in
+// possession, in z'. Failed moves count towards m but carry no value.
+func Build(actions []Action, columns, rows int, version string) (*Grid, error) {
+	if columns <= 0 || rows <= 0 {
+		return nil, fmt.Errorf("invalid grid size %dx%d", columns, rows)
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no actions to build the xT grid from")
+	}
+
+	zones := columns * rows
+	shots := make([]float64, zones)
+	goals := make([]float64, zones)
+	moves := make([]float64, zones)
+	transitions := make([]map[int]float64, zones)
+
+	for _, a := range actions {
+		col, row := cell(a.StartX, a.StartY, columns, rows)
+		from := row*columns + col
+		if a.Shot {
+			shots[from]++
+			if a.Goal {
+				goals[from]++
+			}
+			continue
+		}
+		moves[from]++
+		if a.Successful {
+			endCol, endRow := cell(a.EndX, a.EndY, columns, rows)
+			if transitions[from] == nil {
+				transitions[from] = make(map[int]float64)
+			}
+			transitions[from][endRow*columns+endCol]++
+		}
+	}
+
+	values := make([]float64, zones)
+	for iter := 0; iter < maxIterations; iter++ {
+		next := make([]float64, zones)
+		var change float64
+		for z := 0; z < zones; z++ {
+			total := shots[z] + moves[z]
+			if total == 0 {
+				continue
+			}
+			v := 0.0
+			if shots[z] > 0 {
+				v = shots[z] / total * (goals[z] / shots[z])
+			}
+			if moves[z] > 0 {
+				var moveValue float64
+				for to, n := range transitions[z] {
+					moveValue += n / moves[z] * values[to]
+				}
+				v += moves[z] / total * moveValue
+			}
+			next[z] = v
+			change = math.Max(change, math.Abs(v-values[z]))
+		}
+		values = next
+		if change < tolerance {
+			break
+		}
+	}
+
+	g := &Grid{Version: version, Columns: columns, Rows: rows, Actions: len(actions)}
+	g.Values = make([][]float64, rows)
+	for row := 0; row < rows; row++ {
+		g.Values[row] = make([]float64, columns)
+		for col := 0; col < columns; col++ {
+			g.Values[row][col] = math.Round(values[row*columns+col]*1e6) / 1e6
+		}
+	}
+	return g, nil
+}
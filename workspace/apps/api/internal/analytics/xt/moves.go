@@ -0,0 +1,221 @@
+package xt
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+)
+
+// Metadata keys written on passes and carries.
+const (
+	MetaXT               = "xT"
+	MetaGrid             = "xt_grid"
+	MetaProgressivePass  = "progressive_pass"
+	MetaProgressiveCarry = "progressive_carry"
+	MetaBoxEntry         = "box_entry"
+)
+
+// Penalty area on the canonical pitch, in meters.
+const (
+	boxDepth     = 16.5
+	boxHalfWidth = 20.16
+)
+
+// progressiveShare is how much closer to goal a move must end, relative to where it
+// started, to count as progressive.
+const progressiveShare = 0.25
+
+// incompleteOutcomes are provider pass outcomes that mean the pass failed.
+var incompleteOutcomes = map[string]bool{
+	"incomplete":       true,
+	"out":              true,
+	"unknown":          true,
+	"pass offside":     true,
+	"injury clearance": true,
+	"unsuccessful":     true,
+}
+
+// endKeys are the normalized metadata keys holding a move's end location, per provider.
+var endKeys = [][2]string{
+	{"passendx", "passendy"},   // StatsBomb pass_end_location, Opta PassEndX/PassEndY
+	{"carryendx", "carryendy"}, // StatsBomb carry_end_location
+	{"endx", "endy"},           // Generic provider
+}
+
+// Move is a pass or carry in meters on the canonical pitch.
+type Move struct {
+	StartX, StartY float64
+	EndX, EndY     float64
+	Carry          bool
+	Successful     bool
+}
+
+// IsMoveEvent reports whether an event type is a pass or a carry.
+func IsMoveEvent(eventType string) bool {
+	t := events.Normalize(eventType)
+	return t.IsPass() || t.IsCarry()
+}
+
+// MoveFromEvent builds a move from an event's position and end location metadata,
+// both in the given frame. It returns false for other events and for moves without
+// both locations.
+func MoveFromEvent(eventType string, x, y *float64, meta map[string]interface{}, frame pitch.Frame) (Move, bool) {
+	if !IsMoveEvent(eventType) || x == nil || y == nil {
+		return Move{}, false
+	}
+	endX, endY, ok := endLocation(meta)
+	if !ok {
+		return Move{}, false
+	}
+
+	t := events.Normalize(eventType)
+	m := Move{Carry: t.IsCarry(), Successful: successful(t, meta)}
+	m.StartX, m.StartY = frame.Meters(*x, *y)
+	m.EndX, m.EndY = frame.Meters(endX, endY)
+	return m, true
+}
+
+// Progressive reports whether a completed move ends at least a quarter closer to
+// the opponent's goal than it started.
+func (m Move) Progressive() bool {
+	if !m.Successful || m.EndX <= m.StartX {
+		return false
+	}
+	return goalDistance(m.EndX, m.EndY) <= (1-progressiveShare)*goalDistance(m.StartX, m.StartY)
+}
+
+// BoxEntry reports whether a completed move ends inside the opponent's penalty area
+// after starting outside it.
+func (m Move) BoxEntry() bool {
+	return m.Successful && inBox(m.EndX, m.EndY) && !inBox(m.StartX, m.StartY)
+}
+
+// Threat returns the xT added by a completed move. Failed moves add none.
+func (m Move) Threat(g *Grid) float64 {
+	if !m.Successful {
+		return 0
+	}
+	return g.Value(m.EndX, m.EndY) - g.Value(m.StartX, m.StartY)
+}
+
+// Annotate writes progression flags, and xT when a grid is given, into a pass or
+// carry event's metadata. It returns the updated metadata JSON and whether anything
+// changed, so it can be used both on ingest and to recompute stored events.
+func Annotate(g *Grid, eventType string, x, y *float64, metadata, provider string) (string, bool) {
+	if !IsMoveEvent(eventType) || metadata == "" {
+		return metadata, false
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+		return metadata, false
+	}
+
+	if provider == "" {
+		provider, _ = meta[xg.MetaProvider].(string)
+	}
+	m, ok := MoveFromEvent(eventType, x, y, meta, pitch.FrameFor(meta, provider))
+	if !ok {
+		return metadata, false
+	}
+
+	changed := false
+	set := func(key string, value interface{}) {
+		if meta[key] != value {
+			meta[key] = value
+			changed = true
+		}
+	}
+	if m.Carry {
+		set(MetaProgressiveCarry, m.Progressive())
+	} else {
+		set(MetaProgressivePass, m.Progressive())
+	}
+	set(MetaBoxEntry, m.BoxEntry())
+	if g != nil {
+		set(MetaXT, math.Round(m.Threat(g)*1e4)/1e4)
+		set(MetaGrid, g.Version)
+	}
+	if provider != "" {
+		set(xg.MetaProvider, provider)
+	}
+
+	if !changed {
+		return metadata, false
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return metadata, false
+	}
+	return string(data), true
+}
+
+// ActionFromEvent converts a stored event into an Action for building a grid.
+func ActionFromEvent(eventType string, x, y *float64, meta map[string]interface{}, frame pitch.Frame) (Action, bool) {
+	if xg.IsShotEvent(eventType) {
+		if x == nil || y == nil {
+			return Action{}, false
+		}
+		sx, sy := frame.Meters(*x, *y)
+		return Action{StartX: sx, StartY: sy, Shot: true, Goal: xg.IsGoal(eventType, meta)}, true
+	}
+
+	m, ok := MoveFromEvent(eventType, x, y, meta, frame)
+	if !ok {
+		return Action{}, false
+	}
+	return Action{StartX: m.StartX, StartY: m.StartY, EndX: m.EndX, EndY: m.EndY, Successful: m.Successful}, true
+}
+
+// Completed reports whether a pass or carry event kept the ball. It needs no end
+// location, unlike MoveFromEvent.
+func Completed(eventType string, meta map[string]interface{}) bool {
+	return IsMoveEvent(eventType) && successful(events.Normalize(eventType), meta)
+}
+
+// successful reports whether a pass reached a teammate. Carries always keep the ball.
+func successful(t events.EventType, meta map[string]interface{}) bool {
+	if t.IsCarry() {
+		return true
+	}
+	if t == events.EventTypePassIncomplete {
+		return false
+	}
+	if outcome, _ := meta["outcome"].(string); incompleteOutcomes[strings.ToLower(outcome)] {
+		return false
+	}
+	switch completed := meta["completed"].(type) {
+	case bool:
+		return completed
+	case string:
+		return completed != "false"
+	}
+	return true
+}
+
+// endLocation finds a move's end coordinates in its metadata.
+func endLocation(meta map[string]interface{}) (float64, float64, bool) {
+	values := make(map[string]interface{}, len(meta))
+	for key, value := range meta {
+		values[strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(key))] = value
+	}
+	for _, keys := range endKeys {
+		x, okX := events.ToFloat(values[keys[0]])
+		y, okY := events.ToFloat(values[keys[1]])
+		if okX && okY {
+			return x, y, true
+		}
+	}
+	return 0, 0, false
+}
+
+func goalDistance(x, y float64) float64 {
+	return math.Hypot(pitch.Length-x, y-pitch.Width/2)
+}
+
+func inBox(x, y float64) bool {
+	return x >= pitch.Length-boxDepth && math.Abs(y-pitch.Width/2) <= boxHalfWidth
+}
@@ -0,0 +1,123 @@
+package xt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestAnnotate(t *testing.T) {
+	grid := &Grid{Version: "test", Columns: 2, Rows: 1, Values: [][]float64{{0.01, 0.11}}}
+
+	tests := []struct {
+		name      string
+		grid      *Grid
+		eventType string
+		x, y      float64
+		metadata  string
+		provider  string
+		wantOK    bool
+		want      map[string]interface{}
+	}{
+		{
+			name:      "statsbomb progressive pass into the box",
+			grid:      grid,
+			eventType: "pass",
+			x:         40,
+			y:         40,
+			metadata:  `{"pass_end_x":110,"pass_end_y":40}`,
+			provider:  "statsbomb",
+			wantOK:    true,
+			want:      map[string]interface{}{MetaProgressivePass: true, MetaBoxEntry: true, MetaXT: 0.1, MetaGrid: "test"},
+		},
+		{
+			name:      "incomplete pass adds no threat",
+			grid:      grid,
+			eventType: "pass",
+			x:         60,
+			y:         40,
+			metadata:  `{"pass_end_x":110,"pass_end_y":40,"outcome":"Incomplete"}`,
+			provider:  "statsbomb",
+			wantOK:    true,
+			want:      map[string]interface{}{MetaProgressivePass: false, MetaBoxEntry: false, MetaXT: 0.0},
+		},
+		{
+			name:      "opta sideways carry without grid",
+			eventType: "carry",
+			x:         40,
+			y:         20,
+			metadata:  `{"end_x":45,"end_y":60}`,
+			provider:  "opta",
+			wantOK:    true,
+			want:      map[string]interface{}{MetaProgressiveCarry: false, MetaBoxEntry: false},
+		},
+		{
+			name:      "pass without end location",
+			grid:      grid,
+			eventType: "pass",
+			x:         60,
+			y:         40,
+			metadata:  `{}`,
+			provider:  "statsbomb",
+		},
+		{
+			name:      "shots are left to the xG model",
+			grid:      grid,
+			eventType: "shot",
+			x:         110,
+			y:         40,
+			metadata:  `{"end_x":120,"end_y":40}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Annotate(tt.grid, tt.eventType, ptr(tt.x), ptr(tt.y), tt.metadata, tt.provider)
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				assert.Equal(t, tt.metadata, got)
+				return
+			}
+
+			var meta map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(got), &meta))
+			for key, value := range tt.want {
+				if f, isFloat := value.(float64); isFloat {
+					assert.InDelta(t, f, meta[key], 1e-9, key)
+				} else {
+					assert.Equal(t, value, meta[key], key)
+				}
+			}
+
+			_, again := Annotate(tt.grid, tt.eventType, ptr(tt.x), ptr(tt.y), got, "")
+			assert.False(t, again, "annotating twice should not change the metadata")
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	// Shots from the final third score a third of the time; passes from the middle
+	// reach the final third half the time; passes from the own third reach the middle.
+	var actions []Action
+	for i := 0; i < 30; i++ {
+		actions = append(actions, Action{StartX: 90, StartY: 34, Shot: true, Goal: i%3 == 0})
+	}
+	for i := 0; i < 10; i++ {
+		actions = append(actions, Action{StartX: 50, StartY: 34, EndX: 90, EndY: 34, Successful: i%2 == 0})
+		actions = append(actions, Action{StartX: 10, StartY: 34, EndX: 50, EndY: 34, Successful: true})
+	}
+
+	g, err := Build(actions, 3, 1, "test")
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1.0/3, g.Value(90, 34), 1e-6)
+	assert.InDelta(t, 1.0/6, g.Value(50, 34), 1e-6)
+	assert.InDelta(t, 1.0/6, g.Value(10, 34), 1e-6)
+
+	_, err = Build(nil, 3, 1, "test")
+	assert.Error(t, err)
+}
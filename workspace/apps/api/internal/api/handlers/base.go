@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
 	"github.com/emiliospot/footie/api/internal/config"
 	"github.com/emiliospot/footie/api/internal/infrastructure/events"
 	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
@@ -17,6 +19,8 @@ type BaseHandler struct {
 	redis     *redis.Client
 	publisher *events.Publisher
 	logger    *logger.Logger
+	xg        *xg.Model
+	xt        *xt.Grid
 }
 
 // NewBaseHandler creates a new base handler with common dependencies.
@@ -27,7 +31,18 @@ func NewBaseHandler(
 	logger *logger.Logger,
 ) *BaseHandler {
 	queries := sqlc.New(pool)
-	publisher := events.NewPublisher(redis, logger)
+	publisher := events.NewPublisher(redis, logger, cfg.Streams)
+
+	xgModel, err := xg.Load(cfg.XG.ModelPath)
+	if err != nil {
+		logger.Warn("Failed to load xG model, using the built-in model", "error", err, "path", cfg.XG.ModelPath)
+		xgModel = xg.Default()
+	}
+
+	xtGrid, err := xt.Load(cfg.XT.GridPath)
+	if err != nil {
+		logger.Warn("Failed to load xT grid, xT values disabled", "error", err, "path", cfg.XT.GridPath)
+	}
 
 	return &BaseHandler{
 		cfg:       cfg,
@@ -36,5 +51,7 @@ func NewBaseHandler(
 		redis:     redis,
 		publisher: publisher,
 		logger:    logger,
+		xg:        xgModel,
+		xt:        xtGrid,
 	}
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// analyticsCachePrefix namespaces cached analytics responses in Redis.
+const analyticsCachePrefix = "analytics:"
+
+// getCached loads a cached analytics response into dest. It reports false on a
+// miss, when caching is disabled or when Redis is unavailable.
+func (h *BaseHandler) getCached(ctx context.Context, key string, dest interface{}) bool {
+	if h.redis == nil || h.cfg.Analytics.CacheTTLSeconds <= 0 {
+		return false
+	}
+	data, err := h.redis.Get(ctx, analyticsCachePrefix+key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+// setCached stores an analytics response for the configured TTL. Failures are
+// logged and otherwise ignored; the response is still served.
+func (h *BaseHandler) setCached(ctx context.Context, key string, value interface{}) {
+	if h.redis == nil || h.cfg.Analytics.CacheTTLSeconds <= 0 {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	ttl := time.Duration(h.cfg.Analytics.CacheTTLSeconds) * time.Second
+	if err := h.redis.Set(ctx, analyticsCachePrefix+key, data, ttl).Err(); err != nil {
+		h.logger.Warn("Failed to cache analytics response", "error", err, "key", key)
+	}
+}
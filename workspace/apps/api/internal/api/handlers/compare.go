@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/comparison"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const (
+	minComparedPlayers = 2
+	maxComparedPlayers = 4
+	// defaultComparisonMinutes keeps players with under five full matches out of
+	// the percentile pool
+	defaultComparisonMinutes = 450
+)
+
+// PlayerComparisonRequest represents the query parameters for the player comparison endpoint.
+type PlayerComparisonRequest struct {
+	IDs         string `form:"ids" binding:"required"` // Comma-separated player IDs
+	Season      string `form:"season" binding:"required"`
+	Competition string `form:"competition"`
+	Position    string `form:"position"` // forward, midfielder, defender, goalkeeper
+	MinMinutes  *int32 `form:"min_minutes" binding:"omitempty,min=0"`
+}
+
+// playerIDs parses the comma-separated player IDs, dropping duplicates.
+func (r *PlayerComparisonRequest) playerIDs() ([]int32, error) {
+	var ids []int32
+	seen := map[int32]bool{}
+	for _, s := range strings.Split(r.IDs, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid player ID: %s", s)
+		}
+		if !seen[int32(id)] {
+			seen[int32(id)] = true
+			ids = append(ids, int32(id))
+		}
+	}
+	if len(ids) < minComparedPlayers || len(ids) > maxComparedPlayers {
+		return nil, fmt.Errorf("ids must list %d to %d players", minComparedPlayers, maxComparedPlayers)
+	}
+	return ids, nil
+}
+
+// PlayerComparisonResponse represents compared players' per-90 metrics and
+// their percentiles.
+type PlayerComparisonResponse struct {
+	Season     string           `json:"season"`
+	MinMinutes int32            `json:"min_minutes"`
+	Players    []ComparedPlayer `json:"players"`
+}
+
+// ComparedPlayer represents a player's metrics for their position group, ranked
+// among the players of the group in the same competition with enough minutes.
+type ComparedPlayer struct {
+	PlayerID      int32                    `json:"player_id"`
+	Name          string                   `json:"name"`
+	TeamID        int32                    `json:"team_id"`
+	Position      string                   `json:"position"`
+	Group         comparison.Group         `json:"group"`
+	Competition   string                   `json:"competition"`
+	MatchesPlayed int32                    `json:"matches_played"`
+	MinutesPlayed int32                    `json:"minutes_played"`
+	PoolSize      int                      `json:"pool_size"`
+	Metrics       []comparison.MetricValue `json:"metrics"`
+}
+
+// seasonStatistics returns a player's statistics for a season in a competition,
+// or in the competition they played the most minutes in when competition is
+// empty.
+func seasonStatistics(stats []sqlc.PlayerStatistic, season, competition string) (*sqlc.PlayerStatistic, bool) {
+	var best *sqlc.PlayerStatistic
+	for i := range stats {
+		s := &stats[i]
+		if s.Season != season || (competition != "" && s.Competition != competition) {
+			continue
+		}
+		if best == nil || s.MinutesPlayed > best.MinutesPlayed {
+			best = s
+		}
+	}
+	return best, best != nil
+}
+
+// GetPlayerComparison handles GET /api/v1/players/compare.
+// @Summary Compare players
+// @Description Compare two to four players' per-90 metrics for their position group (forward, midfielder, defender, goalkeeper), each with its percentile among the group's players in the same competition and season with at least min_minutes played
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma-separated player IDs (2 to 4)"
+// @Param season query string true "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition (defaults to each player's competition with the most minutes)"
+// @Param position query string false "Position group for every player (forward, midfielder, defender, goalkeeper); defaults to each player's position"
+// @Param min_minutes query int false "Minimum minutes played to be in the percentile pool" default(450)
+// @Success 200 {object} PlayerComparisonResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/players/compare [get]
+func (h *PlayerHandler) GetPlayerComparison(c *gin.Context) {
+	var req PlayerComparisonRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	ids, err := req.playerIDs()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var group comparison.Group
+	if req.Position != "" {
+		var ok bool
+		if group, ok = comparison.ParseGroup(req.Position); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: " + req.Position})
+			return
+		}
+	}
+	minMinutes := int32(defaultComparisonMinutes)
+	if req.MinMinutes != nil {
+		minMinutes = *req.MinMinutes
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("compare:%s:%s:%s:%s:%d", strings.Trim(fmt.Sprint(ids), "[]"), req.Season, req.Competition, group, minMinutes)
+	var response PlayerComparisonResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	response = PlayerComparisonResponse{Season: req.Season, MinMinutes: minMinutes}
+	pools := map[string][]sqlc.ListPlayerStatsPoolRow{}
+	for _, id := range ids {
+		player, playerErr := h.queries.GetPlayerByID(ctx, id)
+		if playerErr != nil {
+			if errors.Is(playerErr, pgx.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Player not found", "player_id": id})
+				return
+			}
+			h.logger.Error("Failed to get player", "error", playerErr, "player_id", id)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare players"})
+			return
+		}
+		stats, statsErr := h.queries.GetPlayerStatsByPlayer(ctx, id)
+		if statsErr != nil {
+			h.logger.Error("Failed to get player statistics", "error", statsErr, "player_id", id)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare players"})
+			return
+		}
+		season, ok := seasonStatistics(stats, req.Season, req.Competition)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No statistics for player in season", "player_id": id})
+			return
+		}
+
+		playerGroup := group
+		if playerGroup == "" {
+			if playerGroup, ok = comparison.GroupFor(player.Position); !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown position group for player; pass position", "player_id": id, "position": player.Position})
+				return
+			}
+		}
+
+		pool, cached := pools[season.Competition]
+		if !cached {
+			pool, err = h.queries.ListPlayerStatsPool(ctx, sqlc.ListPlayerStatsPoolParams{
+				Season:      req.Season,
+				Competition: season.Competition,
+				MinMinutes:  minMinutes,
+			})
+			if err != nil {
+				h.logger.Error("Failed to get comparison pool", "error", err, "season", req.Season, "competition", season.Competition)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare players"})
+				return
+			}
+			pools[season.Competition] = pool
+		}
+		var poolValues []map[string]float64
+		for i := range pool {
+			if g, _ := comparison.GroupFor(pool[i].Position); g == playerGroup {
+				poolStats := mappers.ToDomainPlayerStatistics(&pool[i].PlayerStatistic)
+				poolValues = append(poolValues, comparison.Values(&poolStats))
+			}
+		}
+
+		domainStats := mappers.ToDomainPlayerStatistics(season)
+		response.Players = append(response.Players, comparedPlayer(&player, &domainStats, playerGroup, poolValues))
+	}
+
+	h.setCached(ctx, key, response)
+	c.JSON(http.StatusOK, response)
+}
+
+// comparedPlayer ranks a player's season statistics within a pool.
+func comparedPlayer(player *sqlc.Player, stats *models.PlayerStatistics, group comparison.Group, pool []map[string]float64) ComparedPlayer {
+	return ComparedPlayer{
+		PlayerID:      player.ID,
+		Name:          player.FullName,
+		TeamID:        player.TeamID,
+		Position:      player.Position,
+		Group:         group,
+		Competition:   stats.Competition,
+		MatchesPlayed: stats.MatchesPlayed,
+		MinutesPlayed: stats.MinutesPlayed,
+		PoolSize:      len(pool),
+		Metrics:       comparison.Rank(group, comparison.Values(stats), pool),
+	}
+}
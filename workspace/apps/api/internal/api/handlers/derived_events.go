@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/emiliospot/footie/api/internal/analytics/assists"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// assignDerivedEvents infers a match's assists, second assists, key passes and
+// shot-creating actions from its events, replacing those stored by an earlier
+// pass, and recounts the assists and key passes of the match's players. It
+// returns the number of annotated events.
+func (h *BaseHandler) assignDerivedEvents(ctx context.Context, matchID int32) (int, error) {
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match events: %w", err)
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+
+	arg := sqlc.SetMatchEventDerivedParams{MatchID: matchID}
+	for _, a := range assists.Annotate(matchEvents) {
+		xa := math.NaN()
+		if a.XA != nil {
+			xa = *a.XA
+		}
+		arg.Ids = append(arg.Ids, a.EventID)
+		arg.DerivedTypes = append(arg.DerivedTypes, a.DerivedType)
+		arg.Xas = append(arg.Xas, xa)
+		arg.ShotEventIds = append(arg.ShotEventIds, a.ShotEventID)
+		arg.CreationTypes = append(arg.CreationTypes, a.CreationType)
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	if err := queries.SetMatchEventDerived(ctx, arg); err != nil {
+		return 0, fmt.Errorf("failed to store derived events: %w", err)
+	}
+	if err := queries.UpdatePlayerCreativeStats(ctx, matchID); err != nil {
+		return 0, fmt.Errorf("failed to update player statistics: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit derived events: %w", err)
+	}
+	return len(arg.Ids), nil
+}
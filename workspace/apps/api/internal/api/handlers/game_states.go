@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/analytics/lineup"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// GameStateRequest represents the game state filters for statistics endpoints:
+// a game state, or a range of the team's goal differential at the time of each event.
+type GameStateRequest struct {
+	GameState       string `form:"game_state"` // winning, drawing, losing
+	MinDifferential *int   `form:"min_differential"`
+	MaxDifferential *int   `form:"max_differential"`
+}
+
+// differentials validates the filters and returns the goal differential range,
+// or nil when the request is not filtered by game state.
+func (r *GameStateRequest) differentials() (*gamestate.Range, error) {
+	if r.GameState != "" {
+		if r.MinDifferential != nil || r.MaxDifferential != nil {
+			return nil, errors.New("game_state cannot be combined with min_differential or max_differential")
+		}
+		state, ok := gamestate.Parse(r.GameState)
+		if !ok {
+			return nil, fmt.Errorf("invalid game_state: %s", r.GameState)
+		}
+		r.GameState = string(state)
+		differentials := gamestate.RangeFor(state)
+		return &differentials, nil
+	}
+	if r.MinDifferential == nil && r.MaxDifferential == nil {
+		return nil, nil
+	}
+	if r.MinDifferential != nil && r.MaxDifferential != nil && *r.MinDifferential > *r.MaxDifferential {
+		return nil, errors.New("min_differential must not be greater than max_differential")
+	}
+	return &gamestate.Range{Min: r.MinDifferential, Max: r.MaxDifferential}, nil
+}
+
+// differentialParams returns a goal differential range as nullable query arguments.
+func differentialParams(r *gamestate.Range) (minDiff, maxDiff *int32) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.Min != nil {
+		v := int32(*r.Min)
+		minDiff = &v
+	}
+	if r.Max != nil {
+		v := int32(*r.Max)
+		maxDiff = &v
+	}
+	return minDiff, maxDiff
+}
+
+// storedClock returns the match clock of an event listed by its period and clock
+// alone.
+func storedClock(period *string, clockMs int64) events.MatchClock {
+	p := ""
+	if period != nil {
+		p = *period
+	}
+	return events.MatchClock{Period: events.NormalizePeriod(p), Ms: clockMs}
+}
+
+// gameStateDurations measures the time a team spent at each goal differential in
+// the finished matches matching the filters.
+func (h *BaseHandler) gameStateDurations(ctx context.Context, teamID int32, arg sqlc.ListGameStateEventsParams) (gamestate.Durations, error) {
+	rows, err := h.queries.ListGameStateEvents(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := gamestate.Durations{}
+	var matchEvents []matchevent.Event
+	for i := range rows {
+		r := &rows[i]
+		matchEvents = append(matchEvents, matchevent.Event{
+			TeamID:    r.TeamID,
+			EventType: r.EventType,
+			Clock:     storedClock(r.Period, r.ClockMs),
+		})
+		if i+1 == len(rows) || rows[i+1].MatchID != r.MatchID {
+			durations.Add(gamestate.DurationsFor(matchEvents, teamID))
+			matchEvents = matchEvents[:0]
+		}
+	}
+	return durations, nil
+}
+
+// playerGameStateDurations measures the time a player spent on the pitch at each
+// of the team's goal differentials in the finished matches matching the filters,
+// following the player on and off the pitch from the match's lineups.
+func (h *BaseHandler) playerGameStateDurations(ctx context.Context, arg sqlc.ListPlayerGameStateEventsParams) (gamestate.Durations, error) {
+	rows, err := h.queries.ListPlayerGameStateEvents(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := gamestate.Durations{}
+	var matchEvents []matchevent.Event
+	for i := range rows {
+		r := &rows[i]
+		matchEvents = append(matchEvents, matchevent.Event{
+			TeamID:            r.TeamID,
+			PlayerID:          r.PlayerID,
+			SecondaryPlayerID: r.SecondaryPlayerID,
+			EventType:         r.EventType,
+			Clock:             storedClock(r.Period, r.ClockMs),
+		})
+		if i+1 == len(rows) || rows[i+1].MatchID != r.MatchID {
+			appearance := lineup.Appearances([]lineup.Player{{PlayerID: arg.PlayerID, Starter: r.Starter}}, matchEvents)[0]
+			durations.Add(gamestate.DurationsWithin(matchEvents, r.PlayerTeamID, appearance.Spells))
+			matchEvents = matchEvents[:0]
+		}
+	}
+	return durations, nil
+}
+
+// assignGameStates tags each of a match's events with its team's game state and
+// goal differential.
+func (h *BaseHandler) assignGameStates(ctx context.Context, matchID int32) (int, error) {
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match events: %w", err)
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+
+	arg := sqlc.SetMatchEventGameStatesParams{MatchID: matchID}
+	for _, tag := range gamestate.Tags(matchEvents) {
+		arg.Ids = append(arg.Ids, tag.EventID)
+		arg.GameStates = append(arg.GameStates, string(tag.State))
+		arg.ScoreDifferentials = append(arg.ScoreDifferentials, int32(tag.Differential))
+	}
+	if err := h.queries.SetMatchEventGameStates(ctx, arg); err != nil {
+		return 0, fmt.Errorf("failed to store game states: %w", err)
+	}
+	return len(arg.Ids), nil
+}
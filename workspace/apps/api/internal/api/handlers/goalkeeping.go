@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/comparison"
+	"github.com/emiliospot/footie/api/internal/analytics/goalkeeping"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// GoalkeepingRequest represents the query parameters for the goalkeeper profile endpoint.
+type GoalkeepingRequest struct {
+	StatisticsRequest
+	MatchID int32 `form:"match_id"`
+}
+
+// GoalkeepingResponse represents a goalkeeper's profile: shot stopping, crosses
+// and distribution.
+type GoalkeepingResponse struct {
+	PlayerID    int32             `json:"player_id"`
+	Season      string            `json:"season,omitempty"`
+	Competition string            `json:"competition,omitempty"`
+	MatchID     int32             `json:"match_id,omitempty"`
+	Goalkeeping goalkeeping.Stats `json:"goalkeeping"`
+}
+
+// goalkeepingMatches splits events listed by match, each match in match clock
+// order.
+func goalkeepingMatches(sqlcEvents []sqlc.MatchEvent) [][]matchevent.Event {
+	var matches [][]matchevent.Event
+	for start := 0; start < len(sqlcEvents); {
+		end := start
+		for end < len(sqlcEvents) && sqlcEvents[end].MatchID == sqlcEvents[start].MatchID {
+			end++
+		}
+		matchEvents := make([]matchevent.Event, 0, end-start)
+		for i := start; i < end; i++ {
+			event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+			matchEvents = append(matchEvents, matchevent.FromModel(&event))
+		}
+		matches = append(matches, matchEvents)
+		start = end
+	}
+	return matches
+}
+
+// knownGoalkeepers returns the players of the teams whose position is goalkeeper.
+func (h *BaseHandler) knownGoalkeepers(ctx context.Context, teamIDs ...int32) (map[int32]bool, error) {
+	known := map[int32]bool{}
+	for _, teamID := range teamIDs {
+		players, err := h.queries.GetPlayersByTeam(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range players {
+			if group, ok := comparison.GroupFor(p.Position); ok && group == comparison.Goalkeeper {
+				known[p.ID] = true
+			}
+		}
+	}
+	return known, nil
+}
+
+// playerGoalkeeping adds up a goalkeeper's statistics over the finished matches
+// the player has events in, or over one match when matchID is set. Matches the
+// player did not keep goal in are left out.
+func (h *BaseHandler) playerGoalkeeping(ctx context.Context, playerID int32, arg sqlc.ListPlayerSeasonMatchEventsParams, known map[int32]bool) (goalkeeping.Stats, error) {
+	arg.PlayerID = &playerID
+	sqlcEvents, err := h.queries.ListPlayerSeasonMatchEvents(ctx, arg)
+	if err != nil {
+		return goalkeeping.Stats{}, err
+	}
+	total := goalkeeping.Stats{PlayerID: playerID}
+	for _, matchEvents := range goalkeepingMatches(sqlcEvents) {
+		for _, s := range goalkeeping.Compute(matchEvents, known) {
+			if s.PlayerID == playerID {
+				total.Add(s)
+			}
+		}
+	}
+	return total, nil
+}
+
+// assignGoalkeeperStats computes the statistics of a match's goalkeepers, stores
+// them, replacing those of an earlier pass, and recomputes the season statistics of
+// the goalkeepers of both passes in the match's season and competition. It returns
+// the number of goalkeepers.
+func (h *BaseHandler) assignGoalkeeperStats(ctx context.Context, matchID int32) (int, error) {
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match: %w", err)
+	}
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match events: %w", err)
+	}
+	known, err := h.knownGoalkeepers(ctx, match.HomeTeamID, match.AwayTeamID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get players: %w", err)
+	}
+
+	arg := sqlc.CreateGoalkeeperMatchStatsParams{MatchID: matchID}
+	for _, matchEvents := range goalkeepingMatches(sqlcEvents) {
+		for _, s := range goalkeeping.Compute(matchEvents, known) {
+			arg.PlayerIds = append(arg.PlayerIds, s.PlayerID)
+			arg.TeamIds = append(arg.TeamIds, s.TeamID)
+			arg.CleanSheets = append(arg.CleanSheets, s.CleanSheets > 0)
+			arg.ShotsOnTarget = append(arg.ShotsOnTarget, int32(s.ShotsOnTarget))
+			arg.Saves = append(arg.Saves, int32(s.Saves))
+			arg.GoalsConceded = append(arg.GoalsConceded, int32(s.GoalsConceded))
+			arg.PostShotXg = append(arg.PostShotXg, s.PostShotXG)
+			arg.GoalsPrevented = append(arg.GoalsPrevented, s.GoalsPrevented)
+			arg.PenaltiesFaced = append(arg.PenaltiesFaced, int32(s.PenaltiesFaced))
+			arg.PenaltiesSaved = append(arg.PenaltiesSaved, int32(s.PenaltiesSaved))
+			arg.CrossesFaced = append(arg.CrossesFaced, int32(s.CrossesFaced))
+			arg.CrossesClaimed = append(arg.CrossesClaimed, int32(s.CrossesClaimed))
+		}
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	previous, err := queries.DeleteGoalkeeperMatchStats(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete goalkeeper statistics: %w", err)
+	}
+	if len(arg.PlayerIds) > 0 {
+		if err := queries.CreateGoalkeeperMatchStats(ctx, arg); err != nil {
+			return 0, fmt.Errorf("failed to store goalkeeper statistics: %w", err)
+		}
+	}
+	// Goalkeepers of an earlier pass are recomputed too, so a keeper who no
+	// longer kept goal in the match loses its statistics.
+	if err := queries.RefreshGoalkeeperStats(ctx, sqlc.RefreshGoalkeeperStatsParams{
+		PlayerIds: append(previous, arg.PlayerIds...),
+		MatchID:   matchID,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to update player statistics: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit goalkeeper statistics: %w", err)
+	}
+	return len(arg.PlayerIds), nil
+}
+
+// GetPlayerGoalkeeping handles GET /api/v1/players/:id/goalkeeping.
+// @Summary Get goalkeeper profile
+// @Description Shot stopping (post-shot xG faced, goals prevented, save percentage overall and by shot zone, penalties), crosses claimed and punched, sweeper actions and distribution accuracy by pass length for one match or over a goalkeeper's finished matches
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param match_id query int false "Match ID"
+// @Success 200 {object} GoalkeepingResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/players/{id}/goalkeeping [get]
+func (h *PlayerHandler) GetPlayerGoalkeeping(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidPlayerID})
+		return
+	}
+	playerID := int32(id)
+
+	var req GoalkeepingRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	player, err := h.queries.GetPlayerByID(ctx, playerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		h.logger.Error("Failed to get player", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve goalkeeper profile"})
+		return
+	}
+
+	// A live match's profile changes with every event, so only finished matches
+	// and season profiles (which only include finished matches) are cached.
+	cacheable := true
+	if req.MatchID != 0 {
+		match, matchErr := h.queries.GetMatchByID(ctx, req.MatchID)
+		if matchErr != nil {
+			if errors.Is(matchErr, pgx.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+				return
+			}
+			h.logger.Error("Failed to get match", "error", matchErr, "match_id", req.MatchID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve goalkeeper profile"})
+			return
+		}
+		cacheable = match.Status == "finished"
+		req.Season, req.Competition = "", ""
+	}
+
+	key := fmt.Sprintf("goalkeeping:%d:%s:%s:%d", playerID, req.Season, req.Competition, req.MatchID)
+	var response GoalkeepingResponse
+	if cacheable && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	known, err := h.knownGoalkeepers(ctx, player.TeamID)
+	if err != nil {
+		h.logger.Error("Failed to get team players", "error", err, "team_id", player.TeamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve goalkeeper profile"})
+		return
+	}
+
+	var stats goalkeeping.Stats
+	if req.MatchID != 0 {
+		sqlcEvents, eventsErr := h.queries.GetMatchEvents(ctx, req.MatchID)
+		if eventsErr != nil {
+			h.logger.Error("Failed to get match events", "error", eventsErr, "match_id", req.MatchID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve goalkeeper profile"})
+			return
+		}
+		stats = goalkeeping.Stats{PlayerID: playerID}
+		for _, matchEvents := range goalkeepingMatches(sqlcEvents) {
+			for _, s := range goalkeeping.Compute(matchEvents, known) {
+				if s.PlayerID == playerID {
+					stats.Add(s)
+				}
+			}
+		}
+	} else {
+		season, competition := req.params()
+		stats, err = h.playerGoalkeeping(ctx, playerID, sqlc.ListPlayerSeasonMatchEventsParams{
+			Season:      season,
+			Competition: competition,
+		}, known)
+		if err != nil {
+			h.logger.Error("Failed to get season events", "error", err, "player_id", playerID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve goalkeeper profile"})
+			return
+		}
+	}
+
+	response = GoalkeepingResponse{
+		PlayerID:    playerID,
+		Season:      req.Season,
+		Competition: req.Competition,
+		MatchID:     req.MatchID,
+		Goalkeeping: stats,
+	}
+	if cacheable {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
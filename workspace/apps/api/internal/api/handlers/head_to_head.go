@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/headtohead"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const defaultHeadToHeadScorers = 10
+
+// HeadToHeadRequest represents the query parameters for the head-to-head endpoint.
+type HeadToHeadRequest struct {
+	Competition string `form:"competition"`
+	Scorers     int    `form:"scorers" binding:"omitempty,min=1,max=50"` // Number of top scorers
+}
+
+// HeadToHeadResponse represents the history between two teams.
+type HeadToHeadResponse struct {
+	TeamID      int32  `json:"team_id"`
+	OpponentID  int32  `json:"opponent_id"`
+	Competition string `json:"competition,omitempty"`
+	// Records are both teams' records, the team's first
+	Records    []headtohead.Summary    `json:"records"`
+	TopScorers []HeadToHeadScorer      `json:"top_scorers"`
+	EventStats []headtohead.EventStats `json:"event_stats"` // Per meeting with events
+	Meetings   []models.Match          `json:"meetings"`    // Latest first
+}
+
+// HeadToHeadScorer represents a player's goals in the fixture.
+type HeadToHeadScorer struct {
+	PlayerID     int32  `json:"player_id"`
+	Name         string `json:"name"`
+	TeamID       int32  `json:"team_id"`
+	Goals        int64  `json:"goals"`
+	PenaltyGoals int64  `json:"penalty_goals"`
+}
+
+// loadTeam parses a team ID path parameter and checks the team exists, writing
+// the error response when it fails.
+func (h *TeamHandler) loadTeam(c *gin.Context, param string) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return 0, false
+	}
+
+	if _, err = h.queries.GetTeamByID(c.Request.Context(), int32(id)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found", "team_id": id})
+			return 0, false
+		}
+		h.logger.Error("Failed to get team", "error", err, "team_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// GetHeadToHead handles GET /api/v1/teams/:id/head-to-head/:opponentId.
+// @Summary Get head-to-head history
+// @Description Get every finished meeting between two teams with both teams' win/draw/loss and goals records, split by venue, the fixture's top scorers and each team's event statistics averaged per meeting
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param opponentId path int true "Opponent team ID"
+// @Param competition query string false "Competition"
+// @Param scorers query int false "Number of top scorers" default(10)
+// @Success 200 {object} HeadToHeadResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/head-to-head/{opponentId} [get]
+func (h *TeamHandler) GetHeadToHead(c *gin.Context) {
+	var req HeadToHeadRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	if req.Scorers == 0 {
+		req.Scorers = defaultHeadToHeadScorers
+	}
+
+	teamID, ok := h.loadTeam(c, "id")
+	if !ok {
+		return
+	}
+	opponentID, ok := h.loadTeam(c, "opponentId")
+	if !ok {
+		return
+	}
+	if teamID == opponentID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A team has no head-to-head with itself"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("head-to-head:%d:%d:%s:%d", teamID, opponentID, req.Competition, req.Scorers)
+	var response HeadToHeadResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	var competition *string
+	if req.Competition != "" {
+		competition = &req.Competition
+	}
+	sqlcMatches, err := h.queries.GetHeadToHeadMatches(ctx, sqlc.GetHeadToHeadMatchesParams{
+		TeamID:      teamID,
+		OpponentID:  opponentID,
+		Competition: competition,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get head-to-head matches", "error", err, "team_id", teamID, "opponent_id", opponentID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve head-to-head"})
+		return
+	}
+	meetings := make([]models.Match, 0, len(sqlcMatches))
+	matchIDs := make([]int32, 0, len(sqlcMatches))
+	for i := range sqlcMatches {
+		meetings = append(meetings, mappers.ToDomainMatch(&sqlcMatches[i]))
+		matchIDs = append(matchIDs, sqlcMatches[i].ID)
+	}
+
+	scorers, err := h.queries.GetHeadToHeadScorers(ctx, sqlc.GetHeadToHeadScorersParams{
+		MatchIds: matchIDs,
+		Limit:    int32(req.Scorers),
+	})
+	if err != nil {
+		h.logger.Error("Failed to get head-to-head scorers", "error", err, "team_id", teamID, "opponent_id", opponentID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve head-to-head"})
+		return
+	}
+	totals, err := h.queries.GetHeadToHeadEventStats(ctx, matchIDs)
+	if err != nil {
+		h.logger.Error("Failed to get head-to-head event statistics", "error", err, "team_id", teamID, "opponent_id", opponentID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve head-to-head"})
+		return
+	}
+
+	response = HeadToHeadResponse{
+		TeamID:      teamID,
+		OpponentID:  opponentID,
+		Competition: req.Competition,
+		Records: []headtohead.Summary{
+			headtohead.Summarize(meetings, teamID),
+			headtohead.Summarize(meetings, opponentID),
+		},
+		TopScorers: make([]HeadToHeadScorer, 0, len(scorers)),
+		Meetings:   meetings,
+	}
+	for _, s := range scorers {
+		response.TopScorers = append(response.TopScorers, HeadToHeadScorer{
+			PlayerID:     s.PlayerID,
+			Name:         s.FullName,
+			TeamID:       s.TeamID,
+			Goals:        s.Goals,
+			PenaltyGoals: s.PenaltyGoals,
+		})
+	}
+	// Both teams are listed, the team's first, even without events
+	byTeam := map[int32]sqlc.GetHeadToHeadEventStatsRow{}
+	for _, t := range totals {
+		byTeam[t.TeamID] = t
+	}
+	for _, id := range []int32{teamID, opponentID} {
+		t := byTeam[id]
+		response.EventStats = append(response.EventStats, headtohead.Average(headtohead.Totals{
+			TeamID:          id,
+			Matches:         t.Matches,
+			Shots:           t.Shots,
+			ShotsOnTarget:   t.ShotsOnTarget,
+			XG:              t.Xg,
+			Passes:          t.Passes,
+			PassesCompleted: t.PassesCompleted,
+			Corners:         t.Corners,
+			Fouls:           t.Fouls,
+			YellowCards:     t.YellowCards,
+			RedCards:        t.RedCards,
+		}))
+	}
+
+	h.setCached(ctx, key, response)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/heatmap"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// HeatmapRequest represents the query parameters for heatmap endpoints.
+type HeatmapRequest struct {
+	StatisticsRequest
+	MatchID  int32  `form:"match_id"`
+	Category string `form:"category"` // Event category (pass, shot, carry, defensive, ...); all events when omitted
+	Period   string `form:"period"`   // first_half, second_half, extra_time_first, extra_time_second
+	Columns  int    `form:"columns" binding:"omitempty,min=1,max=50"`
+	Rows     int    `form:"rows" binding:"omitempty,min=1,max=50"`
+}
+
+// HeatmapResponse represents a player's or team's heatmap.
+type HeatmapResponse struct {
+	PlayerID    int32           `json:"player_id,omitempty"`
+	TeamID      int32           `json:"team_id,omitempty"`
+	Season      string          `json:"season,omitempty"`
+	Competition string          `json:"competition,omitempty"`
+	MatchID     int32           `json:"match_id,omitempty"`
+	Category    string          `json:"category,omitempty"`
+	Period      string          `json:"period,omitempty"`
+	Heatmap     heatmap.Heatmap `json:"heatmap"`
+}
+
+// heatmapFilters holds a validated heatmap request as nullable query arguments.
+type heatmapFilters struct {
+	matchID     *int32
+	season      *string
+	competition *string
+	eventTypes  []string
+	period      *string
+}
+
+// filters validates the category and period and returns the query arguments.
+func (r *HeatmapRequest) filters() (heatmapFilters, error) {
+	var f heatmapFilters
+	f.season, f.competition = r.params()
+	if r.MatchID != 0 {
+		f.matchID = &r.MatchID
+	}
+	if r.Category != "" {
+		f.eventTypes = events.TypesInCategory(events.EventCategory(r.Category))
+		if len(f.eventTypes) == 0 {
+			return f, fmt.Errorf("invalid event category: %s", r.Category)
+		}
+	}
+	if r.Period != "" {
+		period := events.NormalizePeriod(r.Period)
+		if period == events.PeriodRegular {
+			return f, fmt.Errorf("invalid period: %s", r.Period)
+		}
+		r.Period = period.String()
+		f.period = &r.Period
+	}
+	if r.Columns == 0 {
+		r.Columns = heatmap.DefaultColumns
+	}
+	if r.Rows == 0 {
+		r.Rows = heatmap.DefaultRows
+	}
+	return f, nil
+}
+
+// cacheKey identifies the request's result for an entity ("player:7").
+func (r *HeatmapRequest) cacheKey(entity string) string {
+	return fmt.Sprintf("heatmap:%s:%s:%s:%d:%s:%s:%dx%d",
+		entity, r.Season, r.Competition, r.MatchID, r.Category, r.Period, r.Columns, r.Rows)
+}
+
+// response wraps a heatmap with the request's filters.
+func (r *HeatmapRequest) response(h heatmap.Heatmap) HeatmapResponse {
+	return HeatmapResponse{
+		Season:      r.Season,
+		Competition: r.Competition,
+		MatchID:     r.MatchID,
+		Category:    r.Category,
+		Period:      r.Period,
+		Heatmap:     h,
+	}
+}
+
+// heatmapPoint converts a stored position to canonical meters. Events stored
+// before coordinate normalization are read in their provider's system.
+func heatmapPoint(x, y float64, coordinates, provider *string) heatmap.Point {
+	frame := pitch.CanonicalFrame
+	if coordinates == nil || *coordinates != pitch.Canonical {
+		p := ""
+		if provider != nil {
+			p = *provider
+		}
+		frame = pitch.FrameFor(nil, p)
+	}
+	mx, my := frame.Meters(x, y)
+	return heatmap.Point{X: mx, Y: my}
+}
+
+// GetPlayerHeatmap handles GET /api/v1/players/:id/heatmap.
+// @Summary Get player heatmap
+// @Description Bin a player's event locations into a grid of counts (touch map) and normalized densities, with thirds, channels and box summaries
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param match_id query int false "Match ID"
+// @Param category query string false "Event category (pass, shot, carry, defensive, duel, ...)"
+// @Param period query string false "Period (first_half, second_half, extra_time_first, extra_time_second)"
+// @Param columns query int false "Zones along the pitch" default(12)
+// @Param rows query int false "Zones across the pitch" default(8)
+// @Success 200 {object} HeatmapResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/players/{id}/heatmap [get]
+func (h *PlayerHandler) GetPlayerHeatmap(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidPlayerID})
+		return
+	}
+	playerID := int32(id)
+
+	var req HeatmapRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	filters, err := req.filters()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := req.cacheKey(fmt.Sprintf("player:%d", playerID))
+	var response HeatmapResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if _, err = h.queries.GetPlayerByID(ctx, playerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		h.logger.Error("Failed to get player", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player heatmap"})
+		return
+	}
+
+	rows, err := h.queries.GetPlayerHeatmapPoints(ctx, sqlc.GetPlayerHeatmapPointsParams{
+		PlayerID:    &playerID,
+		MatchID:     filters.matchID,
+		Season:      filters.season,
+		Competition: filters.competition,
+		EventTypes:  filters.eventTypes,
+		Period:      filters.period,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get player heatmap", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player heatmap"})
+		return
+	}
+
+	points := make([]heatmap.Point, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, heatmapPoint(row.X, row.Y, row.Coordinates, row.Provider))
+	}
+
+	response = req.response(heatmap.Build(points, req.Columns, req.Rows))
+	response.PlayerID = playerID
+	h.setCached(ctx, key, response)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTeamHeatmap handles GET /api/v1/teams/:id/heatmap.
+// @Summary Get team heatmap
+// @Description Bin a team's event locations into a grid of counts (touch map) and normalized densities, with thirds, channels and box summaries
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param match_id query int false "Match ID"
+// @Param category query string false "Event category (pass, shot, carry, defensive, duel, ...)"
+// @Param period query string false "Period (first_half, second_half, extra_time_first, extra_time_second)"
+// @Param columns query int false "Zones along the pitch" default(12)
+// @Param rows query int false "Zones across the pitch" default(8)
+// @Success 200 {object} HeatmapResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/heatmap [get]
+func (h *TeamHandler) GetTeamHeatmap(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return
+	}
+	teamID := int32(id)
+
+	var req HeatmapRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	filters, err := req.filters()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := req.cacheKey(fmt.Sprintf("team:%d", teamID))
+	var response HeatmapResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if _, err = h.queries.GetTeamByID(ctx, teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		h.logger.Error("Failed to get team", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team heatmap"})
+		return
+	}
+
+	rows, err := h.queries.GetTeamHeatmapPoints(ctx, sqlc.GetTeamHeatmapPointsParams{
+		TeamID:      &teamID,
+		MatchID:     filters.matchID,
+		Season:      filters.season,
+		Competition: filters.competition,
+		EventTypes:  filters.eventTypes,
+		Period:      filters.period,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get team heatmap", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team heatmap"})
+		return
+	}
+
+	points := make([]heatmap.Point, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, heatmapPoint(row.X, row.Y, row.Coordinates, row.Provider))
+	}
+
+	response = req.response(heatmap.Build(points, req.Columns, req.Rows))
+	response.TeamID = teamID
+	h.setCached(ctx, key, response)
+
+	c.JSON(http.StatusOK, response)
+}
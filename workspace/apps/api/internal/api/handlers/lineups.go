@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/analytics/lineup"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/infrastructure/webhooks"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// maxStarters is the size of a starting XI.
+const maxStarters = 11
+
+// LineupRequest represents a team's lineup for a match.
+type LineupRequest struct {
+	TeamID    int32                 `json:"team_id" binding:"required"`
+	Formation string                `json:"formation"` // e.g. 4-3-3
+	Players   []LineupPlayerRequest `json:"players" binding:"required,min=1,dive"`
+}
+
+// LineupPlayerRequest represents a player in a lineup.
+type LineupPlayerRequest struct {
+	PlayerID    int32  `json:"player_id" binding:"required"`
+	ShirtNumber *int32 `json:"shirt_number" binding:"omitempty,min=1,max=99"`
+	Position    string `json:"position"`
+	Starter     bool   `json:"starter"` // In the starting XI; otherwise on the bench
+}
+
+// LineupsResponse represents a match's lineups with each player's time on the pitch.
+type LineupsResponse struct {
+	MatchID int32        `json:"match_id"`
+	Lineups []TeamLineup `json:"lineups"`
+}
+
+// TeamLineup represents a team's formation, starting XI and bench.
+type TeamLineup struct {
+	TeamID    int32          `json:"team_id"`
+	Formation *string        `json:"formation,omitempty"`
+	Starters  []LineupPlayer `json:"starters"`
+	Bench     []LineupPlayer `json:"bench"`
+}
+
+// LineupPlayer represents a player in a lineup and their appearance so far.
+type LineupPlayer struct {
+	lineup.Appearance
+	PlayerName  string  `json:"player_name"`
+	ShirtNumber *int32  `json:"shirt_number,omitempty"`
+	Position    *string `json:"position,omitempty"`
+}
+
+// toLineup converts a lineup request into the format providers produce.
+func (r *LineupRequest) toLineup(matchID int32) *webhooks.Lineup {
+	l := &webhooks.Lineup{MatchID: matchID, TeamID: r.TeamID, Formation: r.Formation}
+	for _, p := range r.Players {
+		l.Players = append(l.Players, webhooks.LineupPlayer{
+			PlayerID:    p.PlayerID,
+			ShirtNumber: p.ShirtNumber,
+			Position:    p.Position,
+			Starter:     p.Starter,
+		})
+	}
+	return l
+}
+
+// validateLineup checks a lineup against its match and returns its normalized
+// formation, or nil if it has none.
+func validateLineup(match *sqlc.Match, l *webhooks.Lineup) (*string, error) {
+	if l.TeamID != match.HomeTeamID && l.TeamID != match.AwayTeamID {
+		return nil, fmt.Errorf("team %d is not playing in match %d", l.TeamID, match.ID)
+	}
+
+	starters := 0
+	seen := make(map[int32]bool, len(l.Players))
+	for _, p := range l.Players {
+		if seen[p.PlayerID] {
+			return nil, fmt.Errorf("player %d is listed twice", p.PlayerID)
+		}
+		seen[p.PlayerID] = true
+		if p.Starter {
+			starters++
+		}
+	}
+	if starters > maxStarters {
+		return nil, fmt.Errorf("lineup has %d starters", starters)
+	}
+
+	if l.Formation == "" {
+		return nil, nil
+	}
+	formation, ok := lineup.NormalizeFormation(l.Formation)
+	if !ok {
+		return nil, fmt.Errorf("invalid formation: %s", l.Formation)
+	}
+	return &formation, nil
+}
+
+// storeLineup replaces a team's lineup for a match. Once the match has finished,
+// its players' minutes and appearance statistics are recomputed.
+func (h *BaseHandler) storeLineup(ctx context.Context, match *sqlc.Match, l *webhooks.Lineup, formation *string) error {
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	stored, err := queries.UpsertMatchLineup(ctx, sqlc.UpsertMatchLineupParams{
+		MatchID:   match.ID,
+		TeamID:    l.TeamID,
+		Formation: formation,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store lineup: %w", err)
+	}
+	if err := queries.DeleteMatchLineupPlayers(ctx, stored.ID); err != nil {
+		return fmt.Errorf("failed to clear lineup players: %w", err)
+	}
+
+	arg := sqlc.CreateMatchLineupPlayersParams{LineupID: stored.ID}
+	for _, p := range l.Players {
+		var shirtNumber int32
+		if p.ShirtNumber != nil {
+			shirtNumber = *p.ShirtNumber
+		}
+		arg.PlayerIds = append(arg.PlayerIds, p.PlayerID)
+		arg.ShirtNumbers = append(arg.ShirtNumbers, shirtNumber)
+		arg.Positions = append(arg.Positions, p.Position)
+		arg.Starters = append(arg.Starters, p.Starter)
+	}
+	if err := queries.CreateMatchLineupPlayers(ctx, arg); err != nil {
+		return fmt.Errorf("failed to store lineup players: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit lineup: %w", err)
+	}
+
+	if match.Status == "finished" {
+		if _, err := h.assignLineupMinutes(ctx, match.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchAppearances follows the players of a match's lineups through its events.
+func (h *BaseHandler) matchAppearances(ctx context.Context, matchID int32) ([]sqlc.ListMatchLineupPlayersRow, []lineup.Appearance, error) {
+	rows, err := h.queries.ListMatchLineupPlayers(ctx, matchID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get lineup players: %w", err)
+	}
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get match events: %w", err)
+	}
+
+	players := make([]lineup.Player, 0, len(rows))
+	for i := range rows {
+		players = append(players, lineup.Player{PlayerID: rows[i].PlayerID, Starter: rows[i].Starter})
+	}
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	return rows, lineup.Appearances(players, matchEvents), nil
+}
+
+// assignLineupMinutes stores the minutes played by each player in a match's
+// lineups and refreshes their season appearance statistics.
+func (h *BaseHandler) assignLineupMinutes(ctx context.Context, matchID int32) (int, error) {
+	_, appearances, err := h.matchAppearances(ctx, matchID)
+	if err != nil {
+		return 0, err
+	}
+	if len(appearances) == 0 {
+		return 0, nil
+	}
+
+	arg := sqlc.SetMatchLineupMinutesParams{MatchID: matchID}
+	for _, a := range appearances {
+		arg.PlayerIds = append(arg.PlayerIds, a.PlayerID)
+		arg.Minutes = append(arg.Minutes, int32(a.Minutes))
+		arg.SubbedOn = append(arg.SubbedOn, a.SubbedOn)
+		arg.SubbedOff = append(arg.SubbedOff, a.SubbedOff)
+	}
+	if err := h.queries.SetMatchLineupMinutes(ctx, arg); err != nil {
+		return 0, fmt.Errorf("failed to store minutes played: %w", err)
+	}
+	if err := h.queries.RefreshPlayerAppearances(ctx, matchID); err != nil {
+		return 0, fmt.Errorf("failed to refresh player appearances: %w", err)
+	}
+	return len(appearances), nil
+}
+
+// GetMatchLineups handles GET /api/v1/matches/:id/lineups.
+// @Summary Get match lineups
+// @Description Get each team's formation, starting XI and bench, with the minutes every player has spent on the pitch (from substitutions, red cards and second yellow cards, including stoppage time)
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Success 200 {object} LineupsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/lineups [get]
+func (h *MatchHandler) GetMatchLineups(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	lineups, err := h.queries.ListMatchLineups(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get lineups", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lineups"})
+		return
+	}
+	rows, appearances, err := h.matchAppearances(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to compute appearances", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lineups"})
+		return
+	}
+
+	response := LineupsResponse{MatchID: match.ID, Lineups: make([]TeamLineup, 0, len(lineups))}
+	for _, l := range lineups {
+		team := TeamLineup{TeamID: l.TeamID, Formation: l.Formation, Starters: []LineupPlayer{}, Bench: []LineupPlayer{}}
+		for i := range rows {
+			row := &rows[i]
+			if row.LineupID != l.ID {
+				continue
+			}
+			player := LineupPlayer{
+				Appearance:  appearances[i],
+				PlayerName:  row.PlayerName,
+				ShirtNumber: row.ShirtNumber,
+				Position:    row.Position,
+			}
+			if row.Starter {
+				team.Starters = append(team.Starters, player)
+			} else {
+				team.Bench = append(team.Bench, player)
+			}
+		}
+		response.Lineups = append(response.Lineups, team)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PutMatchLineup handles PUT /api/v1/matches/:id/lineups.
+// @Summary Set a team's match lineup
+// @Description Create or replace a team's formation, starting XI and bench for a match. For finished matches the players' minutes and season appearance statistics are recomputed.
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param lineup body LineupRequest true "Lineup"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/lineups [put]
+func (h *MatchHandler) PutMatchLineup(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	var req LineupRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	l := req.toLineup(match.ID)
+	formation, err := validateLineup(&match, l)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.storeLineup(c.Request.Context(), &match, l, formation); err != nil {
+		h.logger.Error("Failed to store lineup", "error", err, "match_id", match.ID, "team_id", req.TeamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store lineup"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "stored",
+		"match_id": match.ID,
+		"team_id":  req.TeamID,
+		"players":  len(req.Players),
+	})
+}
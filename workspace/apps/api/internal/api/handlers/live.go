@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	ws "github.com/emiliospot/footie/api/internal/infrastructure/websocket"
+)
+
+const (
+	// maxLiveMatches caps how many matches a single SSE stream may follow.
+	maxLiveMatches = 20
+
+	// sseRetryMillis is the reconnection delay suggested to EventSource clients.
+	sseRetryMillis = 3000
+)
+
+// LiveHandler streams real-time match updates and the live scores ticker over Server-Sent Events.
+type LiveHandler struct {
+	*BaseHandler
+	hub *ws.Hub
+}
+
+// NewLiveHandler creates a new live handler.
+func NewLiveHandler(base *BaseHandler, hub *ws.Hub) *LiveHandler {
+	return &LiveHandler{BaseHandler: base, hub: hub}
+}
+
+// StreamMatch handles GET /api/v1/matches/:id/live.
+// @Summary Stream live match updates
+// @Description Server-Sent Events stream of the messages delivered over /ws/matches/:id. Send Last-Event-ID (or last_event_id) to resume after a match event.
+// @Tags matches
+// @Produce text/event-stream
+// @Param id path int true "Match ID"
+// @Param Last-Event-ID header string false "Last received event ID"
+// @Param last_event_id query string false "Last received event ID, for clients that cannot set headers"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} gin.H
+// @Failure 503 {object} gin.H
+// @Router /api/v1/matches/{id}/live [get]
+func (h *LiveHandler) StreamMatch(c *gin.Context) {
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidMatchID})
+		return
+	}
+
+	h.stream(c, []int32{int32(matchID)})
+}
+
+// StreamMatches handles GET /api/v1/matches/live?ids=1,2,3.
+// @Summary Stream live updates for several matches
+// @Description Server-Sent Events stream multiplexing several matches. Event IDs carry a cursor per match so Last-Event-ID resumes every match.
+// @Tags matches
+// @Produce text/event-stream
+// @Param ids query string true "Comma-separated match IDs"
+// @Param Last-Event-ID header string false "Last received event ID"
+// @Param last_event_id query string false "Last received event ID, for clients that cannot set headers"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} gin.H
+// @Failure 503 {object} gin.H
+// @Router /api/v1/matches/live [get]
+func (h *LiveHandler) StreamMatches(c *gin.Context) {
+	matchIDs, err := parseMatchIDs(c.Query("ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.stream(c, matchIDs)
+}
+
+// stream subscribes to the hub, replays missed events and forwards live messages until the client leaves.
+func (h *LiveHandler) stream(c *gin.Context, matchIDs []int32) {
+	if h.hub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live updates are not available"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	cursor, err := parseSSECursor(lastEventID, matchIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Subscribe before replaying so nothing published in between is lost;
+	// duplicates are filtered against the cursor below.
+	sub, err := h.hub.Subscribe(matchIDs...)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live updates are not available"})
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	rc, ok := startSSE(c)
+	if !ok {
+		return
+	}
+
+	if len(cursor) > 0 {
+		if err := h.replay(c, c.Writer, matchIDs, cursor); err != nil {
+			return
+		}
+	}
+
+	h.pump(c, rc, sub, func(delivery ws.Delivery) error {
+		if delivery.StreamID != "" {
+			if last, ok := cursor[delivery.MatchID]; ok && events.CompareStreamIDs(delivery.StreamID, last) <= 0 {
+				return nil
+			}
+			cursor[delivery.MatchID] = delivery.StreamID
+		}
+		return writeSSE(c.Writer, cursor.eventID(delivery.StreamID, len(matchIDs) > 1), delivery.Type, delivery.Payload)
+	})
+}
+
+// StreamTicker handles GET /api/v1/ticker/live.
+// @Summary Stream the live scores ticker
+// @Description Server-Sent Events stream of score, status, goal and red card updates for every live match. Starts with a ticker_snapshot of all live matches.
+// @Tags matches
+// @Produce text/event-stream
+// @Param competition query string false "Only include matches from this competition"
+// @Success 200 {string} string "event stream"
+// @Failure 503 {object} gin.H
+// @Router /api/v1/ticker/live [get]
+func (h *LiveHandler) StreamTicker(c *gin.Context) {
+	if h.hub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live updates are not available"})
+		return
+	}
+
+	competition := c.Query("competition")
+	sub, err := h.hub.SubscribeTicker(competition)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live updates are not available"})
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	// Built after subscribing; updates queued meanwhile are sent after the snapshot.
+	snapshot, err := h.TickerSnapshot(c.Request.Context(), competition)
+	if err != nil {
+		h.logger.Error("Failed to build ticker snapshot", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load live matches"})
+		return
+	}
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load live matches"})
+		return
+	}
+
+	rc, ok := startSSE(c)
+	if !ok {
+		return
+	}
+	if err := writeSSE(c.Writer, "", snapshot.Type, payload); err != nil {
+		return
+	}
+
+	h.pump(c, rc, sub, func(delivery ws.Delivery) error {
+		return writeSSE(c.Writer, "", delivery.Type, delivery.Payload)
+	})
+}
+
+// TickerSnapshot builds the initial ticker message listing every live match,
+// optionally limited to one competition.
+func (h *LiveHandler) TickerSnapshot(ctx context.Context, competition string) (*ws.Message, error) {
+	matches, err := h.queries.GetLiveMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	competition = strings.TrimSpace(competition)
+	entries := make([]events.TickerUpdate, 0, len(matches))
+	for _, match := range matches {
+		if competition != "" && !strings.EqualFold(match.Competition, competition) {
+			continue
+		}
+		homeTeamID, awayTeamID := match.HomeTeamID, match.AwayTeamID
+		homeScore, awayScore := int(match.HomeTeamScore), int(match.AwayTeamScore)
+		entries = append(entries, events.TickerUpdate{
+			MatchID:       match.ID,
+			Competition:   match.Competition,
+			HomeTeamID:    &homeTeamID,
+			AwayTeamID:    &awayTeamID,
+			HomeTeamScore: &homeScore,
+			AwayTeamScore: &awayScore,
+			Status:        match.Status,
+		})
+	}
+
+	return &ws.Message{
+		Type:        events.TickerTypeSnapshot,
+		Competition: competition,
+		Timestamp:   time.Now(),
+		Data:        entries,
+	}, nil
+}
+
+// startSSE writes the event stream headers. It returns false if the client is already gone.
+func startSSE(c *gin.Context) (*http.ResponseController, bool) {
+	// The server's WriteTimeout would otherwise cut the stream after a few seconds.
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetryMillis); err != nil {
+		return nil, false
+	}
+	return rc, true
+}
+
+// pump forwards queued deliveries through write and sends heartbeat comments
+// until the client disconnects or the hub ends the subscription.
+func (h *LiveHandler) pump(c *gin.Context, rc *http.ResponseController, sub *ws.Subscription, write func(ws.Delivery) error) {
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.Duration(h.cfg.WS.SSEHeartbeatSeconds) * time.Second
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sub.Done():
+			// Slow consumer or shutdown; the client reconnects with Last-Event-ID.
+			return
+
+		case <-sub.Ready():
+			for _, delivery := range sub.Drain() {
+				if err := write(delivery); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// replay writes match events recorded in Redis after the client's cursor.
+func (h *LiveHandler) replay(c *gin.Context, w io.Writer, matchIDs []int32, cursor sseCursor) error {
+	if h.redis == nil {
+		return nil
+	}
+
+	limit := int64(h.cfg.WS.SSEReplayLimit)
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	type replayed struct {
+		matchID int32
+		entry   events.StreamEntry
+	}
+
+	var missed []replayed
+	for _, matchID := range matchIDs {
+		after, ok := cursor[matchID]
+		if !ok {
+			continue
+		}
+		entries, err := h.publisher.ReplayMatchEvents(c.Request.Context(), matchID, after, limit)
+		if err != nil {
+			h.logger.Error("Failed to replay match stream", "error", err, "match_id", matchID)
+			continue
+		}
+		for _, entry := range entries {
+			missed = append(missed, replayed{matchID: matchID, entry: entry})
+		}
+	}
+
+	// Stream IDs are time-based, so this interleaves matches in publish order.
+	sort.SliceStable(missed, func(i, j int) bool {
+		return events.CompareStreamIDs(missed[i].entry.ID, missed[j].entry.ID) < 0
+	})
+
+	for _, m := range missed {
+		event := m.entry.Event
+		payload, err := json.Marshal(ws.Message{
+			Type:      "match_event",
+			MatchID:   m.matchID,
+			StreamID:  m.entry.ID,
+			Timestamp: event.Timestamp,
+			Data:      event,
+		})
+		if err != nil {
+			return err
+		}
+
+		cursor[m.matchID] = m.entry.ID
+		if err := writeSSE(w, cursor.eventID(m.entry.ID, len(matchIDs) > 1), "match_event", payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSSE writes a single Server-Sent Event. The payload is compact JSON and never contains newlines.
+func writeSSE(w io.Writer, id, eventType string, payload []byte) error {
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: ")
+		b.WriteString(id)
+		b.WriteByte('\n')
+	}
+	b.WriteString("event: ")
+	b.WriteString(eventType)
+	b.WriteString("\ndata: ")
+	b.Write(payload)
+	b.WriteString("\n\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sseCursor tracks the last delivered stream ID per match.
+type sseCursor map[int32]string
+
+// parseSSECursor reads a Last-Event-ID value. A bare stream ID applies to every match;
+// multi-match streams use "matchID=streamID" pairs separated by commas.
+func parseSSECursor(value string, matchIDs []int32) (sseCursor, error) {
+	cursor := make(sseCursor)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return cursor, nil
+	}
+
+	if !strings.Contains(value, "=") {
+		if !events.ValidStreamID(value) {
+			return nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		for _, matchID := range matchIDs {
+			cursor[matchID] = value
+		}
+		return cursor, nil
+	}
+
+	wanted := make(map[int32]bool, len(matchIDs))
+	for _, matchID := range matchIDs {
+		wanted[matchID] = true
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		idPart, streamID, _ := strings.Cut(pair, "=")
+		matchID, err := strconv.ParseInt(strings.TrimSpace(idPart), 10, 32)
+		if err != nil || !events.ValidStreamID(streamID) {
+			return nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		if wanted[int32(matchID)] {
+			cursor[int32(matchID)] = streamID
+		}
+	}
+
+	return cursor, nil
+}
+
+// eventID returns the SSE id for a delivery. Messages that are not stream-backed carry no id,
+// so the client's Last-Event-ID keeps pointing at the last match event.
+func (c sseCursor) eventID(streamID string, multi bool) string {
+	if streamID == "" {
+		return ""
+	}
+	if !multi {
+		return streamID
+	}
+
+	matchIDs := make([]int32, 0, len(c))
+	for matchID := range c {
+		matchIDs = append(matchIDs, matchID)
+	}
+	sort.Slice(matchIDs, func(i, j int) bool { return matchIDs[i] < matchIDs[j] })
+
+	pairs := make([]string, 0, len(matchIDs))
+	for _, matchID := range matchIDs {
+		pairs = append(pairs, fmt.Sprintf("%d=%s", matchID, c[matchID]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// parseMatchIDs parses a comma-separated list of match IDs.
+func parseMatchIDs(value string) ([]int32, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("ids is required")
+	}
+
+	seen := make(map[int32]bool)
+	var matchIDs []int32
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match ID %q", part)
+		}
+		if !seen[int32(id)] {
+			seen[int32(id)] = true
+			matchIDs = append(matchIDs, int32(id))
+		}
+	}
+
+	if len(matchIDs) > maxLiveMatches {
+		return nil, fmt.Errorf("at most %d matches can be streamed at once", maxLiveMatches)
+	}
+	return matchIDs, nil
+}
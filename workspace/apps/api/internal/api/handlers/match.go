@@ -9,10 +9,15 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/emiliospot/footie/api/internal/analytics/xg"
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
+	domainEvents "github.com/emiliospot/footie/api/internal/domain/events"
 	"github.com/emiliospot/footie/api/internal/domain/mappers"
 	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
 	"github.com/emiliospot/footie/api/internal/infrastructure/events"
 	"github.com/emiliospot/footie/api/internal/repository/sqlc"
 )
@@ -43,12 +48,29 @@ type CreateMatchEventRequest struct {
 	Description       string   `json:"description"`
 	Metadata          string   `json:"metadata"`
 	Minute            int32    `json:"minute" binding:"required,min=0,max=120"`
+	Second            *int32   `json:"second" binding:"omitempty,min=0,max=59"`
+	Period            string   `json:"period"` // Determined from the minute when omitted
 	TeamID            *int32   `json:"team_id"`
 	PlayerID          *int32   `json:"player_id"`
 	SecondaryPlayerID *int32   `json:"secondary_player_id"`
-	ExtraMinute       *int32   `json:"extra_minute"`
-	PositionX         *float64 `json:"position_x"`
-	PositionY         *float64 `json:"position_y"`
+	ExtraMinute       *int32   `json:"extra_minute" binding:"omitempty,min=0"`       // Stoppage minutes (2 for 45+2)
+	PositionX         *float64 `json:"position_x" binding:"omitempty,min=0,max=105"` // Meters on the canonical 105x68 pitch, attacking left to right
+	PositionY         *float64 `json:"position_y" binding:"omitempty,min=0,max=68"`
+}
+
+// UpdateMatchEventRequest represents a correction to a match event. Omitted
+// fields are left unchanged.
+type UpdateMatchEventRequest struct {
+	EventType         *string `json:"event_type" binding:"omitempty,min=1"`
+	Description       *string `json:"description"`
+	Metadata          *string `json:"metadata"`
+	Minute            *int32  `json:"minute" binding:"omitempty,min=0,max=120"`
+	Second            *int32  `json:"second" binding:"omitempty,min=0,max=59"`
+	Period            *string `json:"period"`
+	TeamID            *int32  `json:"team_id"`
+	PlayerID          *int32  `json:"player_id"`
+	SecondaryPlayerID *int32  `json:"secondary_player_id"`
+	ExtraMinute       *int32  `json:"extra_minute" binding:"omitempty,min=0"`
 }
 
 // ListMatches handles GET /api/v1/matches.
@@ -213,6 +235,19 @@ func (h *MatchHandler) CreateMatchEvent(c *gin.Context) {
 		}
 	}
 
+	// Positions are already canonical; mark them so analytics don't rescale them
+	req.PositionX, req.PositionY, req.Metadata = pitch.NormalizeJSON(pitch.CanonicalFrame, pitch.LeftToRight,
+		req.PositionX, req.PositionY, req.Metadata)
+
+	// Fill in model xG for shots created without one
+	if filled, ok := h.xg.Fill(req.EventType, req.PositionX, req.PositionY, req.Metadata, ""); ok {
+		req.Metadata = filled
+	}
+	// Value passes and carries: progression flags, box entries and xT
+	if annotated, ok := xt.Annotate(h.xt, req.EventType, req.PositionX, req.PositionY, req.Metadata, ""); ok {
+		req.Metadata = annotated
+	}
+
 	// Convert float64 pointers to pgtype.Numeric
 	var posX, posY pgtype.Numeric
 	if req.PositionX != nil {
@@ -234,6 +269,9 @@ func (h *MatchHandler) CreateMatchEvent(c *gin.Context) {
 		desc = &req.Description
 	}
 
+	clock := newMatchClock(req.Period, req.Minute, req.ExtraMinute, req.Second)
+	period := clock.Period.String()
+
 	// Create event in database
 	event, err := h.queries.CreateMatchEvent(c.Request.Context(), sqlc.CreateMatchEventParams{
 		MatchID:           int32(matchID),
@@ -241,8 +279,12 @@ func (h *MatchHandler) CreateMatchEvent(c *gin.Context) {
 		PlayerID:          req.PlayerID,
 		SecondaryPlayerID: req.SecondaryPlayerID,
 		EventType:         req.EventType,
-		Minute:            req.Minute,
-		ExtraMinute:       req.ExtraMinute,
+		Minute:            clock.Minute,
+		Second:            &clock.Second,
+		Period:            &period,
+		ExtraMinute:       extraMinute(clock),
+		PeriodNumber:      clock.PeriodNumber(),
+		ClockMs:           clock.Ms,
 		PositionX:         posX,
 		PositionY:         posY,
 		Description:       desc,
@@ -274,14 +316,276 @@ func (h *MatchHandler) CreateMatchEvent(c *gin.Context) {
 	c.JSON(http.StatusCreated, domainEvent)
 }
 
+// UpdateMatchEvent handles PATCH /api/v1/matches/:id/events/:eventId.
+// @Summary Correct match event
+// @Description Correct a match event's type, players, clock, description or metadata. Corrected metadata is normalized like a created event's and the event's model xG and xT are recomputed. A finished match goes through the post-processing it had when it finished again (possessions, game states, minutes played, derived events, goalkeeper and pressing statistics, win probabilities and ratings); a live match only has its inferred assists, second assists and key passes recomputed
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param eventId path int true "Event ID"
+// @Param event body UpdateMatchEventRequest true "Corrected fields"
+// @Success 200 {object} models.MatchEvent
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/events/{eventId} [patch]
+func (h *MatchHandler) UpdateMatchEvent(c *gin.Context) {
+	current, ok := h.loadMatchEvent(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateMatchEventRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	arg := sqlc.UpdateMatchEventParams{
+		ID:                current.ID,
+		TeamID:            req.TeamID,
+		PlayerID:          req.PlayerID,
+		SecondaryPlayerID: req.SecondaryPlayerID,
+		EventType:         req.EventType,
+		Description:       req.Description,
+	}
+	if req.Metadata != nil {
+		var metadataCheck map[string]interface{}
+		if jsonErr := json.Unmarshal([]byte(*req.Metadata), &metadataCheck); jsonErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metadata JSON"})
+			return
+		}
+		arg.Metadata = []byte(*req.Metadata)
+	}
+
+	// Normalize corrected metadata like a created event's and recompute the
+	// event's model xG and xT for its corrected type and metadata
+	eventType, metadata := current.EventType, string(current.Metadata)
+	if req.EventType != nil {
+		eventType = *req.EventType
+	}
+	position := mappers.ToDomainMatchEvent(&current)
+	if req.Metadata != nil {
+		metadata = correctedMetadata(metadata, *req.Metadata, position.PositionX, position.PositionY)
+	}
+	if annotated := h.reannotate(eventType, position.PositionX, position.PositionY, metadata); annotated != string(current.Metadata) {
+		arg.Metadata = []byte(annotated)
+	}
+
+	// Rebuild the clock from the corrected fields over the current ones
+	if req.Minute != nil || req.Second != nil || req.Period != nil || req.ExtraMinute != nil {
+		period, minute, stoppage, second := "", current.Minute, current.ExtraMinute, current.Second
+		if current.Period != nil {
+			period = *current.Period
+		}
+		if req.Period != nil {
+			period = *req.Period
+		}
+		if req.Minute != nil {
+			minute = *req.Minute
+		}
+		if req.ExtraMinute != nil {
+			stoppage = req.ExtraMinute
+		}
+		if req.Second != nil {
+			second = req.Second
+		}
+		clock := newMatchClock(period, minute, stoppage, second)
+		clockPeriod := clock.Period.String()
+		periodNumber := clock.PeriodNumber()
+		stoppageMinutes := clock.Stoppage
+		arg.Minute = &clock.Minute
+		arg.Second = &clock.Second
+		arg.Period = &clockPeriod
+		arg.ExtraMinute = &stoppageMinutes
+		arg.PeriodNumber = &periodNumber
+		arg.ClockMs = &clock.Ms
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.queries.UpdateMatchEvent(ctx, arg)
+	if err != nil {
+		h.logger.Error("Failed to update match event", "error", err, "event_id", current.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update match event"})
+		return
+	}
+
+	h.recomputeMatch(ctx, event.MatchID)
+	if refreshed, getErr := h.queries.GetMatchEventByID(ctx, event.ID); getErr == nil {
+		event = refreshed
+	}
+
+	h.logger.Info("Match event corrected", "match_id", event.MatchID, "event_id", event.ID)
+
+	c.JSON(http.StatusOK, mappers.ToDomainMatchEvent(&event))
+}
+
+// DeleteMatchEvent handles DELETE /api/v1/matches/:id/events/:eventId.
+// @Summary Delete match event
+// @Description Delete a match event recorded in error. A finished match goes through the post-processing it had when it finished again; a live match only has its inferred assists, second assists and key passes recomputed
+// @Tags matches
+// @Param id path int true "Match ID"
+// @Param eventId path int true "Event ID"
+// @Success 204
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/events/{eventId} [delete]
+func (h *MatchHandler) DeleteMatchEvent(c *gin.Context) {
+	event, ok := h.loadMatchEvent(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.queries.DeleteMatchEvent(ctx, event.ID); err != nil {
+		h.logger.Error("Failed to delete match event", "error", err, "event_id", event.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete match event"})
+		return
+	}
+	h.recomputeMatch(ctx, event.MatchID)
+
+	h.logger.Info("Match event deleted", "match_id", event.MatchID, "event_id", event.ID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// recomputeMatch brings a match's stored analytics up to date after one of its
+// events is corrected or deleted. A finished match goes through the whole
+// post-processing it had when it finished; a live one gets it when it finishes,
+// so only its derived passes, which point at the corrected sequence, are
+// inferred again. Failures are logged.
+func (h *MatchHandler) recomputeMatch(ctx context.Context, matchID int32) {
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to get match", "error", err, "match_id", matchID)
+		return
+	}
+	if match.Status == "finished" {
+		h.processFinishedMatch(ctx, matchID)
+		return
+	}
+	if _, err := h.assignDerivedEvents(ctx, matchID); err != nil {
+		h.logger.Error("Failed to recompute derived events", "error", err, "match_id", matchID)
+	}
+}
+
+// correctedMetadata returns the metadata replacing an event's current metadata.
+// The event keeps its position, so the corrected metadata keeps the coordinate
+// system and provider recorded with it; corrections to other events are in
+// canonical coordinates, as for created events.
+func correctedMetadata(current, corrected string, x, y *float64) string {
+	var previous, meta map[string]interface{}
+	_ = json.Unmarshal([]byte(current), &previous)
+	if err := json.Unmarshal([]byte(corrected), &meta); err != nil || meta == nil {
+		return corrected
+	}
+	for _, key := range []string{pitch.MetaCoordinates, xg.MetaProvider} {
+		if _, ok := meta[key]; !ok && previous[key] != nil {
+			meta[key] = previous[key]
+		}
+	}
+	if _, ok := meta[xg.MetaProvider]; !ok {
+		pitch.Normalize(pitch.CanonicalFrame, pitch.LeftToRight, x, y, meta)
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return corrected
+	}
+	return string(data)
+}
+
+// reannotate recomputes the model values in an event's metadata: the xG of a
+// shot scored by the model, and the progression flags, box entry and xT of a
+// pass or carry. Values that no longer apply to the event type are dropped;
+// provider xG is left alone.
+func (h *BaseHandler) reannotate(eventType string, x, y *float64, metadata string) string {
+	if metadata != "" {
+		var meta map[string]interface{}
+		if err := json.Unmarshal([]byte(metadata), &meta); err != nil || meta == nil {
+			return metadata
+		}
+		if _, scored := meta[xg.MetaModel]; scored {
+			delete(meta, xg.MetaXG)
+			delete(meta, xg.MetaModel)
+		}
+		if _, valued := meta[xt.MetaGrid]; valued {
+			delete(meta, xt.MetaXT)
+			delete(meta, xt.MetaGrid)
+		}
+		for _, key := range []string{xt.MetaProgressivePass, xt.MetaProgressiveCarry, xt.MetaBoxEntry} {
+			delete(meta, key)
+		}
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return metadata
+		}
+		metadata = string(data)
+	}
+
+	if filled, ok := h.xg.Fill(eventType, x, y, metadata, ""); ok {
+		metadata = filled
+	}
+	if annotated, ok := xt.Annotate(h.xt, eventType, x, y, metadata, ""); ok {
+		metadata = annotated
+	}
+	return metadata
+}
+
+// loadMatchEvent parses the match and event ID path parameters and loads the
+// event, writing the error response when it fails or the event belongs to
+// another match.
+func (h *MatchHandler) loadMatchEvent(c *gin.Context) (sqlc.MatchEvent, bool) {
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidMatchID})
+		return sqlc.MatchEvent{}, false
+	}
+	eventID, err := strconv.ParseInt(c.Param("eventId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return sqlc.MatchEvent{}, false
+	}
+
+	event, err := h.queries.GetMatchEventByID(c.Request.Context(), int32(eventID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.logger.Error("Failed to get match event", "error", err, "event_id", eventID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match event"})
+		return sqlc.MatchEvent{}, false
+	}
+	if err != nil || event.MatchID != int32(matchID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match event not found"})
+		return sqlc.MatchEvent{}, false
+	}
+	return event, true
+}
+
+// loadMatch parses the match ID path parameter and loads the match, writing
+// the error response when it fails.
+func (h *MatchHandler) loadMatch(c *gin.Context) (sqlc.Match, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidMatchID})
+		return sqlc.Match{}, false
+	}
+
+	match, err := h.queries.GetMatchByID(c.Request.Context(), int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+			return sqlc.Match{}, false
+		}
+		h.logger.Error("Failed to get match", "error", err, "match_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match"})
+		return sqlc.Match{}, false
+	}
+	return match, true
+}
+
 // publishMatchEventAsync publishes a match event to Redis Streams and Pub/Sub asynchronously.
 // This reduces the cognitive complexity of CreateMatchEvent.
 func (h *MatchHandler) publishMatchEventAsync(ctx context.Context, event *sqlc.MatchEvent) {
-	extraMin := 0
-	if event.ExtraMinute != nil {
-		extraMin = int(*event.ExtraMinute)
-	}
-
 	var posXFloat, posYFloat *float64
 	if event.PositionX.Valid {
 		val, valErr := event.PositionX.Float64Value()
@@ -307,22 +611,62 @@ func (h *MatchHandler) publishMatchEventAsync(ctx context.Context, event *sqlc.M
 		description = *event.Description
 	}
 
-	publishErr := h.publisher.PublishMatchEvent(ctx, &events.MatchEvent{
+	// The live scores ticker filters by competition, so look it up for events it carries.
+	competition := ""
+	if events.IsTickerEvent(event.EventType) {
+		if match, matchErr := h.queries.GetMatchByID(ctx, event.MatchID); matchErr == nil {
+			competition = match.Competition
+		} else {
+			h.logger.Warn("Failed to load match competition for ticker", "error", matchErr, "match_id", event.MatchID)
+		}
+	}
+
+	published := &events.MatchEvent{
 		ID:                event.ID,
 		MatchID:           event.MatchID,
+		Competition:       competition,
 		TeamID:            event.TeamID,
 		PlayerID:          event.PlayerID,
 		SecondaryPlayerID: event.SecondaryPlayerID,
 		EventType:         event.EventType,
-		Minute:            int(event.Minute),
-		ExtraMinute:       extraMin,
 		PositionX:         posXFloat,
 		PositionY:         posYFloat,
 		Description:       description,
 		Metadata:          string(event.Metadata),
 		Timestamp:         event.CreatedAt.Time,
-	})
+	}
+	domainEvent := mappers.ToDomainMatchEvent(event)
+	published.SetClock(domainEvent.Clock())
+
+	publishErr := h.publisher.PublishMatchEvent(ctx, published)
 	if publishErr != nil {
 		h.logger.Error("Failed to publish match event", "error", publishErr, "event_id", event.ID)
+		return
+	}
+
+	h.publishXGTimeline(ctx, event.MatchID, event.EventType)
+	h.publishWinProbability(ctx, event)
+}
+
+// newMatchClock builds the match clock for an event from its optional period,
+// stoppage minute and second.
+func newMatchClock(period string, minute int32, stoppage, second *int32) domainEvents.MatchClock {
+	var stoppageMinutes, seconds int32
+	if stoppage != nil {
+		stoppageMinutes = *stoppage
+	}
+	if second != nil {
+		seconds = *second
+	}
+	return domainEvents.NewMatchClock(domainEvents.NormalizePeriod(period), minute, stoppageMinutes, seconds)
+}
+
+// extraMinute returns the clock's stoppage minutes for the extra_minute column,
+// nil in regular time.
+func extraMinute(clock domainEvents.MatchClock) *int32 {
+	if clock.Stoppage == 0 {
+		return nil
 	}
+	stoppage := clock.Stoppage
+	return &stoppage
 }
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/passnetwork"
+	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+)
+
+// Pass network windows.
+const (
+	windowFirstSubstitution = "first_substitution"
+	windowFullMatch         = "full_match"
+)
+
+// PassNetworkRequest represents the query parameters for the pass network endpoint.
+type PassNetworkRequest struct {
+	Period     string `form:"period"`     // first_half, second_half, extra_time_first, extra_time_second
+	GameState  string `form:"game_state"` // winning, drawing, losing
+	Window     string `form:"window" binding:"omitempty,oneof=first_substitution full_match"`
+	FromMinute *int32 `form:"from_minute" binding:"omitempty,min=0"`
+	ToMinute   *int32 `form:"to_minute" binding:"omitempty,min=1"`
+	MinPasses  int    `form:"min_passes" binding:"omitempty,min=1"`
+}
+
+// options validates the request and converts it to pass network options.
+func (r *PassNetworkRequest) options() (passnetwork.Options, error) {
+	opts := passnetwork.Options{MinPasses: r.MinPasses}
+	if r.Period != "" {
+		period := events.NormalizePeriod(r.Period)
+		if period == events.PeriodRegular {
+			return opts, fmt.Errorf("invalid period: %s", r.Period)
+		}
+		r.Period = period.String()
+		opts.Period = period
+	}
+	if r.GameState != "" {
+		state, ok := gamestate.Parse(r.GameState)
+		if !ok {
+			return opts, fmt.Errorf("invalid game state: %s", r.GameState)
+		}
+		r.GameState = string(state)
+		opts.GameState = state
+	}
+	if r.FromMinute != nil {
+		opts.FromMs = int64(*r.FromMinute) * 60000
+	}
+	if r.ToMinute != nil {
+		if r.FromMinute != nil && *r.ToMinute <= *r.FromMinute {
+			return opts, errors.New("to_minute must be after from_minute")
+		}
+		opts.ToMs = int64(*r.ToMinute) * 60000
+	}
+	if r.Window == "" {
+		r.Window = windowFirstSubstitution
+	}
+	opts.UntilFirstSubstitution = r.Window != windowFullMatch
+	if r.MinPasses == 0 {
+		r.MinPasses = 1
+		opts.MinPasses = 1
+	}
+	return opts, nil
+}
+
+// cacheKey identifies the request's result for a team in a match.
+func (r *PassNetworkRequest) cacheKey(matchID, teamID int32) string {
+	minute := func(m *int32) string {
+		if m == nil {
+			return ""
+		}
+		return strconv.Itoa(int(*m))
+	}
+	return fmt.Sprintf("pass-network:%d:%d:%s:%s:%s:%s:%s:%d",
+		matchID, teamID, r.Period, r.GameState, r.Window, minute(r.FromMinute), minute(r.ToMinute), r.MinPasses)
+}
+
+// PassNetworkNode is a player in a pass network.
+type PassNetworkNode struct {
+	passnetwork.Node
+	PlayerName  string `json:"player_name,omitempty"`
+	ShirtNumber *int32 `json:"shirt_number,omitempty"`
+	Position    string `json:"position,omitempty"`
+}
+
+// PassNetworkResponse represents a team's pass network in a match.
+type PassNetworkResponse struct {
+	MatchID    int32  `json:"match_id"`
+	TeamID     int32  `json:"team_id"`
+	Period     string `json:"period,omitempty"`
+	GameState  string `json:"game_state,omitempty"`
+	Window     string `json:"window"`
+	FromMinute *int32 `json:"from_minute,omitempty"`
+	ToMinute   *int32 `json:"to_minute,omitempty"`
+	Passes     int    `json:"passes"`
+	// FirstSubstitution is the clock of the team's first substitution, if any
+	FirstSubstitution *events.MatchClock `json:"first_substitution,omitempty"`
+	Nodes             []PassNetworkNode  `json:"nodes"`
+	Edges             []passnetwork.Edge `json:"edges"`
+}
+
+// GetMatchPassNetwork handles GET /api/v1/matches/:id/teams/:teamId/pass-network.
+// @Summary Get team pass network
+// @Description Get a team's pass network in a match: each player's average position and completed passes between each pair (passer to receiver). By default passes stop at the team's first substitution
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param teamId path int true "Team ID"
+// @Param period query string false "Period (first_half, second_half, extra_time_first, extra_time_second)"
+// @Param game_state query string false "Game state (winning, drawing, losing)"
+// @Param window query string false "first_substitution or full_match" default(first_substitution)
+// @Param from_minute query int false "Start of the match clock window, in minutes"
+// @Param to_minute query int false "End of the match clock window, in minutes"
+// @Param min_passes query int false "Minimum passes for an edge" default(1)
+// @Success 200 {object} PassNetworkResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/teams/{teamId}/pass-network [get]
+func (h *MatchHandler) GetMatchPassNetwork(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("teamId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return
+	}
+	teamID := int32(id)
+	if teamID != match.HomeTeamID && teamID != match.AwayTeamID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team did not play in this match"})
+		return
+	}
+
+	var req PassNetworkRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only finished matches are cached; live networks change with every pass.
+	ctx := c.Request.Context()
+	key := req.cacheKey(match.ID, teamID)
+	finished := match.Status == "finished"
+	var response PassNetworkResponse
+	if finished && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get match events", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pass network"})
+		return
+	}
+	players, err := h.queries.GetPlayersByTeam(ctx, teamID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.logger.Error("Failed to get team players", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pass network"})
+		return
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	network := passnetwork.Build(matchEvents, teamID, opts)
+
+	response = PassNetworkResponse{
+		MatchID:           match.ID,
+		TeamID:            teamID,
+		Period:            req.Period,
+		GameState:         req.GameState,
+		Window:            req.Window,
+		FromMinute:        req.FromMinute,
+		ToMinute:          req.ToMinute,
+		Passes:            network.Passes,
+		FirstSubstitution: network.FirstSubstitution,
+		Nodes:             make([]PassNetworkNode, 0, len(network.Nodes)),
+		Edges:             network.Edges,
+	}
+	for _, node := range network.Nodes {
+		entry := PassNetworkNode{Node: node}
+		for i := range players {
+			if players[i].ID == node.PlayerID {
+				entry.PlayerName = players[i].FullName
+				entry.ShirtNumber = players[i].ShirtNumber
+				entry.Position = players[i].Position
+				break
+			}
+		}
+		response.Nodes = append(response.Nodes, entry)
+	}
+	if finished {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
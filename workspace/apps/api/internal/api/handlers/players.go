@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const (
+	errInvalidPlayerID = "Invalid player ID"
+)
+
+// PlayerHandler handles player endpoints.
+type PlayerHandler struct {
+	*BaseHandler
+}
+
+// NewPlayerHandler creates a new player handler.
+func NewPlayerHandler(base *BaseHandler) *PlayerHandler {
+	return &PlayerHandler{BaseHandler: base}
+}
+
+// PlayerStatisticsResponse represents a player's statistics.
+type PlayerStatisticsResponse struct {
+	PlayerID    int32                     `json:"player_id"`
+	Season      string                    `json:"season,omitempty"`
+	Competition string                    `json:"competition,omitempty"`
+	Seasons     []models.PlayerStatistics `json:"seasons"` // Stored season statistics matching the filters
+	Threat      ThreatStats               `json:"threat"`
+	Creation    CreationStats             `json:"creation"`
+	// GameState, MinDifferential and MaxDifferential echo the game state filter,
+	// which applies to Threat and Creation but not to the stored season statistics
+	GameState       string `json:"game_state,omitempty"`
+	MinDifferential *int   `json:"min_differential,omitempty"`
+	MaxDifferential *int   `json:"max_differential,omitempty"`
+	// GameStates are the minutes the player spent on the pitch winning, drawing and
+	// losing in finished matches
+	GameStates gamestate.Minutes `json:"game_states"`
+}
+
+// GetPlayerStatistics handles GET /api/v1/players/:id/statistics.
+// @Summary Get player statistics
+// @Description Get season statistics plus expected threat (xT), ball progression and chance creation (xA, key passes, shot-creating and goal-creating actions) totals and per-90 values, optionally split by game state, and the minutes spent in each game state
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param game_state query string false "Game state at the time of each event (winning, drawing, losing)"
+// @Param min_differential query int false "Minimum goal differential at the time of each event"
+// @Param max_differential query int false "Maximum goal differential at the time of each event"
+// @Success 200 {object} PlayerStatisticsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/players/{id}/statistics [get]
+func (h *PlayerHandler) GetPlayerStatistics(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidPlayerID})
+		return
+	}
+	playerID := int32(id)
+
+	var req StatisticsRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	var stateReq GameStateRequest
+	if bindErr := c.ShouldBindQuery(&stateReq); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	differentials, err := stateReq.differentials()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	_, err = h.queries.GetPlayerByID(ctx, playerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		h.logger.Error("Failed to get player", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player statistics"})
+		return
+	}
+
+	stats, err := h.queries.GetPlayerStatsByPlayer(ctx, playerID)
+	if err != nil {
+		h.logger.Error("Failed to get player statistics", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player statistics"})
+		return
+	}
+
+	response := PlayerStatisticsResponse{
+		PlayerID:    playerID,
+		Season:      req.Season,
+		Competition: req.Competition,
+		Seasons:     []models.PlayerStatistics{},
+	}
+	var minutes int64
+	for i := range stats {
+		if req.matches(stats[i].Season, stats[i].Competition) {
+			response.Seasons = append(response.Seasons, mappers.ToDomainPlayerStatistics(&stats[i]))
+			minutes += int64(stats[i].MinutesPlayed)
+		}
+	}
+
+	// Game state minutes are the player's own time on the pitch in each state,
+	// for the team the player played for in each match
+	season, competition := req.params()
+	durations, err := h.playerGameStateDurations(ctx, sqlc.ListPlayerGameStateEventsParams{
+		PlayerID:    playerID,
+		Season:      season,
+		Competition: competition,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get game states", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player statistics"})
+		return
+	}
+	response.GameStates = durations.ByState()
+
+	// Split by game state, per-90 values are over the minutes spent in it
+	if differentials != nil {
+		response.GameState = stateReq.GameState
+		response.MinDifferential, response.MaxDifferential = differentials.Min, differentials.Max
+		minutes = int64(math.Round(durations.Minutes(*differentials)))
+	}
+	minDiff, maxDiff := differentialParams(differentials)
+	threat, err := h.queries.GetPlayerThreat(ctx, sqlc.GetPlayerThreatParams{
+		PlayerID:        &playerID,
+		Season:          season,
+		Competition:     competition,
+		MinDifferential: minDiff,
+		MaxDifferential: maxDiff,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get player threat", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player statistics"})
+		return
+	}
+	response.Threat = newThreatStats(threat.Matches, minutes, threat.ExpectedThreat,
+		threat.ProgressivePasses, threat.ProgressiveCarries, threat.BoxEntries)
+
+	creation, err := h.queries.GetPlayerCreation(ctx, sqlc.GetPlayerCreationParams{
+		PlayerID:        &playerID,
+		Season:          season,
+		Competition:     competition,
+		MinDifferential: minDiff,
+		MaxDifferential: maxDiff,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get player creation", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player statistics"})
+		return
+	}
+	response.Creation = newCreationStats(creation.Matches, minutes, creation.ExpectedAssists,
+		creation.KeyPasses, creation.ShotCreatingActions, creation.GoalCreatingActions)
+
+	c.JSON(http.StatusOK, response)
+}
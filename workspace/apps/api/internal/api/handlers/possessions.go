@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// PossessionsRequest represents the query parameters for the possessions endpoint.
+type PossessionsRequest struct {
+	TeamID int32 `form:"team_id"` // Limits the sequences, not the team statistics
+}
+
+// PossessionsResponse represents a match's reconstructed possessions.
+type PossessionsResponse struct {
+	MatchID     int32                   `json:"match_id"`
+	HomeTeamID  int32                   `json:"home_team_id"`
+	AwayTeamID  int32                   `json:"away_team_id"`
+	Teams       []possession.TeamStats  `json:"teams"`
+	Possessions []possession.Possession `json:"possessions"`
+}
+
+// matchPossessions reconstructs a match's possessions from its event log.
+func (h *BaseHandler) matchPossessions(ctx context.Context, matchID int32) ([]possession.Possession, error) {
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	return possession.Reconstruct(matchEvents), nil
+}
+
+// assignPossessions reconstructs a match's possessions and stores each event's
+// possession_id.
+func (h *BaseHandler) assignPossessions(ctx context.Context, matchID int32) (int, error) {
+	possessions, err := h.matchPossessions(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconstruct possessions: %w", err)
+	}
+
+	eventIDs, possessionIDs := possession.Assignments(possessions)
+	if err := h.queries.SetMatchEventPossessions(ctx, sqlc.SetMatchEventPossessionsParams{
+		Ids:           eventIDs,
+		PossessionIds: possessionIDs,
+		MatchID:       matchID,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to store possessions: %w", err)
+	}
+	return len(possessions), nil
+}
+
+// GetMatchPossessions handles GET /api/v1/matches/:id/possessions.
+// @Summary Get match possessions
+// @Description Reconstruct a match's possessions from its event log, with per-sequence metrics (duration, passes, start zone, outcome, xG) and possession-based team statistics (possession share by time, passes per possession, direct speed, shots and xG per possession)
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param team_id query int false "Only list this team's possessions"
+// @Success 200 {object} PossessionsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/possessions [get]
+func (h *MatchHandler) GetMatchPossessions(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	var req PossessionsRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	// Only finished matches are cached; live possessions change with every event.
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("possessions:%d:%d", match.ID, req.TeamID)
+	finished := match.Status == "finished"
+	var response PossessionsResponse
+	if finished && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	possessions, err := h.matchPossessions(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get match events", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match possessions"})
+		return
+	}
+
+	response = PossessionsResponse{
+		MatchID:     match.ID,
+		HomeTeamID:  match.HomeTeamID,
+		AwayTeamID:  match.AwayTeamID,
+		Teams:       possession.Summarize(possessions, match.HomeTeamID, match.AwayTeamID),
+		Possessions: possessions,
+	}
+	if req.TeamID != 0 {
+		response.Possessions = make([]possession.Possession, 0, len(possessions)/2)
+		for _, p := range possessions {
+			if p.TeamID == req.TeamID {
+				response.Possessions = append(response.Possessions, p)
+			}
+		}
+	}
+	if finished {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
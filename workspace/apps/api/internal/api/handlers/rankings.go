@@ -1,9 +1,45 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/analytics/pressing"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// Ranking categories computed from stored events. They replace mock categories
+// with the same title.
+const (
+	rankingExpectedThreat     = "xT - Expected Threat"
+	rankingProgressivePasses  = "Progressive Passes"
+	rankingProgressiveCarries = "Progressive Carries"
+	rankingBoxPenetrations    = "Box Penetrations"
+
+	rankingExpectedAssists     = "xA - Expected Assists"
+	rankingShotCreatingActions = "Shot-Creating Actions"
+	rankingGoalCreatingActions = "Goal-Creating Actions"
+
+	rankingPPDA                 = "PPDA"
+	rankingHighTurnovers        = "High Turnovers"
+	rankingFinalThirdRecoveries = "Final Third Recoveries"
+	rankingCounterPressRegains  = "Counter-Press Regains"
+
+	rankingSaves          = "Saves"
+	rankingSavePercentage = "Save Percentage"
+	rankingGoalsPrevented = "Goals Prevented"
+	rankingClaimRate      = "Cross Claim Rate"
+)
+
+const (
+	// rankingSize is the number of entries shown per category
+	rankingSize = 5
+	// minRankingMinutes keeps players with very little game time out of per-90 rankings
+	minRankingMinutes = 90
 )
 
 // RankingsHandler handles competition rankings endpoints.
@@ -57,10 +93,8 @@ type RankingsResponse struct {
 func (h *RankingsHandler) GetCompetitionRankings(c *gin.Context) {
 	rankingType := c.DefaultQuery("type", "team")
 	category := c.DefaultQuery("category", "attacking")
-	// Note: championship and season parameters are accepted but not used in mock data
-	// They will be used when connecting to real database
-	_ = c.DefaultQuery("championship", "Cyprus U19 League Division 1")
-	_ = c.DefaultQuery("season", "2025/2026")
+	championship := c.DefaultQuery("championship", "Cyprus U19 League Division 1")
+	season := c.DefaultQuery("season", "2025/2026")
 
 	var response RankingsResponse
 	response.Type = rankingType
@@ -72,9 +106,382 @@ func (h *RankingsHandler) GetCompetitionRankings(c *gin.Context) {
 		response.Categories = h.getPlayerRankings(category)
 	}
 
+	// Attacking rankings use expected threat, ball progression and chance creation
+	// from stored events when there are any; the remaining categories are still
+	// mock data.
+	if category == "attacking" && h.pool != nil {
+		threat, err := h.getThreatRankings(c.Request.Context(), rankingType, championship, season)
+		if err != nil {
+			h.logger.Warn("Failed to get threat rankings, using mock data", "error", err,
+				"championship", championship, "season", season)
+		} else {
+			response.Categories = mergeRankingCategories(response.Categories, threat)
+		}
+		creation, err := h.getCreationRankings(c.Request.Context(), rankingType, championship, season)
+		if err != nil {
+			h.logger.Warn("Failed to get creation rankings", "error", err,
+				"championship", championship, "season", season)
+		} else {
+			response.Categories = mergeRankingCategories(response.Categories, creation)
+		}
+	}
+
+	// Team defending rankings add pressing intensity from the stored pressing match statistics.
+	if category == "defending" && rankingType == "team" && h.pool != nil {
+		pressingCategories, err := h.getPressingRankings(c.Request.Context(), championship, season)
+		if err != nil {
+			h.logger.Warn("Failed to get pressing rankings", "error", err,
+				"championship", championship, "season", season)
+		} else {
+			response.Categories = mergeRankingCategories(response.Categories, pressingCategories)
+		}
+	}
+
+	// Goalkeeper rankings use the stored goalkeeper match statistics.
+	if category == "goalkeeper" && h.pool != nil {
+		goalkeeperCategories, err := h.getGoalkeeperRankings(c.Request.Context(), rankingType, championship, season)
+		if err != nil {
+			h.logger.Warn("Failed to get goalkeeper rankings", "error", err,
+				"championship", championship, "season", season)
+		} else {
+			response.Categories = mergeRankingCategories(response.Categories, goalkeeperCategories)
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// threatTotals holds one team's or player's threat totals for ranking.
+type threatTotals struct {
+	entry  RankingEntry
+	threat ThreatStats
+}
+
+// getThreatRankings ranks teams or players by xT, progressive passes, progressive
+// carries and box entries per 90 minutes. It returns no categories when no events
+// have been valued for the competition and season.
+func (h *RankingsHandler) getThreatRankings(ctx context.Context, rankingType, championship, season string) ([]RankingCategory, error) {
+	var totals []threatTotals
+	if rankingType == "team" {
+		rows, err := h.queries.GetTeamThreatRankings(ctx, sqlc.GetTeamThreatRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			totals = append(totals, threatTotals{
+				entry:  RankingEntry{Name: r.TeamName, Logo: r.TeamLogo},
+				threat: newThreatStats(r.Matches, 0, r.ExpectedThreat, r.ProgressivePasses, r.ProgressiveCarries, r.BoxEntries),
+			})
+		}
+	} else {
+		rows, err := h.queries.GetPlayerThreatRankings(ctx, sqlc.GetPlayerThreatRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			threat := newThreatStats(r.Matches, int64(r.MinutesPlayed), r.ExpectedThreat, r.ProgressivePasses, r.ProgressiveCarries, r.BoxEntries)
+			if threat.Minutes < minRankingMinutes {
+				continue
+			}
+			totals = append(totals, threatTotals{
+				entry:  RankingEntry{Name: r.FullName, Team: r.TeamName, Logo: r.TeamLogo, Initials: stringPtr(initials(r.FullName))},
+				threat: threat,
+			})
+		}
+	}
+
+	if len(totals) == 0 {
+		return nil, nil
+	}
+
+	return []RankingCategory{
+		rankBy(rankingExpectedThreat, totals, func(t ThreatStats) float64 { return t.ExpectedThreatPer90 }),
+		rankBy(rankingProgressivePasses, totals, func(t ThreatStats) float64 { return t.ProgressivePassesPer90 }),
+		rankBy(rankingProgressiveCarries, totals, func(t ThreatStats) float64 { return t.ProgressiveCarriesPer90 }),
+		rankBy(rankingBoxPenetrations, totals, func(t ThreatStats) float64 { return t.BoxEntriesPer90 }),
+	}, nil
+}
+
+// getCreationRankings ranks teams or players by xA and shot-creating and
+// goal-creating actions per 90 minutes. It returns no categories when no events
+// of the competition and season have been inferred.
+func (h *RankingsHandler) getCreationRankings(ctx context.Context, rankingType, championship, season string) ([]RankingCategory, error) {
+	var xa, shotCreating, goalCreating []RankingEntry
+	add := func(entry RankingEntry, s CreationStats) {
+		xa = append(xa, withValue(entry, s.ExpectedAssistsPer90))
+		shotCreating = append(shotCreating, withValue(entry, s.ShotCreatingActionsPer90))
+		goalCreating = append(goalCreating, withValue(entry, s.GoalCreatingActionsPer90))
+	}
+	inferred := false
+	if rankingType == "team" {
+		rows, err := h.queries.GetTeamCreationRankings(ctx, sqlc.GetTeamCreationRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			inferred = inferred || r.ShotCreatingActions > 0
+			add(RankingEntry{Name: r.TeamName, Logo: r.TeamLogo},
+				newCreationStats(r.Matches, 0, r.ExpectedAssists, r.KeyPasses, r.ShotCreatingActions, r.GoalCreatingActions))
+		}
+	} else {
+		rows, err := h.queries.GetPlayerCreationRankings(ctx, sqlc.GetPlayerCreationRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			creation := newCreationStats(r.Matches, int64(r.MinutesPlayed), r.ExpectedAssists, r.KeyPasses,
+				r.ShotCreatingActions, r.GoalCreatingActions)
+			if creation.Minutes < minRankingMinutes {
+				continue
+			}
+			inferred = inferred || r.ShotCreatingActions > 0
+			add(RankingEntry{Name: r.FullName, Team: r.TeamName, Logo: r.TeamLogo, Initials: stringPtr(initials(r.FullName))}, creation)
+		}
+	}
+
+	if !inferred {
+		return nil, nil
+	}
+
+	return []RankingCategory{
+		rankEntries(rankingExpectedAssists, "/90'", xa, false),
+		rankEntries(rankingShotCreatingActions, "/90'", shotCreating, false),
+		rankEntries(rankingGoalCreatingActions, "/90'", goalCreating, false),
+	}, nil
+}
+
+// rankBy builds a per-90 ranking category from the top entries by value.
+func rankBy(title string, totals []threatTotals, value func(ThreatStats) float64) RankingCategory {
+	entries := make([]RankingEntry, 0, len(totals))
+	for _, t := range totals {
+		entry := t.entry
+		entry.Value = value(t.threat)
+		entries = append(entries, entry)
+	}
+	return rankEntries(title, "/90'", entries, false)
+}
+
+// rankEntries builds a ranking category from the top entries by value, highest
+// first unless ascending is set.
+func rankEntries(title, unit string, entries []RankingEntry, ascending bool) RankingCategory {
+	sorted := make([]RankingEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ascending {
+			return sorted[i].Value < sorted[j].Value
+		}
+		return sorted[i].Value > sorted[j].Value
+	})
+
+	category := RankingCategory{Title: title, Unit: unit, Rankings: []RankingEntry{}}
+	for i := 0; i < len(sorted) && i < rankingSize; i++ {
+		entry := sorted[i]
+		entry.Rank = i + 1
+		category.Rankings = append(category.Rankings, entry)
+	}
+	return category
+}
+
+// getPressingRankings ranks teams by PPDA (lowest first), high turnovers, final
+// third recoveries and counter-press regains per match from the stored match
+// statistics of the competition and season. Teams without defensive actions in
+// the opponent's half have no PPDA and are left out of that category. It returns
+// no categories when no pressing statistics have been stored.
+func (h *RankingsHandler) getPressingRankings(ctx context.Context, championship, season string) ([]RankingCategory, error) {
+	rows, err := h.queries.GetTeamPressingRankings(ctx, sqlc.GetTeamPressingRankingsParams{
+		Season:      season,
+		Competition: championship,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var ppda, highTurnovers, recoveries, regains []RankingEntry
+	for i := range rows {
+		r := &rows[i]
+		s := pressing.TeamStats{TeamID: r.TeamID}
+		s.Add(pressing.TeamStats{
+			Matches:              int(r.Matches),
+			OpponentPasses:       int(r.OpponentPasses),
+			DefensiveActions:     int(r.DefensiveActions),
+			HighTurnovers:        int(r.HighTurnovers),
+			FinalThirdRecoveries: int(r.FinalThirdRecoveries),
+			BallLosses:           int(r.BallLosses),
+			CounterPressRegains:  int(r.CounterPressRegains),
+		})
+		entry := RankingEntry{Name: r.TeamName, Logo: r.TeamLogo}
+		minutes := int64(s.Matches) * minutesPerMatch
+		if s.DefensiveActions > 0 {
+			ppda = append(ppda, withValue(entry, s.PPDA))
+		}
+		highTurnovers = append(highTurnovers, withValue(entry, per90(float64(s.HighTurnovers), minutes)))
+		recoveries = append(recoveries, withValue(entry, per90(float64(s.FinalThirdRecoveries), minutes)))
+		regains = append(regains, withValue(entry, per90(float64(s.CounterPressRegains), minutes)))
+	}
+
+	return []RankingCategory{
+		rankEntries(rankingPPDA, "", ppda, true),
+		rankEntries(rankingHighTurnovers, "/90'", highTurnovers, false),
+		rankEntries(rankingFinalThirdRecoveries, "/90'", recoveries, false),
+		rankEntries(rankingCounterPressRegains, "/90'", regains, false),
+	}, nil
+}
+
+// goalkeeperTotals are a goalkeeper's or a team's goalkeepers' stored match
+// statistics added up, for the goalkeeper rankings.
+type goalkeeperTotals struct {
+	entry          RankingEntry
+	minutes        int64
+	shotsOnTarget  int32
+	saves          int32
+	goalsPrevented float64
+	crossesFaced   int32
+	crossesClaimed int32
+}
+
+// getGoalkeeperRankings ranks goalkeepers, or teams by their goalkeepers, by saves
+// per 90 minutes, save percentage, goals prevented and cross claim rate from the
+// stored match statistics of the competition and season. Minutes are the
+// goalkeepers' from the match lineups; without lineups every match counts as 90
+// minutes. Save percentage and claim rate leave out those that faced no shots on
+// target or crosses. It returns no categories when no goalkeeper statistics have
+// been stored.
+func (h *RankingsHandler) getGoalkeeperRankings(ctx context.Context, rankingType, championship, season string) ([]RankingCategory, error) {
+	var totals []goalkeeperTotals
+	if rankingType == "team" {
+		rows, err := h.queries.GetTeamGoalkeeperRankings(ctx, sqlc.GetTeamGoalkeeperRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			minutes := int64(r.MinutesPlayed)
+			if minutes <= 0 {
+				minutes = r.Matches * minutesPerMatch
+			}
+			totals = append(totals, goalkeeperTotals{
+				entry:          RankingEntry{Name: r.TeamName, Logo: r.TeamLogo},
+				minutes:        minutes,
+				shotsOnTarget:  r.ShotsOnTarget,
+				saves:          r.Saves,
+				goalsPrevented: r.GoalsPrevented,
+				crossesFaced:   r.CrossesFaced,
+				crossesClaimed: r.CrossesClaimed,
+			})
+		}
+	} else {
+		rows, err := h.queries.GetPlayerGoalkeeperRankings(ctx, sqlc.GetPlayerGoalkeeperRankingsParams{
+			Season:      season,
+			Competition: championship,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			r := &rows[i]
+			minutes := int64(r.MinutesPlayed)
+			if minutes <= 0 {
+				minutes = r.Matches * minutesPerMatch
+			}
+			if minutes < minRankingMinutes {
+				continue
+			}
+			totals = append(totals, goalkeeperTotals{
+				entry:          RankingEntry{Name: r.FullName, Team: r.TeamName, Logo: r.TeamLogo, Initials: stringPtr(initials(r.FullName))},
+				minutes:        minutes,
+				shotsOnTarget:  r.ShotsOnTarget,
+				saves:          r.Saves,
+				goalsPrevented: r.GoalsPrevented,
+				crossesFaced:   r.CrossesFaced,
+				crossesClaimed: r.CrossesClaimed,
+			})
+		}
+	}
+
+	if len(totals) == 0 {
+		return nil, nil
+	}
+
+	var saves, savePercentage, goalsPrevented, claimRate []RankingEntry
+	for _, t := range totals {
+		saves = append(saves, withValue(t.entry, per90(float64(t.saves), t.minutes)))
+		if t.shotsOnTarget > 0 {
+			savePercentage = append(savePercentage, withValue(t.entry, round(float64(t.saves)*100/float64(t.shotsOnTarget), 2)))
+		}
+		goalsPrevented = append(goalsPrevented, withValue(t.entry, round(t.goalsPrevented, 2)))
+		if t.crossesFaced > 0 {
+			claimRate = append(claimRate, withValue(t.entry, round(float64(t.crossesClaimed)*100/float64(t.crossesFaced), 2)))
+		}
+	}
+
+	return []RankingCategory{
+		rankEntries(rankingSaves, "/90'", saves, false),
+		rankEntries(rankingSavePercentage, "%", savePercentage, false),
+		rankEntries(rankingGoalsPrevented, "", goalsPrevented, false),
+		rankEntries(rankingClaimRate, "%", claimRate, false),
+	}, nil
+}
+
+// withValue returns a copy of a ranking entry with its value set.
+func withValue(entry RankingEntry, value float64) RankingEntry {
+	entry.Value = value
+	return entry
+}
+
+// mergeRankingCategories replaces categories that have the same title and appends the rest.
+func mergeRankingCategories(categories, computed []RankingCategory) []RankingCategory {
+	for _, c := range computed {
+		replaced := false
+		for i := range categories {
+			if categories[i].Title == c.Title {
+				categories[i] = c
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+// initials returns the first letters of a name's first and last words.
+func initials(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	first := []rune(words[0])[:1]
+	if len(words) == 1 {
+		return strings.ToUpper(string(first))
+	}
+	last := []rune(words[len(words)-1])[:1]
+	return strings.ToUpper(string(first) + string(last))
+}
+
 // getTeamRankings returns mock team rankings data.
 // Note: Magic numbers and string literals are intentional for mock data.
 func (h *RankingsHandler) getTeamRankings(category string) []RankingCategory {
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/analytics/rating"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const defaultPredictedMatches = 10
+
+// TeamRatingHistoryRequest represents the query parameters for the rating history endpoint.
+type TeamRatingHistoryRequest struct {
+	Competition string `form:"competition"`
+}
+
+// TeamRatingHistoryResponse represents a team's Elo rating history.
+type TeamRatingHistoryResponse struct {
+	TeamID      int32               `json:"team_id"`
+	Competition string              `json:"competition,omitempty"`
+	Ratings     []CompetitionRating `json:"ratings"` // Current rating in each competition
+	History     []RatingChange      `json:"history"` // Oldest first
+}
+
+// CompetitionRating represents a team's current rating in a competition.
+type CompetitionRating struct {
+	Competition string  `json:"competition"`
+	Rating      float64 `json:"rating"`
+	Matches     int     `json:"matches"`
+}
+
+// RatingChange represents a team's rating change from a match.
+type RatingChange struct {
+	MatchID       int32     `json:"match_id"`
+	OpponentID    int32     `json:"opponent_id"`
+	Home          bool      `json:"home"`
+	Competition   string    `json:"competition"`
+	Season        string    `json:"season"`
+	MatchDate     time.Time `json:"match_date"`
+	RatingBefore  float64   `json:"rating_before"`
+	RatingAfter   float64   `json:"rating_after"`
+	Change        float64   `json:"change"`
+	ExpectedScore float64   `json:"expected_score"`
+}
+
+// MatchPredictionsRequest represents the query parameters for the predictions endpoint.
+type MatchPredictionsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// MatchPredictionsResponse represents the predicted outcomes of upcoming matches.
+type MatchPredictionsResponse struct {
+	Predictions []MatchPrediction `json:"predictions"`
+}
+
+// MatchPrediction represents an upcoming match's predicted outcome.
+type MatchPrediction struct {
+	Match      models.Match       `json:"match"`
+	Prediction rating.Prediction  `json:"prediction"`
+	Home       PredictionTeamForm `json:"home"`
+	Away       PredictionTeamForm `json:"away"`
+}
+
+// PredictionTeamForm represents what a team's prediction is based on.
+type PredictionTeamForm struct {
+	TeamID        int32   `json:"team_id"`
+	Rating        float64 `json:"rating"`         // Elo rating in the competition
+	ExpectedScore float64 `json:"expected_score"` // Elo expected score, from 0 to 1
+	Matches       int     `json:"matches"`        // Recent results its goals model strengths come from
+}
+
+// ratingResults converts listed results to the rating engine's.
+func ratingResults(rows []sqlc.ListMatchResultsRow) []rating.Result {
+	results := make([]rating.Result, 0, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		results = append(results, rating.Result{
+			MatchID:    r.ID,
+			Date:       r.MatchDate.Time,
+			HomeTeamID: r.HomeTeamID,
+			AwayTeamID: r.AwayTeamID,
+			HomeGoals:  r.HomeTeamScore,
+			AwayGoals:  r.AwayTeamScore,
+			HomeXG:     r.HomeXg,
+			AwayXG:     r.AwayXg,
+		})
+	}
+	return results
+}
+
+// updateRatings rebuilds the Elo rating history of a finished match's
+// competition, including the match. It returns the number of matches rated.
+func (h *BaseHandler) updateRatings(ctx context.Context, matchID int32) (int, error) {
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match: %w", err)
+	}
+	rows, err := h.queries.ListMatchResults(ctx, sqlc.ListMatchResultsParams{
+		MatchID:     &matchID,
+		Competition: &match.Competition,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list results: %w", err)
+	}
+	_, changes := rating.Run(rating.DefaultEloConfig, ratingResults(rows))
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	if err := queries.DeleteTeamRatings(ctx, match.Competition); err != nil {
+		return 0, fmt.Errorf("failed to clear ratings: %w", err)
+	}
+	if err := queries.CreateTeamRatings(ctx, ratingChanges(changes)); err != nil {
+		return 0, fmt.Errorf("failed to store ratings: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit ratings: %w", err)
+	}
+	return len(rows), nil
+}
+
+// ratingChanges converts rating changes to their insert parameters.
+func ratingChanges(changes []rating.Change) sqlc.CreateTeamRatingsParams {
+	var arg sqlc.CreateTeamRatingsParams
+	for _, c := range changes {
+		arg.TeamIds = append(arg.TeamIds, c.TeamID)
+		arg.OpponentIds = append(arg.OpponentIds, c.OpponentID)
+		arg.MatchIds = append(arg.MatchIds, c.MatchID)
+		arg.Homes = append(arg.Homes, c.Home)
+		arg.RatingsBefore = append(arg.RatingsBefore, c.Before)
+		arg.RatingsAfter = append(arg.RatingsAfter, c.After)
+		arg.ExpectedScores = append(arg.ExpectedScores, c.Expected)
+	}
+	return arg
+}
+
+// GetTeamRatingHistory handles GET /api/v1/teams/:id/rating-history.
+// @Summary Get team rating history
+// @Description Get a team's Elo rating before and after every finished match, per competition, with its current rating in each. Ratings include home advantage and scale with the goal difference.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param competition query string false "Competition"
+// @Success 200 {object} TeamRatingHistoryResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/rating-history [get]
+func (h *TeamHandler) GetTeamRatingHistory(c *gin.Context) {
+	var req TeamRatingHistoryRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	teamID, ok := h.loadTeam(c, "id")
+	if !ok {
+		return
+	}
+
+	var competition *string
+	if req.Competition != "" {
+		competition = &req.Competition
+	}
+	ctx := c.Request.Context()
+	history, err := h.queries.GetTeamRatingHistory(ctx, sqlc.GetTeamRatingHistoryParams{
+		TeamID:      teamID,
+		Competition: competition,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get rating history", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rating history"})
+		return
+	}
+
+	response := TeamRatingHistoryResponse{
+		TeamID:      teamID,
+		Competition: req.Competition,
+		Ratings:     []CompetitionRating{},
+		History:     make([]RatingChange, 0, len(history)),
+	}
+	// History is oldest first, so a competition's last change is its current rating
+	current := map[string]int{}
+	for _, r := range history {
+		response.History = append(response.History, RatingChange{
+			MatchID:       r.MatchID,
+			OpponentID:    r.OpponentID,
+			Home:          r.Home,
+			Competition:   r.Competition,
+			Season:        r.Season,
+			MatchDate:     r.MatchDate.Time,
+			RatingBefore:  r.RatingBefore,
+			RatingAfter:   r.RatingAfter,
+			Change:        round(r.RatingAfter-r.RatingBefore, 2),
+			ExpectedScore: r.ExpectedScore,
+		})
+		i, seen := current[r.Competition]
+		if !seen {
+			i = len(response.Ratings)
+			current[r.Competition] = i
+			response.Ratings = append(response.Ratings, CompetitionRating{Competition: r.Competition})
+		}
+		response.Ratings[i].Rating = r.RatingAfter
+		response.Ratings[i].Matches++
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMatchPredictions handles GET /api/v1/matches/predictions.
+// @Summary Get match predictions
+// @Description Predict the outcome of upcoming scheduled matches: win, draw and loss probabilities and expected goals from a Poisson goals model fitted on each team's recent goals and xG in the competition, with both teams' Elo ratings
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of upcoming matches" default(10)
+// @Success 200 {object} MatchPredictionsResponse
+// @Failure 400 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/predictions [get]
+func (h *MatchHandler) GetMatchPredictions(c *gin.Context) {
+	var req MatchPredictionsRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPredictedMatches
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("predictions:%d", req.Limit)
+	var response MatchPredictionsResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	matches, err := h.queries.GetUpcomingMatches(ctx, int32(req.Limit))
+	if err != nil {
+		h.logger.Error("Failed to get upcoming matches", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to predict matches"})
+		return
+	}
+
+	// Both models are fitted once per competition on its results so far
+	type fit struct {
+		elo     *rating.Elo
+		poisson *rating.Poisson
+	}
+	fitted := map[string]fit{}
+	response.Predictions = make([]MatchPrediction, 0, len(matches))
+	for i := range matches {
+		match := &matches[i]
+		m, ok := fitted[match.Competition]
+		if !ok {
+			rows, listErr := h.queries.ListMatchResults(ctx, sqlc.ListMatchResultsParams{Competition: &match.Competition})
+			if listErr != nil {
+				h.logger.Error("Failed to list results", "error", listErr, "competition", match.Competition)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to predict matches"})
+				return
+			}
+			results := ratingResults(rows)
+			m.elo, _ = rating.Run(rating.DefaultEloConfig, results)
+			m.poisson = rating.FitPoisson(rating.DefaultPoissonConfig, results)
+			fitted[match.Competition] = m
+		}
+
+		expected := m.elo.Expected(match.HomeTeamID, match.AwayTeamID)
+		response.Predictions = append(response.Predictions, MatchPrediction{
+			Match:      mappers.ToDomainMatch(match),
+			Prediction: m.poisson.Predict(match.HomeTeamID, match.AwayTeamID),
+			Home: PredictionTeamForm{
+				TeamID:        match.HomeTeamID,
+				Rating:        round(m.elo.Rating(match.HomeTeamID), 2),
+				ExpectedScore: round(expected, 2),
+				Matches:       m.poisson.Matches(match.HomeTeamID),
+			},
+			Away: PredictionTeamForm{
+				TeamID:        match.AwayTeamID,
+				Rating:        round(m.elo.Rating(match.AwayTeamID), 2),
+				ExpectedScore: round(1-expected, 2),
+				Matches:       m.poisson.Matches(match.AwayTeamID),
+			},
+		})
+	}
+
+	h.setCached(ctx, key, response)
+	c.JSON(http.StatusOK, response)
+}
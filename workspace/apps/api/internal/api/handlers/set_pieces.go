@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/setpieces"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// SetPiecesRequest represents the query parameters for the team set-piece endpoint.
+type SetPiecesRequest struct {
+	StatisticsRequest
+	MatchID int32 `form:"match_id"`
+}
+
+// SetPiecesResponse represents a team's set-piece report: the routines it took
+// and the ones it defended, with what they produced. Sequences lists the match's
+// routines when match_id is set.
+type SetPiecesResponse struct {
+	TeamID      int32                `json:"team_id"`
+	Season      string               `json:"season,omitempty"`
+	Competition string               `json:"competition,omitempty"`
+	MatchID     int32                `json:"match_id,omitempty"`
+	SetPieces   setpieces.TeamReport `json:"set_pieces"`
+	Sequences   []setpieces.Sequence `json:"sequences,omitempty"`
+}
+
+// setPieceMatch is one match's events with the teams that have events in it.
+type setPieceMatch struct {
+	teamIDs []int32 // In order of their first event
+	events  []matchevent.Event
+}
+
+// setPieceMatches splits events listed by match, each match in match clock order.
+func setPieceMatches(sqlcEvents []sqlc.MatchEvent) []setPieceMatch {
+	var matches []setPieceMatch
+	for start := 0; start < len(sqlcEvents); {
+		end := start
+		for end < len(sqlcEvents) && sqlcEvents[end].MatchID == sqlcEvents[start].MatchID {
+			end++
+		}
+
+		var m setPieceMatch
+		seen := map[int32]bool{}
+		for i := start; i < end; i++ {
+			event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+			m.events = append(m.events, matchevent.FromModel(&event))
+			if event.TeamID != nil && !seen[*event.TeamID] {
+				seen[*event.TeamID] = true
+				m.teamIDs = append(m.teamIDs, *event.TeamID)
+			}
+		}
+		matches = append(matches, m)
+		start = end
+	}
+	return matches
+}
+
+// preferredFeet returns the preferred foot of the players of the teams that have
+// one recorded.
+func (h *BaseHandler) preferredFeet(ctx context.Context, teamIDs ...int32) (map[int32]string, error) {
+	feet := map[int32]string{}
+	for _, teamID := range teamIDs {
+		players, err := h.queries.GetPlayersByTeam(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range players {
+			if p.PreferredFoot != nil {
+				feet[p.ID] = *p.PreferredFoot
+			}
+		}
+	}
+	return feet, nil
+}
+
+// GetTeamSetPieces handles GET /api/v1/teams/:id/set-pieces.
+// @Summary Get team set-piece report
+// @Description Attacking and defending set pieces (corners, free kicks, throw-ins and penalties) with the shots, xG and goals each routine produced, and corner and free kick deliveries by swing (inswinging, outswinging) and target (near post, central, far post, outside the box), for one match or over a team's finished matches
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param match_id query int false "Match ID"
+// @Success 200 {object} SetPiecesResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/set-pieces [get]
+func (h *TeamHandler) GetTeamSetPieces(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return
+	}
+	teamID := int32(id)
+
+	var req SetPiecesRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err = h.queries.GetTeamByID(ctx, teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		h.logger.Error("Failed to get team", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve set pieces"})
+		return
+	}
+
+	// A live match's report changes with every event, so only finished matches
+	// and season reports (which only include finished matches) are cached.
+	cacheable := true
+	if req.MatchID != 0 {
+		match, matchErr := h.queries.GetMatchByID(ctx, req.MatchID)
+		if matchErr != nil && !errors.Is(matchErr, pgx.ErrNoRows) {
+			h.logger.Error("Failed to get match", "error", matchErr, "match_id", req.MatchID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve set pieces"})
+			return
+		}
+		if matchErr != nil || (match.HomeTeamID != teamID && match.AwayTeamID != teamID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found for team"})
+			return
+		}
+		cacheable = match.Status == "finished"
+		req.Season, req.Competition = "", ""
+	}
+
+	key := fmt.Sprintf("set-pieces:%d:%s:%s:%d", teamID, req.Season, req.Competition, req.MatchID)
+	var response SetPiecesResponse
+	if cacheable && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	var sqlcEvents []sqlc.MatchEvent
+	if req.MatchID != 0 {
+		sqlcEvents, err = h.queries.GetMatchEvents(ctx, req.MatchID)
+	} else {
+		season, competition := req.params()
+		sqlcEvents, err = h.queries.ListSeasonMatchEvents(ctx, sqlc.ListSeasonMatchEventsParams{
+			TeamID:      &teamID,
+			Season:      season,
+			Competition: competition,
+		})
+	}
+	if err != nil {
+		h.logger.Error("Failed to get match events", "error", err, "team_id", teamID, "match_id", req.MatchID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve set pieces"})
+		return
+	}
+	matches := setPieceMatches(sqlcEvents)
+
+	// Takers' preferred feet give the swing of deliveries without a body part
+	var teamIDs []int32
+	seen := map[int32]bool{}
+	for _, m := range matches {
+		for _, id := range m.teamIDs {
+			if !seen[id] {
+				seen[id] = true
+				teamIDs = append(teamIDs, id)
+			}
+		}
+	}
+	feet, err := h.preferredFeet(ctx, teamIDs...)
+	if err != nil {
+		h.logger.Error("Failed to get team players", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve set pieces"})
+		return
+	}
+
+	response = SetPiecesResponse{
+		TeamID:      teamID,
+		Season:      req.Season,
+		Competition: req.Competition,
+		MatchID:     req.MatchID,
+		SetPieces:   setpieces.TeamReport{TeamID: teamID},
+	}
+	for _, m := range matches {
+		sequences := setpieces.Sequences(m.events, feet)
+		response.SetPieces.Add(setpieces.Compute(sequences, teamID)[0])
+		if req.MatchID != 0 {
+			response.Sequences = sequences
+		}
+	}
+	if cacheable {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
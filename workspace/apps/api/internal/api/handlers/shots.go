@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/emiliospot/footie/api/internal/analytics/shots"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// ShotmapResponse represents every shot in a match.
+type ShotmapResponse struct {
+	MatchID    int32        `json:"match_id"`
+	HomeTeamID int32        `json:"home_team_id"`
+	AwayTeamID int32        `json:"away_team_id"`
+	Shots      []shots.Shot `json:"shots"`
+}
+
+// XGTimelineResponse represents each team's cumulative xG over a match.
+type XGTimelineResponse struct {
+	MatchID    int32                `json:"match_id"`
+	HomeTeamID int32                `json:"home_team_id"`
+	AwayTeamID int32                `json:"away_team_id"`
+	Teams      []shots.TeamTimeline `json:"teams"`
+}
+
+// matchShots loads a match's shots and own goals in match clock order.
+func (h *BaseHandler) matchShots(ctx context.Context, matchID int32) ([]shots.Shot, error) {
+	rows, err := h.queries.GetShotsByMatch(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]shots.Shot, 0, len(rows))
+	for i := range rows {
+		if shot, ok := shotFromRow(&rows[i]); ok {
+			out = append(out, shot)
+		}
+	}
+	return out, nil
+}
+
+// shotFromRow converts a stored shot event to a shot map entry.
+func shotFromRow(row *sqlc.GetShotsByMatchRow) (shots.Shot, bool) {
+	event := mappers.ToDomainMatchEvent(&sqlc.MatchEvent{
+		ID:           row.ID,
+		MatchID:      row.MatchID,
+		TeamID:       row.TeamID,
+		PlayerID:     row.PlayerID,
+		EventType:    row.EventType,
+		Minute:       row.Minute,
+		ExtraMinute:  row.ExtraMinute,
+		PositionX:    row.PositionX,
+		PositionY:    row.PositionY,
+		Metadata:     row.Metadata,
+		Second:       row.Second,
+		Period:       row.Period,
+		PeriodNumber: row.PeriodNumber,
+		ClockMs:      row.ClockMs,
+	})
+
+	var meta map[string]interface{}
+	if len(row.Metadata) > 0 {
+		_ = json.Unmarshal(row.Metadata, &meta)
+	}
+	shot, ok := shots.FromEvent(event.EventType, event.PositionX, event.PositionY, meta, "")
+	if !ok {
+		return shots.Shot{}, false
+	}
+	shot.EventID = event.ID
+	shot.TeamID = event.TeamID
+	shot.PlayerID = event.PlayerID
+	shot.Clock = event.Clock()
+	if row.PlayerName != nil {
+		shot.PlayerName = *row.PlayerName
+	}
+	return shot, true
+}
+
+// publishXGTimeline rebuilds a match's xG timeline after a shot or own goal and
+// publishes it to WebSocket clients. Failures are logged; the event itself has
+// already been published.
+func (h *BaseHandler) publishXGTimeline(ctx context.Context, matchID int32, eventType string) {
+	if h.publisher == nil || !shots.IsShotMapEvent(eventType) {
+		return
+	}
+
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		h.logger.Warn("Failed to load match for xG timeline", "error", err, "match_id", matchID)
+		return
+	}
+	matchShots, err := h.matchShots(ctx, matchID)
+	if err != nil {
+		h.logger.Warn("Failed to load shots for xG timeline", "error", err, "match_id", matchID)
+		return
+	}
+
+	timeline := XGTimelineResponse{
+		MatchID:    matchID,
+		HomeTeamID: match.HomeTeamID,
+		AwayTeamID: match.AwayTeamID,
+		Teams:      shots.Timeline(matchShots, match.HomeTeamID, match.AwayTeamID),
+	}
+	if err := h.publisher.PublishXGTimeline(ctx, matchID, timeline); err != nil {
+		h.logger.Warn("Failed to publish xG timeline", "error", err, "match_id", matchID)
+	}
+}
+
+// GetMatchShotmap handles GET /api/v1/matches/:id/shotmap.
+// @Summary Get match shot map
+// @Description Get every shot in a match with canonical coordinates, xG, outcome, body part, situation and player, in match clock order
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Success 200 {object} ShotmapResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/shotmap [get]
+func (h *MatchHandler) GetMatchShotmap(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	// Only finished matches are cached; live shot maps change with every shot.
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("shotmap:%d", match.ID)
+	finished := match.Status == "finished"
+	var response ShotmapResponse
+	if finished && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	matchShots, err := h.matchShots(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get match shots", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match shot map"})
+		return
+	}
+
+	response = ShotmapResponse{
+		MatchID:    match.ID,
+		HomeTeamID: match.HomeTeamID,
+		AwayTeamID: match.AwayTeamID,
+		Shots:      make([]shots.Shot, 0, len(matchShots)),
+	}
+	for _, shot := range matchShots {
+		if !shot.OwnGoal {
+			response.Shots = append(response.Shots, shot)
+		}
+	}
+	if finished {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMatchXGTimeline handles GET /api/v1/matches/:id/xg-timeline.
+// @Summary Get match xG timeline
+// @Description Get each team's cumulative xG over the match clock with goals marked. Live matches push updates as xg_timeline WebSocket messages
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Success 200 {object} XGTimelineResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/xg-timeline [get]
+func (h *MatchHandler) GetMatchXGTimeline(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("xg-timeline:%d", match.ID)
+	finished := match.Status == "finished"
+	var response XGTimelineResponse
+	if finished && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	matchShots, err := h.matchShots(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get match shots", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match xG timeline"})
+		return
+	}
+
+	response = XGTimelineResponse{
+		MatchID:    match.ID,
+		HomeTeamID: match.HomeTeamID,
+		AwayTeamID: match.AwayTeamID,
+		Teams:      shots.Timeline(matchShots, match.HomeTeamID, match.AwayTeamID),
+	}
+	if finished {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/comparison"
+	"github.com/emiliospot/footie/api/internal/analytics/similarity"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const (
+	defaultSimilarPlayers = 10
+	// defaultSimilarityMinutes keeps seasons with under ten full matches out of
+	// the search; small samples make per-90 profiles noisy
+	defaultSimilarityMinutes = 900
+)
+
+// SimilarPlayersRequest represents the query parameters for the similar players endpoint.
+type SimilarPlayersRequest struct {
+	Season      string `form:"season"`      // The player's season to match; defaults to their latest
+	Competition string `form:"competition"` // The player's competition to match; defaults to their most minutes
+	Position    string `form:"position"`    // forward, midfielder, defender, goalkeeper
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	MinMinutes  *int32 `form:"min_minutes" binding:"omitempty,min=0"`
+	MinAge      *int   `form:"min_age" binding:"omitempty,min=0"`
+	MaxAge      *int   `form:"max_age" binding:"omitempty,min=0"`
+}
+
+// SimilarPlayersResponse represents the players most similar to a player's season.
+type SimilarPlayersResponse struct {
+	PlayerID    int32                `json:"player_id"`
+	Name        string               `json:"name"`
+	Season      string               `json:"season"`
+	Competition string               `json:"competition"`
+	Group       comparison.Group     `json:"group"`
+	Features    []similarity.Feature `json:"features"`
+	Players     []SimilarPlayer      `json:"players"`
+}
+
+// SimilarPlayer represents a similar player's season and why it is similar.
+type SimilarPlayer struct {
+	similarity.Match
+	PlayerID      int32  `json:"player_id"`
+	Name          string `json:"name"`
+	TeamID        int32  `json:"team_id"`
+	Position      string `json:"position"`
+	Age           *int   `json:"age,omitempty"`
+	Season        string `json:"season"`
+	Competition   string `json:"competition"`
+	MinutesPlayed int32  `json:"minutes_played"`
+}
+
+// targetProfile returns a player's season to match: in the requested season and
+// competition if given, otherwise the latest season, with the most minutes.
+func targetProfile(rows []sqlc.ListPlayerProfilesRow, playerID int32, season, competition string) (*sqlc.ListPlayerProfilesRow, bool) {
+	var best *sqlc.ListPlayerProfilesRow
+	for i := range rows {
+		r := &rows[i]
+		s := &r.PlayerStatistic
+		if s.PlayerID != playerID || (season != "" && s.Season != season) || (competition != "" && s.Competition != competition) {
+			continue
+		}
+		if best == nil || s.Season > best.PlayerStatistic.Season ||
+			(s.Season == best.PlayerStatistic.Season && s.MinutesPlayed > best.PlayerStatistic.MinutesPlayed) {
+			best = r
+		}
+	}
+	return best, best != nil
+}
+
+// optionalInt formats an optional integer for a cache key.
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// GetSimilarPlayers handles GET /api/v1/players/:id/similar.
+// @Summary Get similar players
+// @Description Find the players whose per-90 profiles (season statistics plus xT and ball progression from events) are most similar to a player's season, across competitions and seasons. Features are weighted per position group and standardized over the group, and each result breaks its similarity down by feature.
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param season query string false "The player's season to match (defaults to the latest)"
+// @Param competition query string false "The player's competition to match (defaults to the one with the most minutes)"
+// @Param position query string false "Position group (forward, midfielder, defender, goalkeeper); defaults to the player's position"
+// @Param limit query int false "Number of players" default(10)
+// @Param min_minutes query int false "Minimum minutes played in a season to be a candidate" default(900)
+// @Param min_age query int false "Minimum age"
+// @Param max_age query int false "Maximum age"
+// @Success 200 {object} SimilarPlayersResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/players/{id}/similar [get]
+func (h *PlayerHandler) GetSimilarPlayers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidPlayerID})
+		return
+	}
+	playerID := int32(id)
+
+	var req SimilarPlayersRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	if req.MinAge != nil && req.MaxAge != nil && *req.MinAge > *req.MaxAge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_age must not be greater than max_age"})
+		return
+	}
+	var group comparison.Group
+	if req.Position != "" {
+		var ok bool
+		if group, ok = comparison.ParseGroup(req.Position); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: " + req.Position})
+			return
+		}
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSimilarPlayers
+	}
+	minMinutes := int32(defaultSimilarityMinutes)
+	if req.MinMinutes != nil {
+		minMinutes = *req.MinMinutes
+	}
+
+	ctx := c.Request.Context()
+	player, err := h.queries.GetPlayerByID(ctx, playerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		h.logger.Error("Failed to get player", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find similar players"})
+		return
+	}
+	if group == "" {
+		var ok bool
+		if group, ok = comparison.GroupFor(player.Position); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown position group for player; pass position", "position": player.Position})
+			return
+		}
+	}
+
+	key := fmt.Sprintf("similar:%d:%s:%s:%s:%d:%d:%s:%s", playerID, req.Season, req.Competition, group,
+		req.Limit, minMinutes, optionalInt(req.MinAge), optionalInt(req.MaxAge))
+	var response SimilarPlayersResponse
+	if h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	rows, err := h.queries.ListPlayerProfiles(ctx, sqlc.ListPlayerProfilesParams{
+		MinMinutes: minMinutes,
+		PlayerID:   playerID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get player profiles", "error", err, "player_id", playerID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find similar players"})
+		return
+	}
+	target, ok := targetProfile(rows, playerID, req.Season, req.Competition)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No statistics for player"})
+		return
+	}
+
+	profile := func(r *sqlc.ListPlayerProfilesRow) map[string]float64 {
+		stats := mappers.ToDomainPlayerStatistics(&r.PlayerStatistic)
+		return similarity.Values(&stats, similarity.Threat{
+			ExpectedThreat:     r.ExpectedThreat,
+			ProgressivePasses:  r.ProgressivePasses,
+			ProgressiveCarries: r.ProgressiveCarries,
+			BoxEntries:         r.BoxEntries,
+		})
+	}
+	// Candidates are the other players' seasons in the same position group
+	var candidates []*sqlc.ListPlayerProfilesRow
+	var profiles []map[string]float64
+	for i := range rows {
+		r := &rows[i]
+		if r.Player.ID == playerID || r.PlayerStatistic.MinutesPlayed < minMinutes {
+			continue
+		}
+		if g, _ := comparison.GroupFor(r.Player.Position); g != group {
+			continue
+		}
+		candidates = append(candidates, r)
+		profiles = append(profiles, profile(r))
+	}
+
+	features := similarity.FeaturesFor(group)
+	response = SimilarPlayersResponse{
+		PlayerID:    playerID,
+		Name:        player.FullName,
+		Season:      target.PlayerStatistic.Season,
+		Competition: target.PlayerStatistic.Competition,
+		Group:       group,
+		Features:    features,
+		Players:     []SimilarPlayer{},
+	}
+	// Matches are in order of similarity, so a player's first is their most similar season
+	seen := map[int32]bool{}
+	for _, m := range similarity.Nearest(features, profile(target), profiles) {
+		if len(response.Players) == req.Limit {
+			break
+		}
+		r := candidates[m.Index]
+		if seen[r.Player.ID] {
+			continue
+		}
+		candidate := mappers.ToDomainPlayer(&r.Player)
+		var age *int
+		if candidate.DateOfBirth != nil {
+			a := candidate.Age()
+			age = &a
+		}
+		if (req.MinAge != nil || req.MaxAge != nil) &&
+			(age == nil || (req.MinAge != nil && *age < *req.MinAge) || (req.MaxAge != nil && *age > *req.MaxAge)) {
+			continue
+		}
+		seen[r.Player.ID] = true
+		response.Players = append(response.Players, SimilarPlayer{
+			Match:         m,
+			PlayerID:      r.Player.ID,
+			Name:          r.Player.FullName,
+			TeamID:        r.Player.TeamID,
+			Position:      r.Player.Position,
+			Age:           age,
+			Season:        r.PlayerStatistic.Season,
+			Competition:   r.PlayerStatistic.Competition,
+			MinutesPlayed: r.PlayerStatistic.MinutesPlayed,
+		})
+	}
+
+	h.setCached(ctx, key, response)
+	c.JSON(http.StatusOK, response)
+}
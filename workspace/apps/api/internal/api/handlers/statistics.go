@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"math"
+)
+
+// minutesPerMatch is used for per-90 values when no minutes are recorded.
+const minutesPerMatch = 90
+
+// StatisticsRequest represents the query parameters for statistics endpoints.
+type StatisticsRequest struct {
+	Season      string `form:"season"`
+	Competition string `form:"competition"`
+}
+
+// matches reports whether a stored season row falls within the requested filters.
+func (r StatisticsRequest) matches(season, competition string) bool {
+	return (r.Season == "" || r.Season == season) && (r.Competition == "" || r.Competition == competition)
+}
+
+// params returns the filters as nullable query arguments.
+func (r StatisticsRequest) params() (season, competition *string) {
+	if r.Season != "" {
+		season = &r.Season
+	}
+	if r.Competition != "" {
+		competition = &r.Competition
+	}
+	return season, competition
+}
+
+// ThreatStats holds expected threat (xT) and ball progression totals with per-90 values.
+type ThreatStats struct {
+	Matches                 int64   `json:"matches"`
+	Minutes                 int64   `json:"minutes"`
+	ExpectedThreat          float64 `json:"expected_threat"`
+	ExpectedThreatPer90     float64 `json:"expected_threat_per90"`
+	ProgressivePasses       int64   `json:"progressive_passes"`
+	ProgressivePassesPer90  float64 `json:"progressive_passes_per90"`
+	ProgressiveCarries      int64   `json:"progressive_carries"`
+	ProgressiveCarriesPer90 float64 `json:"progressive_carries_per90"`
+	BoxEntries              int64   `json:"box_entries"`
+	BoxEntriesPer90         float64 `json:"box_entries_per90"`
+}
+
+// newThreatStats builds threat stats from totals. When minutes is zero, every
+// match with events counts as 90 minutes.
+func newThreatStats(matches, minutes int64, xt float64, progressivePasses, progressiveCarries, boxEntries int64) ThreatStats {
+	if minutes <= 0 {
+		minutes = matches * minutesPerMatch
+	}
+	return ThreatStats{
+		Matches:                 matches,
+		Minutes:                 minutes,
+		ExpectedThreat:          round(xt, 3),
+		ExpectedThreatPer90:     per90(xt, minutes),
+		ProgressivePasses:       progressivePasses,
+		ProgressivePassesPer90:  per90(float64(progressivePasses), minutes),
+		ProgressiveCarries:      progressiveCarries,
+		ProgressiveCarriesPer90: per90(float64(progressiveCarries), minutes),
+		BoxEntries:              boxEntries,
+		BoxEntriesPer90:         per90(float64(boxEntries), minutes),
+	}
+}
+
+// CreationStats holds chance creation totals with per-90 values: expected assists
+// (xA, the xG of the shots the player's or team's passes led to), key passes, and
+// shot-creating and goal-creating actions.
+type CreationStats struct {
+	Matches                  int64   `json:"matches"`
+	Minutes                  int64   `json:"minutes"`
+	ExpectedAssists          float64 `json:"expected_assists"`
+	ExpectedAssistsPer90     float64 `json:"expected_assists_per90"`
+	KeyPasses                int64   `json:"key_passes"`
+	KeyPassesPer90           float64 `json:"key_passes_per90"`
+	ShotCreatingActions      int64   `json:"shot_creating_actions"`
+	ShotCreatingActionsPer90 float64 `json:"shot_creating_actions_per90"`
+	GoalCreatingActions      int64   `json:"goal_creating_actions"`
+	GoalCreatingActionsPer90 float64 `json:"goal_creating_actions_per90"`
+}
+
+// newCreationStats builds creation stats from totals. When minutes is zero, every
+// match with events counts as 90 minutes.
+func newCreationStats(matches, minutes int64, xa float64, keyPasses, shotCreating, goalCreating int64) CreationStats {
+	if minutes <= 0 {
+		minutes = matches * minutesPerMatch
+	}
+	return CreationStats{
+		Matches:                  matches,
+		Minutes:                  minutes,
+		ExpectedAssists:          round(xa, 3),
+		ExpectedAssistsPer90:     per90(xa, minutes),
+		KeyPasses:                keyPasses,
+		KeyPassesPer90:           per90(float64(keyPasses), minutes),
+		ShotCreatingActions:      shotCreating,
+		ShotCreatingActionsPer90: per90(float64(shotCreating), minutes),
+		GoalCreatingActions:      goalCreating,
+		GoalCreatingActionsPer90: per90(float64(goalCreating), minutes),
+	}
+}
+
+// per90 scales a total to a 90-minute rate, rounded to two decimals.
+func per90(total float64, minutes int64) float64 {
+	if minutes <= 0 {
+		return 0
+	}
+	return round(total*minutesPerMatch/float64(minutes), 2)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/possession"
+	"github.com/emiliospot/footie/api/internal/analytics/pressing"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// TeamStyleRequest represents the query parameters for the team style endpoint.
+type TeamStyleRequest struct {
+	StatisticsRequest
+	MatchID int32 `form:"match_id"`
+}
+
+// TeamStyleResponse represents a team's style profile: how it presses and how it
+// keeps the ball.
+type TeamStyleResponse struct {
+	TeamID      int32                `json:"team_id"`
+	Season      string               `json:"season,omitempty"`
+	Competition string               `json:"competition,omitempty"`
+	MatchID     int32                `json:"match_id,omitempty"`
+	Matches     int                  `json:"matches"`
+	Pressing    pressing.TeamStats   `json:"pressing"`
+	Possession  possession.TeamStats `json:"possession"`
+}
+
+// matchSequence is one match's events with the possessions reconstructed from them.
+type matchSequence struct {
+	teamIDs     []int32 // Teams with events, in order of their first event
+	events      []matchevent.Event
+	possessions []possession.Possession
+}
+
+// matchSequences splits events listed by match, each match in match clock order,
+// and reconstructs every match's possessions.
+func matchSequences(sqlcEvents []sqlc.MatchEvent) []matchSequence {
+	var sequences []matchSequence
+	for start := 0; start < len(sqlcEvents); {
+		end := start
+		for end < len(sqlcEvents) && sqlcEvents[end].MatchID == sqlcEvents[start].MatchID {
+			end++
+		}
+
+		var m matchSequence
+		seen := map[int32]bool{}
+		for i := start; i < end; i++ {
+			event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+			m.events = append(m.events, matchevent.FromModel(&event))
+			if event.TeamID != nil && !seen[*event.TeamID] {
+				seen[*event.TeamID] = true
+				m.teamIDs = append(m.teamIDs, *event.TeamID)
+			}
+		}
+		m.possessions = possession.Reconstruct(m.events)
+		sequences = append(sequences, m)
+		start = end
+	}
+	return sequences
+}
+
+// seasonSequences lists the finished matches matching the filters and
+// reconstructs their possessions. teamID limits the matches to one team's.
+func (h *BaseHandler) seasonSequences(ctx context.Context, teamID *int32, season, competition *string) ([]matchSequence, error) {
+	sqlcEvents, err := h.queries.ListSeasonMatchEvents(ctx, sqlc.ListSeasonMatchEventsParams{
+		TeamID:      teamID,
+		Season:      season,
+		Competition: competition,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matchSequences(sqlcEvents), nil
+}
+
+// assignPressingStats computes the pressing statistics of a match's teams and
+// stores them, replacing those of an earlier pass. It returns the number of teams.
+func (h *BaseHandler) assignPressingStats(ctx context.Context, matchID int32) (int, error) {
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match: %w", err)
+	}
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match events: %w", err)
+	}
+
+	arg := sqlc.CreateTeamPressingMatchStatsParams{MatchID: matchID}
+	for _, m := range matchSequences(sqlcEvents) {
+		for _, s := range pressing.Compute(m.events, m.possessions, match.HomeTeamID, match.AwayTeamID) {
+			arg.TeamIds = append(arg.TeamIds, s.TeamID)
+			arg.OpponentPasses = append(arg.OpponentPasses, int32(s.OpponentPasses))
+			arg.DefensiveActions = append(arg.DefensiveActions, int32(s.DefensiveActions))
+			arg.HighTurnovers = append(arg.HighTurnovers, int32(s.HighTurnovers))
+			arg.FinalThirdRecoveries = append(arg.FinalThirdRecoveries, int32(s.FinalThirdRecoveries))
+			arg.BallLosses = append(arg.BallLosses, int32(s.BallLosses))
+			arg.CounterPressRegains = append(arg.CounterPressRegains, int32(s.CounterPressRegains))
+		}
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	if err := queries.DeleteTeamPressingMatchStats(ctx, matchID); err != nil {
+		return 0, fmt.Errorf("failed to delete pressing statistics: %w", err)
+	}
+	if len(arg.TeamIds) > 0 {
+		if err := queries.CreateTeamPressingMatchStats(ctx, arg); err != nil {
+			return 0, fmt.Errorf("failed to store pressing statistics: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit pressing statistics: %w", err)
+	}
+	return len(arg.TeamIds), nil
+}
+
+// GetTeamStyle handles GET /api/v1/teams/:id/style.
+// @Summary Get team style profile
+// @Description Pressing intensity (PPDA, high turnovers, final third recoveries, counter-press regains within 5 seconds of a loss) and possession style (possession share, passes per possession, direct speed) for one match or over a team's finished matches
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param match_id query int false "Match ID"
+// @Success 200 {object} TeamStyleResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/style [get]
+func (h *TeamHandler) GetTeamStyle(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return
+	}
+	teamID := int32(id)
+
+	var req TeamStyleRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err = h.queries.GetTeamByID(ctx, teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		h.logger.Error("Failed to get team", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team style"})
+		return
+	}
+
+	// A live match's profile changes with every event, so only finished matches
+	// and season profiles (which only include finished matches) are cached.
+	cacheable := true
+	var sequences []matchSequence
+	if req.MatchID != 0 {
+		match, matchErr := h.queries.GetMatchByID(ctx, req.MatchID)
+		if matchErr != nil && !errors.Is(matchErr, pgx.ErrNoRows) {
+			h.logger.Error("Failed to get match", "error", matchErr, "match_id", req.MatchID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team style"})
+			return
+		}
+		if matchErr != nil || (match.HomeTeamID != teamID && match.AwayTeamID != teamID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found for team"})
+			return
+		}
+		cacheable = match.Status == "finished"
+		req.Season, req.Competition = "", ""
+	}
+
+	key := fmt.Sprintf("style:%d:%s:%s:%d", teamID, req.Season, req.Competition, req.MatchID)
+	var response TeamStyleResponse
+	if cacheable && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if req.MatchID != 0 {
+		sqlcEvents, eventsErr := h.queries.GetMatchEvents(ctx, req.MatchID)
+		if eventsErr != nil {
+			h.logger.Error("Failed to get match events", "error", eventsErr, "match_id", req.MatchID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team style"})
+			return
+		}
+		sequences = matchSequences(sqlcEvents)
+	} else {
+		season, competition := req.params()
+		sequences, err = h.seasonSequences(ctx, &teamID, season, competition)
+		if err != nil {
+			h.logger.Error("Failed to get season events", "error", err, "team_id", teamID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team style"})
+			return
+		}
+	}
+
+	response = TeamStyleResponse{
+		TeamID:      teamID,
+		Season:      req.Season,
+		Competition: req.Competition,
+		MatchID:     req.MatchID,
+		Pressing:    pressing.TeamStats{TeamID: teamID},
+	}
+	var possessions []possession.Possession
+	for _, m := range sequences {
+		response.Pressing.Add(pressing.Compute(m.events, m.possessions, teamID)[0])
+		possessions = append(possessions, m.possessions...)
+	}
+	response.Matches = response.Pressing.Matches
+	response.Possession = possession.Summarize(possessions, teamID)[0]
+	if cacheable {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
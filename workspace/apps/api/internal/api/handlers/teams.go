@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/gamestate"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/domain/models"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const (
+	errInvalidTeamID = "Invalid team ID"
+)
+
+// TeamHandler handles team endpoints.
+type TeamHandler struct {
+	*BaseHandler
+}
+
+// NewTeamHandler creates a new team handler.
+func NewTeamHandler(base *BaseHandler) *TeamHandler {
+	return &TeamHandler{BaseHandler: base}
+}
+
+// TeamStatisticsResponse represents a team's statistics.
+type TeamStatisticsResponse struct {
+	TeamID      int32                   `json:"team_id"`
+	Season      string                  `json:"season,omitempty"`
+	Competition string                  `json:"competition,omitempty"`
+	Seasons     []models.TeamStatistics `json:"seasons"` // Stored season statistics matching the filters
+	Threat      ThreatStats             `json:"threat"`
+	Creation    CreationStats           `json:"creation"`
+	// GameState, MinDifferential and MaxDifferential echo the game state filter,
+	// which applies to Threat and Creation but not to the stored season statistics
+	GameState       string `json:"game_state,omitempty"`
+	MinDifferential *int   `json:"min_differential,omitempty"`
+	MaxDifferential *int   `json:"max_differential,omitempty"`
+	// GameStates are the minutes spent winning, drawing and losing in finished matches
+	GameStates gamestate.Minutes `json:"game_states"`
+}
+
+// GetTeamStatistics handles GET /api/v1/teams/:id/statistics.
+// @Summary Get team statistics
+// @Description Get season statistics plus expected threat (xT), ball progression and chance creation (xA, key passes, shot-creating and goal-creating actions) totals and per-90 values, optionally split by game state, and the minutes spent in each game state
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param season query string false "Season (e.g. 2025/2026)"
+// @Param competition query string false "Competition"
+// @Param game_state query string false "Game state at the time of each event (winning, drawing, losing)"
+// @Param min_differential query int false "Minimum goal differential at the time of each event"
+// @Param max_differential query int false "Maximum goal differential at the time of each event"
+// @Success 200 {object} TeamStatisticsResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/teams/{id}/statistics [get]
+func (h *TeamHandler) GetTeamStatistics(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidTeamID})
+		return
+	}
+	teamID := int32(id)
+
+	var req StatisticsRequest
+	if bindErr := c.ShouldBindQuery(&req); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	var stateReq GameStateRequest
+	if bindErr := c.ShouldBindQuery(&stateReq); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		return
+	}
+	differentials, err := stateReq.differentials()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err = h.queries.GetTeamByID(ctx, teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		h.logger.Error("Failed to get team", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team statistics"})
+		return
+	}
+
+	stats, err := h.queries.GetTeamStatsByTeam(ctx, teamID)
+	if err != nil {
+		h.logger.Error("Failed to get team statistics", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team statistics"})
+		return
+	}
+
+	response := TeamStatisticsResponse{
+		TeamID:      teamID,
+		Season:      req.Season,
+		Competition: req.Competition,
+		Seasons:     []models.TeamStatistics{},
+	}
+	for i := range stats {
+		if req.matches(stats[i].Season, stats[i].Competition) {
+			response.Seasons = append(response.Seasons, mappers.ToDomainTeamStatistics(&stats[i]))
+		}
+	}
+
+	season, competition := req.params()
+	durations, err := h.gameStateDurations(ctx, teamID, sqlc.ListGameStateEventsParams{
+		TeamID:      &teamID,
+		Season:      season,
+		Competition: competition,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get game states", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team statistics"})
+		return
+	}
+	response.GameStates = durations.ByState()
+
+	// Split by game state, per-90 values are over the minutes spent in it
+	var minutes int64
+	if differentials != nil {
+		response.GameState = stateReq.GameState
+		response.MinDifferential, response.MaxDifferential = differentials.Min, differentials.Max
+		minutes = int64(math.Round(durations.Minutes(*differentials)))
+	}
+	minDiff, maxDiff := differentialParams(differentials)
+	threat, err := h.queries.GetTeamThreat(ctx, sqlc.GetTeamThreatParams{
+		TeamID:          &teamID,
+		Season:          season,
+		Competition:     competition,
+		MinDifferential: minDiff,
+		MaxDifferential: maxDiff,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get team threat", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team statistics"})
+		return
+	}
+	response.Threat = newThreatStats(threat.Matches, minutes, threat.ExpectedThreat,
+		threat.ProgressivePasses, threat.ProgressiveCarries, threat.BoxEntries)
+
+	creation, err := h.queries.GetTeamCreation(ctx, sqlc.GetTeamCreationParams{
+		TeamID:          &teamID,
+		Season:          season,
+		Competition:     competition,
+		MinDifferential: minDiff,
+		MaxDifferential: maxDiff,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get team creation", "error", err, "team_id", teamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team statistics"})
+		return
+	}
+	response.Creation = newCreationStats(creation.Matches, minutes, creation.ExpectedAssists,
+		creation.KeyPasses, creation.ShotCreatingActions, creation.GoalCreatingActions)
+
+	c.JSON(http.StatusOK, response)
+}
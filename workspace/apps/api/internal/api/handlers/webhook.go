@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/emiliospot/footie/api/internal/analytics/xt"
 	"github.com/emiliospot/footie/api/internal/config"
 	"github.com/emiliospot/footie/api/internal/infrastructure/events"
 	"github.com/emiliospot/footie/api/internal/infrastructure/webhooks"
@@ -198,6 +199,10 @@ func (h *WebhookHandler) processWebhookEventAsync(ctx context.Context, payload *
 	// Normalize event type (external: "GOAL" -> internal: "goal")
 	eventType := strings.ToLower(payload.EventType)
 
+	// The payload has no period; the clock determines it from the minute
+	clock := newMatchClock("", payload.Minute, payload.ExtraMinute, nil)
+	period := clock.Period.String()
+
 	// Create event in database
 	event, err := h.queries.CreateMatchEvent(ctx, sqlc.CreateMatchEventParams{
 		MatchID:           matchID,
@@ -205,8 +210,11 @@ func (h *WebhookHandler) processWebhookEventAsync(ctx context.Context, payload *
 		PlayerID:          payload.PlayerID,
 		SecondaryPlayerID: payload.SecondaryPlayerID,
 		EventType:         eventType,
-		Minute:            payload.Minute,
-		ExtraMinute:       payload.ExtraMinute,
+		Minute:            clock.Minute,
+		Period:            &period,
+		ExtraMinute:       extraMinute(clock),
+		PeriodNumber:      clock.PeriodNumber(),
+		ClockMs:           clock.Ms,
 		PositionX:         posX,
 		PositionY:         posY,
 		Description:       desc,
@@ -218,11 +226,6 @@ func (h *WebhookHandler) processWebhookEventAsync(ctx context.Context, payload *
 	}
 
 	// Publish to real-time system (Redis Streams + Pub/Sub)
-	extraMin := 0
-	if payload.ExtraMinute != nil {
-		extraMin = int(*payload.ExtraMinute)
-	}
-
 	var posXFloat, posYFloat *float64
 	if event.PositionX.Valid {
 		val, valErr := event.PositionX.Float64Value()
@@ -245,25 +248,27 @@ func (h *WebhookHandler) processWebhookEventAsync(ctx context.Context, payload *
 	}
 
 	// Publish to Redis Streams and Pub/Sub for real-time delivery
-	publishErr := h.publisher.PublishMatchEvent(ctx, &events.MatchEvent{
+	published := &events.MatchEvent{
 		ID:                event.ID,
 		MatchID:           event.MatchID,
 		TeamID:            event.TeamID,
 		PlayerID:          event.PlayerID,
 		SecondaryPlayerID: event.SecondaryPlayerID,
 		EventType:         event.EventType,
-		Minute:            int(event.Minute),
-		ExtraMinute:       extraMin,
 		PositionX:         posXFloat,
 		PositionY:         posYFloat,
 		Description:       description,
 		Metadata:          metadataJSON,
 		Timestamp:         event.CreatedAt.Time,
-	})
+	}
+	published.SetClock(clock)
+	publishErr := h.publisher.PublishMatchEvent(ctx, published)
 	if publishErr != nil {
 		h.logger.Error("Failed to publish webhook event", "error", publishErr, "event_id", event.ID)
 		return
 	}
+	h.publishXGTimeline(ctx, matchID, eventType)
+	h.publishWinProbability(ctx, &event)
 
 	// If it's a goal, invalidate match cache
 	if eventType == "goal" {
@@ -297,6 +302,7 @@ func (h *WebhookHandler) processProviderEventsAsync(ctx context.Context, events
 		}
 
 		// Process single event
+		event.Competition = match.Competition
 		if err := h.processSingleEvent(ctx, event, match.ID, providerName); err != nil {
 			h.logger.Error("Failed to process batch event", "error", err, "match_id", event.MatchID, "index", i, "provider", providerName)
 			failureCount++
@@ -328,6 +334,15 @@ func (h *WebhookHandler) processProviderEventsAsync(ctx context.Context, events
 
 // processSingleEvent processes a single event (used by both single and batch processing).
 func (h *WebhookHandler) processSingleEvent(ctx context.Context, event *events.MatchEvent, matchID int32, providerName string) error {
+	// Fill in model xG for shots the provider sent without one
+	if filled, ok := h.xg.Fill(event.EventType, event.PositionX, event.PositionY, event.Metadata, providerName); ok {
+		event.Metadata = filled
+	}
+	// Value passes and carries: progression flags, box entries and xT
+	if annotated, ok := xt.Annotate(h.xt, event.EventType, event.PositionX, event.PositionY, event.Metadata, providerName); ok {
+		event.Metadata = annotated
+	}
+
 	// Convert metadata to JSON string if it's a map
 	metadataJSON := event.Metadata
 	if event.Metadata == "" {
@@ -383,10 +398,12 @@ func (h *WebhookHandler) processSingleEvent(ctx context.Context, event *events.M
 			}
 			return nil
 		}(),
-		PositionX:   posX,
-		PositionY:   posY,
-		Description: desc,
-		Metadata:    []byte(metadataJSON),
+		PeriodNumber: event.Clock.PeriodNumber(),
+		ClockMs:      event.Clock.Ms,
+		PositionX:    posX,
+		PositionY:    posY,
+		Description:  desc,
+		Metadata:     []byte(metadataJSON),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create match event: %w", err)
@@ -399,6 +416,8 @@ func (h *WebhookHandler) processSingleEvent(ctx context.Context, event *events.M
 	if publishErr := h.publisher.PublishMatchEvent(ctx, event); publishErr != nil {
 		return fmt.Errorf("failed to publish event: %w", publishErr)
 	}
+	h.publishXGTimeline(ctx, event.MatchID, event.EventType)
+	h.publishWinProbability(ctx, &dbEvent)
 
 	return nil
 }
@@ -473,7 +492,7 @@ func (h *WebhookHandler) HandleMatchStatus(c *gin.Context) {
 	}
 
 	// Validate match exists
-	_, err = h.queries.GetMatchByID(c.Request.Context(), int32(matchID))
+	match, err := h.queries.GetMatchByID(c.Request.Context(), int32(matchID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
 		return
@@ -482,9 +501,10 @@ func (h *WebhookHandler) HandleMatchStatus(c *gin.Context) {
 	// Publish status update asynchronously
 	go func() {
 		statusUpdate := &events.MatchStatusUpdate{
-			MatchID:   int32(matchID),
-			Status:    strings.ToLower(status),
-			Timestamp: time.Now(),
+			MatchID:     int32(matchID),
+			Competition: match.Competition,
+			Status:      strings.ToLower(status),
+			Timestamp:   time.Now(),
 		}
 
 		if publishErr := h.publisher.PublishMatchStatusUpdate(c.Request.Context(), statusUpdate); publishErr != nil {
@@ -492,9 +512,175 @@ func (h *WebhookHandler) HandleMatchStatus(c *gin.Context) {
 		}
 	}()
 
+	// Post-process the match once the event log is complete
+	if strings.EqualFold(status, "finished") {
+		go h.processFinishedMatch(context.WithoutCancel(c.Request.Context()), int32(matchID))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":    "accepted",
 		"match_id":  matchID,
 		"new_status": status,
 	})
 }
+
+// processFinishedMatch assigns a finished match's possessions, game states,
+// minutes played, derived events, goalkeeper and pressing statistics and win
+// probabilities and updates ratings, logging each step. It runs when the match
+// finishes and again when one of its events is corrected or deleted, so every
+// step replaces the results of an earlier pass.
+func (h *BaseHandler) processFinishedMatch(ctx context.Context, matchID int32) {
+	count, err := h.assignPossessions(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign possessions", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned possessions", "match_id", matchID, "possessions", count)
+	}
+
+	count, err = h.assignGameStates(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign game states", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned game states", "match_id", matchID, "events", count)
+	}
+
+	count, err = h.assignLineupMinutes(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign minutes played", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned minutes played", "match_id", matchID, "players", count)
+	}
+
+	count, err = h.assignDerivedEvents(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign derived events", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned derived events", "match_id", matchID, "events", count)
+	}
+
+	count, err = h.assignGoalkeeperStats(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign goalkeeper statistics", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned goalkeeper statistics", "match_id", matchID, "goalkeepers", count)
+	}
+
+	count, err = h.assignPressingStats(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign pressing statistics", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned pressing statistics", "match_id", matchID, "teams", count)
+	}
+
+	count, err = h.assignWinProbabilities(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to assign win probabilities", "error", err, "match_id", matchID)
+	} else {
+		h.logger.Info("Assigned win probabilities", "match_id", matchID, "points", count)
+	}
+
+	count, err = h.updateRatings(ctx, matchID)
+	if err != nil {
+		h.logger.Error("Failed to update ratings", "error", err, "match_id", matchID)
+		return
+	}
+	h.logger.Info("Updated ratings", "match_id", matchID, "matches", count)
+}
+
+// HandleMatchLineups handles POST /webhooks/matches/lineups.
+// Receives team lineups (formation, starting XI and bench) from providers whose
+// feeds include them: ?provider=opta|statsbomb|generic
+// @Summary Receive match lineups via webhook
+// @Description Receives lineups from external providers and stores them, replacing each team's previous lineup for the match
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider query string false "Provider name (opta, statsbomb, generic)" default(generic)
+// @Param X-Signature header string true "HMAC SHA256 signature"
+// @Param X-Provider header string false "Provider identifier (alternative to query param)"
+// @Param payload body map[string]interface{} true "Lineup payload (provider-specific format)"
+// @Success 200 {object} gin.H
+// @Failure 400 {object} gin.H
+// @Failure 401 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /webhooks/matches/lineups [post]
+func (h *WebhookHandler) HandleMatchLineups(c *gin.Context) {
+	providerName := c.Query("provider")
+	if providerName == "" {
+		providerName = c.GetHeader("X-Provider")
+	}
+	if providerName == "" {
+		providerName = "generic"
+	}
+	providerName = strings.ToLower(providerName)
+
+	provider, err := h.providerRegistry.GetProvider(providerName)
+	if err != nil {
+		h.logger.Warn("Unknown provider", "provider", providerName, "ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown provider", "available": h.providerRegistry.ListProviders()})
+		return
+	}
+	lineupProvider, ok := provider.(webhooks.LineupProvider)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provider does not support lineups", "provider": providerName})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.logger.Warn("Failed to read request body", "error", err, "ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read payload"})
+		return
+	}
+
+	signature := c.GetHeader("X-Signature")
+	if !provider.VerifySignature(body, signature, h.getProviderSecret(providerName)) {
+		h.logger.Warn("Invalid webhook signature", "provider", providerName, "ip", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	lineups, err := lineupProvider.ExtractLineups(ctx, body)
+	if err != nil {
+		h.logger.Warn("Failed to extract lineups", "error", err, "provider", providerName, "ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload format", "details": err.Error()})
+		return
+	}
+	if len(lineups) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No lineups found in payload"})
+		return
+	}
+
+	// Validate every lineup before storing any of them
+	matches := make([]sqlc.Match, len(lineups))
+	formations := make([]*string, len(lineups))
+	for i, lineup := range lineups {
+		matches[i], err = h.queries.GetMatchByID(ctx, lineup.MatchID)
+		if err != nil {
+			h.logger.Error("Match not found for webhook lineup", "error", err, "match_id", lineup.MatchID, "provider", providerName)
+			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found", "match_id": lineup.MatchID})
+			return
+		}
+		formations[i], err = validateLineup(&matches[i], lineup)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "match_id": lineup.MatchID, "team_id": lineup.TeamID})
+			return
+		}
+	}
+
+	for i, lineup := range lineups {
+		if storeErr := h.storeLineup(ctx, &matches[i], lineup, formations[i]); storeErr != nil {
+			h.logger.Error("Failed to store lineup", "error", storeErr, "match_id", lineup.MatchID, "team_id", lineup.TeamID, "provider", providerName)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store lineup", "match_id": lineup.MatchID, "team_id": lineup.TeamID})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":        "stored",
+		"lineups_count": len(lineups),
+		"provider":      providerName,
+	})
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/emiliospot/footie/api/internal/analytics/matchevent"
+	"github.com/emiliospot/footie/api/internal/analytics/rating"
+	"github.com/emiliospot/footie/api/internal/analytics/winprob"
+	domainEvents "github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/mappers"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+// WinProbabilityResponse represents a match's in-play win probability timeline.
+type WinProbabilityResponse struct {
+	MatchID    int32             `json:"match_id"`
+	HomeTeamID int32             `json:"home_team_id"`
+	AwayTeamID int32             `json:"away_team_id"`
+	PreMatch   rating.Prediction `json:"pre_match"`
+	Timeline   []winprob.Point   `json:"timeline"` // One point after each event, in match clock order
+}
+
+// WinProbabilityUpdate represents the win probability after a live event.
+type WinProbabilityUpdate struct {
+	MatchID    int32 `json:"match_id"`
+	HomeTeamID int32 `json:"home_team_id"`
+	AwayTeamID int32 `json:"away_team_id"`
+	winprob.Point
+}
+
+// preMatchPrediction predicts a match from its competition's results before
+// it. Predictions are cached, since live matches need one for every event.
+func (h *BaseHandler) preMatchPrediction(ctx context.Context, match *sqlc.Match) (rating.Prediction, error) {
+	key := fmt.Sprintf("prematch:%d", match.ID)
+	var prediction rating.Prediction
+	if h.getCached(ctx, key, &prediction) {
+		return prediction, nil
+	}
+
+	rows, err := h.queries.ListMatchResults(ctx, sqlc.ListMatchResultsParams{Competition: &match.Competition})
+	if err != nil {
+		return rating.Prediction{}, fmt.Errorf("failed to list results: %w", err)
+	}
+	results := ratingResults(rows)
+	earlier := results[:0]
+	for _, r := range results {
+		if r.MatchID != match.ID && r.Date.Before(match.MatchDate.Time) {
+			earlier = append(earlier, r)
+		}
+	}
+	prediction = rating.FitPoisson(rating.DefaultPoissonConfig, earlier).Predict(match.HomeTeamID, match.AwayTeamID)
+	h.setCached(ctx, key, prediction)
+	return prediction, nil
+}
+
+// winProbabilityTimeline computes a match's win probability after each of its
+// stored events.
+func (h *BaseHandler) winProbabilityTimeline(ctx context.Context, match *sqlc.Match, prediction rating.Prediction) ([]winprob.Point, error) {
+	sqlcEvents, err := h.queries.GetMatchEvents(ctx, match.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match events: %w", err)
+	}
+
+	matchEvents := make([]matchevent.Event, 0, len(sqlcEvents))
+	for i := range sqlcEvents {
+		event := mappers.ToDomainMatchEvent(&sqlcEvents[i])
+		matchEvents = append(matchEvents, matchevent.FromModel(&event))
+	}
+	model := winprob.New(winprob.DefaultConfig, prediction.HomeGoals, prediction.AwayGoals)
+	return model.Timeline(match.HomeTeamID, matchEvents), nil
+}
+
+// assignWinProbabilities replaces a match's stored win probability timeline
+// with one computed from its events, correcting points stored live from events
+// that arrived out of order. It returns the number of points stored.
+func (h *BaseHandler) assignWinProbabilities(ctx context.Context, matchID int32) (int, error) {
+	match, err := h.queries.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get match: %w", err)
+	}
+	prediction, err := h.preMatchPrediction(ctx, &match)
+	if err != nil {
+		return 0, err
+	}
+	points, err := h.winProbabilityTimeline(ctx, &match, prediction)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := h.queries.WithTx(tx)
+	if err := queries.DeleteWinProbabilities(ctx, matchID); err != nil {
+		return 0, fmt.Errorf("failed to clear win probabilities: %w", err)
+	}
+	if err := queries.CreateWinProbabilities(ctx, winProbabilityParams(matchID, points)); err != nil {
+		return 0, fmt.Errorf("failed to store win probabilities: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit win probabilities: %w", err)
+	}
+	return len(points), nil
+}
+
+// publishWinProbability computes the win probability after a stored live event
+// from the match's latest stored point, stores it and publishes it to WebSocket
+// clients. Failures are logged; the event itself has already been published.
+func (h *BaseHandler) publishWinProbability(ctx context.Context, event *sqlc.MatchEvent) {
+	domainEvent := mappers.ToDomainMatchEvent(event)
+	live := matchevent.FromModel(&domainEvent)
+	if h.publisher == nil || live.Clock.Period == domainEvents.PeriodPenalties {
+		return
+	}
+
+	match, err := h.queries.GetMatchByID(ctx, event.MatchID)
+	if err != nil {
+		h.logger.Warn("Failed to load match for win probability", "error", err, "match_id", event.MatchID)
+		return
+	}
+	prediction, err := h.preMatchPrediction(ctx, &match)
+	if err != nil {
+		h.logger.Warn("Failed to predict match for win probability", "error", err, "match_id", event.MatchID)
+		return
+	}
+	var state winprob.State
+	latest, err := h.queries.GetLatestWinProbability(ctx, event.MatchID)
+	switch {
+	case err == nil:
+		state = winprob.State{
+			HomeGoals:    latest.HomeGoals,
+			AwayGoals:    latest.AwayGoals,
+			HomeRedCards: latest.HomeRedCards,
+			AwayRedCards: latest.AwayRedCards,
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		h.logger.Warn("Failed to load latest win probability", "error", err, "match_id", event.MatchID)
+		return
+	}
+
+	model := winprob.New(winprob.DefaultConfig, prediction.HomeGoals, prediction.AwayGoals)
+	point, ok := model.Next(state, match.HomeTeamID, &live)
+	if !ok {
+		return
+	}
+	if err := h.queries.CreateWinProbabilities(ctx, winProbabilityParams(match.ID, []winprob.Point{point})); err != nil {
+		h.logger.Warn("Failed to store win probability", "error", err, "match_id", event.MatchID)
+	}
+
+	update := WinProbabilityUpdate{
+		MatchID:    match.ID,
+		HomeTeamID: match.HomeTeamID,
+		AwayTeamID: match.AwayTeamID,
+		Point:      point,
+	}
+	if err := h.publisher.PublishWinProbability(ctx, match.ID, update); err != nil {
+		h.logger.Warn("Failed to publish win probability", "error", err, "match_id", event.MatchID)
+	}
+}
+
+// winProbabilityParams converts win probability points to their insert parameters.
+func winProbabilityParams(matchID int32, points []winprob.Point) sqlc.CreateWinProbabilitiesParams {
+	arg := sqlc.CreateWinProbabilitiesParams{MatchID: matchID}
+	for _, p := range points {
+		arg.EventIds = append(arg.EventIds, p.EventID)
+		arg.Periods = append(arg.Periods, p.Clock.Period.String())
+		arg.Minutes = append(arg.Minutes, p.Clock.Minute)
+		arg.Stoppages = append(arg.Stoppages, p.Clock.Stoppage)
+		arg.Seconds = append(arg.Seconds, p.Clock.Second)
+		arg.PeriodNumbers = append(arg.PeriodNumbers, p.Clock.PeriodNumber())
+		arg.ClockMs = append(arg.ClockMs, p.Clock.Ms)
+		arg.HomeGoals = append(arg.HomeGoals, p.HomeGoals)
+		arg.AwayGoals = append(arg.AwayGoals, p.AwayGoals)
+		arg.HomeRedCards = append(arg.HomeRedCards, p.HomeRedCards)
+		arg.AwayRedCards = append(arg.AwayRedCards, p.AwayRedCards)
+		arg.HomeWins = append(arg.HomeWins, p.Home)
+		arg.Draws = append(arg.Draws, p.Draw)
+		arg.AwayWins = append(arg.AwayWins, p.Away)
+	}
+	return arg
+}
+
+// pointFromRow converts a stored win probability to a timeline point.
+func pointFromRow(row *sqlc.MatchWinProbability) winprob.Point {
+	return winprob.Point{
+		EventID: row.EventID,
+		Clock:   domainEvents.NewMatchClock(domainEvents.Period(row.Period), row.Minute, row.Stoppage, row.Second),
+		State: winprob.State{
+			HomeGoals:    row.HomeGoals,
+			AwayGoals:    row.AwayGoals,
+			HomeRedCards: row.HomeRedCards,
+			AwayRedCards: row.AwayRedCards,
+		},
+		Home: row.HomeWin,
+		Draw: row.Draw,
+		Away: row.AwayWin,
+	}
+}
+
+// GetMatchWinProbability handles GET /api/v1/matches/:id/win-probability.
+// @Summary Get match win probability
+// @Description Get each side's in-play win probability after every event, from the pre-match expected goals, the score, the time remaining and sendings-off. Live matches push each new point as a win_probability WebSocket message
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Success 200 {object} WinProbabilityResponse
+// @Failure 400 {object} gin.H
+// @Failure 404 {object} gin.H
+// @Failure 500 {object} gin.H
+// @Router /api/v1/matches/{id}/win-probability [get]
+func (h *MatchHandler) GetMatchWinProbability(c *gin.Context) {
+	match, ok := h.loadMatch(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("win-probability:%d", match.ID)
+	finished := match.Status == "finished"
+	var response WinProbabilityResponse
+	if finished && h.getCached(ctx, key, &response) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	prediction, err := h.preMatchPrediction(ctx, &match)
+	if err != nil {
+		h.logger.Error("Failed to predict match", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match win probability"})
+		return
+	}
+	rows, err := h.queries.GetWinProbabilities(ctx, match.ID)
+	if err != nil {
+		h.logger.Error("Failed to get win probabilities", "error", err, "match_id", match.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match win probability"})
+		return
+	}
+
+	response = WinProbabilityResponse{
+		MatchID:    match.ID,
+		HomeTeamID: match.HomeTeamID,
+		AwayTeamID: match.AwayTeamID,
+		PreMatch:   prediction,
+		Timeline:   make([]winprob.Point, 0, len(rows)),
+	}
+	for i := range rows {
+		response.Timeline = append(response.Timeline, pointFromRow(&rows[i]))
+	}
+	// Matches played before timelines were stored are computed from their events
+	if len(rows) == 0 {
+		response.Timeline, err = h.winProbabilityTimeline(ctx, &match, prediction)
+		if err != nil {
+			h.logger.Error("Failed to compute win probabilities", "error", err, "match_id", match.ID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve match win probability"})
+			return
+		}
+	}
+	if finished {
+		h.setCached(ctx, key, response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -48,7 +48,7 @@ func NewRouter(cfg *config.Config, pool *pgxpool.Pool, redis *redis.Client, hub
 	corsConfig := cors.Config{
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Last-Event-ID"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: cfg.CORS.AllowCredentials,
 		MaxAge:           12 * 3600, // 12 hours
@@ -68,6 +68,9 @@ func NewRouter(cfg *config.Config, pool *pgxpool.Pool, redis *redis.Client, hub
 	healthHandler := handlers.NewHealthHandler(baseHandler)
 	matchHandler := handlers.NewMatchHandler(baseHandler)
 	rankingsHandler := handlers.NewRankingsHandler(baseHandler)
+	playerHandler := handlers.NewPlayerHandler(baseHandler)
+	teamHandler := handlers.NewTeamHandler(baseHandler)
+	liveHandler := handlers.NewLiveHandler(baseHandler, hub)
 	webhookHandler := handlers.NewWebhookHandler(baseHandler, &cfg.Webhook, providerRegistry)
 
 	// Health check endpoint
@@ -77,6 +80,7 @@ func NewRouter(cfg *config.Config, pool *pgxpool.Pool, redis *redis.Client, hub
 	webhooks := router.Group("/webhooks")
 	webhooks.POST("/matches", webhookHandler.HandleMatchEvents)
 	webhooks.POST("/matches/:id/status", webhookHandler.HandleMatchStatus)
+	webhooks.POST("/matches/lineups", webhookHandler.HandleMatchLineups)
 
 	// WebSocket endpoint for real-time match updates
 	router.GET("/ws/matches/:id", func(c *gin.Context) {
@@ -106,6 +110,32 @@ func NewRouter(cfg *config.Config, pool *pgxpool.Pool, redis *redis.Client, hub
 		ws.ServeWs(hub, conn, int32(matchID), userID)
 	})
 
+	// WebSocket endpoint for the live scores ticker (?competition= to filter)
+	router.GET("/ws/ticker", func(c *gin.Context) {
+		if hub == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live updates are not available"})
+			return
+		}
+		competition := c.Query("competition")
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.Error("Failed to upgrade WebSocket", "error", err)
+			return
+		}
+
+		userID := int32(0)
+		if userIDVal, exists := c.Get("user_id"); exists {
+			if uid, ok := userIDVal.(int32); ok {
+				userID = uid
+			}
+		}
+
+		ws.ServeTickerWs(hub, conn, competition, userID, func() (*ws.Message, error) {
+			return liveHandler.TickerSnapshot(c.Request.Context(), competition)
+		})
+	})
+
 	// Swagger documentation
 	if cfg.IsDevelopment() {
 		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -130,18 +160,49 @@ func NewRouter(cfg *config.Config, pool *pgxpool.Pool, redis *redis.Client, hub
 	// Match routes
 	matches := protected.Group("/matches")
 	matches.GET("", matchHandler.ListMatches)
+	matches.GET("/live", liveHandler.StreamMatches)
+	matches.GET("/predictions", matchHandler.GetMatchPredictions)
 	matches.GET("/:id", matchHandler.GetMatch)
+	matches.GET("/:id/live", liveHandler.StreamMatch)
 	matches.GET("/:id/events", matchHandler.GetMatchEvents)
-	matches.POST("/:id/events", matchHandler.CreateMatchEvent) // TODO: Add RequireRole("analyst")
+	matches.GET("/:id/shotmap", matchHandler.GetMatchShotmap)
+	matches.GET("/:id/xg-timeline", matchHandler.GetMatchXGTimeline)
+	matches.GET("/:id/win-probability", matchHandler.GetMatchWinProbability)
+	matches.GET("/:id/teams/:teamId/pass-network", matchHandler.GetMatchPassNetwork)
+	matches.GET("/:id/possessions", matchHandler.GetMatchPossessions)
+	matches.GET("/:id/lineups", matchHandler.GetMatchLineups)
+	matches.PUT("/:id/lineups", matchHandler.PutMatchLineup)              // TODO: Add RequireRole("analyst")
+	matches.POST("/:id/events", matchHandler.CreateMatchEvent)            // TODO: Add RequireRole("analyst")
+	matches.PATCH("/:id/events/:eventId", matchHandler.UpdateMatchEvent)  // TODO: Add RequireRole("analyst")
+	matches.DELETE("/:id/events/:eventId", matchHandler.DeleteMatchEvent) // TODO: Add RequireRole("analyst")
+
+	// Live scores ticker (Server-Sent Events)
+	protected.GET("/ticker/live", liveHandler.StreamTicker)
 
 	// Rankings routes
 	rankings := protected.Group("/rankings")
 	rankings.GET("", rankingsHandler.GetCompetitionRankings)
 
+	// Player and team statistics routes
+	players := protected.Group("/players")
+	players.GET("/compare", playerHandler.GetPlayerComparison)
+	players.GET("/:id/statistics", playerHandler.GetPlayerStatistics)
+	players.GET("/:id/heatmap", playerHandler.GetPlayerHeatmap)
+	players.GET("/:id/goalkeeping", playerHandler.GetPlayerGoalkeeping)
+	players.GET("/:id/similar", playerHandler.GetSimilarPlayers)
+
+	teams := protected.Group("/teams")
+	teams.GET("/:id/statistics", teamHandler.GetTeamStatistics)
+	teams.GET("/:id/heatmap", teamHandler.GetTeamHeatmap)
+	teams.GET("/:id/style", teamHandler.GetTeamStyle)
+	teams.GET("/:id/set-pieces", teamHandler.GetTeamSetPieces)
+	teams.GET("/:id/head-to-head/:opponentId", teamHandler.GetHeadToHead)
+	teams.GET("/:id/rating-history", teamHandler.GetTeamRatingHistory)
+
 	// TODO: Implement additional handlers
 	// - User handler (users CRUD, profile management)
-	// - Team handler (teams CRUD, statistics)
-	// - Player handler (players CRUD, statistics)
+	// - Team handler (teams CRUD)
+	// - Player handler (players CRUD)
 	// - Auth handler (JWT authentication)
 	// - Admin routes (user management)
 
@@ -11,15 +11,21 @@ import (
 
 // Config holds all configuration for the application.
 type Config struct {
-	Database DatabaseConfig
-	AWS      AWSConfig
-	App      AppConfig
-	API      APIConfig
-	Log      LogConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Webhook  WebhookConfig
+	Database  DatabaseConfig
+	AWS       AWSConfig
+	App       AppConfig
+	API       APIConfig
+	Log       LogConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	CORS      CORSConfig
+	Webhook   WebhookConfig
+	WS        WebSocketConfig
+	LiveStats LiveStatsConfig
+	Streams   StreamConfig
+	XG        XGConfig
+	XT        XTConfig
+	Analytics AnalyticsConfig
 }
 
 // AppConfig holds application-level configuration.
@@ -77,6 +83,68 @@ type WebhookConfig struct {
 	ProviderSecrets map[string]string
 }
 
+// WebSocketConfig holds real-time delivery configuration.
+type WebSocketConfig struct {
+	// OverflowPolicy is applied when a client's send queue is full:
+	// "drop_oldest", "coalesce" or "disconnect"
+	OverflowPolicy string
+	// SendQueueSize is the maximum number of messages queued per client
+	SendQueueSize int
+	// SSEHeartbeatSeconds is the interval between keep-alive comments on SSE streams
+	SSEHeartbeatSeconds int
+	// SSEReplayLimit caps how many stream entries are replayed on Last-Event-ID resume
+	SSEReplayLimit int
+}
+
+// LiveStatsConfig holds configuration for the in-match statistics stream consumer.
+type LiveStatsConfig struct {
+	// Enabled starts the consumer when Redis is available
+	Enabled bool
+	// ConsumerGroup is the Redis consumer group reading match:*:stream
+	ConsumerGroup string
+	// PublishIntervalMs is the minimum time between live_stats messages per match
+	PublishIntervalMs int
+	// TTLHours is how long running stats are kept in Redis after the last event
+	TTLHours int
+}
+
+// StreamConfig holds retention and archival configuration for match event streams.
+type StreamConfig struct {
+	// MaxLen approximately caps each match:{id}:stream while events are being added.
+	// It should comfortably exceed the number of events in a full match.
+	MaxLen int64
+	// ArchiveEnabled starts the background archiver when Redis and the database are available
+	ArchiveEnabled bool
+	// ArchiveAfterHours is how long a match must have been finished before its stream is archived
+	ArchiveAfterHours int
+	// ArchiveIntervalMinutes is how often the archiver looks for finished matches
+	ArchiveIntervalMinutes int
+	// ArchiveStore selects where archives are written: "local" or "s3"
+	ArchiveStore string
+	// ArchiveDir is the directory used by the local archive store
+	ArchiveDir string
+	// ArchivePrefix is the key prefix for archived streams
+	ArchivePrefix string
+}
+
+// XGConfig holds configuration for the built-in expected goals model.
+type XGConfig struct {
+	// ModelPath is a JSON coefficients file; empty uses the built-in default model
+	ModelPath string
+}
+
+// XTConfig holds configuration for the expected threat grid.
+type XTConfig struct {
+	// GridPath is a JSON grid built by cmd/xt-grid; empty disables xT values
+	GridPath string
+}
+
+// AnalyticsConfig holds configuration for the aggregated analytics endpoints.
+type AnalyticsConfig struct {
+	// CacheTTLSeconds is how long aggregated results (heatmaps, etc.) are cached in Redis; 0 disables caching
+	CacheTTLSeconds int
+}
+
 // LogConfig holds logging configuration.
 type LogConfig struct {
 	Level  string
@@ -90,6 +158,8 @@ type AWSConfig struct {
 	SecretAccessKey  string
 	S3Bucket         string
 	CloudFrontDomain string
+	// S3Endpoint points at an S3-compatible service (e.g. MinIO); empty means AWS
+	S3Endpoint string
 }
 
 const (
@@ -148,11 +218,42 @@ func Load() (*Config, error) {
 			SecretAccessKey:  getEnv("AWS_SECRET_ACCESS_KEY", ""),
 			S3Bucket:         getEnv("AWS_S3_BUCKET", ""),
 			CloudFrontDomain: getEnv("AWS_CLOUDFRONT_DOMAIN", ""),
+			S3Endpoint:       getEnv("AWS_S3_ENDPOINT", ""),
 		},
 		Webhook: WebhookConfig{
 			DefaultSecret: getEnv("WEBHOOK_SECRET", ""), // Default secret for generic providers
 			ProviderSecrets: parseProviderSecrets(),      // Parse provider-specific secrets
 		},
+		WS: WebSocketConfig{
+			OverflowPolicy:      getEnv("WS_OVERFLOW_POLICY", "drop_oldest"),
+			SendQueueSize:       getEnvAsInt("WS_SEND_QUEUE_SIZE", 256),
+			SSEHeartbeatSeconds: getEnvAsInt("SSE_HEARTBEAT_SECONDS", 15),
+			SSEReplayLimit:      getEnvAsInt("SSE_REPLAY_LIMIT", 1000),
+		},
+		LiveStats: LiveStatsConfig{
+			Enabled:           getEnvAsBool("LIVE_STATS_ENABLED", true),
+			ConsumerGroup:     getEnv("LIVE_STATS_GROUP", "live-stats"),
+			PublishIntervalMs: getEnvAsInt("LIVE_STATS_PUBLISH_INTERVAL_MS", 2000),
+			TTLHours:          getEnvAsInt("LIVE_STATS_TTL_HOURS", 48),
+		},
+		Streams: StreamConfig{
+			MaxLen:                 int64(getEnvAsInt("STREAM_MAX_LEN", 20000)),
+			ArchiveEnabled:         getEnvAsBool("STREAM_ARCHIVE_ENABLED", true),
+			ArchiveAfterHours:      getEnvAsInt("STREAM_ARCHIVE_AFTER_HOURS", 24),
+			ArchiveIntervalMinutes: getEnvAsInt("STREAM_ARCHIVE_INTERVAL_MINUTES", 15),
+			ArchiveStore:           getEnv("STREAM_ARCHIVE_STORE", "local"),
+			ArchiveDir:             getEnv("STREAM_ARCHIVE_DIR", "./data/stream-archive"),
+			ArchivePrefix:          getEnv("STREAM_ARCHIVE_PREFIX", "match-streams"),
+		},
+		XG: XGConfig{
+			ModelPath: getEnv("XG_MODEL_PATH", ""),
+		},
+		XT: XTConfig{
+			GridPath: getEnv("XT_GRID_PATH", ""),
+		},
+		Analytics: AnalyticsConfig{
+			CacheTTLSeconds: getEnvAsInt("ANALYTICS_CACHE_TTL_SECONDS", 600),
+		},
 	}
 
 	// Build DATABASE_URL if not provided
@@ -0,0 +1,103 @@
+package events
+
+// MatchClock is the canonical time of an event within a match.
+//
+// Minute and Stoppage add up to the minute on the match clock: an event at 46:20
+// in the first half is Minute 45, Stoppage 1, Second 20. Events are
+// ordered by period and then by milliseconds on the clock, since stoppage time
+// at the end of a period overlaps the nominal start of the next one.
+type MatchClock struct {
+	Period Period `json:"period"`
+	// Minute is the clock minute, capped at the nominal end of the period
+	Minute int32 `json:"minute"`
+	// Stoppage is the number of minutes played past the nominal end of the period
+	Stoppage int32 `json:"stoppage"`
+	// Second is the second within the minute (0-59)
+	Second int32 `json:"second"`
+	// ElapsedMs is the time since the start of the period
+	ElapsedMs int64 `json:"elapsed_ms"`
+	// Ms is the time on the match clock since kick-off
+	Ms int64 `json:"ms"`
+}
+
+// periodBounds are the nominal start and end minutes of each period. The
+// penalty shootout has no end.
+var periodBounds = map[Period][2]int32{
+	PeriodFirstHalf:       {0, 45},
+	PeriodSecondHalf:      {45, 90},
+	PeriodExtraTimeFirst:  {90, 105},
+	PeriodExtraTimeSecond: {105, 120},
+	PeriodPenalties:       {120, 0},
+}
+
+// NewMatchClock builds the clock for an event at minute (plus stoppage minutes)
+// and second. Providers send stoppage time either as a separate stoppage minute
+// (45+2) or as a continuous clock minute (47 in the first half); both give the
+// same clock. When period is not a playing period (PeriodRegular or empty) it
+// is determined from the minute.
+func NewMatchClock(period Period, minute, stoppage, second int32) MatchClock {
+	if _, ok := periodBounds[period]; !ok {
+		period = DeterminePeriod(minute)
+	}
+	if stoppage < 0 {
+		stoppage = 0
+	}
+	if second < 0 || second > 59 {
+		second = 0
+	}
+
+	bounds := periodBounds[period]
+	clockMinute := minute + stoppage
+	if clockMinute < bounds[0] {
+		clockMinute = bounds[0]
+	}
+
+	c := MatchClock{
+		Period:    period,
+		Minute:    clockMinute,
+		Second:    second,
+		ElapsedMs: (int64(clockMinute-bounds[0])*60 + int64(second)) * 1000,
+		Ms:        (int64(clockMinute)*60 + int64(second)) * 1000,
+	}
+	if bounds[1] > 0 && clockMinute > bounds[1] {
+		c.Minute, c.Stoppage = bounds[1], clockMinute-bounds[1]
+	}
+	return c
+}
+
+// ClockSpan is a stretch of one period on the match clock, from FromMs up to ToMs.
+// The period is needed because stoppage time at the end of a period overlaps the
+// nominal start of the next one.
+type ClockSpan struct {
+	Period Period
+	FromMs int64
+	ToMs   int64
+}
+
+// Overlap returns the milliseconds of a stretch of a period that fall in the spans.
+func Overlap(period Period, fromMs, toMs int64, spans []ClockSpan) int64 {
+	var ms int64
+	for _, s := range spans {
+		if s.Period != period {
+			continue
+		}
+		if lo, hi := max(fromMs, s.FromMs), min(toMs, s.ToMs); hi > lo {
+			ms += hi - lo
+		}
+	}
+	return ms
+}
+
+// PeriodNumber returns the period's position in the match: 1 and 2 for the
+// halves, 3 and 4 for extra time, 5 for penalties and 0 if unknown.
+func (c MatchClock) PeriodNumber() int16 {
+	return c.Period.Number()
+}
+
+// Before reports whether c is earlier in the match than o.
+func (c MatchClock) Before(o MatchClock) bool {
+	if c.PeriodNumber() != o.PeriodNumber() {
+		return c.PeriodNumber() < o.PeriodNumber()
+	}
+	return c.Ms < o.Ms
+}
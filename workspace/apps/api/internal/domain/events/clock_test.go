@@ -0,0 +1,75 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMatchClock(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   Period
+		minute   int32
+		stoppage int32
+		second   int32
+		want     MatchClock
+	}{
+		{
+			name:   "first half",
+			period: PeriodFirstHalf,
+			minute: 12,
+			second: 30,
+			want:   MatchClock{Period: PeriodFirstHalf, Minute: 12, Second: 30, ElapsedMs: 750000, Ms: 750000},
+		},
+		{
+			name:     "stoppage minute is not extra time",
+			period:   PeriodRegular,
+			minute:   45,
+			stoppage: 2,
+			second:   10,
+			want:     MatchClock{Period: PeriodFirstHalf, Minute: 45, Stoppage: 2, Second: 10, ElapsedMs: 2830000, Ms: 2830000},
+		},
+		{
+			name:   "continuous minute in first half stoppage",
+			period: PeriodFirstHalf,
+			minute: 47,
+			second: 10,
+			want:   MatchClock{Period: PeriodFirstHalf, Minute: 45, Stoppage: 2, Second: 10, ElapsedMs: 2830000, Ms: 2830000},
+		},
+		{
+			name:   "second half",
+			period: PeriodSecondHalf,
+			minute: 46,
+			want:   MatchClock{Period: PeriodSecondHalf, Minute: 46, ElapsedMs: 60000, Ms: 2760000},
+		},
+		{
+			name:   "minute past 90 without period is extra time",
+			period: PeriodRegular,
+			minute: 97,
+			want:   MatchClock{Period: PeriodExtraTimeFirst, Minute: 97, ElapsedMs: 420000, Ms: 5820000},
+		},
+		{
+			name:     "second half stoppage",
+			period:   PeriodRegular,
+			minute:   90,
+			stoppage: 4,
+			want:     MatchClock{Period: PeriodSecondHalf, Minute: 90, Stoppage: 4, ElapsedMs: 2940000, Ms: 5640000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewMatchClock(tt.period, tt.minute, tt.stoppage, tt.second))
+		})
+	}
+}
+
+func TestMatchClock_Before(t *testing.T) {
+	firstHalfStoppage := NewMatchClock(PeriodFirstHalf, 45, 3, 0)
+	secondHalfStart := NewMatchClock(PeriodSecondHalf, 46, 0, 0)
+
+	assert.True(t, firstHalfStoppage.Before(secondHalfStart))
+	assert.False(t, secondHalfStart.Before(firstHalfStoppage))
+	assert.True(t, NewMatchClock(PeriodSecondHalf, 60, 0, 1).Before(NewMatchClock(PeriodSecondHalf, 60, 0, 2)))
+}
@@ -0,0 +1,26 @@
+package events
+
+import "strconv"
+
+// MetaFloat returns the first numeric metadata value found under keys.
+// Providers send numbers either as JSON numbers or as strings (Opta qualifiers).
+func MetaFloat(meta map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if f, ok := ToFloat(meta[key]); ok {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// ToFloat reads a JSON number or a numeric string.
+func ToFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,24 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaFloat(t *testing.T) {
+	meta := map[string]interface{}{"xG": 0.12, "GoalMouthY": "45.5", "outcome": "Goal"}
+
+	v, ok := MetaFloat(meta, "xg", "xG")
+	assert.True(t, ok)
+	assert.Equal(t, 0.12, v)
+
+	v, ok = MetaFloat(meta, "GoalMouthY")
+	assert.True(t, ok, "Opta qualifiers are strings")
+	assert.Equal(t, 45.5, v)
+
+	_, ok = MetaFloat(meta, "outcome", "missing")
+	assert.False(t, ok)
+	_, ok = MetaFloat(nil, "xG")
+	assert.False(t, ok)
+}
@@ -36,28 +36,42 @@ func (p Period) IsValid() bool {
 	}
 }
 
-// DeterminePeriod determines the period based on minute and extra_minute.
-// This is useful when period is not explicitly provided.
-func DeterminePeriod(minute int32, extraMinute *int32) Period {
-	// If extra_minute is set, we're in extra time
-	if extraMinute != nil && *extraMinute > 0 {
-		if minute <= 105 {
-			return PeriodExtraTimeFirst
-		}
-		return PeriodExtraTimeSecond
+// Number returns the period's position in the match: 1 and 2 for the halves,
+// 3 and 4 for extra time, 5 for penalties and 0 for PeriodRegular.
+func (p Period) Number() int16 {
+	switch p {
+	case PeriodFirstHalf:
+		return 1
+	case PeriodSecondHalf:
+		return 2
+	case PeriodExtraTimeFirst:
+		return 3
+	case PeriodExtraTimeSecond:
+		return 4
+	case PeriodPenalties:
+		return 5
+	default:
+		return 0
 	}
+}
 
-	// Regular time
-	if minute <= 45 {
+// DeterminePeriod determines the period from the minute.
+// This is useful when period is not explicitly provided. Stoppage time
+// (45+2 is minute 45, extra minute 2) stays in the period the minute belongs
+// to, so the extra minute is not needed.
+func DeterminePeriod(minute int32) Period {
+	switch {
+	case minute <= 45:
 		return PeriodFirstHalf
-	}
-	if minute <= 90 {
+	case minute <= 90:
 		return PeriodSecondHalf
+	case minute <= 105:
+		return PeriodExtraTimeFirst
+	default:
+		// Minutes past 120 are stoppage time at the end of extra time; the
+		// shootout is only recognized when the provider sends the period
+		return PeriodExtraTimeSecond
 	}
-
-	// Beyond 90 minutes without extra_minute might be penalties or data error
-	// Default to second half for safety
-	return PeriodSecondHalf
 }
 
 // NormalizePeriod normalizes period strings from external providers.
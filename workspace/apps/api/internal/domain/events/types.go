@@ -46,6 +46,12 @@ const (
 	EventTypeLongBall      EventType = "long_ball"
 	EventTypeShortPass     EventType = "short_pass"
 
+	// Set pieces
+	EventTypeCorner EventType = "corner"
+
+	// Ball progression
+	EventTypeCarry EventType = "carry"
+
 	// Defensive actions
 	EventTypeTackle        EventType = "tackle"
 	EventTypeTackleWon     EventType = "tackle_won"
@@ -103,6 +109,8 @@ const (
 	CategorySubstitution EventCategory = "substitution"
 	CategoryShot         EventCategory = "shot"
 	CategoryPass         EventCategory = "pass"
+	CategorySetPiece     EventCategory = "set_piece"
+	CategoryCarry        EventCategory = "carry"
 	CategoryDefensive    EventCategory = "defensive"
 	CategoryDuel         EventCategory = "duel"
 	CategoryFoul         EventCategory = "foul"
@@ -137,6 +145,14 @@ func GetCategory(eventType EventType) EventCategory {
 		EventTypeAssist, EventTypeThroughBall, EventTypeCross, EventTypeLongBall, EventTypeShortPass:
 		return CategoryPass
 
+	// Set pieces
+	case EventTypeCorner:
+		return CategorySetPiece
+
+	// Ball progression
+	case EventTypeCarry:
+		return CategoryCarry
+
 	// Defensive
 	case EventTypeTackle, EventTypeTackleWon, EventTypeTackleLost, EventTypeInterception,
 		EventTypeClearance, EventTypeBlock, EventTypeBlockedShot:
@@ -169,6 +185,40 @@ func GetCategory(eventType EventType) EventCategory {
 	}
 }
 
+// knownEventTypes lists the common event types, grouped as in GetCategory.
+var knownEventTypes = []EventType{
+	EventTypeGoal, EventTypeOwnGoal, EventTypePenalty, EventTypePenaltyGoal, EventTypePenaltyMiss,
+	EventTypeYellowCard, EventTypeRedCard, EventTypeSecondYellow,
+	EventTypeSubstitution, EventTypeSubstitutionOn, EventTypeSubstitutionOff,
+	EventTypeShot, EventTypeShotOnTarget, EventTypeShotOffTarget, EventTypeShotBlocked,
+	EventTypeShotSaved, EventTypeShotPost, EventTypeShotWoodwork,
+	EventTypePass, EventTypePassCompleted, EventTypePassIncomplete, EventTypeKeyPass,
+	EventTypeAssist, EventTypeThroughBall, EventTypeCross, EventTypeLongBall, EventTypeShortPass,
+	EventTypeCorner,
+	EventTypeCarry,
+	EventTypeTackle, EventTypeTackleWon, EventTypeTackleLost, EventTypeInterception,
+	EventTypeClearance, EventTypeBlock, EventTypeBlockedShot,
+	EventTypeDuel, EventTypeDuelWon, EventTypeDuelLost, EventTypeAerialDuel,
+	EventTypeAerialDuelWon, EventTypeAerialDuelLost, EventTypeGroundDuel,
+	EventTypeFoul, EventTypeFoulCommitted, EventTypeFoulWon, EventTypeOffside,
+	EventTypeSave, EventTypeSavePenalty, EventTypeSaveSixYardBox, EventTypeSavePenaltyArea,
+	EventTypeSaveOutOfBox, EventTypePunch, EventTypeClaim, EventTypeSweeperKeeper,
+	EventTypeVarReview, EventTypeVarGoal, EventTypeVarPenalty, EventTypeVarRedCard,
+	EventTypeKickOff, EventTypeHalfTime, EventTypeFullTime, EventTypeExtraTime, EventTypePenaltyShootout,
+}
+
+// TypesInCategory returns the common event types in a category, as strings for
+// use in queries. It returns nil for CategoryOther and unknown categories.
+func TypesInCategory(category EventCategory) []string {
+	var types []string
+	for _, eventType := range knownEventTypes {
+		if GetCategory(eventType) == category && category != CategoryOther {
+			types = append(types, eventType.String())
+		}
+	}
+	return types
+}
+
 // IsValid checks if an event type is valid (non-empty and reasonable length).
 // Note: We don't restrict to a fixed list to allow extensibility.
 func IsValid(eventType EventType) bool {
@@ -214,6 +264,16 @@ func (et EventType) IsPass() bool {
 	return GetCategory(et) == CategoryPass
 }
 
+// IsCarry returns true if the event type is a ball carry.
+func (et EventType) IsCarry() bool {
+	return GetCategory(et) == CategoryCarry
+}
+
+// IsSubstitution returns true if the event type is a substitution.
+func (et EventType) IsSubstitution() bool {
+	return GetCategory(et) == CategorySubstitution
+}
+
 // String returns the string representation of the event type.
 func (et EventType) String() string {
 	return string(et)
@@ -95,6 +95,7 @@ func ToDomainMatch(m *sqlc.Match) models.Match {
 		CreatedAt:     pgtypeToTime(m.CreatedAt),
 		UpdatedAt:     pgtypeToTime(m.UpdatedAt),
 		DeletedAt:     pgtypeToTimePtr(m.DeletedAt),
+		FinishedAt:    pgtypeToTimePtr(m.FinishedAt),
 	}
 }
 
@@ -112,6 +113,11 @@ func ToDomainMatchEvent(e *sqlc.MatchEvent) models.MatchEvent {
 		}
 	}
 
+	period := ""
+	if e.Period != nil {
+		period = *e.Period
+	}
+
 	return models.MatchEvent{
 		ID:                e.ID,
 		MatchID:           e.MatchID,
@@ -120,7 +126,18 @@ func ToDomainMatchEvent(e *sqlc.MatchEvent) models.MatchEvent {
 		SecondaryPlayerID: e.SecondaryPlayerID,
 		EventType:         e.EventType,
 		Minute:            e.Minute,
+		Second:            e.Second,
 		ExtraMinute:       e.ExtraMinute,
+		Period:            period,
+		PeriodNumber:      e.PeriodNumber,
+		ClockMs:           e.ClockMs,
+		PossessionID:      e.PossessionID,
+		GameState:         e.GameState,
+		ScoreDifferential: e.ScoreDifferential,
+		DerivedType:       e.DerivedType,
+		XA:                e.Xa,
+		ShotEventID:       e.ShotEventID,
+		CreationType:      e.CreationType,
 		PositionX:         posX,
 		PositionY:         posY,
 		Description:       e.Description,
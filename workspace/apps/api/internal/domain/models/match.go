@@ -28,6 +28,9 @@ type Match struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"-"` // Soft delete timestamp
+
+	// FinishedAt is when the status last changed to finished; nil otherwise
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
 }
 
 // IsFinished returns true if the match is finished.
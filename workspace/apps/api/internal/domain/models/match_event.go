@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
 )
 
 // MatchEvent represents an event that occurred during a match.
@@ -17,7 +19,16 @@ type MatchEvent struct {
 	Minute            int32           `json:"minute"`
 	Second            *int32          `json:"second,omitempty"` // Exact second (0-59)
 	ExtraMinute       *int32          `json:"extra_minute,omitempty"`
-	Period            string          `json:"period,omitempty"` // first_half, second_half, extra_time_first, extra_time_second, penalties
+	Period            string          `json:"period,omitempty"`             // first_half, second_half, extra_time_first, extra_time_second, penalties
+	PeriodNumber      int16           `json:"period_number"`                // 1-2 halves, 3-4 extra time, 5 penalties; events sort by period number, then clock
+	ClockMs           int64           `json:"clock_ms"`                     // Milliseconds on the match clock since kick-off
+	PossessionID      *int32          `json:"possession_id,omitempty"`      // Possession within the match, once reconstructed
+	GameState         *string         `json:"game_state,omitempty"`         // The team's game state before the event (winning, drawing, losing), once tagged
+	ScoreDifferential *int16          `json:"score_differential,omitempty"` // The team's goal differential before the event, once tagged
+	DerivedType       *string         `json:"derived_type,omitempty"`       // assist, second_assist or key_pass when inferred from the event sequence
+	XA                *float64        `json:"xa,omitempty"`                 // Derived assists and key passes: the xG of the shot they led to
+	ShotEventID       *int32          `json:"shot_event_id,omitempty"`      // Derived passes and creating actions: the shot or goal they led to
+	CreationType      *string         `json:"creation_type,omitempty"`      // shot_creating or goal_creating for one of the two actions before a shot
 	PositionX         *float64        `json:"position_x,omitempty"`
 	PositionY         *float64        `json:"position_y,omitempty"`
 	Description       *string         `json:"description,omitempty"`
@@ -27,6 +38,18 @@ type MatchEvent struct {
 	DeletedAt         *time.Time      `json:"-"` // Soft delete timestamp
 }
 
+// Clock returns the event's match clock.
+func (me *MatchEvent) Clock() events.MatchClock {
+	var extraMinute, second int32
+	if me.ExtraMinute != nil {
+		extraMinute = *me.ExtraMinute
+	}
+	if me.Second != nil {
+		second = *me.Second
+	}
+	return events.NewMatchClock(events.Period(me.Period), me.Minute, extraMinute, second)
+}
+
 // IsGoal returns true if the event is a goal.
 func (me *MatchEvent) IsGoal() bool {
 	return me.EventType == "goal"
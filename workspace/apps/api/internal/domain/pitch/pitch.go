@@ -0,0 +1,209 @@
+// Package pitch defines the canonical pitch coordinate system and the transforms
+// from each provider's coordinates into it.
+//
+// Canonical coordinates are meters on a 105x68 pitch. The team in possession always
+// attacks left to right, towards the goal at x = Length; y = 0 is the touchline on
+// the attacking team's left.
+package pitch
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/domain/events"
+)
+
+// Canonical pitch dimensions in meters.
+const (
+	Length = 105.0
+	Width  = 68.0
+)
+
+// Metadata keys written on normalized events.
+const (
+	// MetaCoordinates is set to Canonical once an event's coordinates are normalized
+	MetaCoordinates = "coordinates"
+	// MetaOriginal holds the provider's coordinate system and raw coordinates
+	MetaOriginal = "original_coordinates"
+	// Canonical is the MetaCoordinates value for normalized events
+	Canonical = "canonical"
+)
+
+// Frame describes a provider coordinate system.
+type Frame struct {
+	Name    string
+	Length  float64
+	Width   float64
+	InvertY bool // y = 0 is the attacking team's right touchline
+}
+
+// Provider coordinate systems. StatsBomb and Opta orient every event so the team
+// in possession attacks left to right.
+var (
+	CanonicalFrame = Frame{Name: Canonical, Length: Length, Width: Width}
+	StatsBomb      = Frame{Name: "statsbomb", Length: 120, Width: 80}
+	Opta           = Frame{Name: "opta", Length: 100, Width: 100, InvertY: true}
+	Percent        = Frame{Name: "percent", Length: 100, Width: 100}
+)
+
+// FrameFor returns the coordinate system a stored event's coordinates are in:
+// canonical for events normalized on ingest, otherwise the provider's own system
+// (events stored before normalization, where 0-100 percentages are assumed for
+// the generic provider and manual events).
+func FrameFor(meta map[string]interface{}, provider string) Frame {
+	if coordinates, _ := meta[MetaCoordinates].(string); coordinates == Canonical {
+		return CanonicalFrame
+	}
+	switch strings.ToLower(provider) {
+	case StatsBomb.Name:
+		return StatsBomb
+	case Opta.Name:
+		return Opta
+	}
+	return Percent
+}
+
+// Meters converts a point in the frame to canonical meters, keeping its orientation.
+func (f Frame) Meters(x, y float64) (float64, float64) {
+	if f.Length <= 0 || f.Width <= 0 {
+		return x, y
+	}
+	mx, my := x/f.Length*Length, y/f.Width*Width
+	if f.InvertY {
+		my = Width - my
+	}
+	return mx, my
+}
+
+// Direction is the way a team attacks.
+type Direction int
+
+const (
+	LeftToRight Direction = iota
+	RightToLeft
+)
+
+// ParseDirection parses "left_to_right"/"ltr" or "right_to_left"/"rtl".
+func ParseDirection(s string) (Direction, bool) {
+	switch strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(s))) {
+	case "left_to_right", "ltr":
+		return LeftToRight, true
+	case "right_to_left", "rtl":
+		return RightToLeft, true
+	}
+	return LeftToRight, false
+}
+
+// ForPeriod returns the direction in a period for a team that attacks in direction
+// d in the first half. Teams change ends at half-time and again between the two
+// periods of extra time.
+func (d Direction) ForPeriod(period string) Direction {
+	switch events.NormalizePeriod(period) {
+	case events.PeriodSecondHalf, events.PeriodExtraTimeSecond:
+		if d == LeftToRight {
+			return RightToLeft
+		}
+		return LeftToRight
+	}
+	return d
+}
+
+// ToCanonical converts a point in the frame, attacked in direction d, to canonical
+// coordinates.
+func (f Frame) ToCanonical(x, y float64, d Direction) (float64, float64) {
+	mx, my := f.Meters(x, y)
+	if d == RightToLeft {
+		mx, my = Length-mx, Width-my
+	}
+	return round(mx), round(my)
+}
+
+// endKeys are the normalized metadata keys holding end locations, which are
+// transformed along with the event position.
+var endKeys = [][2]string{
+	{"passendx", "passendy"},   // StatsBomb pass_end_location, Opta PassEndX/PassEndY
+	{"carryendx", "carryendy"}, // StatsBomb carry_end_location
+	{"shotendx", "shotendy"},
+	{"endx", "endy"}, // Generic provider
+}
+
+// Normalize converts an event's position and the end locations in its metadata to
+// canonical coordinates, recording the raw values under MetaOriginal, and marks the
+// metadata as canonical. Events without coordinates are left alone. meta must not be nil.
+func Normalize(f Frame, d Direction, x, y *float64, meta map[string]interface{}) (*float64, *float64) {
+	if coordinates, _ := meta[MetaCoordinates].(string); coordinates == Canonical {
+		return x, y
+	}
+	transform := f != CanonicalFrame || d != LeftToRight
+
+	original := map[string]interface{}{"system": f.Name}
+	if d == RightToLeft {
+		original["direction"] = "right_to_left"
+	}
+	moved := false
+
+	if x != nil && y != nil {
+		original["x"], original["y"] = *x, *y
+		cx, cy := f.ToCanonical(*x, *y, d)
+		x, y = &cx, &cy
+		moved = true
+	}
+
+	keys := make(map[string]string, len(meta))
+	for key := range meta {
+		keys[normalizeKey(key)] = key
+	}
+	for _, end := range endKeys {
+		keyX, okX := keys[end[0]]
+		keyY, okY := keys[end[1]]
+		if !okX || !okY {
+			continue
+		}
+		ex, okX := events.ToFloat(meta[keyX])
+		ey, okY := events.ToFloat(meta[keyY])
+		if !okX || !okY {
+			continue
+		}
+		original[keyX], original[keyY] = meta[keyX], meta[keyY]
+		meta[keyX], meta[keyY] = f.ToCanonical(ex, ey, d)
+		moved = true
+	}
+
+	if !moved {
+		return x, y
+	}
+	if transform {
+		meta[MetaOriginal] = original
+	}
+	meta[MetaCoordinates] = Canonical
+	return x, y
+}
+
+// NormalizeJSON is Normalize for metadata held as a JSON string. Metadata that
+// is not a JSON object is returned unchanged.
+func NormalizeJSON(f Frame, d Direction, x, y *float64, metadata string) (*float64, *float64, string) {
+	meta := map[string]interface{}{}
+	if metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &meta); err != nil || meta == nil {
+			return x, y, metadata
+		}
+	}
+	x, y = Normalize(f, d, x, y, meta)
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return x, y, metadata
+	}
+	return x, y, string(data)
+}
+
+// normalizeKey lowercases a metadata key and drops underscores and spaces, so
+// "pass_end_x" and "PassEndX" compare equal.
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", " ", "").Replace(strings.ToLower(key))
+}
+
+// round keeps two decimals, the precision of the position columns.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,119 @@
+package pitch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		frame     Frame
+		direction Direction
+		x, y      *float64
+		meta      map[string]interface{}
+		wantX     float64
+		wantY     float64
+		wantMeta  map[string]interface{}
+	}{
+		{
+			name:      "statsbomb pass",
+			frame:     StatsBomb,
+			direction: LeftToRight,
+			x:         ptr(60),
+			y:         ptr(40),
+			meta:      map[string]interface{}{"pass_end_x": 120.0, "pass_end_y": 0.0},
+			wantX:     52.5,
+			wantY:     34,
+			wantMeta: map[string]interface{}{
+				"pass_end_x":    105.0,
+				"pass_end_y":    0.0,
+				MetaCoordinates: Canonical,
+				MetaOriginal: map[string]interface{}{
+					"system": "statsbomb", "x": 60.0, "y": 40.0, "pass_end_x": 120.0, "pass_end_y": 0.0,
+				},
+			},
+		},
+		{
+			name:      "opta inverts y and parses qualifier strings",
+			frame:     Opta,
+			direction: LeftToRight,
+			x:         ptr(88.5),
+			y:         ptr(25),
+			meta:      map[string]interface{}{"PassEndX": "100", "PassEndY": "50"},
+			wantX:     92.93,
+			wantY:     51,
+			wantMeta: map[string]interface{}{
+				"PassEndX":      105.0,
+				"PassEndY":      34.0,
+				MetaCoordinates: Canonical,
+				MetaOriginal: map[string]interface{}{
+					"system": "opta", "x": 88.5, "y": 25.0, "PassEndX": "100", "PassEndY": "50",
+				},
+			},
+		},
+		{
+			name:      "attacking right to left is flipped",
+			frame:     Percent,
+			direction: LeftToRight.ForPeriod("second_half"),
+			x:         ptr(10),
+			y:         ptr(25),
+			meta:      map[string]interface{}{},
+			wantX:     94.5,
+			wantY:     51,
+			wantMeta: map[string]interface{}{
+				MetaCoordinates: Canonical,
+				MetaOriginal: map[string]interface{}{
+					"system": "percent", "direction": "right_to_left", "x": 10.0, "y": 25.0,
+				},
+			},
+		},
+		{
+			name:      "canonical positions are only marked",
+			frame:     CanonicalFrame,
+			direction: LeftToRight,
+			x:         ptr(90),
+			y:         ptr(30),
+			meta:      map[string]interface{}{},
+			wantX:     90,
+			wantY:     30,
+			wantMeta:  map[string]interface{}{MetaCoordinates: Canonical},
+		},
+		{
+			name:      "events without coordinates are left alone",
+			frame:     StatsBomb,
+			direction: LeftToRight,
+			meta:      map[string]interface{}{"outcome": "Won"},
+			wantMeta:  map[string]interface{}{"outcome": "Won"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := Normalize(tt.frame, tt.direction, tt.x, tt.y, tt.meta)
+			if tt.x == nil {
+				assert.Nil(t, x)
+				assert.Nil(t, y)
+			} else {
+				assert.InDelta(t, tt.wantX, *x, 0.01)
+				assert.InDelta(t, tt.wantY, *y, 0.01)
+			}
+			assert.Equal(t, tt.wantMeta, tt.meta)
+
+			// Normalizing twice must not move the event again
+			x2, y2 := Normalize(tt.frame, tt.direction, x, y, tt.meta)
+			assert.Equal(t, x, x2)
+			assert.Equal(t, y, y2)
+		})
+	}
+}
+
+func TestDirection_ForPeriod(t *testing.T) {
+	assert.Equal(t, LeftToRight, LeftToRight.ForPeriod("first_half"))
+	assert.Equal(t, RightToLeft, LeftToRight.ForPeriod("second_half"))
+	assert.Equal(t, RightToLeft, LeftToRight.ForPeriod("extra_time_second"))
+	assert.Equal(t, LeftToRight, RightToLeft.ForPeriod("2H"))
+}
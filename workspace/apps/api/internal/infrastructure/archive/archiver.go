@@ -0,0 +1,390 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
+	"github.com/emiliospot/footie/api/internal/repository/sqlc"
+)
+
+const (
+	// streamPattern matches every match event stream.
+	streamPattern = "match:*:stream"
+
+	// pageSize is the number of entries read per XRANGE call and written per restore batch.
+	pageSize = 1000
+)
+
+// MatchGetter looks up a match. It is satisfied by *sqlc.Queries.
+type MatchGetter interface {
+	GetMatchByID(ctx context.Context, id int32) (sqlc.Match, error)
+}
+
+// Entry is one archived stream entry, written as a JSON line.
+type Entry struct {
+	ID     string            `json:"id"`
+	Values map[string]string `json:"values"`
+}
+
+// Key returns the archive key for a match.
+func Key(prefix string, matchID int32) string {
+	return fmt.Sprintf("%s/%d.jsonl.gz", strings.Trim(prefix, "/"), matchID)
+}
+
+// deleteScript deletes a stream only if its last entry is still the one that was
+// archived, so events added during the export are never lost.
+//
+// KEYS: stream. ARGV: last archived entry ID.
+var deleteScript = redis.NewScript(`
+local last = redis.call('XREVRANGE', KEYS[1], '+', '-', 'COUNT', 1)
+if #last == 0 or last[1][1] ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// Archiver moves the streams of long-finished matches from Redis into a Store.
+type Archiver struct {
+	redis    *redis.Client
+	matches  MatchGetter
+	store    Store
+	logger   *logger.Logger
+	prefix   string
+	after    time.Duration
+	interval time.Duration
+}
+
+// NewArchiver creates a new stream archiver.
+func NewArchiver(redis *redis.Client, matches MatchGetter, store Store, logger *logger.Logger, cfg config.StreamConfig) *Archiver {
+	after := time.Duration(cfg.ArchiveAfterHours) * time.Hour
+	if after <= 0 {
+		after = 24 * time.Hour
+	}
+
+	interval := time.Duration(cfg.ArchiveIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	return &Archiver{
+		redis:    redis,
+		matches:  matches,
+		store:    store,
+		logger:   logger,
+		prefix:   cfg.ArchivePrefix,
+		after:    after,
+		interval: interval,
+	}
+}
+
+// Run archives eligible streams every interval until the context is canceled.
+func (a *Archiver) Run(ctx context.Context) {
+	a.logger.Info("Started stream archiver", "after", a.after, "interval", a.interval)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		a.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce archives every stream whose match finished more than the configured
+// number of hours ago.
+func (a *Archiver) RunOnce(ctx context.Context) {
+	var cursor uint64
+	for {
+		keys, next, err := a.redis.Scan(ctx, cursor, streamPattern, 100).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Error("Failed to scan match streams", "error", err)
+			}
+			return
+		}
+
+		for _, key := range keys {
+			matchID, ok := events.MatchIDFromStream(key)
+			if !ok || !a.eligible(ctx, key, matchID) {
+				continue
+			}
+			if err := a.Archive(ctx, matchID); err != nil && ctx.Err() == nil {
+				a.logger.Error("Failed to archive match stream", "error", err, "match_id", matchID)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// eligible reports whether a match has been finished for long enough, counted
+// from when its status changed to finished so later edits to the match do not
+// restart the window. Streams with an expiry were restored from an archive and
+// are left to expire.
+func (a *Archiver) eligible(ctx context.Context, key string, matchID int32) bool {
+	ttl, err := a.redis.TTL(ctx, key).Result()
+	if err != nil || ttl > 0 {
+		return false
+	}
+
+	match, err := a.matches.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return false
+	}
+	if match.Status != "finished" || !match.FinishedAt.Valid {
+		return false
+	}
+	return time.Since(match.FinishedAt.Time) >= a.after
+}
+
+// Archive exports a match stream to the store and deletes it from Redis. A stream
+// recreated after the match was archived, by a late correction for example, is
+// merged into the existing archive instead of replacing it.
+func (a *Archiver) Archive(ctx context.Context, matchID int32) error {
+	stream := events.StreamKey(matchID)
+
+	body, lastID, count, err := Export(ctx, a.redis, stream)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	key := Key(a.prefix, matchID)
+	existing, err := a.store.Get(ctx, key)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		body, count, err = Merge(existing, bytes.NewReader(body))
+		existing.Close()
+		if err != nil {
+			return fmt.Errorf("failed to merge with existing archive: %w", err)
+		}
+	}
+	if err := a.store.Put(ctx, key, body); err != nil {
+		return err
+	}
+
+	deleted, err := deleteScript.Run(ctx, a.redis, []string{stream}, lastID).Int()
+	if err != nil {
+		return fmt.Errorf("failed to delete archived stream: %w", err)
+	}
+	if deleted == 0 {
+		// New entries arrived; the next run archives the stream again in full.
+		a.logger.Warn("Match stream changed during archival, keeping it", "match_id", matchID)
+		return nil
+	}
+
+	a.logger.Info("Archived match stream", "match_id", matchID, "entries", count, "key", key, "bytes", len(body))
+	return nil
+}
+
+// Export reads a whole stream and returns it as gzip-compressed JSON lines, together
+// with the ID of the last entry and the number of entries.
+func Export(ctx context.Context, client *redis.Client, stream string) ([]byte, string, int, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+
+	start := "-"
+	lastID := ""
+	count := 0
+	for {
+		messages, err := client.XRangeN(ctx, stream, start, "+", pageSize).Result()
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("failed to read stream: %w", err)
+		}
+
+		for _, msg := range messages {
+			entry := Entry{ID: msg.ID, Values: make(map[string]string, len(msg.Values))}
+			for field, value := range msg.Values {
+				entry.Values[field] = fmt.Sprint(value)
+			}
+			if err := enc.Encode(entry); err != nil {
+				return nil, "", 0, fmt.Errorf("failed to encode stream entry: %w", err)
+			}
+			lastID = msg.ID
+			count++
+		}
+
+		if len(messages) < pageSize {
+			break
+		}
+		start = "(" + lastID
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, "", 0, fmt.Errorf("failed to compress stream: %w", err)
+	}
+	return buf.Bytes(), lastID, count, nil
+}
+
+// RestoreMatch loads a match's archive from the store into stream. Unless force is
+// set, it refuses to touch a stream that already exists; with force the stream is
+// replaced. A positive ttl expires the restored stream, which also keeps the
+// archiver from archiving it again.
+func RestoreMatch(ctx context.Context, client *redis.Client, store Store, prefix string, matchID int32, stream string, force bool, ttl time.Duration) (int, error) {
+	exists, err := client.Exists(ctx, stream).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check stream: %w", err)
+	}
+	if exists > 0 && !force {
+		return 0, ErrStreamExists
+	}
+
+	body, err := store.Get(ctx, Key(prefix, matchID))
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	if exists > 0 {
+		if err := client.Del(ctx, stream).Err(); err != nil {
+			return 0, fmt.Errorf("failed to delete existing stream: %w", err)
+		}
+	}
+
+	count, err := Restore(ctx, client, body, stream)
+	if err != nil {
+		return count, err
+	}
+	if ttl > 0 && count > 0 {
+		if err := client.Expire(ctx, stream, ttl).Err(); err != nil {
+			return count, fmt.Errorf("failed to set stream expiry: %w", err)
+		}
+	}
+	return count, nil
+}
+
+// Merge combines two archives into one with every entry once, in stream ID
+// order. Entries in both keep the version from next. It returns the merged
+// archive and its number of entries.
+func Merge(prev, next io.Reader) ([]byte, int, error) {
+	byID := map[string]Entry{}
+	collect := func(entry Entry) error {
+		byID[entry.ID] = entry
+		return nil
+	}
+	if _, err := readEntries(prev, collect); err != nil {
+		return nil, 0, err
+	}
+	if _, err := readEntries(next, collect); err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]Entry, 0, len(byID))
+	for _, entry := range byID {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return events.CompareStreamIDs(entries[i].ID, entries[j].ID) < 0
+	})
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return nil, 0, fmt.Errorf("failed to encode stream entry: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to compress stream: %w", err)
+	}
+	return buf.Bytes(), len(entries), nil
+}
+
+// Restore adds archived entries to a stream with their original IDs, so clients
+// can resume replay with the same Last-Event-ID. The stream must be empty or
+// only hold entries older than the archive.
+func Restore(ctx context.Context, client *redis.Client, r io.Reader, stream string) (int, error) {
+	pipe := client.Pipeline()
+	flush := func() error {
+		if pipe.Len() == 0 {
+			return nil
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to restore stream entries: %w", err)
+		}
+		return nil
+	}
+
+	count, err := readEntries(r, func(entry Entry) error {
+		values := make([]interface{}, 0, len(entry.Values)*2)
+		for field, value := range entry.Values {
+			values = append(values, field, value)
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: stream, ID: entry.ID, Values: values})
+		if pipe.Len() >= pageSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := flush(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// readEntries decodes an archive and calls fn with each entry in file order. It
+// returns the number of entries read.
+func readEntries(r io.Reader, fn func(Entry) error) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return count, fmt.Errorf("failed to decode archive line %d: %w", count+1, err)
+		}
+		count++
+		if err := fn(entry); err != nil {
+			return count, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return count, nil
+}
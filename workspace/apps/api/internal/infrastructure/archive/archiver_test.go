@@ -0,0 +1,54 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveOf builds an archive holding the entries.
+func archiveOf(t *testing.T, entries ...Entry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, entry := range entries {
+		require.NoError(t, enc.Encode(entry))
+	}
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestMerge(t *testing.T) {
+	prev := archiveOf(t,
+		Entry{ID: "1700000000000-0", Values: map[string]string{"type": "kick_off"}},
+		Entry{ID: "1700000000000-1", Values: map[string]string{"type": "pass"}},
+		Entry{ID: "1700000009000-0", Values: map[string]string{"type": "full_time"}},
+	)
+	// The stream recreated by a late correction only holds the new tail
+	next := archiveOf(t,
+		Entry{ID: "1700000000000-1", Values: map[string]string{"type": "pass", "corrected": "1"}},
+		Entry{ID: "1700090000000-0", Values: map[string]string{"type": "goal"}},
+	)
+
+	body, count, err := Merge(bytes.NewReader(prev), bytes.NewReader(next))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	var ids []string
+	var corrected string
+	n, err := readEntries(bytes.NewReader(body), func(entry Entry) error {
+		ids = append(ids, entry.ID)
+		if entry.ID == "1700000000000-1" {
+			corrected = entry.Values["corrected"]
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []string{"1700000000000-0", "1700000000000-1", "1700000009000-0", "1700090000000-0"}, ids)
+	assert.Equal(t, "1", corrected, "the newer copy of an entry wins")
+}
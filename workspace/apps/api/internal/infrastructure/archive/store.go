@@ -0,0 +1,108 @@
+// Package archive moves finished match event streams out of Redis into compressed
+// JSONL files and restores them for replay.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emiliospot/footie/api/internal/config"
+)
+
+// ErrNotFound is returned when an archive does not exist.
+var ErrNotFound = errors.New("archive not found")
+
+// ErrStreamExists is returned when restoring into a stream that already exists.
+var ErrStreamExists = errors.New("stream already exists")
+
+// Store persists archive files.
+type Store interface {
+	// Put writes an object, replacing any existing one.
+	Put(ctx context.Context, key string, body []byte) error
+	// Get opens an object for reading. It returns ErrNotFound if the key does not exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// NewStore creates the store selected by the stream configuration.
+func NewStore(streams config.StreamConfig, aws config.AWSConfig) (Store, error) {
+	switch strings.ToLower(streams.ArchiveStore) {
+	case "", "local":
+		return NewLocalStore(streams.ArchiveDir), nil
+	case "s3":
+		if aws.S3Bucket == "" {
+			return nil, fmt.Errorf("AWS_S3_BUCKET is required for the s3 archive store")
+		}
+		return NewS3Store(aws), nil
+	default:
+		return nil, fmt.Errorf("unknown archive store %q", streams.ArchiveStore)
+	}
+}
+
+// LocalStore keeps archives in a directory on local disk.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store rooted at dir.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Put writes the object atomically by renaming a temporary file into place.
+func (s *LocalStore) Put(_ context.Context, key string, body []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move archive file into place: %w", err)
+	}
+	return nil
+}
+
+// Get opens the object for reading.
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	return f, nil
+}
+
+// path resolves a key inside the store directory, rejecting keys that escape it.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid archive key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
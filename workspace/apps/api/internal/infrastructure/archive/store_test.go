@@ -0,0 +1,46 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	key := Key("/match-streams/", 42)
+	assert.Equal(t, "match-streams/42.jsonl.gz", key)
+
+	require.NoError(t, store.Put(ctx, key, []byte("first")))
+	require.NoError(t, store.Put(ctx, key, []byte("second")))
+
+	r, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	body, err := io.ReadAll(r)
+	require.NoError(t, r.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(body))
+
+	_, err = store.Get(ctx, Key("match-streams", 7))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_KeysStayInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(filepath.Join(dir, "archive"))
+
+	require.NoError(t, store.Put(context.Background(), "../../escape.jsonl.gz", []byte("x")))
+
+	_, err := os.Stat(filepath.Join(dir, "archive", "escape.jsonl.gz"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "escape.jsonl.gz"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/emiliospot/footie/api/internal/config"
+	domainEvents "github.com/emiliospot/footie/api/internal/domain/events"
 	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
 )
 
@@ -15,30 +19,53 @@ import (
 type Publisher struct {
 	redis  *redis.Client
 	logger *logger.Logger
+
+	// Approximate cap on each match stream; 0 disables trimming.
+	streamMaxLen int64
 }
 
 // MatchEvent represents a football match event.
 type MatchEvent struct {
-	ID                int32     `json:"id"`
-	MatchID           int32     `json:"match_id"`
-	TeamID            *int32    `json:"team_id,omitempty"`
-	PlayerID          *int32    `json:"player_id,omitempty"`
-	SecondaryPlayerID *int32    `json:"secondary_player_id,omitempty"`
-	EventType         string    `json:"event_type"` // goal, shot, pass, card, substitution
-	Minute            int       `json:"minute"`
-	Second            *int      `json:"second,omitempty"` // Exact second (0-59)
-	Period            string    `json:"period,omitempty"` // first_half, second_half, extra_time_first, extra_time_second, penalties
-	ExtraMinute       int       `json:"extra_minute,omitempty"`
-	PositionX         *float64  `json:"position_x,omitempty"`
-	PositionY         *float64  `json:"position_y,omitempty"`
-	Description       string    `json:"description,omitempty"`
-	Metadata          string    `json:"metadata,omitempty"` // JSON string with xG, pass completion, etc.
-	Timestamp         time.Time `json:"timestamp"`
+	ID                int32                   `json:"id"`
+	MatchID           int32                   `json:"match_id"`
+	Competition       string                  `json:"competition,omitempty"` // Used to filter the live scores ticker
+	TeamID            *int32                  `json:"team_id,omitempty"`
+	PlayerID          *int32                  `json:"player_id,omitempty"`
+	SecondaryPlayerID *int32                  `json:"secondary_player_id,omitempty"`
+	EventType         string                  `json:"event_type"` // goal, shot, pass, card, substitution
+	Minute            int                     `json:"minute"`
+	Second            *int                    `json:"second,omitempty"` // Exact second (0-59)
+	Period            string                  `json:"period,omitempty"` // first_half, second_half, extra_time_first, extra_time_second, penalties
+	ExtraMinute       int                     `json:"extra_minute,omitempty"`
+	Clock             domainEvents.MatchClock `json:"clock"` // Canonical match clock; Minute, Second, Period and ExtraMinute mirror it
+	PositionX         *float64                `json:"position_x,omitempty"`
+	PositionY         *float64                `json:"position_y,omitempty"`
+	Description       string                  `json:"description,omitempty"`
+	Metadata          string                  `json:"metadata,omitempty"` // JSON string with xG, pass completion, etc.
+	Timestamp         time.Time               `json:"timestamp"`
+}
+
+// SetClock sets the event's match clock and the minute, second, period and
+// extra (stoppage) minute derived from it.
+func (e *MatchEvent) SetClock(clock domainEvents.MatchClock) {
+	second := int(clock.Second)
+	e.Clock = clock
+	e.Minute = int(clock.Minute)
+	e.Second = &second
+	e.Period = clock.Period.String()
+	e.ExtraMinute = int(clock.Stoppage)
+}
+
+// StreamEntry is a match event read back from a match's Redis stream.
+type StreamEntry struct {
+	ID    string
+	Event MatchEvent
 }
 
 // ScoreUpdate represents a match score update.
 type ScoreUpdate struct {
 	MatchID       int32     `json:"match_id"`
+	Competition   string    `json:"competition,omitempty"`
 	HomeTeamScore int       `json:"home_team_score"`
 	AwayTeamScore int       `json:"away_team_score"`
 	Timestamp     time.Time `json:"timestamp"`
@@ -46,16 +73,18 @@ type ScoreUpdate struct {
 
 // MatchStatusUpdate represents a match status change.
 type MatchStatusUpdate struct {
-	MatchID   int32     `json:"match_id"`
-	Status    string    `json:"status"` // scheduled, live, finished, postponed, canceled
-	Timestamp time.Time `json:"timestamp"`
+	MatchID     int32     `json:"match_id"`
+	Competition string    `json:"competition,omitempty"`
+	Status      string    `json:"status"` // scheduled, live, finished, postponed, canceled
+	Timestamp   time.Time `json:"timestamp"`
 }
 
 // NewPublisher creates a new event publisher.
-func NewPublisher(redis *redis.Client, logger *logger.Logger) *Publisher {
+func NewPublisher(redis *redis.Client, logger *logger.Logger, cfg config.StreamConfig) *Publisher {
 	return &Publisher{
-		redis:  redis,
-		logger: logger,
+		redis:        redis,
+		logger:       logger,
+		streamMaxLen: cfg.MaxLen,
 	}
 }
 
@@ -69,25 +98,31 @@ func (p *Publisher) PublishMatchEvent(ctx context.Context, event *MatchEvent) er
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// 1. Add to Redis Stream for processing/analytics
-	streamKey := fmt.Sprintf("match:%d:stream", event.MatchID)
-	if err := p.redis.XAdd(ctx, &redis.XAddArgs{
+	// 1. Add to Redis Stream for processing/analytics.
+	// Approximate trimming keeps XADD cheap; the full stream is archived once the match is over.
+	streamKey := StreamKey(event.MatchID)
+	streamID, err := p.redis.XAdd(ctx, &redis.XAddArgs{
 		Stream: streamKey,
+		MaxLen: p.streamMaxLen,
+		Approx: p.streamMaxLen > 0,
 		Values: map[string]interface{}{
 			"event_type": event.EventType,
 			"data":       string(eventJSON),
 			"timestamp":  event.Timestamp.Unix(),
 		},
-	}).Err(); err != nil {
+	}).Result()
+	if err != nil {
 		p.logger.Error("Failed to add event to stream", "error", err, "match_id", event.MatchID)
 		return fmt.Errorf("failed to add to stream: %w", err)
 	}
 
 	// 2. Publish to Pub/Sub for real-time WebSocket delivery
 	channel := fmt.Sprintf("match:%d:events", event.MatchID)
+	// The stream ID lets SSE clients resume from this event with Last-Event-ID.
 	message := map[string]interface{}{
 		"type":      "match_event",
 		"match_id":  event.MatchID,
+		"stream_id": streamID,
 		"timestamp": event.Timestamp,
 		"data":      event,
 	}
@@ -102,6 +137,19 @@ func (p *Publisher) PublishMatchEvent(ctx context.Context, event *MatchEvent) er
 		return fmt.Errorf("failed to publish: %w", err)
 	}
 
+	// 3. Forward goals and sendings-off to the live scores ticker
+	if IsTickerEvent(event.EventType) {
+		minute := event.Minute
+		p.publishTicker(ctx, TickerTypeEvent, &TickerUpdate{
+			MatchID:     event.MatchID,
+			Competition: event.Competition,
+			EventType:   strings.ToLower(event.EventType),
+			TeamID:      event.TeamID,
+			PlayerID:    event.PlayerID,
+			Minute:      &minute,
+		}, event.Timestamp)
+	}
+
 	p.logger.Info("Published match event",
 		"match_id", event.MatchID,
 		"event_type", event.EventType,
@@ -111,6 +159,92 @@ func (p *Publisher) PublishMatchEvent(ctx context.Context, event *MatchEvent) er
 	return nil
 }
 
+// StreamKey returns the Redis stream key holding a match's events.
+func StreamKey(matchID int32) string {
+	return fmt.Sprintf("match:%d:stream", matchID)
+}
+
+// MatchIDFromStream extracts the match ID from a stream key built by StreamKey.
+func MatchIDFromStream(stream string) (int32, bool) {
+	idPart := strings.TrimSuffix(strings.TrimPrefix(stream, "match:"), ":stream")
+	id, err := strconv.ParseInt(idPart, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// ReplayMatchEvents returns up to count events recorded after the given stream ID, oldest first.
+func (p *Publisher) ReplayMatchEvents(ctx context.Context, matchID int32, afterID string, count int64) ([]StreamEntry, error) {
+	start := "-"
+	if afterID != "" {
+		start = "(" + afterID
+	}
+
+	messages, err := p.redis.XRangeN(ctx, StreamKey(matchID), start, "+", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	entries := make([]StreamEntry, 0, len(messages))
+	for _, msg := range messages {
+		data, ok := msg.Values["data"].(string)
+		if !ok {
+			continue
+		}
+
+		var event MatchEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			p.logger.Warn("Skipping malformed stream entry", "error", err, "match_id", matchID, "stream_id", msg.ID)
+			continue
+		}
+		entries = append(entries, StreamEntry{ID: msg.ID, Event: event})
+	}
+
+	return entries, nil
+}
+
+// CompareStreamIDs orders two Redis stream IDs ("<ms>-<seq>").
+// It returns -1, 0 or 1 like strings.Compare; malformed IDs sort first.
+func CompareStreamIDs(a, b string) int {
+	aMs, aSeq := splitStreamID(a)
+	bMs, bSeq := splitStreamID(b)
+
+	switch {
+	case aMs < bMs:
+		return -1
+	case aMs > bMs:
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ValidStreamID reports whether id is a complete Redis stream ID.
+func ValidStreamID(id string) bool {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	if _, err := strconv.ParseUint(msPart, 10, 64); err != nil {
+		return false
+	}
+	_, err := strconv.ParseUint(seqPart, 10, 64)
+	return err == nil
+}
+
+// splitStreamID parses the millisecond and sequence parts of a stream ID.
+func splitStreamID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
 // PublishScoreUpdate publishes a score update.
 func (p *Publisher) PublishScoreUpdate(ctx context.Context, update *ScoreUpdate) error {
 	update.Timestamp = time.Now()
@@ -133,6 +267,13 @@ func (p *Publisher) PublishScoreUpdate(ctx context.Context, update *ScoreUpdate)
 		return fmt.Errorf("failed to publish score update: %w", err)
 	}
 
+	p.publishTicker(ctx, TickerTypeScore, &TickerUpdate{
+		MatchID:       update.MatchID,
+		Competition:   update.Competition,
+		HomeTeamScore: &update.HomeTeamScore,
+		AwayTeamScore: &update.AwayTeamScore,
+	}, update.Timestamp)
+
 	p.logger.Info("Published score update",
 		"match_id", update.MatchID,
 		"home_score", update.HomeTeamScore,
@@ -164,6 +305,12 @@ func (p *Publisher) PublishMatchStatusUpdate(ctx context.Context, update *MatchS
 		return fmt.Errorf("failed to publish status update: %w", err)
 	}
 
+	p.publishTicker(ctx, TickerTypeStatus, &TickerUpdate{
+		MatchID:     update.MatchID,
+		Competition: update.Competition,
+		Status:      update.Status,
+	}, update.Timestamp)
+
 	p.logger.Info("Published match status update",
 		"match_id", update.MatchID,
 		"status", update.Status,
@@ -172,6 +319,77 @@ func (p *Publisher) PublishMatchStatusUpdate(ctx context.Context, update *MatchS
 	return nil
 }
 
+// PublishLiveStats publishes running in-match statistics for a match.
+func (p *Publisher) PublishLiveStats(ctx context.Context, matchID int32, stats interface{}) error {
+	channel := fmt.Sprintf("match:%d:events", matchID)
+	message := map[string]interface{}{
+		"type":      "live_stats",
+		"match_id":  matchID,
+		"timestamp": time.Now(),
+		"data":      stats,
+	}
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal live stats: %w", err)
+	}
+
+	if err := p.redis.Publish(ctx, channel, messageJSON).Err(); err != nil {
+		p.logger.Error("Failed to publish live stats", "error", err, "match_id", matchID)
+		return fmt.Errorf("failed to publish live stats: %w", err)
+	}
+
+	return nil
+}
+
+// PublishXGTimeline publishes a match's cumulative xG timeline. It is sent in
+// full after every shot, so clients can replace their copy.
+func (p *Publisher) PublishXGTimeline(ctx context.Context, matchID int32, timeline interface{}) error {
+	channel := fmt.Sprintf("match:%d:events", matchID)
+	message := map[string]interface{}{
+		"type":      "xg_timeline",
+		"match_id":  matchID,
+		"timestamp": time.Now(),
+		"data":      timeline,
+	}
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal xG timeline: %w", err)
+	}
+
+	if err := p.redis.Publish(ctx, channel, messageJSON).Err(); err != nil {
+		p.logger.Error("Failed to publish xG timeline", "error", err, "match_id", matchID)
+		return fmt.Errorf("failed to publish xG timeline: %w", err)
+	}
+
+	return nil
+}
+
+// PublishWinProbability publishes a match's in-play win probabilities after an
+// event. Each message is one point of the timeline.
+func (p *Publisher) PublishWinProbability(ctx context.Context, matchID int32, point interface{}) error {
+	channel := fmt.Sprintf("match:%d:events", matchID)
+	message := map[string]interface{}{
+		"type":      "win_probability",
+		"match_id":  matchID,
+		"timestamp": time.Now(),
+		"data":      point,
+	}
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal win probability: %w", err)
+	}
+
+	if err := p.redis.Publish(ctx, channel, messageJSON).Err(); err != nil {
+		p.logger.Error("Failed to publish win probability", "error", err, "match_id", matchID)
+		return fmt.Errorf("failed to publish win probability: %w", err)
+	}
+
+	return nil
+}
+
 // InvalidateMatchCache invalidates cached match data.
 func (p *Publisher) InvalidateMatchCache(ctx context.Context, matchID int32) error {
 	keys := []string{
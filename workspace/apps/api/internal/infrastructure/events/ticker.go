@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// TickerChannel is the Redis pub/sub channel carrying compact updates for every live match.
+const TickerChannel = "ticker:live"
+
+// Ticker message types.
+const (
+	TickerTypeScore    = "ticker_score"
+	TickerTypeStatus   = "ticker_status"
+	TickerTypeEvent    = "ticker_event"
+	TickerTypeSnapshot = "ticker_snapshot"
+)
+
+// tickerEventTypes are the match events worth showing on a live scores ticker.
+var tickerEventTypes = map[string]bool{
+	"goal":               true,
+	"own_goal":           true,
+	"penalty_goal":       true,
+	"red_card":           true,
+	"second_yellow_card": true,
+}
+
+// IsTickerEvent reports whether a match event type is forwarded to the ticker.
+func IsTickerEvent(eventType string) bool {
+	return tickerEventTypes[strings.ToLower(eventType)]
+}
+
+// TickerUpdate is a compact live scores ticker entry.
+type TickerUpdate struct {
+	MatchID       int32  `json:"match_id"`
+	Competition   string `json:"competition,omitempty"`
+	HomeTeamID    *int32 `json:"home_team_id,omitempty"` // Snapshot entries only
+	AwayTeamID    *int32 `json:"away_team_id,omitempty"` // Snapshot entries only
+	HomeTeamScore *int   `json:"home_team_score,omitempty"`
+	AwayTeamScore *int   `json:"away_team_score,omitempty"`
+	Status        string `json:"status,omitempty"`
+	EventType     string `json:"event_type,omitempty"`
+	TeamID        *int32 `json:"team_id,omitempty"`
+	PlayerID      *int32 `json:"player_id,omitempty"`
+	Minute        *int   `json:"minute,omitempty"`
+}
+
+// publishTicker publishes a ticker update. Failures are logged rather than returned
+// because the per-match publish has already succeeded.
+func (p *Publisher) publishTicker(ctx context.Context, msgType string, update *TickerUpdate, timestamp time.Time) {
+	message := map[string]interface{}{
+		"type":        msgType,
+		"match_id":    update.MatchID,
+		"competition": update.Competition,
+		"timestamp":   timestamp,
+		"data":        update,
+	}
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		p.logger.Error("Failed to marshal ticker update", "error", err, "match_id", update.MatchID)
+		return
+	}
+
+	if err := p.redis.Publish(ctx, TickerChannel, messageJSON).Err(); err != nil {
+		p.logger.Error("Failed to publish ticker update", "error", err, "match_id", update.MatchID)
+	}
+}
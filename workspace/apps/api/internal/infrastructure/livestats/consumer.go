@@ -0,0 +1,356 @@
+package livestats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
+)
+
+const (
+	// streamPattern matches every match event stream.
+	streamPattern = "match:*:stream"
+
+	// discoveryInterval is how often new match streams are picked up.
+	discoveryInterval = 10 * time.Second
+
+	// readBlock is how long XREADGROUP waits for new entries.
+	readBlock = 2 * time.Second
+
+	// readCount is the maximum number of entries read per stream per call.
+	readCount = 100
+
+	// claimMinIdle is how long an entry must sit unacknowledged with another consumer
+	// before this consumer takes it over.
+	claimMinIdle = time.Minute
+)
+
+// Consumer reads match event streams through a Redis consumer group, keeps running
+// team stats in Redis and publishes throttled live_stats messages.
+type Consumer struct {
+	redis     *redis.Client
+	publisher *events.Publisher
+	logger    *logger.Logger
+
+	group    string
+	name     string
+	interval time.Duration
+	ttl      time.Duration
+
+	// Known streams, only touched by the Run goroutine.
+	streams map[string]bool
+
+	// Matches with unpublished changes.
+	dirty map[int32]bool
+	mu    sync.Mutex
+}
+
+// NewConsumer creates a new live stats consumer.
+func NewConsumer(redis *redis.Client, publisher *events.Publisher, logger *logger.Logger, cfg config.LiveStatsConfig) *Consumer {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = "api"
+	}
+
+	group := cfg.ConsumerGroup
+	if group == "" {
+		group = "live-stats"
+	}
+
+	interval := time.Duration(cfg.PublishIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	ttl := time.Duration(cfg.TTLHours) * time.Hour
+	if ttl <= 0 {
+		ttl = 48 * time.Hour
+	}
+
+	return &Consumer{
+		redis:     redis,
+		publisher: publisher,
+		logger:    logger,
+		group:     group,
+		name:      name,
+		interval:  interval,
+		ttl:       ttl,
+		streams:   make(map[string]bool),
+		dirty:     make(map[int32]bool),
+	}
+}
+
+// Run consumes the match streams until the context is canceled.
+func (c *Consumer) Run(ctx context.Context) {
+	go c.publishLoop(ctx)
+
+	c.logger.Info("Started live stats consumer", "group", c.group, "consumer", c.name)
+
+	c.discover(ctx)
+	c.recoverPending(ctx)
+
+	lastDiscovery := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastDiscovery) >= discoveryInterval {
+			c.discover(ctx)
+			c.claimStale(ctx)
+			lastDiscovery = time.Now()
+		}
+
+		if len(c.streams) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(readBlock):
+			}
+			continue
+		}
+
+		c.read(ctx)
+	}
+}
+
+// discover finds match streams and makes sure the consumer group exists on each.
+func (c *Consumer) discover(ctx context.Context) {
+	var cursor uint64
+	for {
+		keys, next, err := c.redis.Scan(ctx, cursor, streamPattern, 100).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error("Failed to scan match streams", "error", err)
+			}
+			return
+		}
+
+		for _, key := range keys {
+			if c.streams[key] {
+				continue
+			}
+			// Start from the beginning so stats cover events published before the group existed.
+			err := c.redis.XGroupCreate(ctx, key, c.group, "0").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				c.logger.Error("Failed to create consumer group", "error", err, "stream", key)
+				continue
+			}
+			c.streams[key] = true
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return
+		}
+	}
+}
+
+// recoverPending applies entries delivered to this consumer before a restart. They were
+// never counted, because applying and acknowledging happen atomically.
+func (c *Consumer) recoverPending(ctx context.Context) {
+	for key := range c.streams {
+		lastID := "0"
+		for ctx.Err() == nil {
+			result, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    c.group,
+				Consumer: c.name,
+				Streams:  []string{key, lastID},
+				Count:    readCount,
+				Block:    -1,
+			}).Result()
+			if err != nil || len(result) == 0 || len(result[0].Messages) == 0 {
+				break
+			}
+			for _, msg := range result[0].Messages {
+				c.apply(ctx, key, msg)
+				lastID = msg.ID
+			}
+		}
+	}
+}
+
+// read waits for new entries on every known stream and applies them.
+func (c *Consumer) read(ctx context.Context) {
+	keys := make([]string, 0, len(c.streams)*2)
+	for key := range c.streams {
+		keys = append(keys, key)
+	}
+	for range c.streams {
+		keys = append(keys, ">")
+	}
+
+	result, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.name,
+		Streams:  keys,
+		Count:    readCount,
+		Block:    readBlock,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+			return
+		}
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			// A stream was deleted (e.g. archived); rediscover from scratch.
+			c.streams = make(map[string]bool)
+			c.discover(ctx)
+			return
+		}
+		c.logger.Error("Failed to read match streams", "error", err)
+		time.Sleep(time.Second)
+		return
+	}
+
+	for _, stream := range result {
+		for _, msg := range stream.Messages {
+			c.apply(ctx, stream.Stream, msg)
+		}
+	}
+}
+
+// claimStale takes over entries left pending by consumers that went away.
+func (c *Consumer) claimStale(ctx context.Context) {
+	for key := range c.streams {
+		messages, _, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   key,
+			Group:    c.group,
+			Consumer: c.name,
+			MinIdle:  claimMinIdle,
+			Start:    "0",
+			Count:    readCount,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, redis.Nil) {
+				c.logger.Warn("Failed to claim stale entries", "error", err, "stream", key)
+			}
+			continue
+		}
+		for _, msg := range messages {
+			c.apply(ctx, key, msg)
+		}
+	}
+}
+
+// applyScript acknowledges a stream entry and, only if this call acknowledged it,
+// applies its counter increments. Entries claimed by another consumer in the meantime
+// are therefore never counted twice.
+//
+// KEYS: stream, stats hash. ARGV: group, entry ID, TTL seconds, xG field, xG value,
+// then counter field/increment pairs.
+var applyScript = redis.NewScript(`
+if redis.call('XACK', KEYS[1], ARGV[1], ARGV[2]) == 0 then
+	return 0
+end
+for i = 6, #ARGV, 2 do
+	redis.call('HINCRBY', KEYS[2], ARGV[i], ARGV[i + 1])
+end
+if ARGV[5] ~= '' then
+	redis.call('HINCRBYFLOAT', KEYS[2], ARGV[4], ARGV[5])
+end
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+return 1
+`)
+
+// apply updates the running stats for one stream entry and acknowledges it atomically,
+// so an entry is never counted twice or lost.
+func (c *Consumer) apply(ctx context.Context, stream string, msg redis.XMessage) {
+	matchID, ok := events.MatchIDFromStream(stream)
+	if !ok {
+		c.ack(ctx, stream, msg.ID)
+		return
+	}
+
+	data, _ := msg.Values["data"].(string)
+	var event events.MatchEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		c.logger.Warn("Skipping malformed stream entry", "error", err, "stream", stream, "id", msg.ID)
+		c.ack(ctx, stream, msg.ID)
+		return
+	}
+
+	d, ok := deltaFor(&event)
+	if !ok {
+		c.ack(ctx, stream, msg.ID)
+		return
+	}
+
+	xg := ""
+	if d.xg != 0 {
+		xg = strconv.FormatFloat(d.xg, 'f', -1, 64)
+	}
+	args := []interface{}{c.group, msg.ID, int64(c.ttl / time.Second), hashField(d.teamID, fieldXG), xg}
+	for counter, n := range d.counts {
+		args = append(args, hashField(d.teamID, counter), n)
+	}
+
+	applied, err := applyScript.Run(ctx, c.redis, []string{stream, StatsKey(matchID)}, args...).Int()
+	if err != nil {
+		// Left pending; it is retried after a restart or claimed by another consumer.
+		c.logger.Error("Failed to update live stats", "error", err, "match_id", matchID, "id", msg.ID)
+		return
+	}
+	if applied == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	c.dirty[matchID] = true
+	c.mu.Unlock()
+}
+
+// ack acknowledges an entry that does not affect the stats.
+func (c *Consumer) ack(ctx context.Context, stream, id string) {
+	if err := c.redis.XAck(ctx, stream, c.group, id).Err(); err != nil && ctx.Err() == nil {
+		c.logger.Warn("Failed to acknowledge stream entry", "error", err, "stream", stream, "id", id)
+	}
+}
+
+// publishLoop publishes live_stats for changed matches at most once per interval.
+func (c *Consumer) publishLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			dirty := c.dirty
+			c.dirty = make(map[int32]bool)
+			c.mu.Unlock()
+
+			for matchID := range dirty {
+				if err := c.publish(ctx, matchID); err != nil && ctx.Err() == nil {
+					c.logger.Error("Failed to publish live stats", "error", err, "match_id", matchID)
+				}
+			}
+		}
+	}
+}
+
+// publish reads a match's running stats and publishes them.
+func (c *Consumer) publish(ctx context.Context, matchID int32) error {
+	stats, err := Load(ctx, c.redis, matchID)
+	if err != nil {
+		return err
+	}
+	return c.publisher.PublishLiveStats(ctx, matchID, stats)
+}
+
+// Load reads the running stats for a match.
+func Load(ctx context.Context, client *redis.Client, matchID int32) (*MatchStats, error) {
+	hash, err := client.HGetAll(ctx, StatsKey(matchID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read live stats: %w", err)
+	}
+	return parseStats(matchID, hash), nil
+}
@@ -0,0 +1,242 @@
+// Package livestats maintains running in-match team statistics from the match event streams.
+package livestats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domainEvents "github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+)
+
+// Counters kept per team in the live stats hash.
+const (
+	fieldShots           = "shots"
+	fieldShotsOnTarget   = "shots_on_target"
+	fieldXG              = "xg"
+	fieldPasses          = "passes"
+	fieldPassesCompleted = "passes_completed"
+	fieldCorners         = "corners"
+	fieldFouls           = "fouls"
+	fieldYellowCards     = "yellow_cards"
+	fieldRedCards        = "red_cards"
+	fieldTouches         = "touches"
+)
+
+// StatsKey returns the Redis hash holding a match's running stats.
+// It is deliberately not match:{id}:stats, which InvalidateMatchCache deletes.
+func StatsKey(matchID int32) string {
+	return fmt.Sprintf("match:%d:live_stats", matchID)
+}
+
+// TeamStats are the running statistics for one team in a match.
+type TeamStats struct {
+	TeamID          int32   `json:"team_id"`
+	Shots           int64   `json:"shots"`
+	ShotsOnTarget   int64   `json:"shots_on_target"`
+	XG              float64 `json:"xg"`
+	Passes          int64   `json:"passes"`
+	PassesCompleted int64   `json:"passes_completed"`
+	PassAccuracy    float64 `json:"pass_accuracy"` // Percentage of completed passes
+	Corners         int64   `json:"corners"`
+	Fouls           int64   `json:"fouls"`
+	YellowCards     int64   `json:"yellow_cards"`
+	RedCards        int64   `json:"red_cards"`
+	Possession      float64 `json:"possession"` // Share of on-ball events, in percent
+}
+
+// MatchStats are the running statistics for every team in a match.
+type MatchStats struct {
+	MatchID   int32       `json:"match_id"`
+	Teams     []TeamStats `json:"teams"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// delta is the change a single event makes to its team's counters.
+type delta struct {
+	teamID int32
+	counts map[string]int64
+	xg     float64
+}
+
+// shotOutcomesOnTarget are provider shot outcomes that count as on target.
+var shotOutcomesOnTarget = map[string]bool{
+	"goal":          true,
+	"saved":         true,
+	"saved to post": true,
+	"on target":     true,
+}
+
+// passOutcomesIncomplete are provider pass outcomes that mean the pass failed.
+// Passes without an outcome count as completed, following the StatsBomb convention.
+var passOutcomesIncomplete = map[string]bool{
+	"incomplete":       true,
+	"out":              true,
+	"unknown":          true,
+	"pass offside":     true,
+	"injury clearance": true,
+	"unsuccessful":     true,
+}
+
+// deltaFor classifies an event into counter increments. It returns false for
+// events that do not belong to a team or do not affect any counter.
+func deltaFor(event *events.MatchEvent) (delta, bool) {
+	if event.TeamID == nil {
+		return delta{}, false
+	}
+
+	d := delta{teamID: *event.TeamID, counts: make(map[string]int64)}
+	meta := parseMetadata(event.Metadata)
+	outcome := strings.ToLower(metaString(meta, "outcome"))
+	eventType := domainEvents.Normalize(event.EventType)
+
+	switch {
+	case eventType.IsShot(), eventType == domainEvents.EventTypeGoal,
+		eventType == domainEvents.EventTypePenaltyGoal, eventType == domainEvents.EventTypePenaltyMiss:
+		d.counts[fieldShots]++
+		d.counts[fieldTouches]++
+		switch eventType {
+		case domainEvents.EventTypeGoal, domainEvents.EventTypePenaltyGoal,
+			domainEvents.EventTypeShotOnTarget, domainEvents.EventTypeShotSaved:
+			d.counts[fieldShotsOnTarget]++
+		default:
+			if shotOutcomesOnTarget[outcome] {
+				d.counts[fieldShotsOnTarget]++
+			}
+		}
+		if xg, ok := domainEvents.MetaFloat(meta, "xG", "xg"); ok {
+			d.xg = xg
+		}
+
+	case eventType.IsPass():
+		d.counts[fieldPasses]++
+		d.counts[fieldTouches]++
+		if eventType != domainEvents.EventTypePassIncomplete && !passOutcomesIncomplete[outcome] {
+			d.counts[fieldPassesCompleted]++
+		}
+
+	case eventType == domainEvents.EventTypeCorner:
+		d.counts[fieldCorners]++
+		d.counts[fieldTouches]++
+
+	case eventType == domainEvents.EventTypeFoul, eventType == domainEvents.EventTypeFoulCommitted:
+		d.counts[fieldFouls]++
+
+	case eventType == domainEvents.EventTypeYellowCard:
+		d.counts[fieldYellowCards]++
+
+	case eventType == domainEvents.EventTypeRedCard, eventType == domainEvents.EventTypeSecondYellow:
+		d.counts[fieldRedCards]++
+	}
+
+	if len(d.counts) == 0 && d.xg == 0 {
+		return delta{}, false
+	}
+	return d, true
+}
+
+// hashField returns the hash field for a team counter, e.g. "12:shots".
+func hashField(teamID int32, counter string) string {
+	return fmt.Sprintf("%d:%s", teamID, counter)
+}
+
+// parseStats builds match stats from the live stats hash and derives
+// pass accuracy and the possession proxy.
+func parseStats(matchID int32, hash map[string]string) *MatchStats {
+	teams := make(map[int32]*TeamStats)
+	touches := make(map[int32]int64)
+
+	for field, value := range hash {
+		teamPart, counter, ok := strings.Cut(field, ":")
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(teamPart, 10, 32)
+		if err != nil {
+			continue
+		}
+		teamID := int32(id)
+		team, ok := teams[teamID]
+		if !ok {
+			team = &TeamStats{TeamID: teamID}
+			teams[teamID] = team
+		}
+
+		if counter == fieldXG {
+			team.XG, _ = strconv.ParseFloat(value, 64)
+			continue
+		}
+		n, _ := strconv.ParseInt(value, 10, 64)
+		switch counter {
+		case fieldShots:
+			team.Shots = n
+		case fieldShotsOnTarget:
+			team.ShotsOnTarget = n
+		case fieldPasses:
+			team.Passes = n
+		case fieldPassesCompleted:
+			team.PassesCompleted = n
+		case fieldCorners:
+			team.Corners = n
+		case fieldFouls:
+			team.Fouls = n
+		case fieldYellowCards:
+			team.YellowCards = n
+		case fieldRedCards:
+			team.RedCards = n
+		case fieldTouches:
+			touches[teamID] = n
+		}
+	}
+
+	var totalTouches int64
+	for _, n := range touches {
+		totalTouches += n
+	}
+
+	stats := &MatchStats{MatchID: matchID, Teams: make([]TeamStats, 0, len(teams)), UpdatedAt: time.Now()}
+	for teamID, team := range teams {
+		if team.Passes > 0 {
+			team.PassAccuracy = round1(float64(team.PassesCompleted) / float64(team.Passes) * 100)
+		}
+		if totalTouches > 0 {
+			team.Possession = round1(float64(touches[teamID]) / float64(totalTouches) * 100)
+		}
+		team.XG = round2(team.XG)
+		stats.Teams = append(stats.Teams, *team)
+	}
+	sort.Slice(stats.Teams, func(i, j int) bool { return stats.Teams[i].TeamID < stats.Teams[j].TeamID })
+
+	return stats
+}
+
+// parseMetadata decodes an event's metadata JSON, returning nil when it is empty or invalid.
+func parseMetadata(raw string) map[string]interface{} {
+	if raw == "" {
+		return nil
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		return nil
+	}
+	return meta
+}
+
+// metaString returns a string metadata value.
+func metaString(meta map[string]interface{}, key string) string {
+	s, _ := meta[key].(string)
+	return s
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,139 @@
+package livestats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+)
+
+func TestDeltaFor(t *testing.T) {
+	teamID := int32(10)
+
+	tests := []struct {
+		name      string
+		eventType string
+		metadata  string
+		noTeam    bool
+		want      map[string]int64
+		wantXG    float64
+		wantOK    bool
+	}{
+		{
+			name:      "statsbomb shot saved with xG",
+			eventType: "Shot",
+			metadata:  `{"xG": 0.12, "outcome": "Saved"}`,
+			want:      map[string]int64{fieldShots: 1, fieldShotsOnTarget: 1, fieldTouches: 1},
+			wantXG:    0.12,
+			wantOK:    true,
+		},
+		{
+			name:      "off target shot with string xg",
+			eventType: "shot_off_target",
+			metadata:  `{"xg": "0.05"}`,
+			want:      map[string]int64{fieldShots: 1, fieldTouches: 1},
+			wantXG:    0.05,
+			wantOK:    true,
+		},
+		{
+			name:      "goal is on target",
+			eventType: "goal",
+			want:      map[string]int64{fieldShots: 1, fieldShotsOnTarget: 1, fieldTouches: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "pass without outcome is completed",
+			eventType: "pass",
+			want:      map[string]int64{fieldPasses: 1, fieldPassesCompleted: 1, fieldTouches: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "incomplete pass",
+			eventType: "pass",
+			metadata:  `{"outcome": "Incomplete"}`,
+			want:      map[string]int64{fieldPasses: 1, fieldTouches: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "corner",
+			eventType: "corner",
+			want:      map[string]int64{fieldCorners: 1, fieldTouches: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "second yellow is a red card",
+			eventType: "second_yellow_card",
+			want:      map[string]int64{fieldRedCards: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "foul committed",
+			eventType: "foul_committed",
+			want:      map[string]int64{fieldFouls: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "substitution is ignored",
+			eventType: "substitution",
+		},
+		{
+			name:      "event without team is ignored",
+			eventType: "shot",
+			noTeam:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &events.MatchEvent{MatchID: 1, TeamID: &teamID, EventType: tt.eventType, Metadata: tt.metadata}
+			if tt.noTeam {
+				event.TeamID = nil
+			}
+
+			d, ok := deltaFor(event)
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, teamID, d.teamID)
+			assert.Equal(t, tt.want, d.counts)
+			assert.InDelta(t, tt.wantXG, d.xg, 1e-9)
+		})
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	hash := map[string]string{
+		"1:shots":            "4",
+		"1:shots_on_target":  "2",
+		"1:xg":               "1.234",
+		"1:passes":           "8",
+		"1:passes_completed": "6",
+		"1:touches":          "12",
+		"2:passes":           "4",
+		"2:passes_completed": "4",
+		"2:touches":          "4",
+		"2:red_cards":        "1",
+		"malformed":          "3",
+	}
+
+	stats := parseStats(7, hash)
+
+	assert.Equal(t, int32(7), stats.MatchID)
+	require.Len(t, stats.Teams, 2)
+
+	home, away := stats.Teams[0], stats.Teams[1]
+	assert.Equal(t, int32(1), home.TeamID)
+	assert.Equal(t, int64(4), home.Shots)
+	assert.Equal(t, int64(2), home.ShotsOnTarget)
+	assert.InDelta(t, 1.23, home.XG, 1e-9)
+	assert.InDelta(t, 75.0, home.PassAccuracy, 1e-9)
+	assert.InDelta(t, 75.0, home.Possession, 1e-9)
+
+	assert.Equal(t, int32(2), away.TeamID)
+	assert.Equal(t, int64(1), away.RedCards)
+	assert.InDelta(t, 100.0, away.PassAccuracy, 1e-9)
+	assert.InDelta(t, 25.0, away.Possession, 1e-9)
+}
@@ -45,3 +45,28 @@ type NormalizedEvent struct {
 	Description       string
 	Metadata          map[string]interface{} // provider-specific metadata (xG, pass completion, etc.)
 }
+
+// LineupProvider is implemented by providers whose feeds include match lineups.
+type LineupProvider interface {
+	Provider
+
+	// ExtractLineups extracts the lineups in a raw JSON payload. The payload can be
+	// either a single lineup object or an array of lineups.
+	ExtractLineups(ctx context.Context, payload []byte) ([]*Lineup, error)
+}
+
+// Lineup represents a team's lineup for a match in our internal format.
+type Lineup struct {
+	MatchID   int32
+	TeamID    int32
+	Formation string // e.g. "4-3-3"; empty if unknown
+	Players   []LineupPlayer
+}
+
+// LineupPlayer represents a player in a lineup.
+type LineupPlayer struct {
+	PlayerID    int32
+	ShirtNumber *int32
+	Position    string
+	Starter     bool // In the starting XI; otherwise on the bench
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
 	infraEvents "github.com/emiliospot/footie/api/internal/infrastructure/events"
 	"github.com/emiliospot/footie/api/internal/infrastructure/webhooks"
 )
@@ -31,7 +32,7 @@ type GenericPayload struct {
 	Minute            int32                   `json:"minute"`
 	Second            *int32                  `json:"second,omitempty"` // Exact second (0-59)
 	Period            *string                 `json:"period,omitempty"` // "first_half", "second_half", "extra_time_first", "extra_time_second", "penalties"
-	ExtraMinute       *int32                  `json:"extraMinute,omitempty"`
+	ExtraMinute       *int32                  `json:"extraMinute,omitempty"` // Stoppage minutes (2 for 45+2)
 	TeamID            *int32                  `json:"teamId,omitempty"`
 	PlayerID          *int32                  `json:"playerId,omitempty"`
 	SecondaryPlayerID *int32                  `json:"secondaryPlayerId,omitempty"`
@@ -39,6 +40,43 @@ type GenericPayload struct {
 	PositionY         *float64                `json:"positionY,omitempty"`
 	Description       string                 `json:"description,omitempty"`
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	// Pitch is the coordinate system of positionX/Y and end locations; defaults to 0-100 percentages
+	Pitch *GenericPitch `json:"pitch,omitempty"`
+	// AttackingDirection is the direction the event's team attacks in the first half
+	// ("left_to_right" or "right_to_left"). Positions are flipped for periods in which
+	// the team attacks right to left. When omitted, positions are assumed to be
+	// oriented with the team attacking left to right.
+	AttackingDirection *string `json:"attackingDirection,omitempty"`
+}
+
+// GenericPitch describes the coordinate system of a generic payload.
+type GenericPitch struct {
+	Length  float64 `json:"length"`
+	Width   float64 `json:"width"`
+	InvertY bool    `json:"invertY,omitempty"` // y is measured from the attacking team's right touchline
+}
+
+// frame returns the payload's coordinate system.
+func (gp *GenericPayload) frame() (pitch.Frame, error) {
+	if gp.Pitch == nil {
+		return pitch.Percent, nil
+	}
+	if gp.Pitch.Length <= 0 || gp.Pitch.Width <= 0 {
+		return pitch.Frame{}, fmt.Errorf("invalid pitch: %gx%g", gp.Pitch.Length, gp.Pitch.Width)
+	}
+	return pitch.Frame{Name: "generic", Length: gp.Pitch.Length, Width: gp.Pitch.Width, InvertY: gp.Pitch.InvertY}, nil
+}
+
+// direction returns the direction the event's team attacks in the event's period.
+func (gp *GenericPayload) direction(period string) (pitch.Direction, error) {
+	if gp.AttackingDirection == nil {
+		return pitch.LeftToRight, nil
+	}
+	d, ok := pitch.ParseDirection(*gp.AttackingDirection)
+	if !ok {
+		return pitch.LeftToRight, fmt.Errorf("invalid attacking direction: %s", *gp.AttackingDirection)
+	}
+	return d.ForPeriod(period), nil
 }
 
 // ExtractEvent extracts and transforms a single generic payload into our internal format.
@@ -96,66 +134,106 @@ func (p *GenericProvider) extractSingleEvent(genericPayload *GenericPayload) (*i
 		return nil, fmt.Errorf("invalid event type: %s", genericPayload.EventType)
 	}
 
-	// Normalize period (if provided, otherwise determine from minute)
-	var period string
+	// Normalize period (if provided, otherwise the clock determines it from minute)
+	period := events.PeriodRegular
 	if genericPayload.Period != nil {
-		normalizedPeriod := events.NormalizePeriod(*genericPayload.Period)
-		period = normalizedPeriod.String()
-	} else {
-		// Auto-determine period from minute and extra_minute
-		period = events.DeterminePeriod(genericPayload.Minute, genericPayload.ExtraMinute).String()
+		period = events.NormalizePeriod(*genericPayload.Period)
 	}
 
-	// Validate second (0-59)
-	var second *int32
+	// Validate second (0-59) and stoppage minutes
+	var second, stoppage int32
 	if genericPayload.Second != nil {
-		s := *genericPayload.Second
-		if s < 0 || s >= 60 {
-			return nil, fmt.Errorf("invalid second: %d (must be 0-59)", s)
+		second = *genericPayload.Second
+		if second < 0 || second >= 60 {
+			return nil, fmt.Errorf("invalid second: %d (must be 0-59)", second)
+		}
+	}
+	if genericPayload.ExtraMinute != nil {
+		stoppage = *genericPayload.ExtraMinute
+		if stoppage < 0 {
+			return nil, fmt.Errorf("invalid extra minute: %d", stoppage)
 		}
-		second = &s
 	}
+	clock := events.NewMatchClock(period, genericPayload.Minute, stoppage, second)
+
+	// Convert coordinates to the canonical pitch, keeping the originals in metadata
+	frame, err := genericPayload.frame()
+	if err != nil {
+		return nil, err
+	}
+	direction, err := genericPayload.direction(clock.Period.String())
+	if err != nil {
+		return nil, err
+	}
+	metadata := genericPayload.Metadata
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	posX, posY := pitch.Normalize(frame, direction, genericPayload.PositionX, genericPayload.PositionY, metadata)
 
 	// Convert metadata to JSON string
 	metadataJSON := ""
-	if genericPayload.Metadata != nil {
-		metadataBytes, err := json.Marshal(genericPayload.Metadata)
+	if len(metadata) > 0 {
+		metadataBytes, err := json.Marshal(metadata)
 		if err == nil {
 			metadataJSON = string(metadataBytes)
 		}
 	}
 
 	// Convert to infraEvents.MatchEvent (temporary ID, will be set after DB insert)
-	return &infraEvents.MatchEvent{
+	event := &infraEvents.MatchEvent{
 		ID:                0, // Will be set after DB insert
 		MatchID:           genericPayload.MatchID,
 		TeamID:            genericPayload.TeamID,
 		PlayerID:          genericPayload.PlayerID,
 		SecondaryPlayerID: genericPayload.SecondaryPlayerID,
 		EventType:         normalizedType.String(),
-		Minute:            int(genericPayload.Minute),
-		Second: func() *int {
-			if second != nil {
-				s := int(*second)
-				return &s
-			}
-			return nil
-		}(),
-		Period: period,
-		ExtraMinute: func() int {
-			if genericPayload.ExtraMinute != nil {
-				return int(*genericPayload.ExtraMinute)
-			}
-			return 0
-		}(),
-		PositionX:   genericPayload.PositionX,
-		PositionY:   genericPayload.PositionY,
-		Description: genericPayload.Description,
-		Metadata:    metadataJSON,
-	}, nil
+		PositionX:         posX,
+		PositionY:         posY,
+		Description:       genericPayload.Description,
+		Metadata:          metadataJSON,
+	}
+	event.SetClock(clock)
+	return event, nil
 }
 
 // VerifySignature verifies the HMAC SHA256 signature (standard implementation).
 func (p *GenericProvider) VerifySignature(payload []byte, signature string, secret string) bool {
 	return webhooks.VerifyHMACSignature(payload, signature, secret)
 }
+
+// GenericLineupPayload represents the expected format for generic lineup webhooks.
+type GenericLineupPayload struct {
+	MatchID   int32  `json:"matchId"`
+	TeamID    int32  `json:"teamId"`
+	Formation string `json:"formation,omitempty"` // e.g. "4-3-3"
+	Players   []struct {
+		PlayerID    int32  `json:"playerId"`
+		ShirtNumber *int32 `json:"shirtNumber,omitempty"`
+		Position    string `json:"position,omitempty"`
+		Starter     bool   `json:"starter"` // In the starting XI; otherwise on the bench
+	} `json:"players"`
+}
+
+// ExtractLineups extracts generic lineup payloads (single or batch) into our internal format.
+func (p *GenericProvider) ExtractLineups(ctx context.Context, payload []byte) ([]*webhooks.Lineup, error) {
+	batch, err := unmarshalLineups[GenericLineupPayload](payload)
+	if err != nil {
+		return nil, err
+	}
+
+	lineups := make([]*webhooks.Lineup, 0, len(batch))
+	for _, gp := range batch {
+		lineup := &webhooks.Lineup{MatchID: gp.MatchID, TeamID: gp.TeamID, Formation: gp.Formation}
+		for _, player := range gp.Players {
+			lineup.Players = append(lineup.Players, webhooks.LineupPlayer{
+				PlayerID:    player.PlayerID,
+				ShirtNumber: player.ShirtNumber,
+				Position:    player.Position,
+				Starter:     player.Starter,
+			})
+		}
+		lineups = append(lineups, lineup)
+	}
+	return lineups, nil
+}
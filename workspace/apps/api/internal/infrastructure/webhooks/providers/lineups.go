@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// unmarshalLineups parses a lineup payload that is either a single object or an
+// array of objects.
+func unmarshalLineups[T any](payload []byte) ([]T, error) {
+	var batch []T
+	if err := json.Unmarshal(payload, &batch); err == nil {
+		return batch, nil
+	}
+
+	var single T
+	if err := json.Unmarshal(payload, &single); err != nil {
+		return nil, fmt.Errorf("failed to parse payload as single lineup or batch: %w", err)
+	}
+	return []T{single}, nil
+}
+
+// shirtNumber returns a shirt number, or nil when the provider sent none.
+func shirtNumber(n int) *int32 {
+	if n <= 0 {
+		return nil
+	}
+	v := int32(n)
+	return &v
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
 	infraEvents "github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/webhooks"
 )
 
 // OptaProvider handles Opta Sports data feed format.
@@ -137,6 +140,10 @@ func (p *OptaProvider) extractSingleOptaEvent(optaPayload *OptaPayload) (*infraE
 		metadata[qualifier.Type] = qualifier.Value
 	}
 
+	// Opta coordinates are 0-100 percentages with y measured from the right
+	// touchline, already oriented so the team in possession attacks left to right
+	posX, posY = pitch.Normalize(pitch.Opta, pitch.LeftToRight, posX, posY, metadata)
+
 	// Normalize and validate event type
 	eventType := events.Normalize(optaPayload.Event.Type)
 	if !events.IsValid(eventType) {
@@ -152,56 +159,27 @@ func (p *OptaProvider) extractSingleOptaEvent(optaPayload *OptaPayload) (*infraE
 		}
 	}
 
-	// Normalize period from Opta format ("1H", "2H", "ET1", "ET2", "P")
+	// Normalize period from Opta format ("1H", "2H", "ET1", "ET2", "P"); the
+	// clock determines it from the minute if not provided
 	period := events.NormalizePeriod(optaPayload.Event.Period)
-	if period == events.PeriodRegular {
-		// Auto-determine if not provided
-		period = events.DeterminePeriod(int32(optaPayload.Event.Minute), nil)
-	}
 
-	// Extract second (0-59)
-	var second *int32
-	if optaPayload.Event.Second > 0 {
-		s := int32(optaPayload.Event.Second)
-		if s < 60 {
-			second = &s
-		}
-	}
+	// Opta minutes run continuously through stoppage time, like StatsBomb's
+	clock := events.NewMatchClock(period, int32(optaPayload.Event.Minute), 0, int32(optaPayload.Event.Second))
 
-	// Calculate extra minute from period
-	var extraMinute *int32
-	if period == events.PeriodExtraTimeFirst || period == events.PeriodExtraTimeSecond {
-		extraMin := int32(0)
-		extraMinute = &extraMin
-	}
-
-	return &infraEvents.MatchEvent{
+	event := &infraEvents.MatchEvent{
 		ID:                0,
 		MatchID:           matchID,
 		TeamID:            &teamID,
 		PlayerID:          playerID,
 		SecondaryPlayerID: nil,
 		EventType:         eventType.String(),
-		Minute:            optaPayload.Event.Minute,
-		Second: func() *int {
-			if second != nil {
-				s := int(*second)
-				return &s
-			}
-			return nil
-		}(),
-		Period: period.String(),
-		ExtraMinute: func() int {
-			if extraMinute != nil {
-				return int(*extraMinute)
-			}
-			return 0
-		}(),
-		PositionX:   posX,
-		PositionY:   posY,
-		Description: optaPayload.Event.Description,
-		Metadata:    metadataJSON,
-	}, nil
+		PositionX:         posX,
+		PositionY:         posY,
+		Description:       optaPayload.Event.Description,
+		Metadata:          metadataJSON,
+	}
+	event.SetClock(clock)
+	return event, nil
 }
 
 // VerifySignature verifies Opta's signature format (if they use one).
@@ -223,3 +201,54 @@ func (p *OptaProvider) parseID(idStr string) (int32, error) {
 	}
 	return int32(id), nil
 }
+
+// OptaLineupPayload represents the Opta lineup payload structure.
+type OptaLineupPayload struct {
+	Match struct {
+		ID string `json:"id"`
+	} `json:"match"`
+	TeamID        string `json:"teamId"`
+	FormationUsed string `json:"formationUsed,omitempty"` // e.g. "433"
+	Players       []struct {
+		PlayerID    string `json:"playerId"`
+		ShirtNumber int    `json:"shirtNumber,omitempty"`
+		Position    string `json:"position,omitempty"`
+		Status      string `json:"status"` // "Start" or "Sub"
+	} `json:"players"`
+}
+
+// ExtractLineups extracts Opta lineup payloads (single or batch) into our internal format.
+func (p *OptaProvider) ExtractLineups(ctx context.Context, payload []byte) ([]*webhooks.Lineup, error) {
+	batch, err := unmarshalLineups[OptaLineupPayload](payload)
+	if err != nil {
+		return nil, err
+	}
+
+	lineups := make([]*webhooks.Lineup, 0, len(batch))
+	for i, optaPayload := range batch {
+		matchID, err := p.parseID(optaPayload.Match.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match ID in lineup at index %d: %w", i, err)
+		}
+		teamID, err := p.parseID(optaPayload.TeamID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team ID in lineup at index %d: %w", i, err)
+		}
+
+		lineup := &webhooks.Lineup{MatchID: matchID, TeamID: teamID, Formation: optaPayload.FormationUsed}
+		for _, player := range optaPayload.Players {
+			playerID, err := p.parseID(player.PlayerID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid player ID in lineup at index %d: %w", i, err)
+			}
+			lineup.Players = append(lineup.Players, webhooks.LineupPlayer{
+				PlayerID:    playerID,
+				ShirtNumber: shirtNumber(player.ShirtNumber),
+				Position:    player.Position,
+				Starter:     strings.EqualFold(player.Status, "start"),
+			})
+		}
+		lineups = append(lineups, lineup)
+	}
+	return lineups, nil
+}
@@ -7,9 +7,14 @@ import (
 	"strconv"
 
 	"github.com/emiliospot/footie/api/internal/domain/events"
+	"github.com/emiliospot/footie/api/internal/domain/pitch"
 	infraEvents "github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/webhooks"
 )
 
+// yardMeters converts StatsBomb's yards to meters.
+const yardMeters = 0.9144
+
 // StatsBombProvider handles StatsBomb data feed format.
 // StatsBomb uses a flat structure with location arrays.
 type StatsBombProvider struct{}
@@ -38,8 +43,12 @@ type StatsBombPayload struct {
 	Outcome   string   `json:"outcome,omitempty"`
 	BodyPart  string   `json:"body_part,omitempty"`
 	Technique string   `json:"technique,omitempty"`
+	PassType  string   `json:"pass_type,omitempty"` // Set-piece passes: "Corner", "Free Kick", "Throw-in", ...
+	ShotType  string   `json:"shot_type,omitempty"` // "Open Play", "Free Kick", "Penalty", "Corner"
 	XG        *float64 `json:"xG,omitempty"`
 	PassEnd   []float64 `json:"pass_end_location,omitempty"`
+	CarryEnd  []float64 `json:"carry_end_location,omitempty"`
+	ShotEnd   []float64 `json:"shot_end_location,omitempty"` // [x, y] or [x, y, z], z in yards above the ground
 }
 
 // ExtractEvent extracts and transforms a single StatsBomb payload into our internal format.
@@ -137,10 +146,32 @@ func (p *StatsBombProvider) extractSingleStatsBombEvent(sbPayload *StatsBombPayl
 	if sbPayload.Technique != "" {
 		metadata["technique"] = sbPayload.Technique
 	}
+	if sbPayload.PassType != "" {
+		metadata["pass_type"] = sbPayload.PassType
+	}
+	if sbPayload.ShotType != "" {
+		metadata["shot_type"] = sbPayload.ShotType
+	}
 	if len(sbPayload.PassEnd) >= 2 {
 		metadata["pass_end_x"] = sbPayload.PassEnd[0]
 		metadata["pass_end_y"] = sbPayload.PassEnd[1]
 	}
+	if len(sbPayload.CarryEnd) >= 2 {
+		metadata["carry_end_x"] = sbPayload.CarryEnd[0]
+		metadata["carry_end_y"] = sbPayload.CarryEnd[1]
+	}
+	if len(sbPayload.ShotEnd) >= 2 {
+		metadata["shot_end_x"] = sbPayload.ShotEnd[0]
+		metadata["shot_end_y"] = sbPayload.ShotEnd[1]
+		if len(sbPayload.ShotEnd) >= 3 {
+			// Stored in meters; the pitch transform only converts x and y
+			metadata["shot_end_z"] = sbPayload.ShotEnd[2] * yardMeters
+		}
+	}
+
+	// StatsBomb locations are on a 120x80 pitch, already oriented so the team in
+	// possession attacks left to right
+	posX, posY = pitch.Normalize(pitch.StatsBomb, pitch.LeftToRight, posX, posY, metadata)
 
 	// Convert metadata to JSON string
 	metadataJSON := ""
@@ -172,52 +203,27 @@ func (p *StatsBombProvider) extractSingleStatsBombEvent(sbPayload *StatsBombPayl
 		period = events.PeriodPenalties
 	default:
 		// Auto-determine from minute
-		period = events.DeterminePeriod(int32(sbPayload.Minute), nil)
-	}
-
-	// Extract second (0-59)
-	var second *int32
-	if sbPayload.Second > 0 {
-		s := int32(sbPayload.Second)
-		if s < 60 {
-			second = &s
-		}
+		period = events.PeriodRegular
 	}
 
-	// Calculate extra minute (StatsBomb uses period > 2 for extra time)
-	var extraMinute *int32
-	if sbPayload.Period > 2 && sbPayload.Period < 5 {
-		extraMin := int32(0)
-		extraMinute = &extraMin
-	}
+	// StatsBomb minutes run continuously through stoppage time (47 is 45+2 in
+	// the first half), so the clock splits off the stoppage minutes
+	clock := events.NewMatchClock(period, int32(sbPayload.Minute), 0, int32(sbPayload.Second))
 
-	return &infraEvents.MatchEvent{
+	event := &infraEvents.MatchEvent{
 		ID:                0,
 		MatchID:           matchID,
 		TeamID:            &teamID,
 		PlayerID:          playerID,
 		SecondaryPlayerID: nil,
 		EventType:         eventType.String(),
-		Minute:            sbPayload.Minute,
-		Second: func() *int {
-			if second != nil {
-				s := int(*second)
-				return &s
-			}
-			return nil
-		}(),
-		Period: period.String(),
-		ExtraMinute: func() int {
-			if extraMinute != nil {
-				return int(*extraMinute)
-			}
-			return 0
-		}(),
-		PositionX:   posX,
-		PositionY:   posY,
-		Description: "",
-		Metadata:    metadataJSON,
-	}, nil
+		PositionX:         posX,
+		PositionY:         posY,
+		Description:       "",
+		Metadata:          metadataJSON,
+	}
+	event.SetClock(clock)
+	return event, nil
 }
 
 // VerifySignature verifies StatsBomb's signature format.
@@ -238,3 +244,66 @@ func (p *StatsBombProvider) parseID(idStr string) (int32, error) {
 	}
 	return int32(id), nil
 }
+
+// StatsBombLineupPayload represents a StatsBomb "Starting XI" event: the team's
+// formation and starting lineup, with the bench alongside.
+type StatsBombLineupPayload struct {
+	MatchID string `json:"match_id"`
+	Team    string `json:"team"`
+	Tactics struct {
+		Formation int                     `json:"formation"` // e.g. 4231
+		Lineup    []StatsBombLineupPlayer `json:"lineup"`
+	} `json:"tactics"`
+	Bench []StatsBombLineupPlayer `json:"bench,omitempty"`
+}
+
+// StatsBombLineupPlayer represents a player in a StatsBomb lineup.
+type StatsBombLineupPlayer struct {
+	Player       string `json:"player"`
+	Position     string `json:"position,omitempty"`
+	JerseyNumber int    `json:"jersey_number,omitempty"`
+}
+
+// ExtractLineups extracts StatsBomb lineup payloads (single or batch) into our internal format.
+func (p *StatsBombProvider) ExtractLineups(ctx context.Context, payload []byte) ([]*webhooks.Lineup, error) {
+	batch, err := unmarshalLineups[StatsBombLineupPayload](payload)
+	if err != nil {
+		return nil, err
+	}
+
+	lineups := make([]*webhooks.Lineup, 0, len(batch))
+	for i, sbPayload := range batch {
+		matchID, err := p.parseID(sbPayload.MatchID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match ID in lineup at index %d: %w", i, err)
+		}
+		teamID, err := p.parseID(sbPayload.Team)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team ID in lineup at index %d: %w", i, err)
+		}
+
+		lineup := &webhooks.Lineup{MatchID: matchID, TeamID: teamID}
+		if sbPayload.Tactics.Formation > 0 {
+			lineup.Formation = strconv.Itoa(sbPayload.Tactics.Formation)
+		}
+		for _, squad := range []struct {
+			players []StatsBombLineupPlayer
+			starter bool
+		}{{sbPayload.Tactics.Lineup, true}, {sbPayload.Bench, false}} {
+			for _, player := range squad.players {
+				playerID, err := p.parseID(player.Player)
+				if err != nil {
+					return nil, fmt.Errorf("invalid player ID in lineup at index %d: %w", i, err)
+				}
+				lineup.Players = append(lineup.Players, webhooks.LineupPlayer{
+					PlayerID:    playerID,
+					ShirtNumber: shirtNumber(player.JerseyNumber),
+					Position:    player.Position,
+					Starter:     squad.starter,
+				})
+			}
+		}
+		lineups = append(lineups, lineup)
+	}
+	return lineups, nil
+}
@@ -1,15 +1,40 @@
 package websocket
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// Client is a middleman between the websocket connection and the hub.
+type Client struct {
+	// Subscription carrying messages for the client's match.
+	*Subscription
+
+	// The websocket connection.
+	conn *websocket.Conn
+
+	// Message written before anything from the queue, e.g. a ticker snapshot.
+	initial []byte
+
+	// User ID (optional, for authentication).
+	userID int32
+}
+
+// newClient creates a client subscribed to a single match.
+func newClient(hub *Hub, conn *websocket.Conn, matchID, userID int32) *Client {
+	return &Client{
+		Subscription: hub.newSubscription([]int32{matchID}),
+		conn:         conn,
+		userID:       userID,
+	}
+}
+
 // readPump pumps messages from the websocket connection to the hub.
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.unregisterSubscription(c.Subscription)
 		c.conn.Close()
 	}()
 
@@ -41,27 +66,39 @@ func (c *Client) writePump() {
 		c.conn.Close()
 	}()
 
+	if c.initial != nil {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if err := c.conn.WriteMessage(websocket.TextMessage, c.initial); err != nil {
+			return
+		}
+	}
+
 	for {
 		select {
-		case message, ok := <-c.send:
+		case <-c.done:
+			// The hub closed the client (slow consumer, shutdown or disconnect).
 			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				// The hub closed the channel.
-				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
+			_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeText))
+			return
+
+		case <-c.queue.ready:
+			messages := c.queue.drain()
+			if len(messages) == 0 {
+				continue
 			}
 
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			w, err := c.conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
 			}
-			_, _ = w.Write(message)
 
-			// Add queued messages to the current websocket message.
-			n := len(c.send)
-			for i := 0; i < n; i++ {
-				_, _ = w.Write([]byte{'\n'})
-				_, _ = w.Write(<-c.send)
+			// Batch everything that queued up into a single websocket message.
+			for i, message := range messages {
+				if i > 0 {
+					_, _ = w.Write([]byte{'\n'})
+				}
+				_, _ = w.Write(message.Payload)
 			}
 
 			if err := w.Close(); err != nil {
@@ -79,17 +116,44 @@ func (c *Client) writePump() {
 
 // ServeWs handles websocket requests from the peer.
 func ServeWs(hub *Hub, conn *websocket.Conn, matchID, userID int32) {
-	client := &Client{
-		hub:     hub,
-		conn:    conn,
-		send:    make(chan []byte, 256),
-		matchID: matchID,
-		userID:  userID,
+	client := newClient(hub, conn, matchID, userID)
+	if !hub.registerSubscription(client.Subscription) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
 	}
-	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
 	go client.writePump()
 	go client.readPump()
 }
+
+// ServeTickerWs handles live scores ticker websocket requests from the peer.
+// The snapshot is built after subscribing so no update can fall between the two;
+// updates queued meanwhile are delivered after it.
+func ServeTickerWs(hub *Hub, conn *websocket.Conn, competition string, userID int32, snapshot func() (*Message, error)) {
+	client := &Client{
+		Subscription: hub.newTickerSubscription(competition),
+		conn:         conn,
+		userID:       userID,
+	}
+	if !hub.registerSubscription(client.Subscription) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
+	}
+
+	if snapshot != nil {
+		message, err := snapshot()
+		if err == nil {
+			client.initial, err = json.Marshal(message)
+		}
+		if err != nil {
+			hub.logger.Error("Failed to build ticker snapshot", "error", err)
+		}
+	}
+
+	go client.writePump()
+	go client.readPump()
+}
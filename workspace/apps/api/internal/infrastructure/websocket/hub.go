@@ -9,22 +9,33 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
 	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
 )
 
-// Hub maintains the set of active clients and broadcasts messages to the clients.
+// Hub maintains the set of active subscriptions and broadcasts messages to them.
 type Hub struct {
-	// Registered clients per match.
-	clients map[int32]map[*Client]bool
+	// Registered subscriptions per match. Only mutated by the Run goroutine.
+	subscriptions map[int32]map[*Subscription]struct{}
 
-	// Inbound messages from the clients.
+	// Registered live scores ticker subscriptions. Only mutated by the Run goroutine.
+	tickers map[*Subscription]struct{}
+
+	// Inbound messages for the subscribers.
 	broadcast chan *Message
 
-	// Register requests from the clients.
-	register chan *Client
+	// Inbound live scores ticker messages.
+	tickerBroadcast chan *Message
+
+	// Register requests from subscribers.
+	register chan *Subscription
+
+	// Unregister requests from subscribers.
+	unregister chan *Subscription
 
-	// Unregister requests from clients.
-	unregister chan *Client
+	// Closed when Run returns so that callers never block on a stopped hub.
+	done chan struct{}
 
 	// Redis client for pub/sub.
 	redis *redis.Client
@@ -32,33 +43,24 @@ type Hub struct {
 	// Logger.
 	logger *logger.Logger
 
-	// Mutex for thread-safe operations.
-	mu sync.RWMutex
-}
-
-// Client is a middleman between the websocket connection and the hub.
-type Client struct {
-	hub *Hub
+	// Overflow policy applied to every subscriber queue.
+	policy OverflowPolicy
 
-	// The websocket connection.
-	conn *websocket.Conn
+	// Capacity of every subscriber queue.
+	queueSize int
 
-	// Buffered channel of outbound messages.
-	send chan []byte
-
-	// Match ID this client is subscribed to.
-	matchID int32
-
-	// User ID (optional, for authentication).
-	userID int32
+	// Mutex guarding subscriptions for readers outside the Run goroutine.
+	mu sync.RWMutex
 }
 
 // Message represents a real-time event message.
 type Message struct {
-	Type      string      `json:"type"` // "match_event", "score_update", "match_status"
-	MatchID   int32       `json:"match_id"`
-	Timestamp time.Time   `json:"timestamp"`
-	Data      interface{} `json:"data"`
+	Type        string      `json:"type"` // "match_event", "score_update", "match_status", "ticker_*"
+	MatchID     int32       `json:"match_id"`
+	StreamID    string      `json:"stream_id,omitempty"`   // Redis stream entry ID for match events
+	Competition string      `json:"competition,omitempty"` // Set on ticker messages for filtering
+	Timestamp   time.Time   `json:"timestamp"`
+	Data        interface{} `json:"data"`
 }
 
 const (
@@ -73,17 +75,36 @@ const (
 
 	// Maximum message size allowed from peer.
 	maxMessageSize = 512
+
+	// Default capacity of a subscriber's send queue.
+	defaultQueueSize = 256
 )
 
 // NewHub creates a new Hub instance.
-func NewHub(redis *redis.Client, logger *logger.Logger) *Hub {
+func NewHub(redis *redis.Client, logger *logger.Logger, cfg config.WebSocketConfig) *Hub {
+	policy, err := ParseOverflowPolicy(cfg.OverflowPolicy)
+	if err != nil {
+		logger.Warn("Invalid WebSocket overflow policy, falling back to drop_oldest", "error", err)
+		policy = OverflowDropOldest
+	}
+
+	queueSize := cfg.SendQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
 	return &Hub{
-		broadcast:  make(chan *Message, 256),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		clients:    make(map[int32]map[*Client]bool),
-		redis:      redis,
-		logger:     logger,
+		broadcast:       make(chan *Message, 256),
+		tickerBroadcast: make(chan *Message, 256),
+		register:        make(chan *Subscription),
+		unregister:      make(chan *Subscription),
+		done:            make(chan struct{}),
+		subscriptions:   make(map[int32]map[*Subscription]struct{}),
+		tickers:         make(map[*Subscription]struct{}),
+		redis:           redis,
+		logger:          logger,
+		policy:          policy,
+		queueSize:       queueSize,
 	}
 }
 
@@ -96,59 +117,168 @@ func (h *Hub) Run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			h.logger.Info("Hub shutting down")
+			h.closeAll()
+			close(h.done)
 			return
 
-		case client := <-h.register:
-			h.mu.Lock()
-			if h.clients[client.matchID] == nil {
-				h.clients[client.matchID] = make(map[*Client]bool)
-			}
-			h.clients[client.matchID][client] = true
-			h.mu.Unlock()
-			h.logger.Info("Client registered", "match_id", client.matchID, "total_clients", len(h.clients[client.matchID]))
-
-		case client := <-h.unregister:
-			h.mu.Lock()
-			if clients, ok := h.clients[client.matchID]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.send)
-					if len(clients) == 0 {
-						delete(h.clients, client.matchID)
-					}
-				}
-			}
-			h.mu.Unlock()
-			h.logger.Info("Client unregistered", "match_id", client.matchID)
+		case sub := <-h.register:
+			h.addSubscription(sub)
+
+		case sub := <-h.unregister:
+			h.removeSubscription(sub, websocket.CloseNormalClosure, "")
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
-			clients := h.clients[message.MatchID]
-			h.mu.RUnlock()
+			h.deliver(message)
 
-			messageBytes, err := json.Marshal(message)
-			if err != nil {
-				h.logger.Error("Failed to marshal message", "error", err)
-				continue
-			}
+		case message := <-h.tickerBroadcast:
+			h.deliverTicker(message)
+		}
+	}
+}
 
-			for client := range clients {
-				select {
-				case client.send <- messageBytes:
-				default:
-					close(client.send)
-					h.mu.Lock()
-					delete(h.clients[message.MatchID], client)
-					h.mu.Unlock()
-				}
+// registerSubscription hands a subscription to the hub. It returns false if the hub has stopped.
+func (h *Hub) registerSubscription(sub *Subscription) bool {
+	select {
+	case h.register <- sub:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// unregisterSubscription asks the hub to drop a subscription. It never blocks once the hub has stopped.
+func (h *Hub) unregisterSubscription(sub *Subscription) {
+	select {
+	case h.unregister <- sub:
+	case <-h.done:
+	}
+}
+
+// addSubscription registers a subscription for each of its matches, or for the ticker.
+func (h *Hub) addSubscription(sub *Subscription) {
+	h.mu.Lock()
+	if sub.ticker {
+		h.tickers[sub] = struct{}{}
+		h.mu.Unlock()
+		h.logger.Info("Ticker subscriber registered", "competition", sub.competition)
+		return
+	}
+	for _, matchID := range sub.matchIDs {
+		if h.subscriptions[matchID] == nil {
+			h.subscriptions[matchID] = make(map[*Subscription]struct{})
+		}
+		h.subscriptions[matchID][sub] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	h.logger.Info("Subscriber registered", "match_ids", sub.matchIDs)
+}
+
+// removeSubscription unregisters a subscription and closes it with the given close code.
+// Removing a subscription that is already gone is a no-op.
+func (h *Hub) removeSubscription(sub *Subscription, code int, text string) {
+	removed := false
+	h.mu.Lock()
+	if _, ok := h.tickers[sub]; ok {
+		delete(h.tickers, sub)
+		removed = true
+	}
+	for _, matchID := range sub.matchIDs {
+		subs, ok := h.subscriptions[matchID]
+		if !ok {
+			continue
+		}
+		if _, ok := subs[sub]; ok {
+			delete(subs, sub)
+			removed = true
+			if len(subs) == 0 {
+				delete(h.subscriptions, matchID)
 			}
 		}
 	}
+	h.mu.Unlock()
+
+	sub.close(code, text)
+
+	if removed {
+		h.logger.Info("Subscriber unregistered", "match_ids", sub.matchIDs, "close_code", code)
+	}
+}
+
+// deliver queues a message for every subscriber watching its match.
+func (h *Hub) deliver(message *Message) {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("Failed to marshal message", "error", err)
+		return
+	}
+
+	h.mu.RLock()
+	targets := make([]*Subscription, 0, len(h.subscriptions[message.MatchID]))
+	for sub := range h.subscriptions[message.MatchID] {
+		targets = append(targets, sub)
+	}
+	h.mu.RUnlock()
+
+	h.push(targets, Delivery{Type: message.Type, StreamID: message.StreamID, Payload: messageBytes, MatchID: message.MatchID})
+}
+
+// deliverTicker queues a ticker message for every ticker subscriber following its competition.
+func (h *Hub) deliverTicker(message *Message) {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("Failed to marshal ticker message", "error", err)
+		return
+	}
+
+	h.mu.RLock()
+	targets := make([]*Subscription, 0, len(h.tickers))
+	for sub := range h.tickers {
+		if sub.wantsTicker(message.Competition) {
+			targets = append(targets, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	h.push(targets, Delivery{Type: message.Type, Payload: messageBytes, MatchID: message.MatchID})
+}
+
+// push queues a delivery for each target, disconnecting subscribers that cannot keep up.
+func (h *Hub) push(targets []*Subscription, out Delivery) {
+	for _, sub := range targets {
+		if !sub.queue.push(out) {
+			h.logger.Warn("Disconnecting slow subscriber", "match_id", out.MatchID, "queued", sub.queue.len())
+			h.removeSubscription(sub, websocket.CloseTryAgainLater, "client too slow")
+		}
+	}
+}
+
+// closeAll closes every subscription, used when the hub shuts down.
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	subscriptions := h.subscriptions
+	tickers := h.tickers
+	h.subscriptions = make(map[int32]map[*Subscription]struct{})
+	h.tickers = make(map[*Subscription]struct{})
+	h.mu.Unlock()
+
+	for _, matchSubs := range subscriptions {
+		for sub := range matchSubs {
+			sub.close(websocket.CloseGoingAway, "server shutting down")
+		}
+	}
+	for sub := range tickers {
+		sub.close(websocket.CloseGoingAway, "server shutting down")
+	}
 }
 
 // listenToRedis subscribes to Redis pub/sub channels for match updates.
 func (h *Hub) listenToRedis(ctx context.Context) {
-	pubsub := h.redis.PSubscribe(ctx, "match:*:events")
+	if h.redis == nil {
+		return
+	}
+
+	pubsub := h.redis.PSubscribe(ctx, "match:*:events", events.TickerChannel)
 	defer pubsub.Close()
 
 	h.logger.Info("Started Redis pub/sub listener")
@@ -161,24 +291,36 @@ func (h *Hub) listenToRedis(ctx context.Context) {
 		default:
 			msg, err := pubsub.ReceiveMessage(ctx)
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				h.logger.Error("Redis pub/sub error", "error", err)
 				time.Sleep(time.Second)
 				continue
 			}
 
-			// Parse the message and broadcast to WebSocket clients
+			// Parse the message and broadcast to subscribers
 			var message Message
 			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
 				h.logger.Error("Failed to unmarshal Redis message", "error", err)
 				continue
 			}
 
-			h.broadcast <- &message
+			target := h.broadcast
+			if msg.Channel == events.TickerChannel {
+				target = h.tickerBroadcast
+			}
+
+			select {
+			case target <- &message:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 }
 
-// BroadcastToMatch sends a message to all clients watching a specific match.
+// BroadcastToMatch sends a message to all subscribers watching a specific match.
 func (h *Hub) BroadcastToMatch(matchID int32, msgType string, data interface{}) {
 	message := &Message{
 		Type:      msgType,
@@ -186,12 +328,39 @@ func (h *Hub) BroadcastToMatch(matchID int32, msgType string, data interface{})
 		Timestamp: time.Now(),
 		Data:      data,
 	}
-	h.broadcast <- message
+
+	select {
+	case h.broadcast <- message:
+	case <-h.done:
+	}
+}
+
+// BroadcastTicker sends a message to every ticker subscriber following the competition.
+func (h *Hub) BroadcastTicker(matchID int32, competition, msgType string, data interface{}) {
+	message := &Message{
+		Type:        msgType,
+		MatchID:     matchID,
+		Competition: competition,
+		Timestamp:   time.Now(),
+		Data:        data,
+	}
+
+	select {
+	case h.tickerBroadcast <- message:
+	case <-h.done:
+	}
+}
+
+// GetTickerCount returns the number of live scores ticker subscribers.
+func (h *Hub) GetTickerCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.tickers)
 }
 
-// GetClientCount returns the number of clients watching a match.
+// GetClientCount returns the number of subscribers (WebSocket or SSE) watching a match.
 func (h *Hub) GetClientCount(matchID int32) int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	return len(h.clients[matchID])
+	return len(h.subscriptions[matchID])
 }
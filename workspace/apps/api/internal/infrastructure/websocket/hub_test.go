@@ -0,0 +1,310 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emiliospot/footie/api/internal/config"
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+	"github.com/emiliospot/footie/api/internal/infrastructure/logger"
+)
+
+// newTestHub starts a hub without Redis and stops it when the test ends.
+func newTestHub(t *testing.T, queueSize int, policy OverflowPolicy) *Hub {
+	t.Helper()
+
+	hub := NewHub(nil, logger.NewLogger("error", "text"), config.WebSocketConfig{
+		OverflowPolicy: string(policy),
+		SendQueueSize:  queueSize,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-hub.done
+	})
+
+	return hub
+}
+
+// payloadTypes decodes the message types of queued payloads.
+func payloadTypes(t *testing.T, items []Delivery) []string {
+	t.Helper()
+
+	types := make([]string, 0, len(items))
+	for _, item := range items {
+		var msg Message
+		require.NoError(t, json.Unmarshal(item.Payload, &msg))
+		types = append(types, msg.Type)
+	}
+	return types
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    OverflowPolicy
+		wantErr bool
+	}{
+		{input: "drop_oldest", want: OverflowDropOldest},
+		{input: "COALESCE", want: OverflowCoalesce},
+		{input: " disconnect ", want: OverflowDisconnect},
+		{input: "", want: OverflowDropOldest},
+		{input: "block", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOverflowPolicy(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSendQueue_DropOldest(t *testing.T) {
+	q := newSendQueue(2, OverflowDropOldest)
+
+	assert.True(t, q.push(Delivery{Type: "a", Payload: []byte("1")}))
+	assert.True(t, q.push(Delivery{Type: "b", Payload: []byte("2")}))
+	assert.True(t, q.push(Delivery{Type: "c", Payload: []byte("3")}))
+
+	items := q.drain()
+	require.Len(t, items, 2)
+	assert.Equal(t, "b", items[0].Type)
+	assert.Equal(t, "c", items[1].Type)
+	assert.Equal(t, 1, q.droppedCount())
+	assert.Empty(t, q.drain())
+}
+
+func TestSendQueue_CoalesceScoreUpdates(t *testing.T) {
+	q := newSendQueue(3, OverflowCoalesce)
+
+	require.True(t, q.push(Delivery{Type: "score_update", MatchID: 1, Payload: []byte("1-0")}))
+	require.True(t, q.push(Delivery{Type: "match_event", MatchID: 1, Payload: []byte("goal")}))
+	require.True(t, q.push(Delivery{Type: "score_update", MatchID: 2, Payload: []byte("0-0")}))
+
+	// Full: the newer score for match 1 replaces the queued one and moves to the tail.
+	require.True(t, q.push(Delivery{Type: "score_update", MatchID: 1, Payload: []byte("2-0")}))
+
+	items := q.drain()
+	require.Len(t, items, 3)
+	assert.Equal(t, "match_event", items[0].Type)
+	assert.Equal(t, int32(2), items[1].MatchID)
+	assert.Equal(t, "2-0", string(items[2].Payload))
+
+	// Non-coalescable messages fall back to dropping the oldest entry.
+	q = newSendQueue(1, OverflowCoalesce)
+	require.True(t, q.push(Delivery{Type: "match_event", Payload: []byte("first")}))
+	require.True(t, q.push(Delivery{Type: "match_event", Payload: []byte("second")}))
+	items = q.drain()
+	require.Len(t, items, 1)
+	assert.Equal(t, "second", string(items[0].Payload))
+}
+
+func TestSendQueue_DisconnectWhenFull(t *testing.T) {
+	q := newSendQueue(1, OverflowDisconnect)
+
+	assert.True(t, q.push(Delivery{Type: "match_event"}))
+	assert.False(t, q.push(Delivery{Type: "match_event"}))
+	assert.Equal(t, 1, q.len())
+}
+
+func TestHub_SlowClientClosedWithTryAgainLater(t *testing.T) {
+	hub := newTestHub(t, 2, OverflowDisconnect)
+
+	// No write pump is running, so nothing drains the queue.
+	client := newClient(hub, nil, 7, 0)
+	require.True(t, hub.registerSubscription(client.Subscription))
+
+	for i := 0; i < 5; i++ {
+		hub.BroadcastToMatch(7, "match_event", map[string]int{"seq": i})
+	}
+
+	select {
+	case <-client.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow client was not disconnected")
+	}
+	assert.Equal(t, websocket.CloseTryAgainLater, client.closeCode)
+	assert.Eventually(t, func() bool { return hub.GetClientCount(7) == 0 }, time.Second, 10*time.Millisecond)
+
+	// A late unregister from the read pump must not close the client twice.
+	hub.unregisterSubscription(client.Subscription)
+}
+
+func TestHub_DropOldestKeepsClientConnected(t *testing.T) {
+	hub := newTestHub(t, 2, OverflowDropOldest)
+
+	client := newClient(hub, nil, 3, 0)
+	require.True(t, hub.registerSubscription(client.Subscription))
+
+	for i := 0; i < 5; i++ {
+		hub.BroadcastToMatch(3, "match_event", i)
+	}
+	hub.BroadcastToMatch(3, "score_update", nil)
+
+	assert.Eventually(t, func() bool { return client.queue.droppedCount() == 4 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, hub.GetClientCount(3))
+	assert.Equal(t, []string{"match_event", "score_update"}, payloadTypes(t, client.queue.drain()))
+}
+
+func TestHub_ConcurrentRegisterBroadcastUnregister(t *testing.T) {
+	hub := newTestHub(t, 4, OverflowDisconnect)
+
+	const workers = 16
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			matchID := int32(w % 3)
+			client := newClient(hub, nil, matchID, int32(w))
+			if !hub.registerSubscription(client.Subscription) {
+				return
+			}
+			for i := 0; i < 20; i++ {
+				hub.BroadcastToMatch(matchID, "score_update", i)
+				_ = hub.GetClientCount(matchID)
+			}
+			// Unregister twice, as the read pump and a slow-consumer disconnect might.
+			hub.unregisterSubscription(client.Subscription)
+			hub.unregisterSubscription(client.Subscription)
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		return hub.GetClientCount(0)+hub.GetClientCount(1)+hub.GetClientCount(2) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServeWs_DeliversBroadcasts(t *testing.T) {
+	hub := newTestHub(t, 8, OverflowDropOldest)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		ServeWs(hub, conn, 42, 0)
+	}))
+	defer server.Close()
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return hub.GetClientCount(42) == 1 }, time.Second, 10*time.Millisecond)
+	hub.BroadcastToMatch(42, "score_update", map[string]int{"home_team_score": 1})
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(data, &msg))
+	assert.Equal(t, "score_update", msg.Type)
+	assert.Equal(t, int32(42), msg.MatchID)
+
+	// Closing the peer unregisters the client exactly once.
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return hub.GetClientCount(42) == 0 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHub_ShutdownClosesClients(t *testing.T) {
+	hub := NewHub(nil, logger.NewLogger("error", "text"), config.WebSocketConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+
+	client := newClient(hub, nil, 1, 0)
+	require.True(t, hub.registerSubscription(client.Subscription))
+
+	cancel()
+	<-hub.done
+
+	select {
+	case <-client.done:
+	case <-time.After(time.Second):
+		t.Fatal("client not closed on shutdown")
+	}
+	assert.Equal(t, websocket.CloseGoingAway, client.closeCode)
+
+	// Calls after shutdown must not block.
+	hub.BroadcastToMatch(1, "match_event", nil)
+	hub.unregisterSubscription(client.Subscription)
+	assert.False(t, hub.registerSubscription(newClient(hub, nil, 1, 0).Subscription))
+}
+
+func TestHub_SubscriptionAcrossMatches(t *testing.T) {
+	hub := newTestHub(t, 8, OverflowDropOldest)
+
+	sub, err := hub.Subscribe(1, 2)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return hub.GetClientCount(1) == 1 && hub.GetClientCount(2) == 1 }, time.Second, 10*time.Millisecond)
+
+	hub.BroadcastToMatch(1, "match_event", nil)
+	hub.BroadcastToMatch(3, "match_event", nil)
+	hub.BroadcastToMatch(2, "score_update", nil)
+
+	var got []Delivery
+	require.Eventually(t, func() bool {
+		got = append(got, sub.Drain()...)
+		return len(got) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), got[0].MatchID)
+	assert.Equal(t, int32(2), got[1].MatchID)
+
+	hub.Unsubscribe(sub)
+	<-sub.Done()
+	assert.Equal(t, websocket.CloseNormalClosure, sub.CloseCode())
+	assert.Equal(t, 0, hub.GetClientCount(1)+hub.GetClientCount(2))
+
+	// Unsubscribing again is a no-op.
+	hub.Unsubscribe(sub)
+}
+
+func TestHub_TickerFiltersByCompetition(t *testing.T) {
+	hub := newTestHub(t, 8, OverflowCoalesce)
+
+	all, err := hub.SubscribeTicker("")
+	require.NoError(t, err)
+	premier, err := hub.SubscribeTicker("premier league")
+	require.NoError(t, err)
+	matchSub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return hub.GetTickerCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	hub.BroadcastTicker(1, "Premier League", events.TickerTypeScore, nil)
+	hub.BroadcastTicker(2, "La Liga", events.TickerTypeEvent, nil)
+
+	var gotAll, gotPremier []Delivery
+	require.Eventually(t, func() bool {
+		gotAll = append(gotAll, all.Drain()...)
+		gotPremier = append(gotPremier, premier.Drain()...)
+		return len(gotAll) == 2 && len(gotPremier) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), gotPremier[0].MatchID)
+	assert.Empty(t, matchSub.Drain(), "ticker messages must not reach per-match subscribers")
+
+	hub.Unsubscribe(premier)
+	<-premier.Done()
+	assert.Eventually(t, func() bool { return hub.GetTickerCount() == 1 }, time.Second, 10*time.Millisecond)
+}
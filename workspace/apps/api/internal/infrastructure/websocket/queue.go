@@ -0,0 +1,167 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/emiliospot/footie/api/internal/infrastructure/events"
+)
+
+// OverflowPolicy decides what happens when a client's send queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest discards the oldest queued message to make room for the new one.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowCoalesce replaces a queued score/status/stats update for the same match with the
+	// newest one, and falls back to dropping the oldest message when nothing can be merged.
+	OverflowCoalesce OverflowPolicy = "coalesce"
+	// OverflowDisconnect closes the client with close code 1013 (try again later).
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+// ParseOverflowPolicy converts a configuration value into an OverflowPolicy.
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case OverflowDropOldest, OverflowCoalesce, OverflowDisconnect:
+		return policy, nil
+	case "":
+		return OverflowDropOldest, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q", value)
+	}
+}
+
+// Delivery is a serialized hub message waiting to be written to a subscriber.
+type Delivery struct {
+	// Type is the message type, e.g. "match_event" or "score_update".
+	Type string
+	// StreamID is the Redis stream entry ID for stream-backed messages, if any.
+	StreamID string
+	// Payload is the JSON-encoded Message.
+	Payload []byte
+	// MatchID is the match the message belongs to.
+	MatchID int32
+}
+
+// coalescable reports whether only the latest message of this type matters to a client.
+func coalescable(msgType string) bool {
+	switch msgType {
+	case "score_update", "match_status", "live_stats", "xg_timeline", events.TickerTypeScore, events.TickerTypeStatus:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendQueue is a bounded per-client queue of outbound messages.
+// The hub pushes into it and the client's write pump drains it.
+type sendQueue struct {
+	// Signaled (non-blocking) whenever a message is queued.
+	ready chan struct{}
+
+	items    []Delivery
+	capacity int
+	policy   OverflowPolicy
+	dropped  int
+	mu       sync.Mutex
+}
+
+// newSendQueue creates a queue holding at most capacity messages.
+func newSendQueue(capacity int, policy OverflowPolicy) *sendQueue {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &sendQueue{
+		ready:    make(chan struct{}, 1),
+		items:    make([]Delivery, 0, capacity),
+		capacity: capacity,
+		policy:   policy,
+	}
+}
+
+// push queues a message, applying the overflow policy when the queue is full.
+// It returns false when the policy requires the client to be disconnected.
+func (q *sendQueue) push(msg Delivery) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) >= q.capacity {
+		switch q.policy {
+		case OverflowDisconnect:
+			return false
+		case OverflowCoalesce:
+			if !q.coalesceLocked(msg) {
+				q.dropOldestLocked()
+				q.items = append(q.items, msg)
+			}
+		default:
+			q.dropOldestLocked()
+			q.items = append(q.items, msg)
+		}
+	} else {
+		q.items = append(q.items, msg)
+	}
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// coalesceLocked replaces the newest queued message of the same type and match.
+func (q *sendQueue) coalesceLocked(msg Delivery) bool {
+	if !coalescable(msg.Type) {
+		return false
+	}
+	for i := len(q.items) - 1; i >= 0; i-- {
+		if q.items[i].Type == msg.Type && q.items[i].MatchID == msg.MatchID {
+			// Move the replacement to the tail so delivery order stays chronological.
+			copy(q.items[i:], q.items[i+1:])
+			q.items[len(q.items)-1] = msg
+			q.dropped++
+			return true
+		}
+	}
+	return false
+}
+
+// dropOldestLocked discards the message at the head of the queue.
+func (q *sendQueue) dropOldestLocked() {
+	if len(q.items) == 0 {
+		return
+	}
+	copy(q.items, q.items[1:])
+	q.items = q.items[:len(q.items)-1]
+	q.dropped++
+}
+
+// drain removes and returns every queued message.
+func (q *sendQueue) drain() []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return nil
+	}
+	items := make([]Delivery, len(q.items))
+	copy(items, q.items)
+	q.items = q.items[:0]
+	return items
+}
+
+// len returns the number of queued messages.
+func (q *sendQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// droppedCount returns how many messages were discarded or merged by the overflow policy.
+func (q *sendQueue) droppedCount() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrHubStopped is returned when subscribing to a hub that is no longer running.
+var ErrHubStopped = errors.New("hub stopped")
+
+// Subscription is a transport-independent feed of hub messages for one or more matches.
+// WebSocket clients and Server-Sent Events streams both consume hub messages through it.
+type Subscription struct {
+	hub *Hub
+
+	// Bounded queue of pending deliveries.
+	queue *sendQueue
+
+	// Closed exactly once when the subscription ends.
+	done chan struct{}
+
+	// Reason the subscription ended, set before done is closed.
+	closeText string
+	closeCode int
+	closeOnce sync.Once
+
+	// Matches this subscription receives messages for.
+	matchIDs []int32
+
+	// Set for live scores ticker subscriptions, which receive ticker messages
+	// for every live match instead of per-match messages.
+	ticker bool
+
+	// Competition a ticker subscription is limited to; empty means all competitions.
+	competition string
+}
+
+// Subscribe registers a new subscription for the given matches.
+func (h *Hub) Subscribe(matchIDs ...int32) (*Subscription, error) {
+	sub := h.newSubscription(matchIDs)
+	if !h.registerSubscription(sub) {
+		return nil, ErrHubStopped
+	}
+	return sub, nil
+}
+
+// SubscribeTicker registers a live scores ticker subscription.
+// An empty competition receives updates for every competition.
+func (h *Hub) SubscribeTicker(competition string) (*Subscription, error) {
+	sub := h.newTickerSubscription(competition)
+	if !h.registerSubscription(sub) {
+		return nil, ErrHubStopped
+	}
+	return sub, nil
+}
+
+// Unsubscribe removes a subscription. It is safe to call more than once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.unregisterSubscription(sub)
+}
+
+// newSubscription creates a subscription with a queue sized from the hub configuration.
+func (h *Hub) newSubscription(matchIDs []int32) *Subscription {
+	return &Subscription{
+		hub:      h,
+		queue:    newSendQueue(h.queueSize, h.policy),
+		done:     make(chan struct{}),
+		matchIDs: matchIDs,
+	}
+}
+
+// newTickerSubscription creates a ticker subscription with a queue sized from the hub configuration.
+func (h *Hub) newTickerSubscription(competition string) *Subscription {
+	sub := h.newSubscription(nil)
+	sub.ticker = true
+	sub.competition = strings.TrimSpace(competition)
+	return sub
+}
+
+// wantsTicker reports whether a ticker subscription follows the given competition.
+func (s *Subscription) wantsTicker(competition string) bool {
+	return s.competition == "" || strings.EqualFold(s.competition, competition)
+}
+
+// MatchIDs returns the matches this subscription receives messages for.
+func (s *Subscription) MatchIDs() []int32 {
+	return s.matchIDs
+}
+
+// Ready is signaled whenever new deliveries are queued.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.queue.ready
+}
+
+// Done is closed when the hub ends the subscription.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Drain removes and returns every queued delivery.
+func (s *Subscription) Drain() []Delivery {
+	return s.queue.drain()
+}
+
+// CloseCode returns the WebSocket close code the subscription ended with.
+// It is only meaningful after Done is closed.
+func (s *Subscription) CloseCode() int {
+	return s.closeCode
+}
+
+// close ends the subscription. Only the first call has any effect.
+func (s *Subscription) close(code int, text string) {
+	s.closeOnce.Do(func() {
+		s.closeCode = code
+		s.closeText = text
+		close(s.done)
+	})
+}
@@ -41,7 +41,7 @@ func (r *GormMatchEventRepository) FindByMatchID(ctx context.Context, matchID ui
 		Preload("Player").
 		Preload("Team").
 		Preload("SecondaryPlayer").
-		Order("minute ASC, extra_minute ASC").
+		Order("period_number ASC, clock_ms ASC, id ASC").
 		Find(&events).Error
 	return events, err
 }
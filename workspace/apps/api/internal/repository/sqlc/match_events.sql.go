@@ -43,11 +43,12 @@ func (q *Queries) CountMatchEvents(ctx context.Context, matchID int32) (int64, e
 const createMatchEvent = `-- name: CreateMatchEvent :one
 INSERT INTO match_events (
     match_id, team_id, player_id, secondary_player_id, event_type,
-    minute, second, period, extra_minute, position_x, position_y, description, metadata
+    minute, second, period, extra_minute, position_x, position_y, description, metadata,
+    period_number, clock_ms
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
-RETURNING id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period
+RETURNING id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type
 `
 
 type CreateMatchEventParams struct {
@@ -64,6 +65,8 @@ type CreateMatchEventParams struct {
 	PositionY         pgtype.Numeric `json:"position_y"`
 	Description       *string        `json:"description"`
 	Metadata          []byte         `json:"metadata"`
+	PeriodNumber      int16          `json:"period_number"`
+	ClockMs           int64          `json:"clock_ms"`
 }
 
 func (q *Queries) CreateMatchEvent(ctx context.Context, arg CreateMatchEventParams) (MatchEvent, error) {
@@ -81,6 +84,8 @@ func (q *Queries) CreateMatchEvent(ctx context.Context, arg CreateMatchEventPara
 		arg.PositionY,
 		arg.Description,
 		arg.Metadata,
+		arg.PeriodNumber,
+		arg.ClockMs,
 	)
 	var i MatchEvent
 	err := row.Scan(
@@ -101,6 +106,15 @@ func (q *Queries) CreateMatchEvent(ctx context.Context, arg CreateMatchEventPara
 		&i.DeletedAt,
 		&i.Second,
 		&i.Period,
+		&i.PeriodNumber,
+		&i.ClockMs,
+		&i.PossessionID,
+		&i.GameState,
+		&i.ScoreDifferential,
+		&i.DerivedType,
+		&i.Xa,
+		&i.ShotEventID,
+		&i.CreationType,
 	)
 	return i, err
 }
@@ -117,11 +131,11 @@ func (q *Queries) DeleteMatchEvent(ctx context.Context, id int32) error {
 }
 
 const getCardsByMatch = `-- name: GetCardsByMatch :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1
   AND event_type IN ('yellow_card', 'red_card')
   AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 func (q *Queries) GetCardsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error) {
@@ -151,6 +165,15 @@ func (q *Queries) GetCardsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -163,9 +186,9 @@ func (q *Queries) GetCardsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 }
 
 const getGoalsByMatch = `-- name: GetGoalsByMatch :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1 AND event_type = 'goal' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 func (q *Queries) GetGoalsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error) {
@@ -195,6 +218,15 @@ func (q *Queries) GetGoalsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -207,7 +239,7 @@ func (q *Queries) GetGoalsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 }
 
 const getMatchEventByID = `-- name: GetMatchEventByID :one
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -233,14 +265,23 @@ func (q *Queries) GetMatchEventByID(ctx context.Context, id int32) (MatchEvent,
 		&i.DeletedAt,
 		&i.Second,
 		&i.Period,
+		&i.PeriodNumber,
+		&i.ClockMs,
+		&i.PossessionID,
+		&i.GameState,
+		&i.ScoreDifferential,
+		&i.DerivedType,
+		&i.Xa,
+		&i.ShotEventID,
+		&i.CreationType,
 	)
 	return i, err
 }
 
 const getMatchEvents = `-- name: GetMatchEvents :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC, id ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 func (q *Queries) GetMatchEvents(ctx context.Context, matchID int32) ([]MatchEvent, error) {
@@ -270,6 +311,15 @@ func (q *Queries) GetMatchEvents(ctx context.Context, matchID int32) ([]MatchEve
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -282,9 +332,9 @@ func (q *Queries) GetMatchEvents(ctx context.Context, matchID int32) ([]MatchEve
 }
 
 const getMatchEventsByType = `-- name: GetMatchEventsByType :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1 AND event_type = $2 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 type GetMatchEventsByTypeParams struct {
@@ -319,6 +369,15 @@ func (q *Queries) GetMatchEventsByType(ctx context.Context, arg GetMatchEventsBy
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -331,9 +390,9 @@ func (q *Queries) GetMatchEventsByType(ctx context.Context, arg GetMatchEventsBy
 }
 
 const getPassesByMatch = `-- name: GetPassesByMatch :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1 AND event_type = 'pass' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 func (q *Queries) GetPassesByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error) {
@@ -363,6 +422,15 @@ func (q *Queries) GetPassesByMatch(ctx context.Context, matchID int32) ([]MatchE
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -375,7 +443,7 @@ func (q *Queries) GetPassesByMatch(ctx context.Context, matchID int32) ([]MatchE
 }
 
 const getPlayerEvents = `-- name: GetPlayerEvents :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE player_id = $1 AND deleted_at IS NULL
 ORDER BY id DESC
 LIMIT $2 OFFSET $3
@@ -414,6 +482,15 @@ func (q *Queries) GetPlayerEvents(ctx context.Context, arg GetPlayerEventsParams
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -455,7 +532,7 @@ func (q *Queries) GetPlayerPassAccuracy(ctx context.Context, playerID *int32) (G
 
 const getPlayerShotsWithXG = `-- name: GetPlayerShotsWithXG :many
 SELECT
-    me.id, me.match_id, me.team_id, me.player_id, me.secondary_player_id, me.event_type, me.minute, me.extra_minute, me.position_x, me.position_y, me.description, me.metadata, me.created_at, me.updated_at, me.deleted_at, me.second, me.period,
+    me.id, me.match_id, me.team_id, me.player_id, me.secondary_player_id, me.event_type, me.minute, me.extra_minute, me.position_x, me.position_y, me.description, me.metadata, me.created_at, me.updated_at, me.deleted_at, me.second, me.period, me.period_number, me.clock_ms, me.possession_id, me.game_state, me.score_differential, me.derived_type, me.xa, me.shot_event_id, me.creation_type,
     me.metadata->>'xg' as expected_goals,
     me.metadata->>'shot_type' as shot_type,
     me.metadata->>'body_part' as body_part
@@ -491,6 +568,15 @@ type GetPlayerShotsWithXGRow struct {
 	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
 	Second            *int32             `json:"second"`
 	Period            *string            `json:"period"`
+	PeriodNumber      int16              `json:"period_number"`
+	ClockMs           int64              `json:"clock_ms"`
+	PossessionID      *int32             `json:"possession_id"`
+	GameState         *string            `json:"game_state"`
+	ScoreDifferential *int16             `json:"score_differential"`
+	DerivedType       *string            `json:"derived_type"`
+	Xa                *float64           `json:"xa"`
+	ShotEventID       *int32             `json:"shot_event_id"`
+	CreationType      *string            `json:"creation_type"`
 	ExpectedGoals     interface{}        `json:"expected_goals"`
 	ShotType          interface{}        `json:"shot_type"`
 	BodyPart          interface{}        `json:"body_part"`
@@ -524,6 +610,15 @@ func (q *Queries) GetPlayerShotsWithXG(ctx context.Context, arg GetPlayerShotsWi
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 			&i.ExpectedGoals,
 			&i.ShotType,
 			&i.BodyPart,
@@ -539,20 +634,60 @@ func (q *Queries) GetPlayerShotsWithXG(ctx context.Context, arg GetPlayerShotsWi
 }
 
 const getShotsByMatch = `-- name: GetShotsByMatch :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
-WHERE match_id = $1 AND event_type = 'shot' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+SELECT
+    me.id, me.match_id, me.team_id, me.player_id, me.secondary_player_id, me.event_type, me.minute, me.extra_minute, me.position_x, me.position_y, me.description, me.metadata, me.created_at, me.updated_at, me.deleted_at, me.second, me.period, me.period_number, me.clock_ms, me.possession_id, me.game_state, me.score_differential, me.derived_type, me.xa, me.shot_event_id, me.creation_type,
+    p.full_name as player_name
+FROM match_events me
+LEFT JOIN players p ON p.id = me.player_id
+WHERE me.match_id = $1
+  AND me.event_type IN (
+      'shot', 'shot_on_target', 'shot_off_target', 'shot_blocked', 'shot_saved', 'shot_post', 'shot_woodwork',
+      'goal', 'own_goal', 'penalty', 'penalty_goal', 'penalty_miss'
+  )
+  AND me.deleted_at IS NULL
+ORDER BY me.period_number ASC, me.clock_ms ASC, me.id ASC
 `
 
-func (q *Queries) GetShotsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error) {
+type GetShotsByMatchRow struct {
+	ID                int32              `json:"id"`
+	MatchID           int32              `json:"match_id"`
+	TeamID            *int32             `json:"team_id"`
+	PlayerID          *int32             `json:"player_id"`
+	SecondaryPlayerID *int32             `json:"secondary_player_id"`
+	EventType         string             `json:"event_type"`
+	Minute            int32              `json:"minute"`
+	ExtraMinute       *int32             `json:"extra_minute"`
+	PositionX         pgtype.Numeric     `json:"position_x"`
+	PositionY         pgtype.Numeric     `json:"position_y"`
+	Description       *string            `json:"description"`
+	Metadata          []byte             `json:"metadata"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	Second            *int32             `json:"second"`
+	Period            *string            `json:"period"`
+	PeriodNumber      int16              `json:"period_number"`
+	ClockMs           int64              `json:"clock_ms"`
+	PossessionID      *int32             `json:"possession_id"`
+	GameState         *string            `json:"game_state"`
+	ScoreDifferential *int16             `json:"score_differential"`
+	DerivedType       *string            `json:"derived_type"`
+	Xa                *float64           `json:"xa"`
+	ShotEventID       *int32             `json:"shot_event_id"`
+	CreationType      *string            `json:"creation_type"`
+	PlayerName        *string            `json:"player_name"`
+}
+
+// Every attempt on goal plus own goals, which count for the other team.
+func (q *Queries) GetShotsByMatch(ctx context.Context, matchID int32) ([]GetShotsByMatchRow, error) {
 	rows, err := q.db.Query(ctx, getShotsByMatch, matchID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []MatchEvent{}
+	items := []GetShotsByMatchRow{}
 	for rows.Next() {
-		var i MatchEvent
+		var i GetShotsByMatchRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.MatchID,
@@ -571,6 +706,16 @@ func (q *Queries) GetShotsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
+			&i.PlayerName,
 		); err != nil {
 			return nil, err
 		}
@@ -583,9 +728,9 @@ func (q *Queries) GetShotsByMatch(ctx context.Context, matchID int32) ([]MatchEv
 }
 
 const getTeamEventsInMatch = `-- name: GetTeamEventsInMatch :many
-SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period FROM match_events
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
 WHERE match_id = $1 AND team_id = $2 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC
+ORDER BY period_number ASC, clock_ms ASC, id ASC
 `
 
 type GetTeamEventsInMatchParams struct {
@@ -620,6 +765,15 @@ func (q *Queries) GetTeamEventsInMatch(ctx context.Context, arg GetTeamEventsInM
 			&i.DeletedAt,
 			&i.Second,
 			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -631,38 +785,197 @@ func (q *Queries) GetTeamEventsInMatch(ctx context.Context, arg GetTeamEventsInM
 	return items, nil
 }
 
-const getTeamPossessionEvents = `-- name: GetTeamPossessionEvents :many
-SELECT
-    team_id,
-    COUNT(*) as total_events,
-    COUNT(*) FILTER (WHERE event_type = 'pass') as passes,
-    COUNT(*) FILTER (WHERE event_type = 'shot') as shots
-FROM match_events
-WHERE match_id = $1 AND deleted_at IS NULL
-GROUP BY team_id
+const listEventsByTypes = `-- name: ListEventsByTypes :many
+SELECT id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type FROM match_events
+WHERE id > $1
+  AND event_type = ANY($2::text[])
+  AND deleted_at IS NULL
+ORDER BY id ASC
+LIMIT $3
+`
+
+type ListEventsByTypesParams struct {
+	AfterID    int32    `json:"after_id"`
+	EventTypes []string `json:"event_types"`
+	Limit      int32    `json:"limit"`
+}
+
+func (q *Queries) ListEventsByTypes(ctx context.Context, arg ListEventsByTypesParams) ([]MatchEvent, error) {
+	rows, err := q.db.Query(ctx, listEventsByTypes, arg.AfterID, arg.EventTypes, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MatchEvent{}
+	for rows.Next() {
+		var i MatchEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
+			&i.TeamID,
+			&i.PlayerID,
+			&i.SecondaryPlayerID,
+			&i.EventType,
+			&i.Minute,
+			&i.ExtraMinute,
+			&i.PositionX,
+			&i.PositionY,
+			&i.Description,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Second,
+			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlayerSeasonMatchEvents = `-- name: ListPlayerSeasonMatchEvents :many
+SELECT me.id, me.match_id, me.team_id, me.player_id, me.secondary_player_id, me.event_type, me.minute, me.extra_minute, me.position_x, me.position_y, me.description, me.metadata, me.created_at, me.updated_at, me.deleted_at, me.second, me.period, me.period_number, me.clock_ms, me.possession_id, me.game_state, me.score_differential, me.derived_type, me.xa, me.shot_event_id, me.creation_type FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE (m.status = 'finished' OR m.id = $1)
+  AND EXISTS (
+    SELECT 1 FROM match_events pe
+    WHERE pe.match_id = m.id AND pe.player_id = $2 AND pe.deleted_at IS NULL
+  )
+  AND ($3::text IS NULL OR m.season = $3)
+  AND ($4::text IS NULL OR m.competition = $4)
+  AND me.deleted_at IS NULL
+ORDER BY me.match_id ASC, me.period_number ASC, me.clock_ms ASC, me.id ASC
+`
+
+type ListPlayerSeasonMatchEventsParams struct {
+	MatchID     *int32  `json:"match_id"`
+	PlayerID    *int32  `json:"player_id"`
+	Season      *string `json:"season"`
+	Competition *string `json:"competition"`
+}
+
+// Lists the events of the finished matches a player has events in, by match in match clock order. match_id
+// includes a match whose finished status is not stored yet.
+func (q *Queries) ListPlayerSeasonMatchEvents(ctx context.Context, arg ListPlayerSeasonMatchEventsParams) ([]MatchEvent, error) {
+	rows, err := q.db.Query(ctx, listPlayerSeasonMatchEvents,
+		arg.MatchID,
+		arg.PlayerID,
+		arg.Season,
+		arg.Competition,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MatchEvent{}
+	for rows.Next() {
+		var i MatchEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
+			&i.TeamID,
+			&i.PlayerID,
+			&i.SecondaryPlayerID,
+			&i.EventType,
+			&i.Minute,
+			&i.ExtraMinute,
+			&i.PositionX,
+			&i.PositionY,
+			&i.Description,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Second,
+			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeasonMatchEvents = `-- name: ListSeasonMatchEvents :many
+SELECT me.id, me.match_id, me.team_id, me.player_id, me.secondary_player_id, me.event_type, me.minute, me.extra_minute, me.position_x, me.position_y, me.description, me.metadata, me.created_at, me.updated_at, me.deleted_at, me.second, me.period, me.period_number, me.clock_ms, me.possession_id, me.game_state, me.score_differential, me.derived_type, me.xa, me.shot_event_id, me.creation_type FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE m.status = 'finished'
+  AND ($1::int IS NULL OR m.home_team_id = $1 OR m.away_team_id = $1)
+  AND ($2::text IS NULL OR m.season = $2)
+  AND ($3::text IS NULL OR m.competition = $3)
+  AND me.deleted_at IS NULL
+ORDER BY me.match_id ASC, me.period_number ASC, me.clock_ms ASC, me.id ASC
 `
 
-type GetTeamPossessionEventsRow struct {
-	TeamID      *int32 `json:"team_id"`
-	TotalEvents int64  `json:"total_events"`
-	Passes      int64  `json:"passes"`
-	Shots       int64  `json:"shots"`
+type ListSeasonMatchEventsParams struct {
+	TeamID      *int32  `json:"team_id"`
+	Season      *string `json:"season"`
+	Competition *string `json:"competition"`
 }
 
-func (q *Queries) GetTeamPossessionEvents(ctx context.Context, matchID int32) ([]GetTeamPossessionEventsRow, error) {
-	rows, err := q.db.Query(ctx, getTeamPossessionEvents, matchID)
+// Lists the events of finished matches, optionally limited to one team's matches, by match in match clock order.
+func (q *Queries) ListSeasonMatchEvents(ctx context.Context, arg ListSeasonMatchEventsParams) ([]MatchEvent, error) {
+	rows, err := q.db.Query(ctx, listSeasonMatchEvents, arg.TeamID, arg.Season, arg.Competition)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetTeamPossessionEventsRow{}
+	items := []MatchEvent{}
 	for rows.Next() {
-		var i GetTeamPossessionEventsRow
+		var i MatchEvent
 		if err := rows.Scan(
+			&i.ID,
+			&i.MatchID,
 			&i.TeamID,
-			&i.TotalEvents,
-			&i.Passes,
-			&i.Shots,
+			&i.PlayerID,
+			&i.SecondaryPlayerID,
+			&i.EventType,
+			&i.Minute,
+			&i.ExtraMinute,
+			&i.PositionX,
+			&i.PositionY,
+			&i.Description,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Second,
+			&i.Period,
+			&i.PeriodNumber,
+			&i.ClockMs,
+			&i.PossessionID,
+			&i.GameState,
+			&i.ScoreDifferential,
+			&i.DerivedType,
+			&i.Xa,
+			&i.ShotEventID,
+			&i.CreationType,
 		); err != nil {
 			return nil, err
 		}
@@ -674,36 +987,152 @@ func (q *Queries) GetTeamPossessionEvents(ctx context.Context, matchID int32) ([
 	return items, nil
 }
 
+const setMatchEventDerived = `-- name: SetMatchEventDerived :exec
+UPDATE match_events me
+SET derived_type = NULLIF(data.derived_type, ''),
+    xa = NULLIF(data.xa, 'NaN'),
+    shot_event_id = data.shot_event_id,
+    creation_type = NULLIF(data.creation_type, '')
+FROM match_events e
+LEFT JOIN unnest(
+    $1::int[],
+    $2::text[],
+    $3::float8[],
+    $4::int[],
+    $5::text[]
+) AS data(id, derived_type, xa, shot_event_id, creation_type) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = $6
+  AND e.deleted_at IS NULL
+`
+
+type SetMatchEventDerivedParams struct {
+	Ids           []int32   `json:"ids"`
+	DerivedTypes  []string  `json:"derived_types"`
+	Xas           []float64 `json:"xas"`
+	ShotEventIds  []int32   `json:"shot_event_ids"`
+	CreationTypes []string  `json:"creation_types"`
+	MatchID       int32     `json:"match_id"`
+}
+
+// Stores the passes inferred as assists, second assists and key passes, with their xA (NaN for none), the
+// shot-creating and goal-creating actions, and the shot they led to. Empty types are stored as NULL; events
+// missing from ids are cleared.
+func (q *Queries) SetMatchEventDerived(ctx context.Context, arg SetMatchEventDerivedParams) error {
+	_, err := q.db.Exec(ctx, setMatchEventDerived,
+		arg.Ids,
+		arg.DerivedTypes,
+		arg.Xas,
+		arg.ShotEventIds,
+		arg.CreationTypes,
+		arg.MatchID,
+	)
+	return err
+}
+
+const setMatchEventGameStates = `-- name: SetMatchEventGameStates :exec
+UPDATE match_events me
+SET game_state = data.game_state,
+    score_differential = data.score_differential
+FROM match_events e
+LEFT JOIN unnest($1::int[], $2::text[], $3::int[])
+    AS data(id, game_state, score_differential) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = $4
+  AND e.deleted_at IS NULL
+`
+
+type SetMatchEventGameStatesParams struct {
+	Ids                []int32  `json:"ids"`
+	GameStates         []string `json:"game_states"`
+	ScoreDifferentials []int32  `json:"score_differentials"`
+	MatchID            int32    `json:"match_id"`
+}
+
+// Stores the game state and goal differential of each of a match's events; events missing from ids are cleared.
+func (q *Queries) SetMatchEventGameStates(ctx context.Context, arg SetMatchEventGameStatesParams) error {
+	_, err := q.db.Exec(ctx, setMatchEventGameStates,
+		arg.Ids,
+		arg.GameStates,
+		arg.ScoreDifferentials,
+		arg.MatchID,
+	)
+	return err
+}
+
+const setMatchEventPossessions = `-- name: SetMatchEventPossessions :exec
+UPDATE match_events me
+SET possession_id = data.possession_id
+FROM match_events e
+LEFT JOIN unnest($1::int[], $2::int[]) AS data(id, possession_id) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = $3
+  AND e.deleted_at IS NULL
+`
+
+type SetMatchEventPossessionsParams struct {
+	Ids           []int32 `json:"ids"`
+	PossessionIds []int32 `json:"possession_ids"`
+	MatchID       int32   `json:"match_id"`
+}
+
+// Stores the possession each of a match's events belongs to; events missing from ids are cleared.
+func (q *Queries) SetMatchEventPossessions(ctx context.Context, arg SetMatchEventPossessionsParams) error {
+	_, err := q.db.Exec(ctx, setMatchEventPossessions, arg.Ids, arg.PossessionIds, arg.MatchID)
+	return err
+}
+
 const updateMatchEvent = `-- name: UpdateMatchEvent :one
 UPDATE match_events
 SET
-    event_type = COALESCE($1, event_type),
-    minute = COALESCE($2, minute),
-    extra_minute = COALESCE($3, extra_minute),
-    position_x = COALESCE($4, position_x),
-    position_y = COALESCE($5, position_y),
-    description = COALESCE($6, description),
-    metadata = COALESCE($7, metadata)
-WHERE id = $8 AND deleted_at IS NULL
-RETURNING id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period
+    team_id = COALESCE($1, team_id),
+    player_id = COALESCE($2, player_id),
+    secondary_player_id = COALESCE($3, secondary_player_id),
+    event_type = COALESCE($4, event_type),
+    minute = COALESCE($5, minute),
+    second = COALESCE($6, second),
+    period = COALESCE($7, period),
+    extra_minute = COALESCE($8, extra_minute),
+    period_number = COALESCE($9, period_number),
+    clock_ms = COALESCE($10, clock_ms),
+    position_x = COALESCE($11, position_x),
+    position_y = COALESCE($12, position_y),
+    description = COALESCE($13, description),
+    metadata = COALESCE($14, metadata)
+WHERE id = $15 AND deleted_at IS NULL
+RETURNING id, match_id, team_id, player_id, secondary_player_id, event_type, minute, extra_minute, position_x, position_y, description, metadata, created_at, updated_at, deleted_at, second, period, period_number, clock_ms, possession_id, game_state, score_differential, derived_type, xa, shot_event_id, creation_type
 `
 
 type UpdateMatchEventParams struct {
-	EventType   *string        `json:"event_type"`
-	Minute      *int32         `json:"minute"`
-	ExtraMinute *int32         `json:"extra_minute"`
-	PositionX   pgtype.Numeric `json:"position_x"`
-	PositionY   pgtype.Numeric `json:"position_y"`
-	Description *string        `json:"description"`
-	Metadata    []byte         `json:"metadata"`
-	ID          int32          `json:"id"`
+	TeamID            *int32         `json:"team_id"`
+	PlayerID          *int32         `json:"player_id"`
+	SecondaryPlayerID *int32         `json:"secondary_player_id"`
+	EventType         *string        `json:"event_type"`
+	Minute            *int32         `json:"minute"`
+	Second            *int32         `json:"second"`
+	Period            *string        `json:"period"`
+	ExtraMinute       *int32         `json:"extra_minute"`
+	PeriodNumber      *int16         `json:"period_number"`
+	ClockMs           *int64         `json:"clock_ms"`
+	PositionX         pgtype.Numeric `json:"position_x"`
+	PositionY         pgtype.Numeric `json:"position_y"`
+	Description       *string        `json:"description"`
+	Metadata          []byte         `json:"metadata"`
+	ID                int32          `json:"id"`
 }
 
 func (q *Queries) UpdateMatchEvent(ctx context.Context, arg UpdateMatchEventParams) (MatchEvent, error) {
 	row := q.db.QueryRow(ctx, updateMatchEvent,
+		arg.TeamID,
+		arg.PlayerID,
+		arg.SecondaryPlayerID,
 		arg.EventType,
 		arg.Minute,
+		arg.Second,
+		arg.Period,
 		arg.ExtraMinute,
+		arg.PeriodNumber,
+		arg.ClockMs,
 		arg.PositionX,
 		arg.PositionY,
 		arg.Description,
@@ -729,6 +1158,31 @@ func (q *Queries) UpdateMatchEvent(ctx context.Context, arg UpdateMatchEventPara
 		&i.DeletedAt,
 		&i.Second,
 		&i.Period,
+		&i.PeriodNumber,
+		&i.ClockMs,
+		&i.PossessionID,
+		&i.GameState,
+		&i.ScoreDifferential,
+		&i.DerivedType,
+		&i.Xa,
+		&i.ShotEventID,
+		&i.CreationType,
 	)
 	return i, err
 }
+
+const updateMatchEventMetadata = `-- name: UpdateMatchEventMetadata :exec
+UPDATE match_events
+SET metadata = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateMatchEventMetadataParams struct {
+	ID       int32  `json:"id"`
+	Metadata []byte `json:"metadata"`
+}
+
+func (q *Queries) UpdateMatchEventMetadata(ctx context.Context, arg UpdateMatchEventMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateMatchEventMetadata, arg.ID, arg.Metadata)
+	return err
+}
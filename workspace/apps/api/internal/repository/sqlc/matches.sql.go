@@ -42,7 +42,7 @@ INSERT INTO matches (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at
+RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at
 `
 
 type CreateMatchParams struct {
@@ -93,6 +93,7 @@ func (q *Queries) CreateMatch(ctx context.Context, arg CreateMatchParams) (Match
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
@@ -108,8 +109,63 @@ func (q *Queries) DeleteMatch(ctx context.Context, id int32) error {
 	return err
 }
 
+const getHeadToHeadMatches = `-- name: GetHeadToHeadMatches :many
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
+WHERE ((home_team_id = $1 AND away_team_id = $2)
+    OR (home_team_id = $2 AND away_team_id = $1))
+  AND status = 'finished'
+  AND ($3::text IS NULL OR competition = $3)
+  AND deleted_at IS NULL
+ORDER BY match_date DESC
+`
+
+type GetHeadToHeadMatchesParams struct {
+	TeamID      int32   `json:"team_id"`
+	OpponentID  int32   `json:"opponent_id"`
+	Competition *string `json:"competition"`
+}
+
+// Lists the finished matches between two teams, with either at home, latest first.
+func (q *Queries) GetHeadToHeadMatches(ctx context.Context, arg GetHeadToHeadMatchesParams) ([]Match, error) {
+	rows, err := q.db.Query(ctx, getHeadToHeadMatches, arg.TeamID, arg.OpponentID, arg.Competition)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Match{}
+	for rows.Next() {
+		var i Match
+		if err := rows.Scan(
+			&i.ID,
+			&i.HomeTeamID,
+			&i.AwayTeamID,
+			&i.MatchDate,
+			&i.Competition,
+			&i.Season,
+			&i.Round,
+			&i.Stadium,
+			&i.Attendance,
+			&i.Status,
+			&i.Referee,
+			&i.HomeTeamScore,
+			&i.AwayTeamScore,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLiveMatches = `-- name: GetLiveMatches :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE status = 'live' AND deleted_at IS NULL
 ORDER BY match_date DESC
 `
@@ -140,6 +196,7 @@ func (q *Queries) GetLiveMatches(ctx context.Context) ([]Match, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +209,7 @@ func (q *Queries) GetLiveMatches(ctx context.Context) ([]Match, error) {
 }
 
 const getMatchByID = `-- name: GetMatchByID :one
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 `
@@ -177,13 +234,14 @@ func (q *Queries) GetMatchByID(ctx context.Context, id int32) (Match, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
 
 const getMatchWithTeams = `-- name: GetMatchWithTeams :one
 SELECT
-    m.id, m.home_team_id, m.away_team_id, m.match_date, m.competition, m.season, m.round, m.stadium, m.attendance, m.status, m.referee, m.home_team_score, m.away_team_score, m.created_at, m.updated_at, m.deleted_at,
+    m.id, m.home_team_id, m.away_team_id, m.match_date, m.competition, m.season, m.round, m.stadium, m.attendance, m.status, m.referee, m.home_team_score, m.away_team_score, m.created_at, m.updated_at, m.deleted_at, m.finished_at,
     ht.id as home_team_id,
     ht.name as home_team_name,
     ht.short_name as home_team_short_name,
@@ -218,6 +276,7 @@ type GetMatchWithTeamsRow struct {
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	FinishedAt        pgtype.Timestamptz `json:"finished_at"`
 	HomeTeamID_2      *int32             `json:"home_team_id_2"`
 	HomeTeamName      *string            `json:"home_team_name"`
 	HomeTeamShortName *string            `json:"home_team_short_name"`
@@ -250,6 +309,7 @@ func (q *Queries) GetMatchWithTeams(ctx context.Context, id int32) (GetMatchWith
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 		&i.HomeTeamID_2,
 		&i.HomeTeamName,
 		&i.HomeTeamShortName,
@@ -265,7 +325,7 @@ func (q *Queries) GetMatchWithTeams(ctx context.Context, id int32) (GetMatchWith
 }
 
 const getMatchesByCompetition = `-- name: GetMatchesByCompetition :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE competition = $1 AND deleted_at IS NULL
 ORDER BY match_date DESC
 LIMIT $2 OFFSET $3
@@ -303,6 +363,7 @@ func (q *Queries) GetMatchesByCompetition(ctx context.Context, arg GetMatchesByC
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -315,7 +376,7 @@ func (q *Queries) GetMatchesByCompetition(ctx context.Context, arg GetMatchesByC
 }
 
 const getMatchesByCompetitionAndSeason = `-- name: GetMatchesByCompetitionAndSeason :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE competition = $1 AND season = $2 AND deleted_at IS NULL
 ORDER BY match_date DESC
 `
@@ -351,6 +412,7 @@ func (q *Queries) GetMatchesByCompetitionAndSeason(ctx context.Context, arg GetM
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -363,7 +425,7 @@ func (q *Queries) GetMatchesByCompetitionAndSeason(ctx context.Context, arg GetM
 }
 
 const getMatchesBySeason = `-- name: GetMatchesBySeason :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE season = $1 AND deleted_at IS NULL
 ORDER BY match_date DESC
 LIMIT $2 OFFSET $3
@@ -401,6 +463,7 @@ func (q *Queries) GetMatchesBySeason(ctx context.Context, arg GetMatchesBySeason
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -413,7 +476,7 @@ func (q *Queries) GetMatchesBySeason(ctx context.Context, arg GetMatchesBySeason
 }
 
 const getMatchesByStatus = `-- name: GetMatchesByStatus :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE status = $1 AND deleted_at IS NULL
 ORDER BY match_date DESC
 LIMIT $2 OFFSET $3
@@ -451,6 +514,7 @@ func (q *Queries) GetMatchesByStatus(ctx context.Context, arg GetMatchesByStatus
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -463,7 +527,7 @@ func (q *Queries) GetMatchesByStatus(ctx context.Context, arg GetMatchesByStatus
 }
 
 const getMatchesByTeam = `-- name: GetMatchesByTeam :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE (home_team_id = $1 OR away_team_id = $1)
   AND deleted_at IS NULL
 ORDER BY match_date DESC
@@ -502,6 +566,7 @@ func (q *Queries) GetMatchesByTeam(ctx context.Context, arg GetMatchesByTeamPara
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -514,7 +579,7 @@ func (q *Queries) GetMatchesByTeam(ctx context.Context, arg GetMatchesByTeamPara
 }
 
 const getUpcomingMatches = `-- name: GetUpcomingMatches :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE match_date > NOW() AND status = 'scheduled' AND deleted_at IS NULL
 ORDER BY match_date ASC
 LIMIT $1
@@ -546,6 +611,7 @@ func (q *Queries) GetUpcomingMatches(ctx context.Context, limit int32) ([]Match,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -558,7 +624,7 @@ func (q *Queries) GetUpcomingMatches(ctx context.Context, limit int32) ([]Match,
 }
 
 const listMatches = `-- name: ListMatches :many
-SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at FROM matches
+SELECT id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at FROM matches
 WHERE deleted_at IS NULL
 ORDER BY match_date DESC
 LIMIT $1 OFFSET $2
@@ -595,6 +661,7 @@ func (q *Queries) ListMatches(ctx context.Context, arg ListMatchesParams) ([]Mat
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -620,7 +687,7 @@ SET
     home_team_score = COALESCE($9, home_team_score),
     away_team_score = COALESCE($10, away_team_score)
 WHERE id = $11 AND deleted_at IS NULL
-RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at
+RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at
 `
 
 type UpdateMatchParams struct {
@@ -669,6 +736,7 @@ func (q *Queries) UpdateMatch(ctx context.Context, arg UpdateMatchParams) (Match
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
@@ -679,7 +747,7 @@ SET
     home_team_score = $2,
     away_team_score = $3
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at
+RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at
 `
 
 type UpdateMatchScoreParams struct {
@@ -708,6 +776,7 @@ func (q *Queries) UpdateMatchScore(ctx context.Context, arg UpdateMatchScorePara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
@@ -716,7 +785,7 @@ const updateMatchStatus = `-- name: UpdateMatchStatus :one
 UPDATE matches
 SET status = $2
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at
+RETURNING id, home_team_id, away_team_id, match_date, competition, season, round, stadium, attendance, status, referee, home_team_score, away_team_score, created_at, updated_at, deleted_at, finished_at
 `
 
 type UpdateMatchStatusParams struct {
@@ -744,6 +813,7 @@ func (q *Queries) UpdateMatchStatus(ctx context.Context, arg UpdateMatchStatusPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
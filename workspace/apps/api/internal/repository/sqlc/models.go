@@ -8,6 +8,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type GoalkeeperMatchStatistic struct {
+	ID             int32              `json:"id"`
+	MatchID        int32              `json:"match_id"`
+	PlayerID       int32              `json:"player_id"`
+	TeamID         int32              `json:"team_id"`
+	CleanSheet     bool               `json:"clean_sheet"`
+	ShotsOnTarget  int32              `json:"shots_on_target"`
+	Saves          int32              `json:"saves"`
+	GoalsConceded  int32              `json:"goals_conceded"`
+	PostShotXg     float64            `json:"post_shot_xg"`
+	GoalsPrevented float64            `json:"goals_prevented"`
+	PenaltiesFaced int32              `json:"penalties_faced"`
+	PenaltiesSaved int32              `json:"penalties_saved"`
+	CrossesFaced   int32              `json:"crosses_faced"`
+	CrossesClaimed int32              `json:"crosses_claimed"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type Match struct {
 	ID            int32              `json:"id"`
 	HomeTeamID    int32              `json:"home_team_id"`
@@ -25,6 +43,7 @@ type Match struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	FinishedAt    pgtype.Timestamptz `json:"finished_at"`
 }
 
 type MatchEvent struct {
@@ -45,6 +64,58 @@ type MatchEvent struct {
 	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
 	Second            *int32             `json:"second"`
 	Period            *string            `json:"period"`
+	PeriodNumber      int16              `json:"period_number"`
+	ClockMs           int64              `json:"clock_ms"`
+	PossessionID      *int32             `json:"possession_id"`
+	GameState         *string            `json:"game_state"`
+	ScoreDifferential *int16             `json:"score_differential"`
+	DerivedType       *string            `json:"derived_type"`
+	Xa                *float64           `json:"xa"`
+	ShotEventID       *int32             `json:"shot_event_id"`
+	CreationType      *string            `json:"creation_type"`
+}
+
+type MatchLineup struct {
+	ID        int32              `json:"id"`
+	MatchID   int32              `json:"match_id"`
+	TeamID    int32              `json:"team_id"`
+	Formation *string            `json:"formation"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MatchLineupPlayer struct {
+	ID            int32              `json:"id"`
+	LineupID      int32              `json:"lineup_id"`
+	PlayerID      int32              `json:"player_id"`
+	ShirtNumber   *int32             `json:"shirt_number"`
+	Position      *string            `json:"position"`
+	Starter       bool               `json:"starter"`
+	MinutesPlayed *int32             `json:"minutes_played"`
+	SubbedOn      bool               `json:"subbed_on"`
+	SubbedOff     bool               `json:"subbed_off"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type MatchWinProbability struct {
+	ID           int32              `json:"id"`
+	MatchID      int32              `json:"match_id"`
+	EventID      int32              `json:"event_id"`
+	Period       string             `json:"period"`
+	Minute       int32              `json:"minute"`
+	Stoppage     int32              `json:"stoppage"`
+	Second       int32              `json:"second"`
+	PeriodNumber int16              `json:"period_number"`
+	ClockMs      int64              `json:"clock_ms"`
+	HomeGoals    int32              `json:"home_goals"`
+	AwayGoals    int32              `json:"away_goals"`
+	HomeRedCards int32              `json:"home_red_cards"`
+	AwayRedCards int32              `json:"away_red_cards"`
+	HomeWin      float64            `json:"home_win"`
+	Draw         float64            `json:"draw"`
+	AwayWin      float64            `json:"away_win"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
 type Player struct {
@@ -128,6 +199,34 @@ type Team struct {
 	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
 }
 
+type TeamPressingMatchStatistic struct {
+	ID                   int32              `json:"id"`
+	MatchID              int32              `json:"match_id"`
+	TeamID               int32              `json:"team_id"`
+	OpponentPasses       int32              `json:"opponent_passes"`
+	DefensiveActions     int32              `json:"defensive_actions"`
+	HighTurnovers        int32              `json:"high_turnovers"`
+	FinalThirdRecoveries int32              `json:"final_third_recoveries"`
+	BallLosses           int32              `json:"ball_losses"`
+	CounterPressRegains  int32              `json:"counter_press_regains"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+}
+
+type TeamRating struct {
+	ID            int32              `json:"id"`
+	TeamID        int32              `json:"team_id"`
+	OpponentID    int32              `json:"opponent_id"`
+	MatchID       int32              `json:"match_id"`
+	Competition   string             `json:"competition"`
+	Season        string             `json:"season"`
+	MatchDate     pgtype.Timestamptz `json:"match_date"`
+	Home          bool               `json:"home"`
+	RatingBefore  float64            `json:"rating_before"`
+	RatingAfter   float64            `json:"rating_after"`
+	ExpectedScore float64            `json:"expected_score"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type TeamStatistic struct {
 	ID                      int32              `json:"id"`
 	TeamID                  int32              `json:"team_id"`
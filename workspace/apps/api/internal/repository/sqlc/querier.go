@@ -17,22 +17,48 @@ type Querier interface {
 	CountPlayersByTeam(ctx context.Context, teamID int32) (int64, error)
 	CountTeams(ctx context.Context) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
+	// Stores the statistics of a match's goalkeepers.
+	CreateGoalkeeperMatchStats(ctx context.Context, arg CreateGoalkeeperMatchStatsParams) error
 	CreateMatch(ctx context.Context, arg CreateMatchParams) (Match, error)
 	CreateMatchEvent(ctx context.Context, arg CreateMatchEventParams) (MatchEvent, error)
+	// Adds players to a lineup; a shirt number of 0 and an empty position are stored as NULL.
+	CreateMatchLineupPlayers(ctx context.Context, arg CreateMatchLineupPlayersParams) error
 	CreatePlayer(ctx context.Context, arg CreatePlayerParams) (Player, error)
 	CreatePlayerStats(ctx context.Context, arg CreatePlayerStatsParams) (PlayerStatistic, error)
 	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
+	// Stores the pressing statistics of a match's teams.
+	CreateTeamPressingMatchStats(ctx context.Context, arg CreateTeamPressingMatchStatsParams) error
+	// Stores rating changes; the competition, season and date are the match's.
+	CreateTeamRatings(ctx context.Context, arg CreateTeamRatingsParams) error
 	CreateTeamStats(ctx context.Context, arg CreateTeamStatsParams) (TeamStatistic, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// Stores a match's win probabilities; an event's existing point is kept.
+	CreateWinProbabilities(ctx context.Context, arg CreateWinProbabilitiesParams) error
+	// Deletes the statistics of a match's goalkeepers and returns the goalkeepers.
+	DeleteGoalkeeperMatchStats(ctx context.Context, matchID int32) ([]int32, error)
 	DeleteMatch(ctx context.Context, id int32) error
 	DeleteMatchEvent(ctx context.Context, id int32) error
+	DeleteMatchLineupPlayers(ctx context.Context, lineupID int32) error
 	DeletePlayer(ctx context.Context, id int32) error
 	DeletePlayerStats(ctx context.Context, id int32) error
 	DeleteTeam(ctx context.Context, id int32) error
+	DeleteTeamPressingMatchStats(ctx context.Context, matchID int32) error
+	DeleteTeamRatings(ctx context.Context, competition string) error
 	DeleteTeamStats(ctx context.Context, id int32) error
 	DeleteUser(ctx context.Context, id int32) error
+	DeleteWinProbabilities(ctx context.Context, matchID int32) error
 	GetCardsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error)
 	GetGoalsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error)
+	// Sums each team's event statistics over the given matches, following the live stats
+	// classification; matches counts the matches with events for the team. Shootout kicks do not count.
+	GetHeadToHeadEventStats(ctx context.Context, matchIds []int32) ([]GetHeadToHeadEventStatsRow, error)
+	// Lists the finished matches between two teams, with either at home, latest first.
+	GetHeadToHeadMatches(ctx context.Context, arg GetHeadToHeadMatchesParams) ([]Match, error)
+	// Counts each player's goals in the given matches, for the team they scored for. Own goals
+	// and shootout kicks do not count.
+	GetHeadToHeadScorers(ctx context.Context, arg GetHeadToHeadScorersParams) ([]GetHeadToHeadScorersRow, error)
+	// Gets a match's latest win probability in match clock order.
+	GetLatestWinProbability(ctx context.Context, matchID int32) (MatchWinProbability, error)
 	GetLeagueTable(ctx context.Context, arg GetLeagueTableParams) ([]GetLeagueTableRow, error)
 	GetLiveMatches(ctx context.Context) ([]Match, error)
 	GetMatchByID(ctx context.Context, id int32) (Match, error)
@@ -47,7 +73,12 @@ type Querier interface {
 	GetMatchesByTeam(ctx context.Context, arg GetMatchesByTeamParams) ([]Match, error)
 	GetPassesByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error)
 	GetPlayerByID(ctx context.Context, id int32) (Player, error)
+	GetPlayerCreation(ctx context.Context, arg GetPlayerCreationParams) (GetPlayerCreationRow, error)
+	GetPlayerCreationRankings(ctx context.Context, arg GetPlayerCreationRankingsParams) ([]GetPlayerCreationRankingsRow, error)
 	GetPlayerEvents(ctx context.Context, arg GetPlayerEventsParams) ([]MatchEvent, error)
+	// Adds up goalkeepers' match statistics in a season and competition, with their minutes played from the match lineups.
+	GetPlayerGoalkeeperRankings(ctx context.Context, arg GetPlayerGoalkeeperRankingsParams) ([]GetPlayerGoalkeeperRankingsRow, error)
+	GetPlayerHeatmapPoints(ctx context.Context, arg GetPlayerHeatmapPointsParams) ([]GetPlayerHeatmapPointsRow, error)
 	GetPlayerPassAccuracy(ctx context.Context, playerID *int32) (GetPlayerPassAccuracyRow, error)
 	// Analytics queries for match events
 	GetPlayerShotsWithXG(ctx context.Context, arg GetPlayerShotsWithXGParams) ([]GetPlayerShotsWithXGRow, error)
@@ -55,41 +86,100 @@ type Querier interface {
 	GetPlayerStatsByID(ctx context.Context, id int32) (PlayerStatistic, error)
 	GetPlayerStatsByPlayer(ctx context.Context, playerID int32) ([]PlayerStatistic, error)
 	GetPlayerStatsByPlayerAndSeason(ctx context.Context, arg GetPlayerStatsByPlayerAndSeasonParams) (PlayerStatistic, error)
+	GetPlayerThreat(ctx context.Context, arg GetPlayerThreatParams) (GetPlayerThreatRow, error)
+	GetPlayerThreatRankings(ctx context.Context, arg GetPlayerThreatRankingsParams) ([]GetPlayerThreatRankingsRow, error)
 	GetPlayerWithTeam(ctx context.Context, id int32) (GetPlayerWithTeamRow, error)
 	GetPlayersByPosition(ctx context.Context, arg GetPlayersByPositionParams) ([]Player, error)
 	GetPlayersByTeam(ctx context.Context, teamID int32) ([]Player, error)
-	GetShotsByMatch(ctx context.Context, matchID int32) ([]MatchEvent, error)
+	// Every attempt on goal plus own goals, which count for the other team.
+	GetShotsByMatch(ctx context.Context, matchID int32) ([]GetShotsByMatchRow, error)
 	GetTeamByCode(ctx context.Context, code string) (Team, error)
 	GetTeamByID(ctx context.Context, id int32) (Team, error)
+	GetTeamCreation(ctx context.Context, arg GetTeamCreationParams) (GetTeamCreationRow, error)
+	GetTeamCreationRankings(ctx context.Context, arg GetTeamCreationRankingsParams) ([]GetTeamCreationRankingsRow, error)
 	GetTeamEventsInMatch(ctx context.Context, arg GetTeamEventsInMatchParams) ([]MatchEvent, error)
-	GetTeamPossessionEvents(ctx context.Context, matchID int32) ([]GetTeamPossessionEventsRow, error)
+	// Adds up the match statistics of teams' goalkeepers in a season and competition, with the goalkeepers' minutes
+	// played from the match lineups.
+	GetTeamGoalkeeperRankings(ctx context.Context, arg GetTeamGoalkeeperRankingsParams) ([]GetTeamGoalkeeperRankingsRow, error)
+	GetTeamHeatmapPoints(ctx context.Context, arg GetTeamHeatmapPointsParams) ([]GetTeamHeatmapPointsRow, error)
+	// Adds up the pressing statistics of teams' finished matches in a season and competition.
+	GetTeamPressingRankings(ctx context.Context, arg GetTeamPressingRankingsParams) ([]GetTeamPressingRankingsRow, error)
+	// Lists a team's rating changes oldest first, optionally in one competition.
+	GetTeamRatingHistory(ctx context.Context, arg GetTeamRatingHistoryParams) ([]TeamRating, error)
 	// Team Statistics Queries
 	GetTeamStatsByID(ctx context.Context, id int32) (TeamStatistic, error)
 	GetTeamStatsByTeam(ctx context.Context, teamID int32) ([]TeamStatistic, error)
 	GetTeamStatsByTeamAndSeason(ctx context.Context, arg GetTeamStatsByTeamAndSeasonParams) (TeamStatistic, error)
+	GetTeamThreat(ctx context.Context, arg GetTeamThreatParams) (GetTeamThreatRow, error)
+	GetTeamThreatRankings(ctx context.Context, arg GetTeamThreatRankingsParams) ([]GetTeamThreatRankingsRow, error)
 	GetTeamsByCountry(ctx context.Context, country string) ([]Team, error)
 	GetTopAssisters(ctx context.Context, arg GetTopAssistersParams) ([]GetTopAssistersRow, error)
 	GetTopScorers(ctx context.Context, arg GetTopScorersParams) ([]GetTopScorersRow, error)
 	GetUpcomingMatches(ctx context.Context, limit int32) ([]Match, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
+	// Lists a match's win probabilities in match clock order.
+	GetWinProbabilities(ctx context.Context, matchID int32) ([]MatchWinProbability, error)
+	ListEventsByTypes(ctx context.Context, arg ListEventsByTypesParams) ([]MatchEvent, error)
+	// Lists the goals and the last event of each period in finished matches, from which the time spent in each game state is measured.
+	ListGameStateEvents(ctx context.Context, arg ListGameStateEventsParams) ([]ListGameStateEventsRow, error)
+	// Lists the players in a match's lineups by team, starters first, by shirt number.
+	ListMatchLineupPlayers(ctx context.Context, matchID int32) ([]ListMatchLineupPlayersRow, error)
+	ListMatchLineups(ctx context.Context, matchID int32) ([]MatchLineup, error)
+	// Lists finished matches oldest first, optionally in one competition, with each team's xG from its shots
+	// (NULL without shot events). match_id includes a match whose finished status is not stored yet.
+	ListMatchResults(ctx context.Context, arg ListMatchResultsParams) ([]ListMatchResultsRow, error)
 	ListMatches(ctx context.Context, arg ListMatchesParams) ([]Match, error)
+	// Lists the goals, substitutions, sendings-off and the last event of each period in the finished matches a player is in the lineups of, with the team the player played for, from which the player's time on the pitch in each game state is measured.
+	ListPlayerGameStateEvents(ctx context.Context, arg ListPlayerGameStateEventsParams) ([]ListPlayerGameStateEventsRow, error)
+	// Lists players' season statistics in every season and competition with their xT and ball progression totals from events. Seasons with fewer than min_minutes played are left out, except for player_id's.
+	ListPlayerProfiles(ctx context.Context, arg ListPlayerProfilesParams) ([]ListPlayerProfilesRow, error)
+	// Lists the events of the finished matches a player has events in, by match in match clock order. match_id
+	// includes a match whose finished status is not stored yet.
+	ListPlayerSeasonMatchEvents(ctx context.Context, arg ListPlayerSeasonMatchEventsParams) ([]MatchEvent, error)
+	// Lists the season statistics of the players in a competition with at least min_minutes played, with their position.
+	ListPlayerStatsPool(ctx context.Context, arg ListPlayerStatsPoolParams) ([]ListPlayerStatsPoolRow, error)
 	ListPlayers(ctx context.Context, arg ListPlayersParams) ([]Player, error)
+	// Lists the events of finished matches, optionally limited to one team's matches, by match in match clock order.
+	ListSeasonMatchEvents(ctx context.Context, arg ListSeasonMatchEventsParams) ([]MatchEvent, error)
 	ListTeams(ctx context.Context, arg ListTeamsParams) ([]Team, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	// Recomputes the clean sheets, goals conceded, saves, save percentage (NULL for no shots on target faced) and
+	// penalties saved of goalkeepers in a match's season and competition from their match statistics in its finished
+	// matches and the match itself. Goalkeepers left without match statistics are reset.
+	RefreshGoalkeeperStats(ctx context.Context, arg RefreshGoalkeeperStatsParams) error
+	// Recomputes the appearance statistics of a match's players in the match's season and competition from the lineups with minutes played.
+	RefreshPlayerAppearances(ctx context.Context, id int32) error
 	SearchPlayersByName(ctx context.Context, arg SearchPlayersByNameParams) ([]Player, error)
 	SearchTeamsByName(ctx context.Context, arg SearchTeamsByNameParams) ([]Team, error)
+	// Stores the passes inferred as assists, second assists and key passes, with their xA (NaN for none), the
+	// shot-creating and goal-creating actions, and the shot they led to. Empty types are stored as NULL; events
+	// missing from ids are cleared.
+	SetMatchEventDerived(ctx context.Context, arg SetMatchEventDerivedParams) error
+	// Stores the game state and goal differential of each of a match's events; events missing from ids are cleared.
+	SetMatchEventGameStates(ctx context.Context, arg SetMatchEventGameStatesParams) error
+	// Stores the possession each of a match's events belongs to; events missing from ids are cleared.
+	SetMatchEventPossessions(ctx context.Context, arg SetMatchEventPossessionsParams) error
+	// Stores the minutes played by the players in a match's lineups and whether they were substituted on or off.
+	SetMatchLineupMinutes(ctx context.Context, arg SetMatchLineupMinutesParams) error
 	UpdateMatch(ctx context.Context, arg UpdateMatchParams) (Match, error)
 	UpdateMatchEvent(ctx context.Context, arg UpdateMatchEventParams) (MatchEvent, error)
+	UpdateMatchEventMetadata(ctx context.Context, arg UpdateMatchEventMetadataParams) error
 	UpdateMatchScore(ctx context.Context, arg UpdateMatchScoreParams) (Match, error)
 	UpdateMatchStatus(ctx context.Context, arg UpdateMatchStatusParams) (Match, error)
 	UpdatePlayer(ctx context.Context, arg UpdatePlayerParams) (Player, error)
+	// Recounts the assists and key passes, assists included, of a match's players in its season and competition
+	// from provider assist and key_pass events and derived ones, each pass counted once. Players of deleted events
+	// are recounted too.
+	UpdatePlayerCreativeStats(ctx context.Context, matchID int32) error
 	UpdatePlayerStats(ctx context.Context, arg UpdatePlayerStatsParams) (PlayerStatistic, error)
 	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
 	UpdateTeamStats(ctx context.Context, arg UpdateTeamStatsParams) (TeamStatistic, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	// Creates a team's lineup for a match or replaces its formation.
+	UpsertMatchLineup(ctx context.Context, arg UpsertMatchLineupParams) (MatchLineup, error)
 }
 
 var _ Querier = (*Queries)(nil)
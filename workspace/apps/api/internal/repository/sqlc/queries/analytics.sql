@@ -0,0 +1,383 @@
+-- name: GetPlayerCreation :one
+SELECT
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM(me.xa), 0)::float8 as expected_assists,
+    COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass')) as key_passes,
+    COUNT(*) FILTER (WHERE me.creation_type IS NOT NULL) as shot_creating_actions,
+    COUNT(*) FILTER (WHERE me.creation_type = 'goal_creating') as goal_creating_actions
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.player_id = sqlc.arg('player_id')
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('min_differential')::int IS NULL OR me.score_differential >= sqlc.narg('min_differential'))
+  AND (sqlc.narg('max_differential')::int IS NULL OR me.score_differential <= sqlc.narg('max_differential'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetPlayerCreationRankings :many
+SELECT
+    p.id as player_id,
+    p.full_name,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(MAX(ps.minutes_played), 0)::int as minutes_played,
+    COALESCE(SUM(me.xa), 0)::float8 as expected_assists,
+    COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass')) as key_passes,
+    COUNT(*) FILTER (WHERE me.creation_type IS NOT NULL) as shot_creating_actions,
+    COUNT(*) FILTER (WHERE me.creation_type = 'goal_creating') as goal_creating_actions
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+JOIN players p ON me.player_id = p.id AND p.deleted_at IS NULL
+JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL
+LEFT JOIN player_statistics ps ON ps.player_id = p.id
+    AND ps.season = m.season
+    AND ps.competition = m.competition
+    AND ps.deleted_at IS NULL
+WHERE m.season = $1
+  AND m.competition = $2
+  AND me.deleted_at IS NULL
+GROUP BY p.id, p.full_name, t.name, t.logo;
+
+-- name: GetPlayerGoalkeeperRankings :many
+-- Adds up goalkeepers' match statistics in a season and competition, with their minutes played from the match lineups.
+SELECT
+    p.id as player_id,
+    p.full_name,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(*) as matches,
+    COALESCE(SUM(lp.minutes_played), 0)::int as minutes_played,
+    SUM(gs.shots_on_target)::int as shots_on_target,
+    SUM(gs.saves)::int as saves,
+    SUM(gs.goals_prevented)::float8 as goals_prevented,
+    SUM(gs.crosses_faced)::int as crosses_faced,
+    SUM(gs.crosses_claimed)::int as crosses_claimed
+FROM goalkeeper_match_statistics gs
+JOIN matches m ON gs.match_id = m.id AND m.deleted_at IS NULL
+JOIN players p ON gs.player_id = p.id AND p.deleted_at IS NULL
+JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL
+LEFT JOIN match_lineups l ON l.match_id = gs.match_id AND l.team_id = gs.team_id
+LEFT JOIN match_lineup_players lp ON lp.lineup_id = l.id AND lp.player_id = gs.player_id
+WHERE m.season = $1
+  AND m.competition = $2
+GROUP BY p.id, p.full_name, t.name, t.logo;
+
+-- name: GetPlayerHeatmapPoints :many
+SELECT
+    me.position_x::float8 as x,
+    me.position_y::float8 as y,
+    (me.metadata->>'coordinates')::text as coordinates,
+    (me.metadata->>'provider')::text as provider
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.player_id = sqlc.arg('player_id')
+  AND me.position_x IS NOT NULL
+  AND me.position_y IS NOT NULL
+  AND (sqlc.narg('match_id')::int IS NULL OR me.match_id = sqlc.narg('match_id'))
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('event_types')::text[] IS NULL OR me.event_type = ANY(sqlc.narg('event_types')::text[]))
+  AND (sqlc.narg('period')::text IS NULL OR me.period = sqlc.narg('period'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetPlayerThreat :one
+SELECT
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM((me.metadata->>'xT')::numeric), 0)::float8 as expected_threat,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_pass' = 'true') as progressive_passes,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_carry' = 'true') as progressive_carries,
+    COUNT(*) FILTER (WHERE me.metadata->>'box_entry' = 'true') as box_entries
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.player_id = sqlc.arg('player_id')
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('min_differential')::int IS NULL OR me.score_differential >= sqlc.narg('min_differential'))
+  AND (sqlc.narg('max_differential')::int IS NULL OR me.score_differential <= sqlc.narg('max_differential'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetPlayerThreatRankings :many
+SELECT
+    p.id as player_id,
+    p.full_name,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(MAX(ps.minutes_played), 0)::int as minutes_played,
+    COALESCE(SUM((me.metadata->>'xT')::numeric), 0)::float8 as expected_threat,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_pass' = 'true') as progressive_passes,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_carry' = 'true') as progressive_carries,
+    COUNT(*) FILTER (WHERE me.metadata->>'box_entry' = 'true') as box_entries
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+JOIN players p ON me.player_id = p.id AND p.deleted_at IS NULL
+JOIN teams t ON p.team_id = t.id AND t.deleted_at IS NULL
+LEFT JOIN player_statistics ps ON ps.player_id = p.id
+    AND ps.season = m.season
+    AND ps.competition = m.competition
+    AND ps.deleted_at IS NULL
+WHERE m.season = $1
+  AND m.competition = $2
+  AND me.deleted_at IS NULL
+GROUP BY p.id, p.full_name, t.name, t.logo;
+
+-- name: GetTeamCreation :one
+SELECT
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM(me.xa), 0)::float8 as expected_assists,
+    COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass')) as key_passes,
+    COUNT(*) FILTER (WHERE me.creation_type IS NOT NULL) as shot_creating_actions,
+    COUNT(*) FILTER (WHERE me.creation_type = 'goal_creating') as goal_creating_actions
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.team_id = sqlc.arg('team_id')
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('min_differential')::int IS NULL OR me.score_differential >= sqlc.narg('min_differential'))
+  AND (sqlc.narg('max_differential')::int IS NULL OR me.score_differential <= sqlc.narg('max_differential'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetTeamCreationRankings :many
+SELECT
+    t.id as team_id,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM(me.xa), 0)::float8 as expected_assists,
+    COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass')) as key_passes,
+    COUNT(*) FILTER (WHERE me.creation_type IS NOT NULL) as shot_creating_actions,
+    COUNT(*) FILTER (WHERE me.creation_type = 'goal_creating') as goal_creating_actions
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+JOIN teams t ON me.team_id = t.id AND t.deleted_at IS NULL
+WHERE m.season = $1
+  AND m.competition = $2
+  AND me.deleted_at IS NULL
+GROUP BY t.id, t.name, t.logo;
+
+-- name: GetTeamGoalkeeperRankings :many
+-- Adds up the match statistics of teams' goalkeepers in a season and competition, with the goalkeepers' minutes
+-- played from the match lineups.
+SELECT
+    t.id as team_id,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(DISTINCT gs.match_id) as matches,
+    COALESCE(SUM(lp.minutes_played), 0)::int as minutes_played,
+    SUM(gs.shots_on_target)::int as shots_on_target,
+    SUM(gs.saves)::int as saves,
+    SUM(gs.goals_prevented)::float8 as goals_prevented,
+    SUM(gs.crosses_faced)::int as crosses_faced,
+    SUM(gs.crosses_claimed)::int as crosses_claimed
+FROM goalkeeper_match_statistics gs
+JOIN matches m ON gs.match_id = m.id AND m.deleted_at IS NULL
+JOIN teams t ON gs.team_id = t.id AND t.deleted_at IS NULL
+LEFT JOIN match_lineups l ON l.match_id = gs.match_id AND l.team_id = gs.team_id
+LEFT JOIN match_lineup_players lp ON lp.lineup_id = l.id AND lp.player_id = gs.player_id
+WHERE m.season = $1
+  AND m.competition = $2
+GROUP BY t.id, t.name, t.logo;
+
+-- name: GetTeamHeatmapPoints :many
+SELECT
+    me.position_x::float8 as x,
+    me.position_y::float8 as y,
+    (me.metadata->>'coordinates')::text as coordinates,
+    (me.metadata->>'provider')::text as provider
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.team_id = sqlc.arg('team_id')
+  AND me.position_x IS NOT NULL
+  AND me.position_y IS NOT NULL
+  AND (sqlc.narg('match_id')::int IS NULL OR me.match_id = sqlc.narg('match_id'))
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('event_types')::text[] IS NULL OR me.event_type = ANY(sqlc.narg('event_types')::text[]))
+  AND (sqlc.narg('period')::text IS NULL OR me.period = sqlc.narg('period'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetTeamPressingRankings :many
+-- Adds up the pressing statistics of teams' finished matches in a season and competition.
+SELECT
+    t.id as team_id,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(*) as matches,
+    SUM(ps.opponent_passes)::int as opponent_passes,
+    SUM(ps.defensive_actions)::int as defensive_actions,
+    SUM(ps.high_turnovers)::int as high_turnovers,
+    SUM(ps.final_third_recoveries)::int as final_third_recoveries,
+    SUM(ps.ball_losses)::int as ball_losses,
+    SUM(ps.counter_press_regains)::int as counter_press_regains
+FROM team_pressing_match_statistics ps
+JOIN matches m ON ps.match_id = m.id AND m.deleted_at IS NULL
+JOIN teams t ON ps.team_id = t.id AND t.deleted_at IS NULL
+WHERE m.season = $1
+  AND m.competition = $2
+  AND m.status = 'finished'
+GROUP BY t.id, t.name, t.logo;
+
+-- name: GetTeamThreat :one
+SELECT
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM((me.metadata->>'xT')::numeric), 0)::float8 as expected_threat,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_pass' = 'true') as progressive_passes,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_carry' = 'true') as progressive_carries,
+    COUNT(*) FILTER (WHERE me.metadata->>'box_entry' = 'true') as box_entries
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE me.team_id = sqlc.arg('team_id')
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND (sqlc.narg('min_differential')::int IS NULL OR me.score_differential >= sqlc.narg('min_differential'))
+  AND (sqlc.narg('max_differential')::int IS NULL OR me.score_differential <= sqlc.narg('max_differential'))
+  AND me.deleted_at IS NULL;
+
+-- name: GetTeamThreatRankings :many
+SELECT
+    t.id as team_id,
+    t.name as team_name,
+    t.logo as team_logo,
+    COUNT(DISTINCT me.match_id) as matches,
+    COALESCE(SUM((me.metadata->>'xT')::numeric), 0)::float8 as expected_threat,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_pass' = 'true') as progressive_passes,
+    COUNT(*) FILTER (WHERE me.metadata->>'progressive_carry' = 'true') as progressive_carries,
+    COUNT(*) FILTER (WHERE me.metadata->>'box_entry' = 'true') as box_entries
+FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+JOIN teams t ON me.team_id = t.id AND t.deleted_at IS NULL
+WHERE m.season = $1
+  AND m.competition = $2
+  AND me.deleted_at IS NULL
+GROUP BY t.id, t.name, t.logo;
+
+-- name: ListGameStateEvents :many
+-- Lists the goals and the last event of each period in finished matches, from which the time spent in each game state is measured.
+SELECT e.match_id, e.team_id, e.event_type, e.period, e.clock_ms
+FROM (
+    SELECT
+        me.id, me.match_id, me.team_id, me.event_type, me.period, me.period_number, me.clock_ms,
+        ROW_NUMBER() OVER (PARTITION BY me.match_id, me.period_number ORDER BY me.clock_ms DESC, me.id DESC) as from_end
+    FROM match_events me
+    JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+    WHERE m.status = 'finished'
+      AND (sqlc.narg('team_id')::int IS NULL OR m.home_team_id = sqlc.narg('team_id') OR m.away_team_id = sqlc.narg('team_id'))
+      AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+      AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+      AND me.deleted_at IS NULL
+) e
+WHERE e.from_end = 1 OR e.event_type IN ('goal', 'penalty_goal', 'own_goal')
+ORDER BY e.match_id ASC, e.period_number ASC, e.clock_ms ASC, e.id ASC;
+
+-- name: ListPlayerGameStateEvents :many
+-- Lists the goals, substitutions, sendings-off and the last event of each period in the finished matches a player is in the lineups of, with the team the player played for, from which the player's time on the pitch in each game state is measured.
+SELECT e.match_id, e.player_team_id, e.starter, e.team_id, e.player_id, e.secondary_player_id, e.event_type, e.period, e.clock_ms
+FROM (
+    SELECT
+        me.id, me.match_id, l.team_id as player_team_id, lp.starter, me.team_id, me.player_id, me.secondary_player_id,
+        me.event_type, me.period, me.period_number, me.clock_ms,
+        ROW_NUMBER() OVER (PARTITION BY me.match_id, me.period_number ORDER BY me.clock_ms DESC, me.id DESC) as from_end
+    FROM match_lineup_players lp
+    JOIN match_lineups l ON l.id = lp.lineup_id
+    JOIN matches m ON m.id = l.match_id AND m.deleted_at IS NULL
+    JOIN match_events me ON me.match_id = m.id AND me.deleted_at IS NULL
+    WHERE lp.player_id = sqlc.arg('player_id')
+      AND m.status = 'finished'
+      AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+      AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+) e
+WHERE e.from_end = 1
+   OR e.event_type IN ('goal', 'penalty_goal', 'own_goal',
+       'substitution', 'substitution_on', 'substitution_off', 'red_card', 'second_yellow_card', 'var_red_card')
+ORDER BY e.match_id ASC, e.period_number ASC, e.clock_ms ASC, e.id ASC;
+
+-- name: ListPlayerProfiles :many
+-- Lists players' season statistics in every season and competition with their xT and ball progression totals from events. Seasons with fewer than min_minutes played are left out, except for player_id's.
+WITH threat AS (
+    SELECT
+        me.player_id,
+        m.season,
+        m.competition,
+        COALESCE(SUM((me.metadata->>'xT')::numeric), 0)::float8 as expected_threat,
+        COUNT(*) FILTER (WHERE me.metadata->>'progressive_pass' = 'true') as progressive_passes,
+        COUNT(*) FILTER (WHERE me.metadata->>'progressive_carry' = 'true') as progressive_carries,
+        COUNT(*) FILTER (WHERE me.metadata->>'box_entry' = 'true') as box_entries
+    FROM match_events me
+    JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+    WHERE me.player_id IS NOT NULL
+      AND me.deleted_at IS NULL
+    GROUP BY me.player_id, m.season, m.competition
+)
+SELECT
+    sqlc.embed(ps),
+    sqlc.embed(p),
+    COALESCE(th.expected_threat, 0)::float8 as expected_threat,
+    COALESCE(th.progressive_passes, 0)::bigint as progressive_passes,
+    COALESCE(th.progressive_carries, 0)::bigint as progressive_carries,
+    COALESCE(th.box_entries, 0)::bigint as box_entries
+FROM player_statistics ps
+JOIN players p ON ps.player_id = p.id AND p.deleted_at IS NULL
+LEFT JOIN threat th ON th.player_id = ps.player_id
+    AND th.season = ps.season
+    AND th.competition = ps.competition
+WHERE ps.deleted_at IS NULL
+  AND (ps.minutes_played >= sqlc.arg('min_minutes')::int OR ps.player_id = sqlc.arg('player_id'))
+ORDER BY ps.player_id, ps.season, ps.competition;
+
+-- name: GetHeadToHeadEventStats :many
+-- Sums each team's event statistics over the given matches, following the live stats
+-- classification; matches counts the matches with events for the team. Shootout kicks do not count.
+SELECT
+    me.team_id::int as team_id,
+    COUNT(DISTINCT me.match_id) as matches,
+    COUNT(*) FILTER (WHERE me.event_type IN (
+        'shot', 'shot_on_target', 'shot_off_target', 'shot_blocked', 'shot_saved', 'shot_post', 'shot_woodwork',
+        'goal', 'penalty_goal', 'penalty_miss'
+    )) as shots,
+    COUNT(*) FILTER (WHERE me.event_type IN ('shot_on_target', 'shot_saved', 'goal', 'penalty_goal')
+        OR (me.event_type IN ('shot', 'shot_off_target', 'shot_blocked', 'shot_post', 'shot_woodwork', 'penalty_miss')
+            AND lower(me.metadata->>'outcome') IN ('goal', 'saved', 'saved to post', 'on target'))) as shots_on_target,
+    COALESCE(SUM(COALESCE(me.metadata->>'xG', me.metadata->>'xg')::numeric) FILTER (WHERE me.event_type IN (
+        'shot', 'shot_on_target', 'shot_off_target', 'shot_blocked', 'shot_saved', 'shot_post', 'shot_woodwork',
+        'goal', 'penalty_goal', 'penalty_miss'
+    )), 0)::float8 as xg,
+    COUNT(*) FILTER (WHERE me.event_type IN (
+        'pass', 'pass_completed', 'pass_incomplete', 'key_pass', 'assist', 'through_ball', 'cross', 'long_ball', 'short_pass'
+    )) as passes,
+    COUNT(*) FILTER (WHERE me.event_type IN (
+        'pass', 'pass_completed', 'key_pass', 'assist', 'through_ball', 'cross', 'long_ball', 'short_pass'
+    ) AND COALESCE(lower(me.metadata->>'outcome'), '') NOT IN (
+        'incomplete', 'out', 'unknown', 'pass offside', 'injury clearance', 'unsuccessful'
+    )) as passes_completed,
+    COUNT(*) FILTER (WHERE me.event_type = 'corner') as corners,
+    COUNT(*) FILTER (WHERE me.event_type IN ('foul', 'foul_committed')) as fouls,
+    COUNT(*) FILTER (WHERE me.event_type = 'yellow_card') as yellow_cards,
+    COUNT(*) FILTER (WHERE me.event_type IN ('red_card', 'second_yellow_card')) as red_cards
+FROM match_events me
+WHERE me.match_id = ANY(sqlc.arg('match_ids')::int[])
+  AND me.team_id IS NOT NULL
+  AND me.period IS DISTINCT FROM 'penalties'
+  AND me.deleted_at IS NULL
+GROUP BY me.team_id
+ORDER BY me.team_id;
+
+-- name: GetHeadToHeadScorers :many
+-- Counts each player's goals in the given matches, for the team they scored for. Own goals
+-- and shootout kicks do not count.
+SELECT
+    p.id as player_id,
+    p.full_name,
+    me.team_id::int as team_id,
+    COUNT(*) as goals,
+    COUNT(*) FILTER (WHERE me.event_type = 'penalty_goal') as penalty_goals
+FROM match_events me
+JOIN players p ON me.player_id = p.id
+WHERE me.match_id = ANY(sqlc.arg('match_ids')::int[])
+  AND me.event_type IN ('goal', 'penalty_goal')
+  AND me.team_id IS NOT NULL
+  AND me.period IS DISTINCT FROM 'penalties'
+  AND me.deleted_at IS NULL
+GROUP BY p.id, p.full_name, me.team_id
+ORDER BY goals DESC, p.full_name ASC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,74 @@
+-- name: UpsertMatchLineup :one
+-- Creates a team's lineup for a match or replaces its formation.
+INSERT INTO match_lineups (match_id, team_id, formation)
+VALUES ($1, $2, $3)
+ON CONFLICT (match_id, team_id) DO UPDATE SET formation = EXCLUDED.formation
+RETURNING *;
+
+-- name: DeleteMatchLineupPlayers :exec
+DELETE FROM match_lineup_players
+WHERE lineup_id = $1;
+
+-- name: CreateMatchLineupPlayers :exec
+-- Adds players to a lineup; a shirt number of 0 and an empty position are stored as NULL.
+INSERT INTO match_lineup_players (lineup_id, player_id, shirt_number, position, starter)
+SELECT sqlc.arg('lineup_id')::int, data.player_id, NULLIF(data.shirt_number, 0), NULLIF(data.position, ''), data.starter
+FROM unnest(sqlc.arg('player_ids')::int[], sqlc.arg('shirt_numbers')::int[], sqlc.arg('positions')::text[], sqlc.arg('starters')::bool[])
+    AS data(player_id, shirt_number, position, starter);
+
+-- name: ListMatchLineups :many
+SELECT * FROM match_lineups
+WHERE match_id = $1
+ORDER BY id;
+
+-- name: ListMatchLineupPlayers :many
+-- Lists the players in a match's lineups by team, starters first, by shirt number.
+SELECT lp.*, l.team_id, p.full_name AS player_name
+FROM match_lineup_players lp
+JOIN match_lineups l ON l.id = lp.lineup_id
+JOIN players p ON p.id = lp.player_id
+WHERE l.match_id = $1
+ORDER BY l.team_id, lp.starter DESC, lp.shirt_number NULLS LAST, lp.id;
+
+-- name: SetMatchLineupMinutes :exec
+-- Stores the minutes played by the players in a match's lineups and whether they were substituted on or off.
+UPDATE match_lineup_players lp
+SET minutes_played = data.minutes_played,
+    subbed_on = data.subbed_on,
+    subbed_off = data.subbed_off
+FROM match_lineups l,
+    unnest(sqlc.arg('player_ids')::int[], sqlc.arg('minutes')::int[], sqlc.arg('subbed_on')::bool[], sqlc.arg('subbed_off')::bool[])
+    AS data(player_id, minutes_played, subbed_on, subbed_off)
+WHERE lp.lineup_id = l.id
+  AND l.match_id = sqlc.arg('match_id')
+  AND lp.player_id = data.player_id;
+
+-- name: RefreshPlayerAppearances :exec
+-- Recomputes the appearance statistics of a match's players in the match's season and competition from the lineups with minutes played.
+INSERT INTO player_statistics (player_id, season, competition, matches_played, matches_started, minutes_played, sub_on, sub_off)
+SELECT
+    lp.player_id,
+    m.season,
+    m.competition,
+    COUNT(*) FILTER (WHERE lp.starter OR lp.subbed_on),
+    COUNT(*) FILTER (WHERE lp.starter),
+    COALESCE(SUM(lp.minutes_played), 0),
+    COUNT(*) FILTER (WHERE lp.subbed_on),
+    COUNT(*) FILTER (WHERE lp.subbed_off)
+FROM match_lineup_players lp
+JOIN match_lineups l ON l.id = lp.lineup_id
+JOIN matches m ON m.id = l.match_id AND m.deleted_at IS NULL
+JOIN matches current ON current.id = $1 AND current.season = m.season AND current.competition = m.competition
+WHERE lp.minutes_played IS NOT NULL
+  AND lp.player_id IN (
+    SELECT clp.player_id FROM match_lineup_players clp
+    JOIN match_lineups cl ON cl.id = clp.lineup_id
+    WHERE cl.match_id = $1
+  )
+GROUP BY lp.player_id, m.season, m.competition
+ON CONFLICT (player_id, season, competition) DO UPDATE SET
+    matches_played = EXCLUDED.matches_played,
+    matches_started = EXCLUDED.matches_started,
+    minutes_played = EXCLUDED.minutes_played,
+    sub_on = EXCLUDED.sub_on,
+    sub_off = EXCLUDED.sub_off;
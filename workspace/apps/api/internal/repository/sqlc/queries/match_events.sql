@@ -6,12 +6,12 @@ LIMIT 1;
 -- name: GetMatchEvents :many
 SELECT * FROM match_events
 WHERE match_id = $1 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC, id ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: GetMatchEventsByType :many
 SELECT * FROM match_events
 WHERE match_id = $1 AND event_type = $2 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: GetPlayerEvents :many
 SELECT * FROM match_events
@@ -22,45 +22,63 @@ LIMIT $2 OFFSET $3;
 -- name: GetTeamEventsInMatch :many
 SELECT * FROM match_events
 WHERE match_id = $1 AND team_id = $2 AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: GetGoalsByMatch :many
 SELECT * FROM match_events
 WHERE match_id = $1 AND event_type = 'goal' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: GetCardsByMatch :many
 SELECT * FROM match_events
 WHERE match_id = $1
   AND event_type IN ('yellow_card', 'red_card')
   AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: GetShotsByMatch :many
-SELECT * FROM match_events
-WHERE match_id = $1 AND event_type = 'shot' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+-- Every attempt on goal plus own goals, which count for the other team.
+SELECT
+    me.*,
+    p.full_name as player_name
+FROM match_events me
+LEFT JOIN players p ON p.id = me.player_id
+WHERE me.match_id = $1
+  AND me.event_type IN (
+      'shot', 'shot_on_target', 'shot_off_target', 'shot_blocked', 'shot_saved', 'shot_post', 'shot_woodwork',
+      'goal', 'own_goal', 'penalty', 'penalty_goal', 'penalty_miss'
+  )
+  AND me.deleted_at IS NULL
+ORDER BY me.period_number ASC, me.clock_ms ASC, me.id ASC;
 
 -- name: GetPassesByMatch :many
 SELECT * FROM match_events
 WHERE match_id = $1 AND event_type = 'pass' AND deleted_at IS NULL
-ORDER BY minute ASC, extra_minute ASC;
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
 
 -- name: CreateMatchEvent :one
 INSERT INTO match_events (
     match_id, team_id, player_id, secondary_player_id, event_type,
-    minute, second, period, extra_minute, position_x, position_y, description, metadata
+    minute, second, period, extra_minute, position_x, position_y, description, metadata,
+    period_number, clock_ms
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
 RETURNING *;
 
 -- name: UpdateMatchEvent :one
 UPDATE match_events
 SET
+    team_id = COALESCE(sqlc.narg('team_id'), team_id),
+    player_id = COALESCE(sqlc.narg('player_id'), player_id),
+    secondary_player_id = COALESCE(sqlc.narg('secondary_player_id'), secondary_player_id),
     event_type = COALESCE(sqlc.narg('event_type'), event_type),
     minute = COALESCE(sqlc.narg('minute'), minute),
+    second = COALESCE(sqlc.narg('second'), second),
+    period = COALESCE(sqlc.narg('period'), period),
     extra_minute = COALESCE(sqlc.narg('extra_minute'), extra_minute),
+    period_number = COALESCE(sqlc.narg('period_number'), period_number),
+    clock_ms = COALESCE(sqlc.narg('clock_ms'), clock_ms),
     position_x = COALESCE(sqlc.narg('position_x'), position_x),
     position_y = COALESCE(sqlc.narg('position_y'), position_y),
     description = COALESCE(sqlc.narg('description'), description),
@@ -73,6 +91,88 @@ UPDATE match_events
 SET deleted_at = NOW()
 WHERE id = $1;
 
+-- name: UpdateMatchEventMetadata :exec
+UPDATE match_events
+SET metadata = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListEventsByTypes :many
+SELECT * FROM match_events
+WHERE id > sqlc.arg('after_id')
+  AND event_type = ANY(sqlc.arg('event_types')::text[])
+  AND deleted_at IS NULL
+ORDER BY id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ListSeasonMatchEvents :many
+-- Lists the events of finished matches, optionally limited to one team's matches, by match in match clock order.
+SELECT me.* FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE m.status = 'finished'
+  AND (sqlc.narg('team_id')::int IS NULL OR m.home_team_id = sqlc.narg('team_id') OR m.away_team_id = sqlc.narg('team_id'))
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND me.deleted_at IS NULL
+ORDER BY me.match_id ASC, me.period_number ASC, me.clock_ms ASC, me.id ASC;
+
+-- name: ListPlayerSeasonMatchEvents :many
+-- Lists the events of the finished matches a player has events in, by match in match clock order. match_id
+-- includes a match whose finished status is not stored yet.
+SELECT me.* FROM match_events me
+JOIN matches m ON me.match_id = m.id AND m.deleted_at IS NULL
+WHERE (m.status = 'finished' OR m.id = sqlc.narg('match_id'))
+  AND EXISTS (
+    SELECT 1 FROM match_events pe
+    WHERE pe.match_id = m.id AND pe.player_id = sqlc.arg('player_id') AND pe.deleted_at IS NULL
+  )
+  AND (sqlc.narg('season')::text IS NULL OR m.season = sqlc.narg('season'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND me.deleted_at IS NULL
+ORDER BY me.match_id ASC, me.period_number ASC, me.clock_ms ASC, me.id ASC;
+
+-- name: SetMatchEventPossessions :exec
+-- Stores the possession each of a match's events belongs to; events missing from ids are cleared.
+UPDATE match_events me
+SET possession_id = data.possession_id
+FROM match_events e
+LEFT JOIN unnest(sqlc.arg('ids')::int[], sqlc.arg('possession_ids')::int[]) AS data(id, possession_id) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = sqlc.arg('match_id')
+  AND e.deleted_at IS NULL;
+
+-- name: SetMatchEventDerived :exec
+-- Stores the passes inferred as assists, second assists and key passes, with their xA (NaN for none), the
+-- shot-creating and goal-creating actions, and the shot they led to. Empty types are stored as NULL; events
+-- missing from ids are cleared.
+UPDATE match_events me
+SET derived_type = NULLIF(data.derived_type, ''),
+    xa = NULLIF(data.xa, 'NaN'),
+    shot_event_id = data.shot_event_id,
+    creation_type = NULLIF(data.creation_type, '')
+FROM match_events e
+LEFT JOIN unnest(
+    sqlc.arg('ids')::int[],
+    sqlc.arg('derived_types')::text[],
+    sqlc.arg('xas')::float8[],
+    sqlc.arg('shot_event_ids')::int[],
+    sqlc.arg('creation_types')::text[]
+) AS data(id, derived_type, xa, shot_event_id, creation_type) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = sqlc.arg('match_id')
+  AND e.deleted_at IS NULL;
+
+-- name: SetMatchEventGameStates :exec
+-- Stores the game state and goal differential of each of a match's events; events missing from ids are cleared.
+UPDATE match_events me
+SET game_state = data.game_state,
+    score_differential = data.score_differential
+FROM match_events e
+LEFT JOIN unnest(sqlc.arg('ids')::int[], sqlc.arg('game_states')::text[], sqlc.arg('score_differentials')::int[])
+    AS data(id, game_state, score_differential) ON data.id = e.id
+WHERE me.id = e.id
+  AND e.match_id = sqlc.arg('match_id')
+  AND e.deleted_at IS NULL;
+
 -- name: CountMatchEvents :one
 SELECT COUNT(*) FROM match_events
 WHERE match_id = $1 AND deleted_at IS NULL;
@@ -108,13 +208,3 @@ FROM match_events
 WHERE player_id = $1
   AND event_type = 'pass'
   AND deleted_at IS NULL;
-
--- name: GetTeamPossessionEvents :many
-SELECT
-    team_id,
-    COUNT(*) as total_events,
-    COUNT(*) FILTER (WHERE event_type = 'pass') as passes,
-    COUNT(*) FILTER (WHERE event_type = 'shot') as shots
-FROM match_events
-WHERE match_id = $1 AND deleted_at IS NULL
-GROUP BY team_id;
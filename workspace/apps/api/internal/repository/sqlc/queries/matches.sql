@@ -35,6 +35,16 @@ WHERE (home_team_id = $1 OR away_team_id = $1)
 ORDER BY match_date DESC
 LIMIT $2 OFFSET $3;
 
+-- name: GetHeadToHeadMatches :many
+-- Lists the finished matches between two teams, with either at home, latest first.
+SELECT * FROM matches
+WHERE ((home_team_id = sqlc.arg('team_id') AND away_team_id = sqlc.arg('opponent_id'))
+    OR (home_team_id = sqlc.arg('opponent_id') AND away_team_id = sqlc.arg('team_id')))
+  AND status = 'finished'
+  AND (sqlc.narg('competition')::text IS NULL OR competition = sqlc.narg('competition'))
+  AND deleted_at IS NULL
+ORDER BY match_date DESC;
+
 -- name: GetMatchesByCompetition :many
 SELECT * FROM matches
 WHERE competition = $1 AND deleted_at IS NULL
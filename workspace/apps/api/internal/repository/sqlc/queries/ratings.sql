@@ -0,0 +1,56 @@
+-- name: ListMatchResults :many
+-- Lists finished matches oldest first, optionally in one competition, with each team's xG from its shots
+-- (NULL without shot events). match_id includes a match whose finished status is not stored yet.
+SELECT
+    m.id,
+    m.home_team_id,
+    m.away_team_id,
+    m.match_date,
+    m.competition,
+    m.season,
+    m.home_team_score,
+    m.away_team_score,
+    xg.home_xg,
+    xg.away_xg
+FROM matches m
+LEFT JOIN LATERAL (
+    SELECT
+        SUM(COALESCE(me.metadata->>'xG', me.metadata->>'xg')::numeric) FILTER (WHERE me.team_id = m.home_team_id)::float8 as home_xg,
+        SUM(COALESCE(me.metadata->>'xG', me.metadata->>'xg')::numeric) FILTER (WHERE me.team_id = m.away_team_id)::float8 as away_xg
+    FROM match_events me
+    WHERE me.match_id = m.id
+      AND me.event_type IN (
+          'shot', 'shot_on_target', 'shot_off_target', 'shot_blocked', 'shot_saved', 'shot_post', 'shot_woodwork',
+          'goal', 'penalty', 'penalty_goal', 'penalty_miss'
+      )
+      AND me.period IS DISTINCT FROM 'penalties'
+      AND me.deleted_at IS NULL
+) xg ON true
+WHERE (m.status = 'finished' OR m.id = sqlc.narg('match_id'))
+  AND (sqlc.narg('competition')::text IS NULL OR m.competition = sqlc.narg('competition'))
+  AND m.deleted_at IS NULL
+ORDER BY m.match_date ASC, m.id ASC;
+
+-- name: DeleteTeamRatings :exec
+DELETE FROM team_ratings
+WHERE competition = $1;
+
+-- name: CreateTeamRatings :exec
+-- Stores rating changes; the competition, season and date are the match's.
+INSERT INTO team_ratings (
+    team_id, opponent_id, match_id, competition, season, match_date, home, rating_before, rating_after, expected_score
+)
+SELECT data.team_id, data.opponent_id, m.id, m.competition, m.season, m.match_date, data.home,
+    data.rating_before, data.rating_after, data.expected_score
+FROM unnest(
+    sqlc.arg('team_ids')::int[], sqlc.arg('opponent_ids')::int[], sqlc.arg('match_ids')::int[], sqlc.arg('homes')::bool[],
+    sqlc.arg('ratings_before')::float8[], sqlc.arg('ratings_after')::float8[], sqlc.arg('expected_scores')::float8[]
+) AS data(team_id, opponent_id, match_id, home, rating_before, rating_after, expected_score)
+JOIN matches m ON m.id = data.match_id;
+
+-- name: GetTeamRatingHistory :many
+-- Lists a team's rating changes oldest first, optionally in one competition.
+SELECT * FROM team_ratings
+WHERE team_id = sqlc.arg('team_id')
+  AND (sqlc.narg('competition')::text IS NULL OR competition = sqlc.narg('competition'))
+ORDER BY match_date ASC, id ASC;
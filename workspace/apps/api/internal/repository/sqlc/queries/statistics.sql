@@ -46,6 +46,17 @@ WHERE ps.season = $1
 ORDER BY ps.assists DESC, ps.goals DESC
 LIMIT $3;
 
+-- name: ListPlayerStatsPool :many
+-- Lists the season statistics of the players in a competition with at least min_minutes played, with their position.
+SELECT sqlc.embed(ps), p.full_name, p.position, p.team_id
+FROM player_statistics ps
+JOIN players p ON ps.player_id = p.id AND p.deleted_at IS NULL
+WHERE ps.season = sqlc.arg('season')
+  AND ps.competition = sqlc.arg('competition')
+  AND ps.minutes_played >= sqlc.arg('min_minutes')::int
+  AND ps.deleted_at IS NULL
+ORDER BY ps.player_id;
+
 -- name: CreatePlayerStats :one
 INSERT INTO player_statistics (
     player_id, season, competition, matches_played, matches_started, minutes_played,
@@ -95,6 +106,106 @@ SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: UpdatePlayerCreativeStats :exec
+-- Recounts the assists and key passes, assists included, of a match's players in its season and competition
+-- from provider assist and key_pass events and derived ones, each pass counted once. Players of deleted events
+-- are recounted too.
+UPDATE player_statistics ps
+SET assists = counts.assists,
+    key_passes = counts.key_passes
+FROM (
+    SELECT
+        me.player_id,
+        m.season,
+        m.competition,
+        COUNT(*) FILTER (WHERE me.event_type = 'assist' OR me.derived_type = 'assist')::int as assists,
+        COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass'))::int as key_passes
+    FROM matches cur
+    JOIN matches m ON m.season = cur.season
+        AND m.competition = cur.competition
+        AND (m.status = 'finished' OR m.id = cur.id)
+        AND m.deleted_at IS NULL
+    JOIN match_events me ON me.match_id = m.id AND me.deleted_at IS NULL
+    WHERE cur.id = sqlc.arg('match_id')
+      AND me.player_id IN (SELECT e.player_id FROM match_events e WHERE e.match_id = cur.id)
+    GROUP BY me.player_id, m.season, m.competition
+) counts
+WHERE ps.player_id = counts.player_id
+  AND ps.season = counts.season
+  AND ps.competition = counts.competition
+  AND ps.deleted_at IS NULL;
+
+-- name: DeleteGoalkeeperMatchStats :many
+-- Deletes the statistics of a match's goalkeepers and returns the goalkeepers.
+DELETE FROM goalkeeper_match_statistics
+WHERE match_id = $1
+RETURNING player_id;
+
+-- name: CreateGoalkeeperMatchStats :exec
+-- Stores the statistics of a match's goalkeepers.
+INSERT INTO goalkeeper_match_statistics (
+    match_id, player_id, team_id, clean_sheet, shots_on_target, saves, goals_conceded, post_shot_xg,
+    goals_prevented, penalties_faced, penalties_saved, crosses_faced, crosses_claimed
+)
+SELECT
+    sqlc.arg('match_id')::int, data.player_id, data.team_id, data.clean_sheet, data.shots_on_target, data.saves,
+    data.goals_conceded, data.post_shot_xg, data.goals_prevented, data.penalties_faced, data.penalties_saved,
+    data.crosses_faced, data.crosses_claimed
+FROM unnest(
+    sqlc.arg('player_ids')::int[],
+    sqlc.arg('team_ids')::int[],
+    sqlc.arg('clean_sheets')::bool[],
+    sqlc.arg('shots_on_target')::int[],
+    sqlc.arg('saves')::int[],
+    sqlc.arg('goals_conceded')::int[],
+    sqlc.arg('post_shot_xg')::float8[],
+    sqlc.arg('goals_prevented')::float8[],
+    sqlc.arg('penalties_faced')::int[],
+    sqlc.arg('penalties_saved')::int[],
+    sqlc.arg('crosses_faced')::int[],
+    sqlc.arg('crosses_claimed')::int[]
+) AS data(
+    player_id, team_id, clean_sheet, shots_on_target, saves, goals_conceded, post_shot_xg, goals_prevented,
+    penalties_faced, penalties_saved, crosses_faced, crosses_claimed
+);
+
+-- name: RefreshGoalkeeperStats :exec
+-- Recomputes the clean sheets, goals conceded, saves, save percentage (NULL for no shots on target faced) and
+-- penalties saved of goalkeepers in a match's season and competition from their match statistics in its finished
+-- matches and the match itself. Goalkeepers left without match statistics are reset.
+UPDATE player_statistics ps
+SET clean_sheets = totals.clean_sheets,
+    goals_conceded = totals.goals_conceded,
+    saves_total = totals.saves,
+    save_percentage = totals.save_percentage,
+    penalties_saved = totals.penalties_saved
+FROM (
+    SELECT
+        keeper.player_id,
+        cur.season,
+        cur.competition,
+        COUNT(*) FILTER (WHERE gs.clean_sheet)::int as clean_sheets,
+        COALESCE(SUM(gs.goals_conceded), 0)::int as goals_conceded,
+        COALESCE(SUM(gs.saves), 0)::int as saves,
+        ROUND(SUM(gs.saves) * 100.0 / NULLIF(SUM(gs.shots_on_target), 0), 2) as save_percentage,
+        COALESCE(SUM(gs.penalties_saved), 0)::int as penalties_saved
+    FROM matches cur
+    CROSS JOIN (SELECT DISTINCT unnest(sqlc.arg('player_ids')::int[]) AS player_id) keeper
+    LEFT JOIN (
+        goalkeeper_match_statistics gs
+        JOIN matches m ON m.id = gs.match_id AND m.deleted_at IS NULL
+    ) ON gs.player_id = keeper.player_id
+        AND m.season = cur.season
+        AND m.competition = cur.competition
+        AND (m.status = 'finished' OR m.id = cur.id)
+    WHERE cur.id = sqlc.arg('match_id')
+    GROUP BY keeper.player_id, cur.season, cur.competition
+) totals
+WHERE ps.player_id = totals.player_id
+  AND ps.season = totals.season
+  AND ps.competition = totals.competition
+  AND ps.deleted_at IS NULL;
+
 -- name: DeletePlayerStats :exec
 UPDATE player_statistics
 SET deleted_at = NOW()
@@ -174,6 +285,32 @@ SET
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
+-- name: DeleteTeamPressingMatchStats :exec
+DELETE FROM team_pressing_match_statistics
+WHERE match_id = $1;
+
+-- name: CreateTeamPressingMatchStats :exec
+-- Stores the pressing statistics of a match's teams.
+INSERT INTO team_pressing_match_statistics (
+    match_id, team_id, opponent_passes, defensive_actions, high_turnovers, final_third_recoveries, ball_losses,
+    counter_press_regains
+)
+SELECT
+    sqlc.arg('match_id')::int, data.team_id, data.opponent_passes, data.defensive_actions, data.high_turnovers,
+    data.final_third_recoveries, data.ball_losses, data.counter_press_regains
+FROM unnest(
+    sqlc.arg('team_ids')::int[],
+    sqlc.arg('opponent_passes')::int[],
+    sqlc.arg('defensive_actions')::int[],
+    sqlc.arg('high_turnovers')::int[],
+    sqlc.arg('final_third_recoveries')::int[],
+    sqlc.arg('ball_losses')::int[],
+    sqlc.arg('counter_press_regains')::int[]
+) AS data(
+    team_id, opponent_passes, defensive_actions, high_turnovers, final_third_recoveries, ball_losses,
+    counter_press_regains
+);
+
 -- name: DeleteTeamStats :exec
 UPDATE team_statistics
 SET deleted_at = NOW()
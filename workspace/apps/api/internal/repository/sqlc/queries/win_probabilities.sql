@@ -0,0 +1,35 @@
+-- name: DeleteWinProbabilities :exec
+DELETE FROM match_win_probabilities
+WHERE match_id = $1;
+
+-- name: CreateWinProbabilities :exec
+-- Stores a match's win probabilities; an event's existing point is kept.
+INSERT INTO match_win_probabilities (
+    match_id, event_id, period, minute, stoppage, second, period_number, clock_ms,
+    home_goals, away_goals, home_red_cards, away_red_cards, home_win, draw, away_win
+)
+SELECT sqlc.arg('match_id'), data.event_id, data.period, data.minute, data.stoppage, data.second, data.period_number, data.clock_ms,
+    data.home_goals, data.away_goals, data.home_red_cards, data.away_red_cards, data.home_win, data.draw, data.away_win
+FROM unnest(
+    sqlc.arg('event_ids')::int[], sqlc.arg('periods')::text[], sqlc.arg('minutes')::int[], sqlc.arg('stoppages')::int[],
+    sqlc.arg('seconds')::int[], sqlc.arg('period_numbers')::smallint[], sqlc.arg('clock_ms')::bigint[],
+    sqlc.arg('home_goals')::int[], sqlc.arg('away_goals')::int[], sqlc.arg('home_red_cards')::int[], sqlc.arg('away_red_cards')::int[],
+    sqlc.arg('home_wins')::float8[], sqlc.arg('draws')::float8[], sqlc.arg('away_wins')::float8[]
+) AS data(
+    event_id, period, minute, stoppage, second, period_number, clock_ms,
+    home_goals, away_goals, home_red_cards, away_red_cards, home_win, draw, away_win
+)
+ON CONFLICT (event_id) DO NOTHING;
+
+-- name: GetLatestWinProbability :one
+-- Gets a match's latest win probability in match clock order.
+SELECT * FROM match_win_probabilities
+WHERE match_id = $1
+ORDER BY period_number DESC, clock_ms DESC, id DESC
+LIMIT 1;
+
+-- name: GetWinProbabilities :many
+-- Lists a match's win probabilities in match clock order.
+SELECT * FROM match_win_probabilities
+WHERE match_id = $1
+ORDER BY period_number ASC, clock_ms ASC, id ASC;
@@ -11,6 +11,70 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createGoalkeeperMatchStats = `-- name: CreateGoalkeeperMatchStats :exec
+INSERT INTO goalkeeper_match_statistics (
+    match_id, player_id, team_id, clean_sheet, shots_on_target, saves, goals_conceded, post_shot_xg,
+    goals_prevented, penalties_faced, penalties_saved, crosses_faced, crosses_claimed
+)
+SELECT
+    $1::int, data.player_id, data.team_id, data.clean_sheet, data.shots_on_target, data.saves,
+    data.goals_conceded, data.post_shot_xg, data.goals_prevented, data.penalties_faced, data.penalties_saved,
+    data.crosses_faced, data.crosses_claimed
+FROM unnest(
+    $2::int[],
+    $3::int[],
+    $4::bool[],
+    $5::int[],
+    $6::int[],
+    $7::int[],
+    $8::float8[],
+    $9::float8[],
+    $10::int[],
+    $11::int[],
+    $12::int[],
+    $13::int[]
+) AS data(
+    player_id, team_id, clean_sheet, shots_on_target, saves, goals_conceded, post_shot_xg, goals_prevented,
+    penalties_faced, penalties_saved, crosses_faced, crosses_claimed
+)
+`
+
+type CreateGoalkeeperMatchStatsParams struct {
+	MatchID        int32     `json:"match_id"`
+	PlayerIds      []int32   `json:"player_ids"`
+	TeamIds        []int32   `json:"team_ids"`
+	CleanSheets    []bool    `json:"clean_sheets"`
+	ShotsOnTarget  []int32   `json:"shots_on_target"`
+	Saves          []int32   `json:"saves"`
+	GoalsConceded  []int32   `json:"goals_conceded"`
+	PostShotXg     []float64 `json:"post_shot_xg"`
+	GoalsPrevented []float64 `json:"goals_prevented"`
+	PenaltiesFaced []int32   `json:"penalties_faced"`
+	PenaltiesSaved []int32   `json:"penalties_saved"`
+	CrossesFaced   []int32   `json:"crosses_faced"`
+	CrossesClaimed []int32   `json:"crosses_claimed"`
+}
+
+// Stores the statistics of a match's goalkeepers.
+func (q *Queries) CreateGoalkeeperMatchStats(ctx context.Context, arg CreateGoalkeeperMatchStatsParams) error {
+	_, err := q.db.Exec(ctx, createGoalkeeperMatchStats,
+		arg.MatchID,
+		arg.PlayerIds,
+		arg.TeamIds,
+		arg.CleanSheets,
+		arg.ShotsOnTarget,
+		arg.Saves,
+		arg.GoalsConceded,
+		arg.PostShotXg,
+		arg.GoalsPrevented,
+		arg.PenaltiesFaced,
+		arg.PenaltiesSaved,
+		arg.CrossesFaced,
+		arg.CrossesClaimed,
+	)
+	return err
+}
+
 const createPlayerStats = `-- name: CreatePlayerStats :one
 INSERT INTO player_statistics (
     player_id, season, competition, matches_played, matches_started, minutes_played,
@@ -153,6 +217,54 @@ func (q *Queries) CreatePlayerStats(ctx context.Context, arg CreatePlayerStatsPa
 	return i, err
 }
 
+const createTeamPressingMatchStats = `-- name: CreateTeamPressingMatchStats :exec
+INSERT INTO team_pressing_match_statistics (
+    match_id, team_id, opponent_passes, defensive_actions, high_turnovers, final_third_recoveries, ball_losses,
+    counter_press_regains
+)
+SELECT
+    $1::int, data.team_id, data.opponent_passes, data.defensive_actions, data.high_turnovers,
+    data.final_third_recoveries, data.ball_losses, data.counter_press_regains
+FROM unnest(
+    $2::int[],
+    $3::int[],
+    $4::int[],
+    $5::int[],
+    $6::int[],
+    $7::int[],
+    $8::int[]
+) AS data(
+    team_id, opponent_passes, defensive_actions, high_turnovers, final_third_recoveries, ball_losses,
+    counter_press_regains
+)
+`
+
+type CreateTeamPressingMatchStatsParams struct {
+	MatchID              int32   `json:"match_id"`
+	TeamIds              []int32 `json:"team_ids"`
+	OpponentPasses       []int32 `json:"opponent_passes"`
+	DefensiveActions     []int32 `json:"defensive_actions"`
+	HighTurnovers        []int32 `json:"high_turnovers"`
+	FinalThirdRecoveries []int32 `json:"final_third_recoveries"`
+	BallLosses           []int32 `json:"ball_losses"`
+	CounterPressRegains  []int32 `json:"counter_press_regains"`
+}
+
+// Stores the pressing statistics of a match's teams.
+func (q *Queries) CreateTeamPressingMatchStats(ctx context.Context, arg CreateTeamPressingMatchStatsParams) error {
+	_, err := q.db.Exec(ctx, createTeamPressingMatchStats,
+		arg.MatchID,
+		arg.TeamIds,
+		arg.OpponentPasses,
+		arg.DefensiveActions,
+		arg.HighTurnovers,
+		arg.FinalThirdRecoveries,
+		arg.BallLosses,
+		arg.CounterPressRegains,
+	)
+	return err
+}
+
 const createTeamStats = `-- name: CreateTeamStats :one
 INSERT INTO team_statistics (
     team_id, season, competition, matches_played, wins, draws, losses, points, position,
@@ -264,6 +376,33 @@ func (q *Queries) CreateTeamStats(ctx context.Context, arg CreateTeamStatsParams
 	return i, err
 }
 
+const deleteGoalkeeperMatchStats = `-- name: DeleteGoalkeeperMatchStats :many
+DELETE FROM goalkeeper_match_statistics
+WHERE match_id = $1
+RETURNING player_id
+`
+
+// Deletes the statistics of a match's goalkeepers and returns the goalkeepers.
+func (q *Queries) DeleteGoalkeeperMatchStats(ctx context.Context, matchID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, deleteGoalkeeperMatchStats, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var player_id int32
+		if err := rows.Scan(&player_id); err != nil {
+			return nil, err
+		}
+		items = append(items, player_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deletePlayerStats = `-- name: DeletePlayerStats :exec
 UPDATE player_statistics
 SET deleted_at = NOW()
@@ -275,6 +414,16 @@ func (q *Queries) DeletePlayerStats(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteTeamPressingMatchStats = `-- name: DeleteTeamPressingMatchStats :exec
+DELETE FROM team_pressing_match_statistics
+WHERE match_id = $1
+`
+
+func (q *Queries) DeleteTeamPressingMatchStats(ctx context.Context, matchID int32) error {
+	_, err := q.db.Exec(ctx, deleteTeamPressingMatchStats, matchID)
+	return err
+}
+
 const deleteTeamStats = `-- name: DeleteTeamStats :exec
 UPDATE team_statistics
 SET deleted_at = NOW()
@@ -1013,6 +1162,179 @@ func (q *Queries) GetTopScorers(ctx context.Context, arg GetTopScorersParams) ([
 	return items, nil
 }
 
+const listPlayerStatsPool = `-- name: ListPlayerStatsPool :many
+SELECT ps.id, ps.player_id, ps.season, ps.competition, ps.matches_played, ps.matches_started, ps.minutes_played, ps.sub_on, ps.sub_off, ps.goals, ps.assists, ps.shots_total, ps.shots_on_target, ps.shot_accuracy, ps.goal_conversion, ps.passes_total, ps.passes_completed, ps.pass_accuracy, ps.key_passes, ps.crosses, ps.tackles, ps.tackles_won, ps.interceptions, ps.clearances, ps.blocked_shots, ps.duels, ps.duels_won, ps.aerial_duels, ps.aerial_duels_won, ps.yellow_cards, ps.red_cards, ps.fouls, ps.fouls_drawn, ps.clean_sheets, ps.goals_conceded, ps.saves_total, ps.save_percentage, ps.penalties_saved, ps.created_at, ps.updated_at, ps.deleted_at, p.full_name, p.position, p.team_id
+FROM player_statistics ps
+JOIN players p ON ps.player_id = p.id AND p.deleted_at IS NULL
+WHERE ps.season = $1
+  AND ps.competition = $2
+  AND ps.minutes_played >= $3::int
+  AND ps.deleted_at IS NULL
+ORDER BY ps.player_id
+`
+
+type ListPlayerStatsPoolParams struct {
+	Season      string `json:"season"`
+	Competition string `json:"competition"`
+	MinMinutes  int32  `json:"min_minutes"`
+}
+
+type ListPlayerStatsPoolRow struct {
+	PlayerStatistic PlayerStatistic `json:"player_statistic"`
+	FullName        string          `json:"full_name"`
+	Position        string          `json:"position"`
+	TeamID          int32           `json:"team_id"`
+}
+
+// Lists the season statistics of the players in a competition with at least min_minutes played, with their position.
+func (q *Queries) ListPlayerStatsPool(ctx context.Context, arg ListPlayerStatsPoolParams) ([]ListPlayerStatsPoolRow, error) {
+	rows, err := q.db.Query(ctx, listPlayerStatsPool, arg.Season, arg.Competition, arg.MinMinutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPlayerStatsPoolRow{}
+	for rows.Next() {
+		var i ListPlayerStatsPoolRow
+		if err := rows.Scan(
+			&i.PlayerStatistic.ID,
+			&i.PlayerStatistic.PlayerID,
+			&i.PlayerStatistic.Season,
+			&i.PlayerStatistic.Competition,
+			&i.PlayerStatistic.MatchesPlayed,
+			&i.PlayerStatistic.MatchesStarted,
+			&i.PlayerStatistic.MinutesPlayed,
+			&i.PlayerStatistic.SubOn,
+			&i.PlayerStatistic.SubOff,
+			&i.PlayerStatistic.Goals,
+			&i.PlayerStatistic.Assists,
+			&i.PlayerStatistic.ShotsTotal,
+			&i.PlayerStatistic.ShotsOnTarget,
+			&i.PlayerStatistic.ShotAccuracy,
+			&i.PlayerStatistic.GoalConversion,
+			&i.PlayerStatistic.PassesTotal,
+			&i.PlayerStatistic.PassesCompleted,
+			&i.PlayerStatistic.PassAccuracy,
+			&i.PlayerStatistic.KeyPasses,
+			&i.PlayerStatistic.Crosses,
+			&i.PlayerStatistic.Tackles,
+			&i.PlayerStatistic.TacklesWon,
+			&i.PlayerStatistic.Interceptions,
+			&i.PlayerStatistic.Clearances,
+			&i.PlayerStatistic.BlockedShots,
+			&i.PlayerStatistic.Duels,
+			&i.PlayerStatistic.DuelsWon,
+			&i.PlayerStatistic.AerialDuels,
+			&i.PlayerStatistic.AerialDuelsWon,
+			&i.PlayerStatistic.YellowCards,
+			&i.PlayerStatistic.RedCards,
+			&i.PlayerStatistic.Fouls,
+			&i.PlayerStatistic.FoulsDrawn,
+			&i.PlayerStatistic.CleanSheets,
+			&i.PlayerStatistic.GoalsConceded,
+			&i.PlayerStatistic.SavesTotal,
+			&i.PlayerStatistic.SavePercentage,
+			&i.PlayerStatistic.PenaltiesSaved,
+			&i.PlayerStatistic.CreatedAt,
+			&i.PlayerStatistic.UpdatedAt,
+			&i.PlayerStatistic.DeletedAt,
+			&i.FullName,
+			&i.Position,
+			&i.TeamID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshGoalkeeperStats = `-- name: RefreshGoalkeeperStats :exec
+UPDATE player_statistics ps
+SET clean_sheets = totals.clean_sheets,
+    goals_conceded = totals.goals_conceded,
+    saves_total = totals.saves,
+    save_percentage = totals.save_percentage,
+    penalties_saved = totals.penalties_saved
+FROM (
+    SELECT
+        keeper.player_id,
+        cur.season,
+        cur.competition,
+        COUNT(*) FILTER (WHERE gs.clean_sheet)::int as clean_sheets,
+        COALESCE(SUM(gs.goals_conceded), 0)::int as goals_conceded,
+        COALESCE(SUM(gs.saves), 0)::int as saves,
+        ROUND(SUM(gs.saves) * 100.0 / NULLIF(SUM(gs.shots_on_target), 0), 2) as save_percentage,
+        COALESCE(SUM(gs.penalties_saved), 0)::int as penalties_saved
+    FROM matches cur
+    CROSS JOIN (SELECT DISTINCT unnest($1::int[]) AS player_id) keeper
+    LEFT JOIN (
+        goalkeeper_match_statistics gs
+        JOIN matches m ON m.id = gs.match_id AND m.deleted_at IS NULL
+    ) ON gs.player_id = keeper.player_id
+        AND m.season = cur.season
+        AND m.competition = cur.competition
+        AND (m.status = 'finished' OR m.id = cur.id)
+    WHERE cur.id = $2
+    GROUP BY keeper.player_id, cur.season, cur.competition
+) totals
+WHERE ps.player_id = totals.player_id
+  AND ps.season = totals.season
+  AND ps.competition = totals.competition
+  AND ps.deleted_at IS NULL
+`
+
+type RefreshGoalkeeperStatsParams struct {
+	PlayerIds []int32 `json:"player_ids"`
+	MatchID   int32   `json:"match_id"`
+}
+
+// Recomputes the clean sheets, goals conceded, saves, save percentage (NULL for no shots on target faced) and
+// penalties saved of goalkeepers in a match's season and competition from their match statistics in its finished
+// matches and the match itself. Goalkeepers left without match statistics are reset.
+func (q *Queries) RefreshGoalkeeperStats(ctx context.Context, arg RefreshGoalkeeperStatsParams) error {
+	_, err := q.db.Exec(ctx, refreshGoalkeeperStats, arg.PlayerIds, arg.MatchID)
+	return err
+}
+
+const updatePlayerCreativeStats = `-- name: UpdatePlayerCreativeStats :exec
+UPDATE player_statistics ps
+SET assists = counts.assists,
+    key_passes = counts.key_passes
+FROM (
+    SELECT
+        me.player_id,
+        m.season,
+        m.competition,
+        COUNT(*) FILTER (WHERE me.event_type = 'assist' OR me.derived_type = 'assist')::int as assists,
+        COUNT(*) FILTER (WHERE me.event_type IN ('assist', 'key_pass') OR me.derived_type IN ('assist', 'key_pass'))::int as key_passes
+    FROM matches cur
+    JOIN matches m ON m.season = cur.season
+        AND m.competition = cur.competition
+        AND (m.status = 'finished' OR m.id = cur.id)
+        AND m.deleted_at IS NULL
+    JOIN match_events me ON me.match_id = m.id AND me.deleted_at IS NULL
+    WHERE cur.id = $1
+      AND me.player_id IN (SELECT e.player_id FROM match_events e WHERE e.match_id = cur.id)
+    GROUP BY me.player_id, m.season, m.competition
+) counts
+WHERE ps.player_id = counts.player_id
+  AND ps.season = counts.season
+  AND ps.competition = counts.competition
+  AND ps.deleted_at IS NULL
+`
+
+// Recounts the assists and key passes, assists included, of a match's players in its season and competition
+// from provider assist and key_pass events and derived ones, each pass counted once. Players of deleted events
+// are recounted too.
+func (q *Queries) UpdatePlayerCreativeStats(ctx context.Context, matchID int32) error {
+	_, err := q.db.Exec(ctx, updatePlayerCreativeStats, matchID)
+	return err
+}
+
 const updatePlayerStats = `-- name: UpdatePlayerStats :one
 UPDATE player_statistics
 SET
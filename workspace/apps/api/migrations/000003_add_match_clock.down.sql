@@ -0,0 +1,7 @@
+-- Remove the match clock
+DROP INDEX IF EXISTS idx_match_events_clock;
+CREATE INDEX idx_match_events_time ON match_events(match_id, period, minute, second) WHERE deleted_at IS NULL;
+
+ALTER TABLE match_events
+DROP COLUMN IF EXISTS period_number,
+DROP COLUMN IF EXISTS clock_ms;
@@ -0,0 +1,33 @@
+-- Add the canonical match clock to match_events
+-- Events are ordered by period and then by time on the match clock; stoppage time
+-- at the end of a period overlaps the nominal start of the next one (45+3 vs 46')
+
+ALTER TABLE match_events
+ADD COLUMN period_number SMALLINT NOT NULL DEFAULT 0, -- 1-2 halves, 3-4 extra time, 5 penalties, 0 unknown
+ADD COLUMN clock_ms BIGINT NOT NULL DEFAULT 0; -- Milliseconds on the match clock since kick-off
+
+-- extra_minute is stoppage time (45+2), which earlier ingestion mistook for extra time
+UPDATE match_events
+SET period = CASE
+    WHEN minute <= 45 THEN 'first_half'
+    ELSE 'second_half'
+END
+WHERE extra_minute > 0
+  AND minute <= 90
+  AND period IN ('extra_time_first', 'extra_time_second');
+
+-- Backfill the clock from the stored minute, stoppage minute and second
+UPDATE match_events
+SET period_number = CASE period
+        WHEN 'first_half' THEN 1
+        WHEN 'second_half' THEN 2
+        WHEN 'extra_time_first' THEN 3
+        WHEN 'extra_time_second' THEN 4
+        WHEN 'penalties' THEN 5
+        ELSE 0
+    END,
+    clock_ms = ((minute + COALESCE(extra_minute, 0)) * 60 + COALESCE(second, 0))::BIGINT * 1000;
+
+-- Replace the minute-based time index with the clock
+DROP INDEX IF EXISTS idx_match_events_time;
+CREATE INDEX idx_match_events_clock ON match_events(match_id, period_number, clock_ms, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+-- Remove possession assignments
+DROP INDEX IF EXISTS idx_match_events_possession;
+
+ALTER TABLE match_events
+DROP COLUMN IF EXISTS possession_id;
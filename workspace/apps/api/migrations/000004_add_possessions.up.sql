@@ -0,0 +1,8 @@
+-- Assign match events to possessions
+-- Possessions are numbered from 1 within a match by the sequence reconstructor
+-- (internal/analytics/possession); NULL until the match is reconstructed
+
+ALTER TABLE match_events
+ADD COLUMN possession_id INTEGER;
+
+CREATE INDEX idx_match_events_possession ON match_events(match_id, possession_id) WHERE possession_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,6 @@
+-- Remove game state tags
+DROP INDEX IF EXISTS idx_match_events_score_differential;
+
+ALTER TABLE match_events
+DROP COLUMN IF EXISTS score_differential,
+DROP COLUMN IF EXISTS game_state;
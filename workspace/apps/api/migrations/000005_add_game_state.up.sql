@@ -0,0 +1,10 @@
+-- Tag match events with the acting team's game state
+-- Both are taken from the score just before the event (a goal is tagged with the
+-- state it was scored in) by internal/analytics/gamestate; NULL until the match
+-- is tagged or for events without a team
+
+ALTER TABLE match_events
+ADD COLUMN game_state VARCHAR(10), -- winning, drawing, losing
+ADD COLUMN score_differential SMALLINT; -- Goals scored minus conceded by the event's team
+
+CREATE INDEX idx_match_events_score_differential ON match_events(team_id, score_differential) WHERE score_differential IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Remove match lineups
+DROP TABLE IF EXISTS match_lineup_players;
+DROP TABLE IF EXISTS match_lineups;
@@ -0,0 +1,36 @@
+-- Match lineups: each team's formation and squad (starting XI and bench)
+-- Minutes played are computed from the lineup and the substitution and
+-- sending-off events by internal/analytics/lineup; NULL until the match finishes
+
+CREATE TABLE match_lineups (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    formation VARCHAR(20), -- e.g. 4-3-3
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(match_id, team_id)
+);
+
+CREATE TABLE match_lineup_players (
+    id SERIAL PRIMARY KEY,
+    lineup_id INTEGER NOT NULL REFERENCES match_lineups(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    shirt_number INTEGER,
+    position VARCHAR(50),
+    starter BOOLEAN NOT NULL DEFAULT FALSE, -- In the starting XI; otherwise on the bench
+    minutes_played INTEGER,
+    subbed_on BOOLEAN NOT NULL DEFAULT FALSE,
+    subbed_off BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(lineup_id, player_id)
+);
+
+CREATE INDEX idx_match_lineup_players_player ON match_lineup_players(player_id);
+
+CREATE TRIGGER update_match_lineups_updated_at BEFORE UPDATE ON match_lineups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_match_lineup_players_updated_at BEFORE UPDATE ON match_lineup_players
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Remove team ratings
+DROP TABLE IF EXISTS team_ratings;
@@ -0,0 +1,22 @@
+-- Team Elo rating history: each team's rating before and after every finished
+-- match, per competition. Rebuilt from the results by internal/analytics/rating
+-- whenever a competition's match finishes, or with cmd/ratings
+
+CREATE TABLE team_ratings (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    opponent_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    competition VARCHAR(100) NOT NULL,
+    season VARCHAR(20) NOT NULL,
+    match_date TIMESTAMPTZ NOT NULL,
+    home BOOLEAN NOT NULL,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    expected_score DOUBLE PRECISION NOT NULL, -- 0 (certain loss) to 1 (certain win)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(team_id, match_id)
+);
+
+CREATE INDEX idx_team_ratings_team_date ON team_ratings(team_id, match_date);
+CREATE INDEX idx_team_ratings_competition ON team_ratings(competition, match_date);
@@ -0,0 +1,2 @@
+-- Remove match win probabilities
+DROP TABLE IF EXISTS match_win_probabilities;
@@ -0,0 +1,26 @@
+-- In-play win probabilities: each side's chance of winning after every event
+-- of a match, from internal/analytics/winprob. Added live as events arrive and
+-- rebuilt from the stored events when the match finishes
+
+CREATE TABLE match_win_probabilities (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES match_events(id) ON DELETE CASCADE,
+    period VARCHAR(20) NOT NULL,
+    minute INTEGER NOT NULL,
+    stoppage INTEGER NOT NULL,
+    second INTEGER NOT NULL,
+    period_number SMALLINT NOT NULL,
+    clock_ms BIGINT NOT NULL,
+    home_goals INTEGER NOT NULL,
+    away_goals INTEGER NOT NULL,
+    home_red_cards INTEGER NOT NULL,
+    away_red_cards INTEGER NOT NULL,
+    home_win DOUBLE PRECISION NOT NULL,
+    draw DOUBLE PRECISION NOT NULL,
+    away_win DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(event_id)
+);
+
+CREATE INDEX idx_match_win_probabilities_match_clock ON match_win_probabilities(match_id, period_number, clock_ms);
@@ -0,0 +1,7 @@
+-- Remove derived assist and key pass flags
+DROP INDEX IF EXISTS idx_match_events_derived_type;
+
+ALTER TABLE match_events
+DROP COLUMN IF EXISTS shot_event_id,
+DROP COLUMN IF EXISTS xa,
+DROP COLUMN IF EXISTS derived_type;
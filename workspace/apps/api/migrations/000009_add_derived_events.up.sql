@@ -0,0 +1,12 @@
+-- Flag passes inferred as assists, second assists and key passes
+-- Derived from each match's event sequence by internal/analytics/assists, for
+-- providers that do not send assist and key_pass events; NULL until the match is
+-- inferred or for passes that did not lead to a shot. Recomputed whenever the
+-- match's events are corrected
+
+ALTER TABLE match_events
+ADD COLUMN derived_type VARCHAR(20), -- assist, second_assist, key_pass
+ADD COLUMN xa DOUBLE PRECISION, -- Assists and key passes: the xG of the shot they led to
+ADD COLUMN shot_event_id INTEGER REFERENCES match_events(id) ON DELETE SET NULL; -- The shot or goal the pass led to
+
+CREATE INDEX idx_match_events_derived_type ON match_events(player_id, derived_type) WHERE derived_type IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,5 @@
+-- Remove shot-creating and goal-creating action flags
+DROP INDEX IF EXISTS idx_match_events_creation_type;
+
+ALTER TABLE match_events
+DROP COLUMN IF EXISTS creation_type;
@@ -0,0 +1,10 @@
+-- Flag shot-creating and goal-creating actions
+-- The two offensive actions (completed passes, dribbles and fouls won) directly
+-- before each shot, inferred by internal/analytics/assists with the derived
+-- passes; shot_event_id links them to the shot. NULL until the match is
+-- inferred. Recomputed whenever the match's events are corrected
+
+ALTER TABLE match_events
+ADD COLUMN creation_type VARCHAR(20); -- shot_creating, goal_creating (the shot was scored)
+
+CREATE INDEX idx_match_events_creation_type ON match_events(player_id, creation_type) WHERE creation_type IS NOT NULL AND deleted_at IS NULL;
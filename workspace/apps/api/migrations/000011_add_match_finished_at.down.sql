@@ -0,0 +1,6 @@
+-- Remove match finish times
+DROP TRIGGER IF EXISTS set_matches_finished_at ON matches;
+DROP FUNCTION IF EXISTS set_match_finished_at();
+
+ALTER TABLE matches
+DROP COLUMN IF EXISTS finished_at;
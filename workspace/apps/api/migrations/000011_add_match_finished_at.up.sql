@@ -0,0 +1,25 @@
+-- Record when each match finished
+-- Set when a match's status changes to finished and cleared when it changes
+-- away, so later edits to a finished match (score corrections, rating updates)
+-- leave it alone. Stream archival counts its retention window from it.
+
+ALTER TABLE matches
+ADD COLUMN finished_at TIMESTAMPTZ;
+
+-- Best available value for matches that finished before the column existed
+UPDATE matches SET finished_at = updated_at WHERE status = 'finished';
+
+CREATE OR REPLACE FUNCTION set_match_finished_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status <> 'finished' THEN
+        NEW.finished_at = NULL;
+    ELSIF TG_OP = 'INSERT' OR OLD.status <> 'finished' THEN
+        NEW.finished_at = COALESCE(NEW.finished_at, NOW());
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_matches_finished_at BEFORE INSERT OR UPDATE OF status ON matches
+    FOR EACH ROW EXECUTE FUNCTION set_match_finished_at();
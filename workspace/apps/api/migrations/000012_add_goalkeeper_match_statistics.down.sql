@@ -0,0 +1,2 @@
+-- Remove goalkeeper match statistics
+DROP TABLE IF EXISTS goalkeeper_match_statistics;
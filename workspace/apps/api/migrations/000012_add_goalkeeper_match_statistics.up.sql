@@ -0,0 +1,26 @@
+-- Goalkeepers' statistics in each match
+-- Computed from the match's events by internal/analytics/goalkeeping when the
+-- match finishes. The season goalkeeper columns of player_statistics and the
+-- goalkeeper rankings are added up from these rows
+
+CREATE TABLE goalkeeper_match_statistics (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE, -- The team the goalkeeper played for
+    clean_sheet BOOLEAN NOT NULL DEFAULT FALSE,
+    shots_on_target INTEGER NOT NULL DEFAULT 0, -- The opponent's shots on target faced, goals included
+    saves INTEGER NOT NULL DEFAULT 0,
+    goals_conceded INTEGER NOT NULL DEFAULT 0,
+    post_shot_xg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    goals_prevented DOUBLE PRECISION NOT NULL DEFAULT 0,
+    penalties_faced INTEGER NOT NULL DEFAULT 0,
+    penalties_saved INTEGER NOT NULL DEFAULT 0,
+    crosses_faced INTEGER NOT NULL DEFAULT 0,
+    crosses_claimed INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(match_id, player_id)
+);
+
+CREATE INDEX idx_goalkeeper_match_stats_player ON goalkeeper_match_statistics(player_id);
+CREATE INDEX idx_goalkeeper_match_stats_team ON goalkeeper_match_statistics(team_id);
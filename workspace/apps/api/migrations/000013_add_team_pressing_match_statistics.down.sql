@@ -0,0 +1,2 @@
+-- Remove team pressing match statistics
+DROP TABLE IF EXISTS team_pressing_match_statistics;
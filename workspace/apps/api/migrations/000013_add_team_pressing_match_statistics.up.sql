@@ -0,0 +1,19 @@
+-- Teams' pressing statistics in each match
+-- Computed from the match's events and possessions by internal/analytics/pressing
+-- when the match finishes. The pressing rankings are added up from these rows
+
+CREATE TABLE team_pressing_match_statistics (
+    id SERIAL PRIMARY KEY,
+    match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    opponent_passes INTEGER NOT NULL DEFAULT 0, -- The opponent's passes in its own 60% of the pitch
+    defensive_actions INTEGER NOT NULL DEFAULT 0, -- Defensive actions in the opponent's 60% of the pitch
+    high_turnovers INTEGER NOT NULL DEFAULT 0,
+    final_third_recoveries INTEGER NOT NULL DEFAULT 0,
+    ball_losses INTEGER NOT NULL DEFAULT 0,
+    counter_press_regains INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(match_id, team_id)
+);
+
+CREATE INDEX idx_team_pressing_match_stats_team ON team_pressing_match_statistics(team_id);